@@ -38,7 +38,7 @@ ADD dir/c.txt .
 	refs, err := f.b.BuildImage(f.ctx, f.ps, f.getNameFromTest(), model.DockerBuild{
 		Dockerfile: df.String(),
 		BuildPath:  f.Path(),
-	}, model.EmptyMatcher)
+	}, model.EmptyMatcher, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -73,7 +73,7 @@ ADD $some_variable_name /test.txt`)
 		Dockerfile: df.String(),
 		BuildPath:  f.Path(),
 		BuildArgs:  ba,
-	}, model.EmptyMatcher)
+	}, model.EmptyMatcher, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -99,7 +99,7 @@ ADD a.txt .`)
 		Dockerfile: df.String(),
 		BuildPath:  f.Path(),
 		ExtraTags:  []string{"fe:jenkins-1234"},
-	}, model.EmptyMatcher)
+	}, model.EmptyMatcher, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -125,7 +125,7 @@ func TestDetectBuildkitCorruption(t *testing.T) {
 RUN echo 'failed to create LLB definition: failed commit on ref "unknown-sha256:b72fa303a3a5fbf52c723bfcfb93948bb53b3d7e8d22418e9d171a27ad7dcd84": "unknown-sha256:b72fa303a3a5fbf52c723bfcfb93948bb53b3d7e8d22418e9d171a27ad7dcd84" failed size validation: 80941 != 80929: failed precondition' && exit 1
 `,
 		BuildPath: f.Path(),
-	}, model.EmptyMatcher)
+	}, model.EmptyMatcher, nil)
 	assert.Error(t, err)
 	assert.Contains(t, out.String(), "Detected Buildkit corruption. Rebuilding without Buildkit")
 	assert.Contains(t, out.String(), "[1/2] FROM docker.io/library/alpine") // buildkit-style output
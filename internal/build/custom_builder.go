@@ -19,6 +19,9 @@ import (
 
 type CustomBuilder interface {
 	Build(ctx context.Context, refs container.RefSet, cb model.CustomBuild) (container.TaggedRefs, error)
+
+	// Returns a builder that checks images against the Docker daemon for the given orchestrator.
+	ForOrchestrator(orc model.Orchestrator) CustomBuilder
 }
 
 type ExecCustomBuilder struct {
@@ -33,6 +36,10 @@ func NewExecCustomBuilder(dCli docker.Client, clock Clock) *ExecCustomBuilder {
 	}
 }
 
+func (b *ExecCustomBuilder) ForOrchestrator(orc model.Orchestrator) CustomBuilder {
+	return NewExecCustomBuilder(b.dCli.ForOrchestrator(orc), b.clock)
+}
+
 func (b *ExecCustomBuilder) Build(ctx context.Context, refs container.RefSet, cb model.CustomBuild) (container.TaggedRefs, error) {
 	workDir := cb.WorkDir
 	expectedTag := cb.Tag
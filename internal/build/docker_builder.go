@@ -33,17 +33,24 @@ type dockerImageBuilder struct {
 	//
 	// By default, all builds are labeled with a build mode.
 	extraLabels dockerfile.Labels
+
+	// Connects to standalone BuildKit daemons, for builds with a builder.
+	dialBuildkit BuildkitDialer
 }
 
 type DockerBuilder interface {
 	// Returns whether this docker builder is going to build to the given kubernetes context.
 	WillBuildToKubeContext(kctx k8s.KubeContext) bool
 
-	BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet, db model.DockerBuild, filter model.PathMatcher) (container.TaggedRefs, error)
+	// Builds the image, with the given labels in addition to the builder's own.
+	BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet, db model.DockerBuild, filter model.PathMatcher, labels dockerfile.Labels) (container.TaggedRefs, error)
 	DumpImageDeployRef(ctx context.Context, ref string) (reference.NamedTagged, error)
 	PushImage(ctx context.Context, name reference.NamedTagged) error
 	TagRefs(ctx context.Context, refs container.RefSet, dig digest.Digest) (container.TaggedRefs, error)
 	ImageExists(ctx context.Context, ref reference.NamedTagged) (bool, error)
+
+	// Returns a builder that builds with the Docker daemon for the given orchestrator.
+	ForOrchestrator(orc model.Orchestrator) DockerBuilder
 }
 
 func DefaultDockerBuilder(b *dockerImageBuilder) DockerBuilder {
@@ -54,23 +61,34 @@ var _ DockerBuilder = &dockerImageBuilder{}
 
 func NewDockerImageBuilder(dCli docker.Client, extraLabels dockerfile.Labels) *dockerImageBuilder {
 	return &dockerImageBuilder{
-		dCli:        dCli,
-		extraLabels: extraLabels,
+		dCli:         dCli,
+		extraLabels:  extraLabels,
+		dialBuildkit: DialBuildkit,
 	}
 }
 
+func (d *dockerImageBuilder) ForOrchestrator(orc model.Orchestrator) DockerBuilder {
+	b := NewDockerImageBuilder(d.dCli.ForOrchestrator(orc), d.extraLabels)
+	b.dialBuildkit = d.dialBuildkit
+	return b
+}
+
 func (d *dockerImageBuilder) WillBuildToKubeContext(kctx k8s.KubeContext) bool {
 	return d.dCli.Env().WillBuildToKubeContext(kctx)
 }
 
-func (d *dockerImageBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet, db model.DockerBuild, filter model.PathMatcher) (container.TaggedRefs, error) {
+func (d *dockerImageBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet, db model.DockerBuild, filter model.PathMatcher, labels dockerfile.Labels) (container.TaggedRefs, error) {
+	if db.Builder != "" {
+		return d.buildOnBuilder(ctx, ps, refs, db, filter, labels)
+	}
+
 	paths := []PathMapping{
 		{
 			LocalPath:     db.BuildPath,
 			ContainerPath: "/",
 		},
 	}
-	return d.buildFromDf(ctx, ps, db, paths, filter, refs)
+	return d.buildFromDf(ctx, ps, db, paths, filter, refs, labels)
 }
 
 func (d *dockerImageBuilder) DumpImageDeployRef(ctx context.Context, ref string) (reference.NamedTagged, error) {
@@ -125,9 +143,13 @@ func (d *dockerImageBuilder) TagRefs(ctx context.Context, refs container.RefSet,
 // we're running in has access to the given registry. And if it doesn't, we should either emit an
 // error, or push to a registry that kubernetes does have access to (e.g., a local registry).
 func (d *dockerImageBuilder) PushImage(ctx context.Context, ref reference.NamedTagged) error {
+	return pushImage(ctx, d.dCli, ref)
+}
+
+func pushImage(ctx context.Context, dCli docker.Client, ref reference.NamedTagged) error {
 	l := logger.Get(ctx)
 
-	imagePushResponse, err := d.dCli.ImagePush(ctx, ref)
+	imagePushResponse, err := dCli.ImagePush(ctx, ref)
 	if err != nil {
 		return errors.Wrap(err, "PushImage#ImagePush")
 	}
@@ -158,7 +180,7 @@ func (d *dockerImageBuilder) ImageExists(ctx context.Context, ref reference.Name
 	return true, nil
 }
 
-func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState, db model.DockerBuild, paths []PathMapping, filter model.PathMatcher, refs container.RefSet) (container.TaggedRefs, error) {
+func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState, db model.DockerBuild, paths []PathMapping, filter model.PathMatcher, refs container.RefSet, labels dockerfile.Labels) (container.TaggedRefs, error) {
 	logger.Get(ctx).Infof("Building Dockerfile:\n%s\n", indent(db.Dockerfile, "  "))
 
 	ps.StartBuildStep(ctx, "Tarring context…")
@@ -173,7 +195,7 @@ func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState,
 	ps.StartBuildStep(ctx, "Building image")
 	allowBuildkit := true
 	ctx = ps.AttachLogger(ctx)
-	digest, err := d.buildFromDfToDigest(ctx, db, paths, filter, allowBuildkit)
+	digest, err := d.buildFromDfToDigest(ctx, db, paths, filter, labels, allowBuildkit)
 	if err != nil {
 		isMysteriousCorruption := strings.Contains(err.Error(), "failed precondition") &&
 			strings.Contains(err.Error(), "failed commit on ref")
@@ -194,7 +216,7 @@ func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState,
 			// If this happens, just try again without buildkit.
 			allowBuildkit = false
 			logger.Get(ctx).Infof("Detected Buildkit corruption. Rebuilding without Buildkit")
-			digest, err = d.buildFromDfToDigest(ctx, db, paths, filter, allowBuildkit)
+			digest, err = d.buildFromDfToDigest(ctx, db, paths, filter, labels, allowBuildkit)
 		}
 
 		if err != nil {
@@ -212,7 +234,7 @@ func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState,
 
 // A helper function that builds the paths to the given docker image,
 // then returns the output digest.
-func (d *dockerImageBuilder) buildFromDfToDigest(ctx context.Context, db model.DockerBuild, paths []PathMapping, filter model.PathMatcher, allowBuildkit bool) (digest.Digest, error) {
+func (d *dockerImageBuilder) buildFromDfToDigest(ctx context.Context, db model.DockerBuild, paths []PathMapping, filter model.PathMatcher, labels dockerfile.Labels, allowBuildkit bool) (digest.Digest, error) {
 	pr, pw := io.Pipe()
 	go func(ctx context.Context) {
 		err := tarContextAndUpdateDf(ctx, pw, dockerfile.Dockerfile(db.Dockerfile), paths, filter)
@@ -228,6 +250,13 @@ func (d *dockerImageBuilder) buildFromDfToDigest(ctx context.Context, db model.D
 	}()
 
 	options := Options(pr, db)
+	options.Labels = make(map[string]string, len(d.extraLabels)+len(labels))
+	for k, v := range d.extraLabels {
+		options.Labels[string(k)] = string(v)
+	}
+	for k, v := range labels {
+		options.Labels[string(k)] = string(v)
+	}
 	if !allowBuildkit {
 		options.ForceLegacyBuilder = true
 	}
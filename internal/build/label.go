@@ -14,6 +14,11 @@ const (
 
 	// Label when an image is for path caching.
 	CacheImage dockerfile.Label = "tilt.cache"
+
+	// Label with the hash of the inputs an image was built from.
+	//
+	// See BuildHash.
+	BuildHashLabel dockerfile.Label = "tilt.build"
 )
 
 const (
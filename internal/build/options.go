@@ -2,7 +2,9 @@ package build
 
 import (
 	"flag"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -21,9 +23,26 @@ func Options(archive io.Reader, db model.DockerBuild) docker.BuildOptions {
 		SecretSpecs: db.SecretSpecs,
 		CacheFrom:   db.CacheFrom,
 		PullParent:  db.PullParent,
+		Platform:    db.Platform,
 	}
 }
 
+// Checks that the platform looks like os/arch[/variant], e.g., "linux/arm64/v8".
+//
+// The builder does the real validation, but we catch typos early.
+func ValidatePlatform(platform string) error {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("expected a platform like os/arch or os/arch/variant, e.g., linux/amd64")
+	}
+	for _, part := range parts {
+		if part == "" || strings.ContainsAny(part, " ,") {
+			return fmt.Errorf("expected a platform like os/arch or os/arch/variant, e.g., linux/amd64")
+		}
+	}
+	return nil
+}
+
 func shouldRemoveImage() bool {
 	return flag.Lookup("test.v") != nil
 }
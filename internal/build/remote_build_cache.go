@@ -0,0 +1,80 @@
+package build
+
+import (
+	"context"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+)
+
+// A RemoteBuildCache finds images that were already built from the same
+// inputs (see BuildHash) and pushed to a registry, e.g., by CI or a teammate,
+// so that we can deploy them instead of building our own.
+type RemoteBuildCache interface {
+	// Returns refs to an image in the cache with the given build hash,
+	// and whether one exists.
+	Lookup(ctx context.Context, refs container.RefSet, hash string) (container.TaggedRefs, bool, error)
+
+	// Tags an image that we built with the given build hash, so that
+	// others can find it in the cache once it's pushed.
+	//
+	// Returns the refs to push.
+	Tag(ctx context.Context, refs container.RefSet, built container.TaggedRefs, hash string) (container.TaggedRefs, error)
+}
+
+// A RemoteBuildCache that finds images in the registry they're pushed to,
+// under a tag derived from their build hash.
+type DockerRemoteBuildCache struct {
+	dCli docker.Client
+}
+
+var _ RemoteBuildCache = &DockerRemoteBuildCache{}
+
+func NewDockerRemoteBuildCache(dCli docker.Client) *DockerRemoteBuildCache {
+	return &DockerRemoteBuildCache{dCli: dCli}
+}
+
+func (c *DockerRemoteBuildCache) Lookup(ctx context.Context, refs container.RefSet, hash string) (container.TaggedRefs, bool, error) {
+	tag, err := BuildHashTag(hash)
+	if err != nil {
+		return container.TaggedRefs{}, false, errors.Wrap(err, "RemoteBuildCache")
+	}
+
+	tagged, err := refs.AddTagSuffix(tag)
+	if err != nil {
+		return container.TaggedRefs{}, false, errors.Wrap(err, "RemoteBuildCache")
+	}
+
+	// The tag has the whole hash, so if the registry has a manifest
+	// for it, we don't need to pull the image to check its labels.
+	// Builds that depend on it will pull it if they need it.
+	_, err = c.dCli.DistributionInspect(ctx, tagged.LocalRef)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return container.TaggedRefs{}, false, nil
+		}
+		return container.TaggedRefs{}, false, errors.Wrapf(err, "inspecting image %q", tagged.LocalRef.String())
+	}
+	return tagged, true, nil
+}
+
+func (c *DockerRemoteBuildCache) Tag(ctx context.Context, refs container.RefSet, built container.TaggedRefs, hash string) (container.TaggedRefs, error) {
+	tag, err := BuildHashTag(hash)
+	if err != nil {
+		return container.TaggedRefs{}, errors.Wrap(err, "RemoteBuildCache")
+	}
+
+	tagged, err := refs.AddTagSuffix(tag)
+	if err != nil {
+		return container.TaggedRefs{}, errors.Wrap(err, "RemoteBuildCache")
+	}
+
+	err = c.dCli.ImageTag(ctx, built.LocalRef.String(), tagged.LocalRef.String())
+	if err != nil {
+		return container.TaggedRefs{}, errors.Wrap(err, "RemoteBuildCache")
+	}
+	return tagged, nil
+}
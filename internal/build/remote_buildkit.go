@@ -0,0 +1,347 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	fstypes "github.com/tonistiigi/fsutil/types"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/dockerfile"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The name of the build context that we sync to the builder.
+const remoteContextName = "context"
+
+// The parts of the BuildKit client that we use.
+type BuildkitClient interface {
+	Solve(ctx context.Context, def *llb.Definition, opt bkclient.SolveOpt, statusChan chan *bkclient.SolveStatus) (*bkclient.SolveResponse, error)
+	Close() error
+}
+
+// Connects to the BuildKit daemon that the build runs on.
+type BuildkitDialer func(ctx context.Context, db model.DockerBuild) (BuildkitClient, error)
+
+// Checks that the address of a BuildKit daemon is one we know how to dial.
+func ValidateBuilderAddress(addr string) error {
+	_, _, err := builderNetworkAddress(addr)
+	return err
+}
+
+// Checks that the TLS settings for a builder are complete.
+func ValidateBuilderTLS(addr string, tls model.BuilderTLS) error {
+	if tls.Empty() {
+		return nil
+	}
+	if !strings.HasPrefix(addr, "tcp://") {
+		return fmt.Errorf("TLS is only supported for tcp:// builders")
+	}
+	if tls.CACert == "" {
+		return fmt.Errorf("TLS requires a CA certificate to verify the builder")
+	}
+	if (tls.Cert == "") != (tls.Key == "") {
+		return fmt.Errorf("a client certificate and key must be specified together")
+	}
+	return nil
+}
+
+func builderNetworkAddress(addr string) (string, string, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("expected an address like tcp://host:port")
+		}
+		return "tcp", u.Host, nil
+	case "unix":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("expected an address like unix:///path/to/buildkitd.sock")
+		}
+		return "unix", u.Path, nil
+	}
+	return "", "", fmt.Errorf("unsupported scheme %q (expected tcp:// or unix://)", u.Scheme)
+}
+
+func DialBuildkit(ctx context.Context, db model.DockerBuild) (BuildkitClient, error) {
+	network, netAddr, err := builderNetworkAddress(db.Builder)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []bkclient.ClientOpt{
+		bkclient.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, netAddr)
+		}),
+	}
+
+	// Without TLS settings, the client connects insecurely.
+	tls := db.BuilderTLS
+	if !tls.Empty() {
+		serverName, _, err := net.SplitHostPort(netAddr)
+		if err != nil {
+			serverName = netAddr
+		}
+		opts = append(opts, bkclient.WithCredentials(serverName, tls.CACert, tls.Cert, tls.Key))
+	}
+
+	client, err := bkclient.New(ctx, db.Builder, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to BuildKit at %s", db.Builder)
+	}
+	return client, nil
+}
+
+// The key in the BuildKit exporter response for the digest of the image manifest.
+const exporterImageDigestKey = "containerimage.digest"
+
+// Builds the image on a standalone BuildKit daemon, which pushes it straight
+// to the registry, so the image never lands in the local Docker daemon.
+//
+// The build context is synced to the daemon over a file-sync session, the
+// same way `docker buildx` sends it, and registry credentials come from the
+// local Docker config.
+//
+// Like a local build, the image is tagged with its digest. We don't know the
+// digest until the image is built, so we build it once without pushing, then
+// export it again with the tag. The second build is all cache hits.
+func (d *dockerImageBuilder) buildOnBuilder(ctx context.Context, ps *PipelineState, refs container.RefSet, db model.DockerBuild, filter model.PathMatcher, labels dockerfile.Labels) (container.TaggedRefs, error) {
+	err := checkBuilderCanPush(refs)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	logger.Get(ctx).Infof("Building Dockerfile on %s:\n%s\n", db.Builder, indent(db.Dockerfile, "  "))
+
+	ps.StartBuildStep(ctx, "Building image")
+	buildCtx := ps.AttachLogger(ctx)
+
+	client, err := d.dialBuildkit(buildCtx, db)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	attrs := d.frontendAttrs(db, labels)
+	resp, err := solveOnBuilder(buildCtx, client, db, filter, attrs, map[string]string{})
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	imageDigest, err := digest.Parse(resp.ExporterResponse[exporterImageDigestKey])
+	if err != nil {
+		return container.TaggedRefs{}, errors.Wrap(err, "reading image digest from builder")
+	}
+	tag, err := digestAsTag(imageDigest)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	tagged, err := refs.AddTagSuffix(tag)
+	if err != nil {
+		return container.TaggedRefs{}, errors.Wrap(err, "buildOnBuilder")
+	}
+
+	// The image never lands in the local daemon, so the builder pushes
+	// the extra tags along with the tag we deploy.
+	names := append([]string{tagged.LocalRef.String()}, db.ExtraTags...)
+
+	ps.StartBuildStep(ctx, "Pushing %s", container.FamiliarString(tagged.LocalRef))
+	_, err = solveOnBuilder(ps.AttachLogger(ctx), client, db, filter, attrs, map[string]string{
+		"name": strings.Join(names, ","),
+		"push": "true",
+	})
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+	return tagged, nil
+}
+
+// The builder pushes the image, so it needs a registry to push to: either
+// a default_registry / the cluster's local registry, or a registry in the
+// image name. Otherwise the push goes to docker.io/library, which never works.
+func checkBuilderCanPush(refs container.RefSet) error {
+	if !refs.Registry().Empty() {
+		return nil
+	}
+	ref := refs.LocalRef()
+	if reference.Domain(ref) == "docker.io" && strings.HasPrefix(reference.Path(ref), "library/") {
+		return fmt.Errorf("Image %s is built on a builder, which pushes it to a registry, "+
+			"but no registry is configured. Use default_registry(), a cluster with a local registry, "+
+			"or an image name with a registry (e.g., gcr.io/my-project/%s)",
+			container.FamiliarString(ref), strings.TrimPrefix(reference.Path(ref), "library/"))
+	}
+	return nil
+}
+
+// Runs the Dockerfile frontend on the builder, and exports the result
+// as an image with the given exporter attributes.
+func solveOnBuilder(ctx context.Context, client BuildkitClient, db model.DockerBuild, filter model.PathMatcher, attrs, exportAttrs map[string]string) (*bkclient.SolveResponse, error) {
+	l := logger.Get(ctx)
+	opt := bkclient.SolveOpt{
+		Frontend:      "dockerfile.v0",
+		FrontendAttrs: attrs,
+		FrontendInputs: map[string]llb.State{
+			"dockerfile": llb.Scratch().File(llb.Mkfile("/Dockerfile", 0644, []byte(db.Dockerfile))),
+		},
+		Exports: []bkclient.ExportEntry{
+			{
+				Type:  bkclient.ExporterImage,
+				Attrs: exportAttrs,
+			},
+		},
+		Session: []session.Attachable{
+			authprovider.NewDockerAuthProvider(l.Writer(logger.DebugLvl)),
+			filesync.NewFSSyncProvider([]filesync.SyncedDir{
+				{
+					Name: remoteContextName,
+					Dir:  db.BuildPath,
+					Map:  remoteContextFilter(ctx, db.BuildPath, filter),
+				},
+			}),
+		},
+	}
+
+	statusCh := make(chan *bkclient.SolveStatus)
+	statusDone := make(chan struct{})
+	go func() {
+		defer close(statusDone)
+		printBuildkitStatus(ctx, statusCh)
+	}()
+
+	// Solve closes the status channel when it returns.
+	resp, err := client.Solve(ctx, nil, opt, statusCh)
+	<-statusDone
+	if err != nil {
+		return nil, errors.New(cleanupDockerBuildError(err.Error()))
+	}
+	return resp, nil
+}
+
+// Streams the build progress to the logger in the context.
+func printBuildkitStatus(ctx context.Context, statusCh chan *bkclient.SolveStatus) {
+	b := newBuildkitPrinter(logger.Get(ctx))
+	for status := range statusCh {
+		err := b.parseAndPrint(solveStatusToVertexes(status))
+		if err != nil {
+			logger.Get(ctx).Debugf("Printing BuildKit status: %v", err)
+		}
+	}
+}
+
+func solveStatusToVertexes(status *bkclient.SolveStatus) ([]*vertex, []*vertexLog, []*vertexStatus) {
+	vertexes := []*vertex{}
+	logs := []*vertexLog{}
+	statuses := []*vertexStatus{}
+
+	for _, v := range status.Vertexes {
+		duration := time.Duration(0)
+		started := v.Started != nil
+		completed := v.Completed != nil
+		if started && completed {
+			duration = (*v.Completed).Sub((*v.Started))
+		}
+		vertexes = append(vertexes, &vertex{
+			digest:    v.Digest,
+			name:      v.Name,
+			error:     v.Error,
+			started:   started,
+			completed: completed,
+			cached:    v.Cached,
+			duration:  duration,
+		})
+	}
+	for _, v := range status.Logs {
+		logs = append(logs, &vertexLog{
+			vertex: v.Vertex,
+			msg:    v.Data,
+		})
+	}
+	for _, s := range status.Statuses {
+		statuses = append(statuses, &vertexStatus{
+			vertex:    s.Vertex,
+			id:        s.ID,
+			total:     s.Total,
+			current:   s.Current,
+			timestamp: s.Timestamp,
+		})
+	}
+	return vertexes, logs, statuses
+}
+
+// Decides which files in the build context to sync to the builder.
+//
+// The builder applies the .dockerignore itself. We also leave out
+// the files that the filter ignores, as we do for local builds.
+func remoteContextFilter(ctx context.Context, buildPath string, filter model.PathMatcher) func(string, *fstypes.Stat) bool {
+	return func(p string, st *fstypes.Stat) bool {
+		// Make the files look the same no matter who synced them,
+		// as `docker buildx` does.
+		st.Uid = 0
+		st.Gid = 0
+
+		matches, err := filter.Matches(filepath.Join(buildPath, filepath.FromSlash(p)))
+		if err != nil {
+			logger.Get(ctx).Debugf("Matching %s: %v", p, err)
+			return true
+		}
+		return !matches
+	}
+}
+
+// The options to the Dockerfile frontend, equivalent to the options that
+// we pass to the Docker daemon for a local build.
+func (d *dockerImageBuilder) frontendAttrs(db model.DockerBuild, labels dockerfile.Labels) map[string]string {
+	attrs := map[string]string{
+		"filename": "Dockerfile",
+	}
+	if db.TargetStage != "" {
+		attrs["target"] = string(db.TargetStage)
+	}
+	if db.Platform != "" {
+		attrs["platform"] = db.Platform
+	}
+	if db.Network != "" {
+		attrs["force-network-mode"] = db.Network
+	}
+	if db.PullParent {
+		attrs["image-resolve-mode"] = "pull"
+	}
+	if len(db.CacheFrom) > 0 {
+		attrs["cache-from"] = strings.Join(db.CacheFrom, ",")
+	}
+	for k, v := range db.BuildArgs {
+		attrs["build-arg:"+k] = v
+	}
+	for k, v := range d.extraLabels {
+		attrs["label:"+string(k)] = string(v)
+	}
+	for k, v := range labels {
+		attrs["label:"+string(k)] = string(v)
+	}
+	for k, v := range docker.BuiltByTiltLabel {
+		attrs["label:"+k] = v
+	}
+	return attrs
+}
@@ -0,0 +1,280 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	fstypes "github.com/tonistiigi/fsutil/types"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/dockerfile"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestValidateBuilderAddress(t *testing.T) {
+	assert.NoError(t, ValidateBuilderAddress("tcp://buildkitd:1234"))
+	assert.NoError(t, ValidateBuilderAddress("unix:///run/buildkit/buildkitd.sock"))
+
+	err := ValidateBuilderAddress("buildkitd:1234")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported scheme")
+
+	err = ValidateBuilderAddress("tcp://")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tcp://host:port")
+}
+
+func TestValidateBuilderTLS(t *testing.T) {
+	assert.NoError(t, ValidateBuilderTLS("tcp://buildkitd:1234", model.BuilderTLS{}))
+	assert.NoError(t, ValidateBuilderTLS("tcp://buildkitd:1234", model.BuilderTLS{CACert: "ca.pem"}))
+	assert.NoError(t, ValidateBuilderTLS("tcp://buildkitd:1234", model.BuilderTLS{CACert: "ca.pem", Cert: "cert.pem", Key: "key.pem"}))
+
+	err := ValidateBuilderTLS("unix:///run/buildkit/buildkitd.sock", model.BuilderTLS{CACert: "ca.pem"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only supported for tcp://")
+
+	err = ValidateBuilderTLS("tcp://buildkitd:1234", model.BuilderTLS{Cert: "cert.pem", Key: "key.pem"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CA certificate")
+
+	err = ValidateBuilderTLS("tcp://buildkitd:1234", model.BuilderTLS{CACert: "ca.pem", Cert: "cert.pem"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "together")
+}
+
+func TestRemoteBuildSolveOpt(t *testing.T) {
+	f := newRemoteBuildFixture(t)
+	f.WriteFile("main.go", "package main // hello")
+
+	refs, err := f.build(model.DockerBuild{
+		Dockerfile:  "FROM golang\nADD . .",
+		BuildPath:   f.Path(),
+		BuildArgs:   model.DockerBuildArgs{"VERSION": "1"},
+		TargetStage: "app",
+		Platform:    "linux/amd64",
+		Builder:     "tcp://buildkitd:1234",
+		ExtraTags:   []string{"gcr.io/foo/bar:latest"},
+	}, dockerfile.Labels{BuildHashLabel: "abc"})
+	require.NoError(t, err)
+
+	require.Equal(t, "tcp://buildkitd:1234", f.client.db.Builder)
+	require.Len(t, f.client.opts, 2)
+
+	// The first solve builds the image, so that we know its digest.
+	build := f.client.opts[0]
+	require.Len(t, build.Exports, 1)
+	assert.Equal(t, "image", build.Exports[0].Type)
+	assert.Empty(t, build.Exports[0].Attrs)
+
+	// The second solve tags and pushes it.
+	opt := f.client.opts[1]
+	assert.Equal(t, "dockerfile.v0", opt.Frontend)
+	assert.Equal(t, "linux/amd64", opt.FrontendAttrs["platform"])
+	assert.Equal(t, "app", opt.FrontendAttrs["target"])
+	assert.Equal(t, "1", opt.FrontendAttrs["build-arg:VERSION"])
+	assert.Equal(t, "abc", opt.FrontendAttrs["label:tilt.build"])
+	assert.Equal(t, docker.BuiltByValue, opt.FrontendAttrs["label:"+docker.BuiltByLabel])
+
+	require.Len(t, opt.Exports, 1)
+	assert.Equal(t, "image", opt.Exports[0].Type)
+	assert.Equal(t, "true", opt.Exports[0].Attrs["push"])
+	assert.Equal(t, refs.LocalRef.String()+",gcr.io/foo/bar:latest", opt.Exports[0].Attrs["name"])
+	assert.True(t, strings.HasPrefix(refs.LocalRef.Tag(), ImageTagPrefix))
+
+	// The context is synced over the session, not sent inline.
+	assert.Len(t, opt.Session, 2)
+	assert.Empty(t, opt.LocalDirs)
+	def, err := opt.FrontendInputs["dockerfile"].Marshal(f.ctx)
+	require.NoError(t, err)
+	assert.True(t, defContains(def.Def, "ADD . ."))
+
+	assert.Contains(t, f.out.String(), "[1/2] FROM docker.io/library/golang")
+}
+
+func TestRemoteContextFilter(t *testing.T) {
+	f := newRemoteBuildFixture(t)
+	matcher, err := model.NewSimpleFileMatcher(f.JoinPath("secret.txt"))
+	require.NoError(t, err)
+
+	filter := remoteContextFilter(f.ctx, f.Path(), matcher)
+	st := &fstypes.Stat{Uid: 501, Gid: 20}
+	assert.True(t, filter("main.go", st))
+	assert.Equal(t, uint32(0), st.Uid)
+	assert.Equal(t, uint32(0), st.Gid)
+	assert.False(t, filter("secret.txt", &fstypes.Stat{}))
+}
+
+func TestRemoteBuildTagsWithImageDigest(t *testing.T) {
+	f := newRemoteBuildFixture(t)
+	db := model.DockerBuild{
+		Dockerfile: "FROM golang:latest\nADD . .",
+		BuildPath:  f.Path(),
+		Builder:    "tcp://buildkitd:1234",
+	}
+	f.WriteFile("main.go", "package main")
+
+	f.client.imageDigest = digest.FromString("image-1")
+	refs1, err := f.build(db, nil)
+	require.NoError(t, err)
+	expected, err := digestAsTag(f.client.imageDigest)
+	require.NoError(t, err)
+	assert.Equal(t, expected, refs1.LocalRef.Tag())
+
+	// Same inputs, but the base image changed, so the image did too.
+	f.client.imageDigest = digest.FromString("image-2")
+	refs2, err := f.build(db, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, refs1.LocalRef.String(), refs2.LocalRef.String())
+}
+
+func TestRemoteBuildMissingDigest(t *testing.T) {
+	f := newRemoteBuildFixture(t)
+	f.client.imageDigest = "none"
+
+	_, err := f.build(model.DockerBuild{
+		Dockerfile: "FROM golang",
+		BuildPath:  f.Path(),
+		Builder:    "tcp://buildkitd:1234",
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading image digest from builder")
+	assert.Len(t, f.client.opts, 1)
+}
+
+func TestRemoteBuildError(t *testing.T) {
+	f := newRemoteBuildFixture(t)
+	f.client.solveErr = io.ErrUnexpectedEOF
+
+	_, err := f.build(model.DockerBuild{
+		Dockerfile: "FROM golang",
+		BuildPath:  f.Path(),
+		Builder:    "tcp://buildkitd:1234",
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected EOF")
+}
+
+func TestRemoteBuildNeedsRegistry(t *testing.T) {
+	f := newRemoteBuildFixture(t)
+	db := model.DockerBuild{
+		Dockerfile: "FROM golang",
+		BuildPath:  f.Path(),
+		Builder:    "tcp://buildkitd:1234",
+	}
+	ps := NewPipelineState(f.ctx, 1, ProvideClock())
+
+	refs, err := container.NewRefSet(container.MustParseSelector("foo"), container.Registry{})
+	require.NoError(t, err)
+	_, err = f.b.BuildImage(f.ctx, ps, refs, db, model.EmptyMatcher, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no registry is configured")
+	assert.Empty(t, f.client.opts)
+
+	refs, err = container.NewRefSet(container.MustParseSelector("foo"), container.MustNewRegistry("localhost:5000"))
+	require.NoError(t, err)
+	tagged, err := f.b.BuildImage(f.ctx, ps, refs, db, model.EmptyMatcher, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(tagged.LocalRef.String(), "localhost:5000/foo:"+ImageTagPrefix))
+}
+
+func defContains(def [][]byte, s string) bool {
+	for _, op := range def {
+		if bytes.Contains(op, []byte(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+type remoteBuildFixture struct {
+	*tempdir.TempDirFixture
+	ctx    context.Context
+	out    *bytes.Buffer
+	b      *dockerImageBuilder
+	client *fakeBuildkitClient
+}
+
+func newRemoteBuildFixture(t *testing.T) *remoteBuildFixture {
+	f := tempdir.NewTempDirFixture(t)
+	t.Cleanup(f.TearDown)
+
+	out := &bytes.Buffer{}
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	ctx = logger.WithLogger(ctx, logger.NewTestLogger(out))
+
+	client := &fakeBuildkitClient{}
+	b := NewDockerImageBuilder(docker.NewFakeClient(), nil)
+	b.dialBuildkit = func(ctx context.Context, db model.DockerBuild) (BuildkitClient, error) {
+		client.db = db
+		return client, nil
+	}
+
+	return &remoteBuildFixture{
+		TempDirFixture: f,
+		ctx:            ctx,
+		out:            out,
+		b:              b,
+		client:         client,
+	}
+}
+
+func (f *remoteBuildFixture) build(db model.DockerBuild, labels dockerfile.Labels) (container.TaggedRefs, error) {
+	refs, err := container.NewRefSet(container.MustParseSelector("gcr.io/foo/bar"), container.Registry{})
+	require.NoError(f.T(), err)
+	ps := NewPipelineState(f.ctx, 1, ProvideClock())
+	return f.b.BuildImage(f.ctx, ps, refs, db, model.EmptyMatcher, labels)
+}
+
+// Records the solve options, reports one completed build step,
+// and returns imageDigest as the digest of the image.
+type fakeBuildkitClient struct {
+	db          model.DockerBuild
+	opts        []bkclient.SolveOpt
+	solveErr    error
+	imageDigest digest.Digest
+}
+
+func (c *fakeBuildkitClient) Solve(ctx context.Context, def *llb.Definition, opt bkclient.SolveOpt, statusChan chan *bkclient.SolveStatus) (*bkclient.SolveResponse, error) {
+	defer close(statusChan)
+
+	c.opts = append(c.opts, opt)
+	if c.solveErr != nil {
+		return nil, c.solveErr
+	}
+
+	now := time.Now()
+	statusChan <- &bkclient.SolveStatus{
+		Vertexes: []*bkclient.Vertex{
+			{
+				Digest:    digest.FromString("from"),
+				Name:      "[1/2] FROM docker.io/library/golang",
+				Started:   &now,
+				Completed: &now,
+			},
+		},
+	}
+	imageDigest := c.imageDigest
+	if imageDigest == "" {
+		imageDigest = digest.FromString("image")
+	}
+	return &bkclient.SolveResponse{
+		ExporterResponse: map[string]string{exporterImageDigestKey: imageDigest.String()},
+	}, nil
+}
+
+func (c *fakeBuildkitClient) Close() error {
+	return nil
+}
@@ -1,8 +1,116 @@
 package build
 
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
 // The image tag prefix can be customized.
 //
 // This allows our integration tests to customize
 // the prefix so that they can write to a public
 // registry without interfering with each other.
 var ImageTagPrefix = "tilt-"
+
+// Computes a hash of everything that goes into a Docker build:
+// the Dockerfile, build args, target stage, and the contents of
+// the build context that the filter doesn't ignore.
+//
+// Unlike the image digest, the hash is known before the build runs,
+// so we can use it to look for an image that someone else already built
+// from the same inputs. File modification times are deliberately
+// left out, so that two checkouts of the same commit hash the same.
+//
+// Base images aren't resolved, so an image built against an older
+// version of a floating base image tag will hash the same.
+func BuildHash(ctx context.Context, db model.DockerBuild, filter model.PathMatcher) (string, error) {
+	h := sha256.New()
+	writeField := func(name, value string) {
+		_, _ = fmt.Fprintf(h, "%s %d %s\n", name, len(value), value)
+	}
+
+	writeField("dockerfile", db.Dockerfile)
+	writeField("target", string(db.TargetStage))
+	writeField("platform", db.Platform)
+
+	argNames := make([]string, 0, len(db.BuildArgs))
+	for k := range db.BuildArgs {
+		argNames = append(argNames, k)
+	}
+	sort.Strings(argNames)
+	for _, k := range argNames {
+		writeField("arg", fmt.Sprintf("%s=%s", k, db.BuildArgs[k]))
+	}
+	for _, spec := range db.SSHSpecs {
+		writeField("ssh", spec)
+	}
+	for _, spec := range db.SecretSpecs {
+		writeField("secret", spec)
+	}
+
+	ab := NewArchiveBuilder(ioutil.Discard, filter)
+	entries, err := ab.entriesForPath(ctx, db.BuildPath, "/")
+	if err != nil {
+		return "", errors.Wrap(err, "BuildHash")
+	}
+	entries = dedupeEntries(entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].header.Name < entries[j].header.Name
+	})
+
+	for _, entry := range entries {
+		header := entry.header
+		writeField("entry", fmt.Sprintf("%s %c %o %d %s", header.Name, header.Typeflag, header.Mode, header.Size, header.Linkname))
+		if !entry.info.Mode().IsRegular() {
+			continue
+		}
+
+		err := hashFile(h, entry.path, entry.info.Size())
+		if err != nil {
+			return "", errors.Wrap(err, "BuildHash")
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, path string, size int64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		// In case the file has been deleted since we last looked at it.
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "%s: open", path)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, err = io.CopyN(w, file, size)
+	if err != nil && err != io.EOF {
+		return errors.Wrapf(err, "%s: read", path)
+	}
+	return nil
+}
+
+// The tag we push alongside the digest tag, so that
+// images can be found by their BuildHash.
+//
+// The tag has the whole hash, so that finding the tag in
+// the registry is enough to know the image is a match.
+func BuildHashTag(hash string) (string, error) {
+	if len(hash) != sha256.Size*2 {
+		return "", fmt.Errorf("malformed build hash: %s", hash)
+	}
+	return fmt.Sprintf("%sbuild-%s", ImageTagPrefix, hash), nil
+}
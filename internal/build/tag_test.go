@@ -0,0 +1,104 @@
+package build
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestBuildHashIgnoresModTime(t *testing.T) {
+	f := newBuildHashFixture(t)
+
+	hash := f.hash(f.db, model.EmptyMatcher)
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(f.JoinPath("main.go"), future, future))
+	assert.Equal(t, hash, f.hash(f.db, model.EmptyMatcher))
+}
+
+func TestBuildHashChangesWithContents(t *testing.T) {
+	f := newBuildHashFixture(t)
+
+	hash := f.hash(f.db, model.EmptyMatcher)
+	f.WriteFile("main.go", "package main // changed")
+	assert.NotEqual(t, hash, f.hash(f.db, model.EmptyMatcher))
+}
+
+func TestBuildHashChangesWithNewFile(t *testing.T) {
+	f := newBuildHashFixture(t)
+
+	hash := f.hash(f.db, model.EmptyMatcher)
+	f.WriteFile("pkg/util.go", "package pkg")
+	assert.NotEqual(t, hash, f.hash(f.db, model.EmptyMatcher))
+}
+
+func TestBuildHashSkipsFilteredFiles(t *testing.T) {
+	f := newBuildHashFixture(t)
+	filter, err := model.NewSimpleFileMatcher(f.JoinPath("build.log"))
+	require.NoError(t, err)
+
+	hash := f.hash(f.db, filter)
+	f.WriteFile("build.log", "hello")
+	assert.Equal(t, hash, f.hash(f.db, filter))
+}
+
+func TestBuildHashChangesWithBuildArgs(t *testing.T) {
+	f := newBuildHashFixture(t)
+
+	hash := f.hash(f.db, model.EmptyMatcher)
+
+	db := f.db
+	db.BuildArgs = model.DockerBuildArgs{"VERSION": "2"}
+	assert.NotEqual(t, hash, f.hash(db, model.EmptyMatcher))
+
+	db = f.db
+	db.Dockerfile = "FROM alpine\nRUN echo hi"
+	assert.NotEqual(t, hash, f.hash(db, model.EmptyMatcher))
+
+	db = f.db
+	db.TargetStage = "test"
+	assert.NotEqual(t, hash, f.hash(db, model.EmptyMatcher))
+}
+
+func TestBuildHashTag(t *testing.T) {
+	hash := strings.Repeat("0123456789abcdef", 4)
+	tag, err := BuildHashTag(hash)
+	require.NoError(t, err)
+	assert.Equal(t, "tilt-build-"+hash, tag)
+
+	_, err = BuildHashTag("0123")
+	assert.Error(t, err)
+}
+
+type buildHashFixture struct {
+	*tempdir.TempDirFixture
+	db model.DockerBuild
+}
+
+func newBuildHashFixture(t *testing.T) *buildHashFixture {
+	f := tempdir.NewTempDirFixture(t)
+	t.Cleanup(f.TearDown)
+
+	f.WriteFile("main.go", "package main")
+	return &buildHashFixture{
+		TempDirFixture: f,
+		db: model.DockerBuild{
+			Dockerfile: "FROM alpine",
+			BuildPath:  f.Path(),
+			BuildArgs:  model.DockerBuildArgs{"VERSION": "1"},
+		},
+	}
+}
+
+func (f *buildHashFixture) hash(db model.DockerBuild, filter model.PathMatcher) string {
+	hash, err := BuildHash(context.Background(), db, filter)
+	require.NoError(f.T(), err)
+	return hash
+}
@@ -16,6 +16,7 @@ The APIs of these commands may change frequently.
 	addCommand(result, newUpdogCmd())
 	addCommand(result, newGetCmd())
 	addCommand(result, newApiresourcesCmd())
+	result.AddCommand(newDiffCmd())
 
 	return result
 }
@@ -74,7 +74,10 @@ up-to-date in real-time. Think 'docker build && kubectl apply' or 'docker-compos
 	rootCmd.AddCommand(analytics.NewCommand())
 	rootCmd.AddCommand(newDumpCmd(rootCmd))
 	rootCmd.AddCommand(newTriggerCmd())
+	rootCmd.AddCommand(newDisableCmd())
+	rootCmd.AddCommand(newEnableCmd())
 	rootCmd.AddCommand(newAlphaCmd())
+	rootCmd.AddCommand(newExtCmd())
 
 	globalFlags := rootCmd.PersistentFlags()
 	globalFlags.BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
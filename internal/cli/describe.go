@@ -24,17 +24,23 @@ package cli
 import (
 	"context"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/kubectl/pkg/cmd/describe"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	pkgdescribe "k8s.io/kubectl/pkg/describe"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -93,6 +99,57 @@ func (c *describeCmd) run(ctx context.Context, args []string) error {
 	f := cmdutil.NewFactory(getter)
 	cmd := c.cmd
 	cmdutil.CheckErr(o.Complete(f, cmd, args))
+
+	dynamicClient, err := f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	describer := o.Describer
+	o.Describer = func(mapping *meta.RESTMapping) (pkgdescribe.ResourceDescriber, error) {
+		d, err := describer(mapping)
+		if err != nil {
+			return nil, err
+		}
+		return secretEnvDescriber{ctx: ctx, delegate: d, client: dynamicClient, mapping: mapping}, nil
+	}
+
 	cmdutil.CheckErr(o.Run())
 	return nil
 }
+
+// Hides the values of environment variables that Tilt marked as secrets.
+type secretEnvDescriber struct {
+	ctx      context.Context
+	delegate pkgdescribe.ResourceDescriber
+	client   dynamic.Interface
+	mapping  *meta.RESTMapping
+}
+
+func (d secretEnvDescriber) Describe(namespace, name string, settings pkgdescribe.DescriberSettings) (string, error) {
+	out, err := d.delegate.Describe(namespace, name, settings)
+	if err != nil {
+		return out, err
+	}
+
+	obj, err := d.client.Resource(d.mapping.Resource).Namespace(namespace).Get(d.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return out, nil
+	}
+
+	secretEnv := obj.GetAnnotations()[v1alpha1.AnnotationSecretEnv]
+	if secretEnv == "" {
+		return out, nil
+	}
+
+	env, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "env")
+	secrets := model.SecretSet{}
+	for _, secretName := range strings.Split(secretEnv, ",") {
+		for _, e := range env {
+			parts := strings.SplitN(e, "=", 2)
+			if len(parts) == 2 && parts[0] == secretName {
+				secrets.AddSecret(name, secretName, []byte(parts[1]))
+			}
+		}
+	}
+	return string(secrets.Scrub([]byte(out))), nil
+}
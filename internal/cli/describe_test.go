@@ -33,3 +33,34 @@ func TestDescribe(t *testing.T) {
 
 	assert.Contains(t, out.String(), `Name:         my-sleep`)
 }
+
+func TestDescribeHidesSecretEnv(t *testing.T) {
+	f := newServerFixture(t)
+	defer f.TearDown()
+
+	err := f.client.Create(f.ctx, &v1alpha1.Cmd{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-server",
+			Annotations: map[string]string{
+				v1alpha1.AnnotationSecretEnv: "TOKEN",
+			},
+		},
+		Spec: v1alpha1.CmdSpec{
+			Args: []string{"./server"},
+			Env:  []string{"TOKEN=abc123-token", "PORT=8080"},
+		},
+	})
+	require.NoError(t, err)
+
+	out := bytes.NewBuffer(nil)
+	describe := newDescribeCmd()
+	describe.register()
+	describe.options.IOStreams.Out = out
+
+	err = describe.run(f.ctx, []string{"cmd", "my-server"})
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "TOKEN=[redacted secret my-server:TOKEN]")
+	assert.Contains(t, out.String(), "PORT=8080")
+	assert.NotContains(t, out.String(), "abc123-token")
+}
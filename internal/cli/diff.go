@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff RESOURCE_NAME",
+		Short: "Show what the next update of a Kubernetes resource would change",
+		Long: `Show what the next update of a Kubernetes resource would change in the cluster.
+
+Asks the running Tilt to render the resource's YAML with its most recently built images,
+then compares it to the live objects with a server-side dry run. Nothing in the cluster changes.
+`,
+		Example: "tilt alpha diff frontend",
+		Args:    cobra.ExactArgs(1),
+		Run:     diffResource,
+	}
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func diffResource(cmd *cobra.Command, args []string) {
+	resource := args[0]
+	body := apiGet(fmt.Sprintf("diff?resource=%s", url.QueryEscape(resource)))
+	defer func() {
+		_ = body.Close()
+	}()
+
+	diff, err := ioutil.ReadAll(body)
+	if err != nil {
+		cmdFail(fmt.Errorf("reading diff: %v", err))
+	}
+
+	if len(diff) == 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "No changes to resource: %q\n", resource)
+		return
+	}
+	_, _ = os.Stdout.Write(diff)
+}
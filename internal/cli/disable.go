@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newDisableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disable RESOURCE_NAME [RESOURCE_NAME...]",
+		Short: "Turn off the specified resources",
+		Long: `Turn off the specified resources.
+
+Tilt stops building a disabled resource and watching its files. Anything it deployed
+is torn down: Kubernetes objects are deleted, Docker Compose containers are removed,
+and local servers are stopped.
+
+The resources stay disabled when the Tiltfile reloads. Use 'tilt enable' to turn them back on.
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			setDisabled(args, true)
+		},
+	}
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func newEnableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enable RESOURCE_NAME [RESOURCE_NAME...]",
+		Short: "Turn the specified resources back on",
+		Long: `Turn the specified resources back on, after they were turned off with 'tilt disable'.
+
+Tilt starts over with each resource as if the Tiltfile had just loaded it.
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			setDisabled(args, false)
+		},
+	}
+	addConnectServerFlags(cmd)
+	return cmd
+}
+
+func setDisabled(resources []string, disabled bool) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"manifest_names": resources,
+		"disabled":       disabled,
+	})
+	if err != nil {
+		cmdFail(err)
+	}
+
+	body := apiPostJson("override/disable", payload)
+	_ = body.Close()
+
+	verb := "enabled"
+	if disabled {
+		verb = "disabled"
+	}
+	fmt.Printf("Successfully %s: %s\n", verb, strings.Join(resources, ", "))
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -123,6 +125,14 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 
 	registryDisplay, err := clusterLocalRegistryDisplay(ctx)
 	printField("Cluster Local Registry", registryDisplay, err)
+	if err == nil && registryDisplay == "none" {
+		instructions := k8s.LocalRegistrySetupInstructions(env, clusterName)
+		if instructions != "" {
+			fmt.Println("")
+			fmt.Println(instructions)
+			fmt.Println("")
+		}
+	}
 
 	fmt.Println("---")
 	fmt.Println("Thanks for seeing the Tilt Doctor!")
@@ -158,7 +168,24 @@ func clusterLocalRegistryDisplay(ctx context.Context) (string, error) {
 	if registry.Empty() {
 		return "none", nil
 	}
-	return fmt.Sprintf("%+v", registry), nil
+
+	var sb strings.Builder
+	sb.WriteString(registry.Host)
+	hosting := kClient.LocalRegistryHosting(newCtx)
+	if hosting.Host == registry.Host {
+		if hosting.HostFromContainerRuntime != "" {
+			sb.WriteString(fmt.Sprintf("\n  - Host from container runtime: %s", hosting.HostFromContainerRuntime))
+		}
+		if hosting.HostFromClusterNetwork != "" {
+			sb.WriteString(fmt.Sprintf("\n  - Host from cluster network: %s", hosting.HostFromClusterNetwork))
+		}
+		if hosting.Help != "" {
+			sb.WriteString(fmt.Sprintf("\n  - Help: %s", hosting.Help))
+		}
+	} else if registry.HostFromCluster() != registry.Host {
+		sb.WriteString(fmt.Sprintf("\n  - Host from cluster: %s", registry.HostFromCluster()))
+	}
+	return sb.String(), nil
 }
 
 func printField(name string, v interface{}, err error) {
@@ -8,9 +8,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/engine"
+	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -42,6 +44,8 @@ Namespaces are not deleted by default. Use --delete-namespaces to change that.
 
 Kubernetes resources with the annotation 'tilt.dev/down-policy: keep' are not deleted.
 
+Resources created with k8s_custom_deploy are deleted by running their delete_cmd.
+
 For more complex cases, the Tiltfile has APIs to add additional flags and arguments to the Tilt CLI.
 These arguments can be scripted to define custom subsets of resources to delete.
 See https://docs.tilt.dev/tiltfile_config.html for examples.
@@ -107,10 +111,25 @@ func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) er
 		}
 	}
 
+	// Keep tearing down after a failure, so that one broken resource
+	// doesn't leave the rest running.
+	var errs []error
 	if len(entities) > 0 {
 		err = downDeps.kClient.Delete(ctx, entities)
 		if err != nil {
-			return errors.Wrap(err, "Deleting k8s entities")
+			errs = append(errs, errors.Wrap(err, "Deleting k8s entities"))
+		}
+	}
+
+	// Resources deployed with a custom command don't have YAML to delete,
+	// so they're deleted with their delete command.
+	for _, m := range tlr.Manifests {
+		if !m.IsK8s() || !m.K8sTarget().HasCustomDeploy() {
+			continue
+		}
+		err = buildcontrol.DeleteK8sTarget(ctx, downDeps.kClient, m.K8sTarget())
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "Deleting %s", m.Name))
 		}
 	}
 
@@ -126,9 +145,9 @@ func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) er
 		dcc := downDeps.dcClient
 		err = dcc.Down(ctx, dcConfigPaths, logger.Get(ctx).Writer(logger.InfoLvl), logger.Get(ctx).Writer(logger.InfoLvl))
 		if err != nil {
-			return errors.Wrap(err, "Running `docker-compose down`")
+			errs = append(errs, errors.Wrap(err, "Running `docker-compose down`"))
 		}
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/tiltfile"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -111,6 +112,36 @@ func TestDownDCFails(t *testing.T) {
 	}
 }
 
+func TestDownContinuesAfterK8sFails(t *testing.T) {
+	f := newDownFixture(t)
+	defer f.TearDown()
+
+	manifests := append(newK8sManifest(), newDCManifest()...)
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: manifests}
+	f.kCli.DeleteError = fmt.Errorf("K8S-GARBLE")
+	f.dcc.DownError = fmt.Errorf("DC-GARBLE")
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "K8S-GARBLE")
+		assert.Contains(t, err.Error(), "DC-GARBLE")
+	}
+}
+
+func TestDownCustomDeployDeleteCmdFails(t *testing.T) {
+	f := newDownFixture(t)
+	defer f.TearDown()
+
+	manifests := append(newCustomDeployManifest("exit 1"), newDCManifest()...)
+	f.tfl.Result = tiltfile.TiltfileLoadResult{Manifests: manifests}
+	f.dcc.DownError = fmt.Errorf("DC-GARBLE")
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Deleting custom")
+		assert.Contains(t, err.Error(), "DC-GARBLE")
+	}
+	assert.Equal(t, "", f.kCli.DeletedYaml)
+}
+
 func TestDownArgs(t *testing.T) {
 	f := newDownFixture(t)
 	defer f.TearDown()
@@ -132,6 +163,17 @@ func newK8sManifest() []model.Manifest {
 	return []model.Manifest{model.Manifest{Name: "fe"}.WithDeployTarget(k8s.MustTarget("fe", testyaml.SanchoYAML))}
 }
 
+func newCustomDeployManifest(deleteCmd string) []model.Manifest {
+	kTarget := model.K8sTarget{
+		Name: "custom",
+		KubernetesApplySpec: v1alpha1.KubernetesApplySpec{
+			ApplyCmd:  &v1alpha1.KubernetesApplyCmd{Args: model.ToHostCmd("true").Argv},
+			DeleteCmd: &v1alpha1.KubernetesApplyCmd{Args: model.ToHostCmd(deleteCmd).Argv},
+		},
+	}
+	return []model.Manifest{model.Manifest{Name: "custom"}.WithDeployTarget(kTarget)}
+}
+
 func newDCManifest() []model.Manifest {
 	return []model.Manifest{model.Manifest{Name: "fe"}.WithDeployTarget(model.DockerComposeTarget{
 		Name:        "fe",
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/tiltfile/tiltextension"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func newExtCmd() *cobra.Command {
+	result := &cobra.Command{
+		Use:   "ext",
+		Short: "Manage the Tiltfile extensions loaded with load('ext://...')",
+	}
+
+	addCommand(result, &extUpdateCmd{})
+
+	return result
+}
+
+type extUpdateCmd struct {
+	fileName string
+}
+
+func (c *extUpdateCmd) name() model.TiltSubcommand { return "ext-update" }
+
+func (c *extUpdateCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update [EXTENSION_NAME...]",
+		Short: "Update pinned extensions to the latest commit of their ref",
+		Long: `Update pinned extensions to the latest commit of their ref.
+
+The first time a Tiltfile loads an extension, Tilt pins the commit it loaded
+in tilt_extensions.lock, next to the Tiltfile. Later loads use the pinned commit,
+even offline. Check the lockfile in so that everyone loads the same extensions.
+
+With no arguments, updates every extension in the lockfile.
+`,
+		Example: "tilt ext update\ntilt ext update restart_process",
+	}
+
+	addTiltfileFlag(cmd, &c.fileName)
+
+	return cmd
+}
+
+func (c *extUpdateCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.ext-update", nil)
+	defer a.Flush(time.Second)
+
+	absFilename, err := filepath.Abs(c.fileName)
+	if err != nil {
+		return err
+	}
+	tiltfileDir := filepath.Dir(absFilename)
+
+	repoCache, err := tiltextension.NewTiltDevRepoCache()
+	if err != nil {
+		return err
+	}
+
+	ext := tiltextension.NewExtension(
+		tiltextension.NewGitFetcherFactory(repoCache),
+		tiltextension.NewLocalStore(tiltfileDir),
+		tiltextension.LockFilePath(tiltfileDir))
+	return ext.Update(ctx, args)
+}
@@ -2,19 +2,36 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"regexp"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/tilt-dev/tilt/internal/hud"
 	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 )
 
 type logsCmd struct {
-	follow bool // if true, follow logs (otherwise print current logs and exit)
+	follow  bool // if true, follow logs (otherwise print current logs and exit)
+	history bool // if true, print archived logs from before the in-memory window
+
+	selector string
+	spans    []string
+	level    string
+	since    string
+	until    string
+	pattern  string
+	tail     int
+	output   string
 }
 
 func (c *logsCmd) name() model.TiltSubcommand { return "logs" }
@@ -28,12 +45,26 @@ func (c *logsCmd) register() *cobra.Command {
 
 By default, looks for a running Tilt instance on localhost:10350
 (this is configurable with the --port and --host flags).
+
+With --output=json, prints one JSON object per log line, with the resource
+name, span ID, level, timestamp and build number.
 `,
+		Example: `  tilt logs frontend --since=5m
+  tilt logs -l team=backend --level=warn
+  tilt logs --tail=100 -f -o json | jq .text`,
 	}
 
 	cmd.Flags().BoolVarP(&c.follow, "follow", "f", false, "If true, stream the requested logs; otherwise, print the requested logs at the current moment in time, then exit.")
+	cmd.Flags().BoolVar(&c.history, "history", false, "If true, also print older logs that Tilt has saved to disk (including logs from previous sessions). Requires 'tilt up --persist-logs'.")
+	cmd.Flags().StringVarP(&c.selector, "selector", "l", "", "Only print logs for resources with matching labels (e.g., -l key1=value1,key2=value2)")
+	cmd.Flags().StringSliceVar(&c.spans, "span", nil, "Only print logs from these span IDs (e.g., build:fe:1)")
+	cmd.Flags().StringVar(&c.level, "level", "", "Only print logs at least this severe. One of: debug, verbose, info, warn, error")
+	cmd.Flags().StringVar(&c.since, "since", "", "Only print logs at or after this time. Either a duration (e.g., 5m, 1h) or an RFC3339 timestamp")
+	cmd.Flags().StringVar(&c.until, "until", "", "Only print logs before this time (RFC3339)")
+	cmd.Flags().StringVar(&c.pattern, "grep", "", "Only print logs matching this regular expression")
+	cmd.Flags().IntVar(&c.tail, "tail", -1, "Number of lines of existing logs to print. If -1, print all of them")
+	cmd.Flags().StringVarP(&c.output, "output", "o", "", "Output format. Empty for plain text, or 'json' for one JSON object per line")
 
-	// TODO: log level flags
 	addConnectServerFlags(cmd)
 	return cmd
 }
@@ -48,10 +79,94 @@ func (c *logsCmd) run(ctx context.Context, args []string) error {
 		log.Printf("Tilt analytics disabled: %s", reason)
 	}
 
+	if c.output != "" && c.output != "json" {
+		return fmt.Errorf("invalid --output %q. Must be 'json' or empty", c.output)
+	}
+
+	selector, err := labels.Parse(c.selector)
+	if err != nil {
+		return errors.Wrap(err, "invalid --selector")
+	}
+
+	filter, err := c.filter(time.Now())
+	if err != nil {
+		return err
+	}
+
 	logDeps, err := wireLogsDeps(ctx, a, "logs")
 	if err != nil {
 		return err
 	}
 
-	return server.StreamLogs(ctx, c.follow, logDeps.url, args, logDeps.printer)
+	var printer server.LogPrinter = logDeps.printer
+	suppressPrefix := false
+	if c.output == "json" {
+		// The JSON printer reports the resource name in its own field.
+		printer = hud.NewJSONPrinter(hud.ProvideStdout())
+		suppressPrefix = true
+	}
+
+	return server.StreamLogs(ctx, logDeps.url, server.StreamLogsOptions{
+		Follow:         c.follow,
+		History:        c.history,
+		Resources:      args,
+		Selector:       selector,
+		Filter:         filter,
+		SuppressPrefix: suppressPrefix,
+		Tail:           c.tail,
+	}, printer)
+}
+
+func (c *logsCmd) filter(now time.Time) (logstore.Filter, error) {
+	filter := logstore.Filter{}
+	if len(c.spans) > 0 {
+		filter.SpanIDs = make(map[logstore.SpanID]bool, len(c.spans))
+		for _, spanID := range c.spans {
+			filter.SpanIDs[logstore.SpanID(spanID)] = true
+		}
+	}
+
+	if c.level != "" {
+		level, err := logger.ParseLevel(c.level)
+		if err != nil {
+			return logstore.Filter{}, err
+		}
+		filter.MinLevel = level
+	}
+
+	var err error
+	if c.since != "" {
+		filter.Since, err = parseSince(c.since, now)
+		if err != nil {
+			return logstore.Filter{}, err
+		}
+	}
+	if c.until != "" {
+		filter.Until, err = time.Parse(time.RFC3339, c.until)
+		if err != nil {
+			return logstore.Filter{}, fmt.Errorf("invalid --until time %q. Must be RFC3339", c.until)
+		}
+	}
+
+	if c.pattern != "" {
+		filter.Pattern, err = regexp.Compile(c.pattern)
+		if err != nil {
+			return logstore.Filter{}, errors.Wrap(err, "invalid --grep pattern")
+		}
+	}
+	return filter, nil
+}
+
+// Parses a --since value, which is either a duration relative to now (like
+// `kubectl logs --since`) or an absolute RFC3339 timestamp.
+func parseSince(since string, now time.Time) (time.Time, error) {
+	d, err := time.ParseDuration(since)
+	if err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since time %q. Must be a duration (e.g., 5m) or RFC3339", since)
+	}
+	return t, nil
 }
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+func TestLogsFilter(t *testing.T) {
+	c := &logsCmd{
+		spans:   []string{"build:fe:1"},
+		level:   "warn",
+		since:   "2021-01-02T15:04:05Z",
+		pattern: "err(or)?",
+	}
+	filter, err := c.filter(time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[logstore.SpanID]bool{"build:fe:1": true}, filter.SpanIDs)
+	assert.Equal(t, logger.WarnLvl, filter.MinLevel)
+	assert.Equal(t, time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC), filter.Since)
+	assert.True(t, filter.Until.IsZero())
+	assert.True(t, filter.Pattern.MatchString("an error"))
+}
+
+func TestLogsFilterInvalid(t *testing.T) {
+	for _, c := range []*logsCmd{
+		{level: "loud"},
+		{since: "yesterday"},
+		{pattern: "("},
+	} {
+		_, err := c.filter(time.Now())
+		assert.Error(t, err)
+	}
+}
+
+func TestLogsFilterSinceDuration(t *testing.T) {
+	now := time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC)
+	c := &logsCmd{since: "5m"}
+	filter, err := c.filter(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 1, 2, 14, 59, 5, 0, time.UTC), filter.Since)
+}
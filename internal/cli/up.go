@@ -2,15 +2,19 @@ package cli
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
 	"k8s.io/klog/v2"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
@@ -21,6 +25,7 @@ import (
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	"github.com/tilt-dev/tilt/web"
 )
 
@@ -35,6 +40,7 @@ var logActionsFlag bool = false
 type upCmd struct {
 	fileName             string
 	outputSnapshotOnExit string
+	persistLogs          bool
 
 	hud    bool
 	legacy bool
@@ -64,6 +70,8 @@ By default:
 This default behavior does not apply if the Tiltfile uses config.parse or config.set_enabled_resources.
 In that case, see https://tilt.dev/user_config.html and/or comments in your Tiltfile
 
+If the Tiltfile uses config.parse, run tilt up -- --help to see the Tiltfile args it accepts.
+
 When you exit Tilt (using Ctrl+C), Kubernetes resources and Docker Compose resources continue running;
 you can use tilt down (https://docs.tilt.dev/cli/tilt_down.html) to delete these resources. Any long-running
 local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
@@ -82,6 +90,7 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 	addKubeContextFlag(cmd)
 	cmd.Flags().Lookup("logactions").Hidden = true
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "", "If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
+	cmd.Flags().BoolVar(&c.persistLogs, "persist-logs", false, "If true, Tilt will save logs to disk, so that you can page back through logs from earlier in this session or from previous sessions")
 
 	cmd.PreRun = func(cmd *cobra.Command, args []string) {
 		c.hudFlagExplicitlySet = cmd.Flag("hud").Changed
@@ -164,8 +173,19 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 
 	engineMode := store.EngineModeUp
 
+	var logArchive *logstore.Archive
+	if c.persistLogs {
+		logArchive, err = openLogArchive(c.fileName)
+		if err != nil {
+			logger.Get(ctx).Infof("Unable to persist logs: %v", err)
+		} else {
+			defer func() { _ = logArchive.Close() }()
+		}
+	}
+
 	err = upper.Start(ctx, args, cmdUpDeps.TiltBuild, engineMode,
-		c.fileName, termMode, a.UserOpt(), cmdUpDeps.Token, string(cmdUpDeps.CloudAddress))
+		c.fileName, termMode, a.UserOpt(), cmdUpDeps.Token, string(cmdUpDeps.CloudAddress),
+		logArchive)
 	if err != context.Canceled {
 		return err
 	} else {
@@ -173,6 +193,27 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 	}
 }
 
+// Each Tiltfile gets its own log archive under ~/.tilt-dev/logs, so that
+// logs from previous sessions of the same project are viewable on restart.
+func openLogArchive(tiltfilePath string) (*logstore.Archive, error) {
+	absPath, err := filepath.Abs(tiltfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := dirs.UseTiltDevDir()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256([]byte(absPath))
+	archiveDir, err := dir.Abs(filepath.Join("logs", hex.EncodeToString(hash[:])[:16]))
+	if err != nil {
+		return nil, err
+	}
+	return logstore.OpenArchive(archiveDir)
+}
+
 func redirectLogs(ctx context.Context, l logger.Logger) context.Context {
 	ctx = logger.WithLogger(ctx, l)
 	log.SetOutput(l.Writer(logger.InfoLvl))
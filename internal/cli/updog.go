@@ -124,7 +124,7 @@ func (c *updogCmd) run(ctx context.Context, args []string) error {
 	// controllers registered.
 	err = deps.Upper.Start(ctx, args, deps.TiltBuild, store.EngineModeCI,
 		"Tiltfile", store.TerminalModeStream, a.UserOpt(), deps.Token,
-		string(deps.CloudAddress))
+		string(deps.CloudAddress), nil)
 	if err != context.Canceled {
 		return err
 	} else {
@@ -168,6 +168,11 @@ func (s *updogSubscriber) OnChange(_ context.Context, _ store.RStore, _ store.Ch
 	return nil
 }
 
+// Updog doesn't deploy anything, so there's nothing to diff.
+func provideUpdogDiffer() server.ResourceDiffer {
+	return nil
+}
+
 func provideUpdogCmdSubscribers(
 	hudsc *server.HeadsUpServerController,
 	tscm *controllers.TiltServerControllerManager,
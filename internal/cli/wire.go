@@ -34,14 +34,16 @@ import (
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
-	"github.com/tilt-dev/tilt/internal/engine/dcwatch"
+	"github.com/tilt-dev/tilt/internal/engine/disable"
 	"github.com/tilt-dev/tilt/internal/engine/dockerprune"
+	"github.com/tilt-dev/tilt/internal/engine/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/engine/fswatch"
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
 	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	"github.com/tilt-dev/tilt/internal/engine/portforward"
+	"github.com/tilt-dev/tilt/internal/engine/readiness"
 	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
@@ -98,16 +100,17 @@ var BaseWireSet = wire.NewSet(
 	portforward.NewSubscriber,
 	engine.NewBuildController,
 	local.NewServerController,
+	disable.NewController,
+	readiness.NewController,
 	kubernetesdiscovery.NewContainerRestartDetector,
 	k8swatch.NewManifestSubscriber,
 	k8swatch.NewServiceWatcher,
 	k8swatch.NewEventWatchManager,
 	uisession.NewSubscriber,
 	uiresource.NewSubscriber,
+	extensionrepo.NewSubscriber,
 	configs.NewConfigsController,
 	telemetry.NewController,
-	dcwatch.NewEventWatcher,
-	runtimelog.NewDockerComposeLogManager,
 	cloud.WireSet,
 	cloudurl.ProvideAddress,
 	k8srollout.NewPodMonitor,
@@ -165,6 +168,7 @@ var CLIClientWireSet = wire.NewSet(
 var UpWireSet = wire.NewSet(
 	BaseWireSet,
 	engine.ProvideSubscribers,
+	wire.Bind(new(server.ResourceDiffer), new(*buildcontrol.ImageBuildAndDeployer)),
 )
 
 func wireTiltfileResult(ctx context.Context, analytics *analytics.TiltAnalytics, subcommand model.TiltSubcommand) (cmdTiltfileResultDeps, error) {
@@ -217,6 +221,7 @@ func wireCmdUpdog(ctx context.Context,
 	objects []ctrlclient.Object) (CmdUpdogDeps, error) {
 	wire.Build(BaseWireSet,
 		provideUpdogSubscriber,
+		provideUpdogDiffer,
 		provideUpdogCmdSubscribers,
 		wire.Struct(new(CmdUpdogDeps), "*"))
 	return CmdUpdogDeps{}, nil
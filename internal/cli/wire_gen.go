@@ -26,6 +26,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/containerupdate"
 	"github.com/tilt-dev/tilt/internal/controllers"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch/fsevent"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesdiscovery"
@@ -41,14 +42,16 @@ import (
 	analytics2 "github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
-	"github.com/tilt-dev/tilt/internal/engine/dcwatch"
+	"github.com/tilt-dev/tilt/internal/engine/disable"
 	"github.com/tilt-dev/tilt/internal/engine/dockerprune"
+	"github.com/tilt-dev/tilt/internal/engine/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/engine/fswatch"
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
 	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	portforward2 "github.com/tilt-dev/tilt/internal/engine/portforward"
+	"github.com/tilt-dev/tilt/internal/engine/readiness"
 	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
@@ -197,7 +200,47 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	snapshotUploader := cloud.NewSnapshotUploader(httpClient, address)
 	websocketList := server.NewWebsocketList()
 	deferredClient := controllers.ProvideDeferredClient()
-	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader, websocketList, deferredClient)
+	k8sKubeContextOverride := ProvideKubeContextOverride()
+	clientConfig := k8s.ProvideClientConfig(k8sKubeContextOverride)
+	apiConfig, err := k8s.ProvideKubeConfig(clientConfig, k8sKubeContextOverride)
+	if err != nil {
+		return CmdUpDeps{}, err
+	}
+	env := k8s.ProvideEnv(ctx, apiConfig)
+	restConfigOrError := k8s.ProvideRESTConfig(clientConfig)
+	clientsetOrError := k8s.ProvideClientset(restConfigOrError)
+	portForwardClient := k8s.ProvidePortForwardClient(restConfigOrError, clientsetOrError)
+	namespace := k8s.ProvideConfigNamespace(clientConfig)
+	kubeContext, err := k8s.ProvideKubeContext(apiConfig)
+	if err != nil {
+		return CmdUpDeps{}, err
+	}
+	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, minikubeClient, clientConfig)
+	runtime := k8s.ProvideContainerRuntime(ctx, client)
+	clusterEnv := docker.ProvideClusterEnv(ctx, kubeContext, env, runtime, minikubeClient)
+	localEnv := docker.ProvideLocalEnv(ctx, kubeContext, env, clusterEnv)
+	localClient := docker.ProvideLocalCli(ctx, localEnv)
+	clusterClient, err := docker.ProvideClusterCli(ctx, localEnv, clusterEnv, localClient)
+	if err != nil {
+		return CmdUpDeps{}, err
+	}
+	switchCli := docker.ProvideSwitchCli(clusterClient, localClient)
+	buildcontrolUpdateModeFlag := provideUpdateModeFlag()
+	updateMode, err := buildcontrol.ProvideUpdateMode(buildcontrolUpdateModeFlag, kubeContext, clusterEnv)
+	if err != nil {
+		return CmdUpDeps{}, err
+	}
+	buildClock := build.ProvideClock()
+	labels := _wireLabelsValue
+	dockerImageBuilder := build.NewDockerImageBuilder(switchCli, labels)
+	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
+	execCustomBuilder := build.NewExecCustomBuilder(switchCli, buildClock)
+	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
+	kindLoader := buildcontrol.NewKINDLoader(env, clusterName)
+	dockerRemoteBuildCache := build.NewDockerRemoteBuildCache(switchCli)
+	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, client, env, kubeContext, analytics3, updateMode, buildClock, kindLoader, dockerRemoteBuildCache)
+	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader, websocketList, deferredClient, imageBuildAndDeployer)
 	if err != nil {
 		return CmdUpDeps{}, err
 	}
@@ -219,23 +262,6 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	proberManager := cmd.ProvideProberManager()
 	clock := clockwork.NewRealClock()
 	cmdController := cmd.NewController(ctx, execer, proberManager, deferredClient, storeStore, clock)
-	k8sKubeContextOverride := ProvideKubeContextOverride()
-	clientConfig := k8s.ProvideClientConfig(k8sKubeContextOverride)
-	apiConfig, err := k8s.ProvideKubeConfig(clientConfig, k8sKubeContextOverride)
-	if err != nil {
-		return CmdUpDeps{}, err
-	}
-	env := k8s.ProvideEnv(ctx, apiConfig)
-	restConfigOrError := k8s.ProvideRESTConfig(clientConfig)
-	clientsetOrError := k8s.ProvideClientset(restConfigOrError)
-	portForwardClient := k8s.ProvidePortForwardClient(restConfigOrError, clientsetOrError)
-	namespace := k8s.ProvideConfigNamespace(clientConfig)
-	kubeContext, err := k8s.ProvideKubeContext(apiConfig)
-	if err != nil {
-		return CmdUpDeps{}, err
-	}
-	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, minikubeClient, clientConfig)
 	podlogstreamController := podlogstream.NewController(ctx, deferredClient, storeStore, client)
 	ownerFetcher := k8s.ProvideOwnerFetcher(ctx, client)
 	containerRestartDetector := kubernetesdiscovery.NewContainerRestartDetector()
@@ -244,7 +270,9 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	uiresourceReconciler := uiresource.NewReconciler(websocketList)
 	uibuttonReconciler := uibutton.NewReconciler(websocketList)
 	portforwardReconciler := portforward.NewReconciler(storeStore, client)
-	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler)
+	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
+	dockercomposeserviceReconciler := dockercomposeservice.NewReconciler(ctx, dockerComposeClient, localClient, storeStore)
+	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, dockercomposeserviceReconciler)
 	controllerBuilder := controllers.NewControllerBuilder(tiltServerControllerManager, v)
 	v2 := provideClock()
 	renderer := hud.NewRenderer(v2)
@@ -260,34 +288,12 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	podLogManager := runtimelog.NewPodLogManager(deferredClient)
 	subscriber := portforward2.NewSubscriber(client, deferredClient)
 	fswatchManifestSubscriber := fswatch.NewManifestSubscriber(deferredClient)
-	runtime := k8s.ProvideContainerRuntime(ctx, client)
-	clusterEnv := docker.ProvideClusterEnv(ctx, kubeContext, env, runtime, minikubeClient)
-	localEnv := docker.ProvideLocalEnv(ctx, kubeContext, env, clusterEnv)
-	localClient := docker.ProvideLocalCli(ctx, localEnv)
-	clusterClient, err := docker.ProvideClusterCli(ctx, localEnv, clusterEnv, localClient)
-	if err != nil {
-		return CmdUpDeps{}, err
-	}
-	switchCli := docker.ProvideSwitchCli(clusterClient, localClient)
 	dockerUpdater := containerupdate.NewDockerUpdater(switchCli)
 	execUpdater := containerupdate.NewExecUpdater(client)
-	buildcontrolUpdateModeFlag := provideUpdateModeFlag()
-	updateMode, err := buildcontrol.ProvideUpdateMode(buildcontrolUpdateModeFlag, kubeContext, clusterEnv)
-	if err != nil {
-		return CmdUpDeps{}, err
-	}
-	buildClock := build.ProvideClock()
-	liveUpdateBuildAndDeployer := buildcontrol.NewLiveUpdateBuildAndDeployer(dockerUpdater, execUpdater, updateMode, kubeContext, buildClock)
-	labels := _wireLabelsValue
-	dockerImageBuilder := build.NewDockerImageBuilder(switchCli, labels)
-	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
-	execCustomBuilder := build.NewExecCustomBuilder(switchCli, buildClock)
-	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
-	kindLoader := buildcontrol.NewKINDLoader(env, clusterName)
-	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, client, env, kubeContext, analytics3, updateMode, buildClock, kindLoader)
-	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
+	dockerComposeUpdater := containerupdate.NewDockerComposeUpdater(dockerComposeClient)
+	liveUpdateBuildAndDeployer := buildcontrol.NewLiveUpdateBuildAndDeployer(dockerUpdater, execUpdater, dockerComposeUpdater, updateMode, kubeContext, buildClock)
 	imageBuilder := buildcontrol.NewImageBuilder(dockerBuilder, execCustomBuilder, updateMode)
-	dockerComposeBuildAndDeployer := buildcontrol.NewDockerComposeBuildAndDeployer(dockerComposeClient, switchCli, imageBuilder, buildClock)
+	dockerComposeBuildAndDeployer := buildcontrol.NewDockerComposeBuildAndDeployer(dockercomposeserviceReconciler, switchCli, imageBuilder, buildClock)
 	localTargetBuildAndDeployer := buildcontrol.NewLocalTargetBuildAndDeployer(buildClock)
 	buildOrder := engine.DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, updateMode, env, runtime)
 	spanCollector := tracer.NewSpanCollector(ctx)
@@ -303,8 +309,6 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	defaults := _wireDefaultsValue
 	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, client, extension, versionExtension, configExtension, dockerComposeClient, webHost, defaults, env)
 	configsController := configs.NewConfigsController(tiltfileLoader, switchCli)
-	eventWatcher := dcwatch.NewEventWatcher(dockerComposeClient, localClient)
-	dockerComposeLogManager := runtimelog.NewDockerComposeLogManager(dockerComposeClient)
 	analyticsReporter := analytics2.ProvideAnalyticsReporter(analytics3, storeStore, client, env)
 	analyticsUpdater := analytics2.NewAnalyticsUpdater(analytics3, cmdTags)
 	eventWatchManager := k8swatch.NewEventWatchManager(client, ownerFetcher, namespace)
@@ -319,7 +323,10 @@ func wireCmdUp(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdTags
 	metricsController := metrics.NewController(deferredExporter, tiltBuild, gitRemote)
 	uisessionSubscriber := uisession2.NewSubscriber(deferredClient)
 	uiresourceSubscriber := uiresource2.NewSubscriber(deferredClient)
-	v3 := engine.ProvideSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, headsUpDisplay, terminalStream, terminalPrompt, manifestSubscriber, serviceWatcher, podLogManager, subscriber, fswatchManifestSubscriber, buildController, configsController, eventWatcher, dockerComposeLogManager, analyticsReporter, analyticsUpdater, eventWatchManager, cloudStatusManager, dockerPruner, telemetryController, serverController, podMonitor, sessionController, metricsController, uisessionSubscriber, uiresourceSubscriber)
+	extensionrepoSubscriber := extensionrepo.NewSubscriber(deferredClient)
+	disableController := disable.NewController(client, dockercomposeserviceReconciler)
+	readinessController := readiness.NewController(proberManager)
+	v3 := engine.ProvideSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, headsUpDisplay, terminalStream, terminalPrompt, manifestSubscriber, serviceWatcher, podLogManager, subscriber, fswatchManifestSubscriber, buildController, configsController, analyticsReporter, analyticsUpdater, eventWatchManager, cloudStatusManager, dockerPruner, telemetryController, serverController, podMonitor, sessionController, metricsController, uisessionSubscriber, uiresourceSubscriber, disableController, readinessController, extensionrepoSubscriber)
 	upper, err := engine.NewUpper(ctx, storeStore, v3)
 	if err != nil {
 		return CmdUpDeps{}, err
@@ -392,7 +399,47 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	snapshotUploader := cloud.NewSnapshotUploader(httpClient, address)
 	websocketList := server.NewWebsocketList()
 	deferredClient := controllers.ProvideDeferredClient()
-	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader, websocketList, deferredClient)
+	k8sKubeContextOverride := ProvideKubeContextOverride()
+	clientConfig := k8s.ProvideClientConfig(k8sKubeContextOverride)
+	apiConfig, err := k8s.ProvideKubeConfig(clientConfig, k8sKubeContextOverride)
+	if err != nil {
+		return CmdCIDeps{}, err
+	}
+	env := k8s.ProvideEnv(ctx, apiConfig)
+	restConfigOrError := k8s.ProvideRESTConfig(clientConfig)
+	clientsetOrError := k8s.ProvideClientset(restConfigOrError)
+	portForwardClient := k8s.ProvidePortForwardClient(restConfigOrError, clientsetOrError)
+	namespace := k8s.ProvideConfigNamespace(clientConfig)
+	kubeContext, err := k8s.ProvideKubeContext(apiConfig)
+	if err != nil {
+		return CmdCIDeps{}, err
+	}
+	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, minikubeClient, clientConfig)
+	runtime := k8s.ProvideContainerRuntime(ctx, client)
+	clusterEnv := docker.ProvideClusterEnv(ctx, kubeContext, env, runtime, minikubeClient)
+	localEnv := docker.ProvideLocalEnv(ctx, kubeContext, env, clusterEnv)
+	localClient := docker.ProvideLocalCli(ctx, localEnv)
+	clusterClient, err := docker.ProvideClusterCli(ctx, localEnv, clusterEnv, localClient)
+	if err != nil {
+		return CmdCIDeps{}, err
+	}
+	switchCli := docker.ProvideSwitchCli(clusterClient, localClient)
+	buildcontrolUpdateModeFlag := provideUpdateModeFlag()
+	updateMode, err := buildcontrol.ProvideUpdateMode(buildcontrolUpdateModeFlag, kubeContext, clusterEnv)
+	if err != nil {
+		return CmdCIDeps{}, err
+	}
+	buildClock := build.ProvideClock()
+	labels := _wireLabelsValue
+	dockerImageBuilder := build.NewDockerImageBuilder(switchCli, labels)
+	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
+	execCustomBuilder := build.NewExecCustomBuilder(switchCli, buildClock)
+	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
+	kindLoader := buildcontrol.NewKINDLoader(env, clusterName)
+	dockerRemoteBuildCache := build.NewDockerRemoteBuildCache(switchCli)
+	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, client, env, kubeContext, analytics3, updateMode, buildClock, kindLoader, dockerRemoteBuildCache)
+	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader, websocketList, deferredClient, imageBuildAndDeployer)
 	if err != nil {
 		return CmdCIDeps{}, err
 	}
@@ -414,23 +461,6 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	proberManager := cmd.ProvideProberManager()
 	clock := clockwork.NewRealClock()
 	cmdController := cmd.NewController(ctx, execer, proberManager, deferredClient, storeStore, clock)
-	k8sKubeContextOverride := ProvideKubeContextOverride()
-	clientConfig := k8s.ProvideClientConfig(k8sKubeContextOverride)
-	apiConfig, err := k8s.ProvideKubeConfig(clientConfig, k8sKubeContextOverride)
-	if err != nil {
-		return CmdCIDeps{}, err
-	}
-	env := k8s.ProvideEnv(ctx, apiConfig)
-	restConfigOrError := k8s.ProvideRESTConfig(clientConfig)
-	clientsetOrError := k8s.ProvideClientset(restConfigOrError)
-	portForwardClient := k8s.ProvidePortForwardClient(restConfigOrError, clientsetOrError)
-	namespace := k8s.ProvideConfigNamespace(clientConfig)
-	kubeContext, err := k8s.ProvideKubeContext(apiConfig)
-	if err != nil {
-		return CmdCIDeps{}, err
-	}
-	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, minikubeClient, clientConfig)
 	podlogstreamController := podlogstream.NewController(ctx, deferredClient, storeStore, client)
 	ownerFetcher := k8s.ProvideOwnerFetcher(ctx, client)
 	containerRestartDetector := kubernetesdiscovery.NewContainerRestartDetector()
@@ -439,7 +469,9 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	uiresourceReconciler := uiresource.NewReconciler(websocketList)
 	uibuttonReconciler := uibutton.NewReconciler(websocketList)
 	portforwardReconciler := portforward.NewReconciler(storeStore, client)
-	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler)
+	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
+	dockercomposeserviceReconciler := dockercomposeservice.NewReconciler(ctx, dockerComposeClient, localClient, storeStore)
+	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, dockercomposeserviceReconciler)
 	controllerBuilder := controllers.NewControllerBuilder(tiltServerControllerManager, v)
 	v2 := provideClock()
 	renderer := hud.NewRenderer(v2)
@@ -455,34 +487,12 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	podLogManager := runtimelog.NewPodLogManager(deferredClient)
 	subscriber := portforward2.NewSubscriber(client, deferredClient)
 	fswatchManifestSubscriber := fswatch.NewManifestSubscriber(deferredClient)
-	runtime := k8s.ProvideContainerRuntime(ctx, client)
-	clusterEnv := docker.ProvideClusterEnv(ctx, kubeContext, env, runtime, minikubeClient)
-	localEnv := docker.ProvideLocalEnv(ctx, kubeContext, env, clusterEnv)
-	localClient := docker.ProvideLocalCli(ctx, localEnv)
-	clusterClient, err := docker.ProvideClusterCli(ctx, localEnv, clusterEnv, localClient)
-	if err != nil {
-		return CmdCIDeps{}, err
-	}
-	switchCli := docker.ProvideSwitchCli(clusterClient, localClient)
 	dockerUpdater := containerupdate.NewDockerUpdater(switchCli)
 	execUpdater := containerupdate.NewExecUpdater(client)
-	buildcontrolUpdateModeFlag := provideUpdateModeFlag()
-	updateMode, err := buildcontrol.ProvideUpdateMode(buildcontrolUpdateModeFlag, kubeContext, clusterEnv)
-	if err != nil {
-		return CmdCIDeps{}, err
-	}
-	buildClock := build.ProvideClock()
-	liveUpdateBuildAndDeployer := buildcontrol.NewLiveUpdateBuildAndDeployer(dockerUpdater, execUpdater, updateMode, kubeContext, buildClock)
-	labels := _wireLabelsValue
-	dockerImageBuilder := build.NewDockerImageBuilder(switchCli, labels)
-	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
-	execCustomBuilder := build.NewExecCustomBuilder(switchCli, buildClock)
-	clusterName := k8s.ProvideClusterName(ctx, apiConfig)
-	kindLoader := buildcontrol.NewKINDLoader(env, clusterName)
-	imageBuildAndDeployer := buildcontrol.NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, client, env, kubeContext, analytics3, updateMode, buildClock, kindLoader)
-	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
+	dockerComposeUpdater := containerupdate.NewDockerComposeUpdater(dockerComposeClient)
+	liveUpdateBuildAndDeployer := buildcontrol.NewLiveUpdateBuildAndDeployer(dockerUpdater, execUpdater, dockerComposeUpdater, updateMode, kubeContext, buildClock)
 	imageBuilder := buildcontrol.NewImageBuilder(dockerBuilder, execCustomBuilder, updateMode)
-	dockerComposeBuildAndDeployer := buildcontrol.NewDockerComposeBuildAndDeployer(dockerComposeClient, switchCli, imageBuilder, buildClock)
+	dockerComposeBuildAndDeployer := buildcontrol.NewDockerComposeBuildAndDeployer(dockercomposeserviceReconciler, switchCli, imageBuilder, buildClock)
 	localTargetBuildAndDeployer := buildcontrol.NewLocalTargetBuildAndDeployer(buildClock)
 	buildOrder := engine.DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, updateMode, env, runtime)
 	spanCollector := tracer.NewSpanCollector(ctx)
@@ -498,8 +508,6 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	defaults := _wireDefaultsValue
 	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, client, extension, versionExtension, configExtension, dockerComposeClient, webHost, defaults, env)
 	configsController := configs.NewConfigsController(tiltfileLoader, switchCli)
-	eventWatcher := dcwatch.NewEventWatcher(dockerComposeClient, localClient)
-	dockerComposeLogManager := runtimelog.NewDockerComposeLogManager(dockerComposeClient)
 	analyticsReporter := analytics2.ProvideAnalyticsReporter(analytics3, storeStore, client, env)
 	cmdTags := _wireCmdTagsValue
 	analyticsUpdater := analytics2.NewAnalyticsUpdater(analytics3, cmdTags)
@@ -515,7 +523,10 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics, subcomm
 	metricsController := metrics.NewController(deferredExporter, tiltBuild, gitRemote)
 	uisessionSubscriber := uisession2.NewSubscriber(deferredClient)
 	uiresourceSubscriber := uiresource2.NewSubscriber(deferredClient)
-	v3 := engine.ProvideSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, headsUpDisplay, terminalStream, terminalPrompt, manifestSubscriber, serviceWatcher, podLogManager, subscriber, fswatchManifestSubscriber, buildController, configsController, eventWatcher, dockerComposeLogManager, analyticsReporter, analyticsUpdater, eventWatchManager, cloudStatusManager, dockerPruner, telemetryController, serverController, podMonitor, sessionController, metricsController, uisessionSubscriber, uiresourceSubscriber)
+	extensionrepoSubscriber := extensionrepo.NewSubscriber(deferredClient)
+	disableController := disable.NewController(client, dockercomposeserviceReconciler)
+	readinessController := readiness.NewController(proberManager)
+	v3 := engine.ProvideSubscribers(headsUpServerController, tiltServerControllerManager, controllerBuilder, headsUpDisplay, terminalStream, terminalPrompt, manifestSubscriber, serviceWatcher, podLogManager, subscriber, fswatchManifestSubscriber, buildController, configsController, analyticsReporter, analyticsUpdater, eventWatchManager, cloudStatusManager, dockerPruner, telemetryController, serverController, podMonitor, sessionController, metricsController, uisessionSubscriber, uiresourceSubscriber, disableController, readinessController, extensionrepoSubscriber)
 	upper, err := engine.NewUpper(ctx, storeStore, v3)
 	if err != nil {
 		return CmdCIDeps{}, err
@@ -584,7 +595,8 @@ func wireCmdUpdog(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdT
 	snapshotUploader := cloud.NewSnapshotUploader(httpClient, address)
 	websocketList := server.NewWebsocketList()
 	deferredClient := controllers.ProvideDeferredClient()
-	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader, websocketList, deferredClient)
+	resourceDiffer := provideUpdogDiffer()
+	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader, websocketList, deferredClient, resourceDiffer)
 	if err != nil {
 		return CmdUpdogDeps{}, err
 	}
@@ -631,7 +643,13 @@ func wireCmdUpdog(ctx context.Context, analytics3 *analytics.TiltAnalytics, cmdT
 	uiresourceReconciler := uiresource.NewReconciler(websocketList)
 	uibuttonReconciler := uibutton.NewReconciler(websocketList)
 	portforwardReconciler := portforward.NewReconciler(storeStore, k8sClient)
-	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler)
+	runtime := k8s.ProvideContainerRuntime(ctx, k8sClient)
+	clusterEnv := docker.ProvideClusterEnv(ctx, kubeContext, env, runtime, minikubeClient)
+	localEnv := docker.ProvideLocalEnv(ctx, kubeContext, env, clusterEnv)
+	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
+	localClient := docker.ProvideLocalCli(ctx, localEnv)
+	dockercomposeserviceReconciler := dockercomposeservice.NewReconciler(ctx, dockerComposeClient, localClient, storeStore)
+	v := controllers.ProvideControllers(controller, cmdController, podlogstreamController, reconciler, uisessionReconciler, uiresourceReconciler, uibuttonReconciler, portforwardReconciler, dockercomposeserviceReconciler)
 	controllerBuilder := controllers.NewControllerBuilder(tiltServerControllerManager, v)
 	stdout := hud.ProvideStdout()
 	incrementalPrinter := hud.NewIncrementalPrinter(stdout)
@@ -932,7 +950,7 @@ func wireClientGetter(ctx context.Context) (*client2.Getter, error) {
 var K8sWireSet = wire.NewSet(k8s.ProvideEnv, k8s.ProvideClusterName, k8s.ProvideKubeContext, k8s.ProvideKubeConfig, k8s.ProvideClientConfig, k8s.ProvideClientset, k8s.ProvideRESTConfig, k8s.ProvidePortForwardClient, k8s.ProvideConfigNamespace, k8s.ProvideContainerRuntime, k8s.ProvideServerVersion, k8s.ProvideK8sClient, k8s.ProvideOwnerFetcher, ProvideKubeContextOverride)
 
 var BaseWireSet = wire.NewSet(
-	K8sWireSet, tiltfile.WireSet, git.ProvideGitRemote, docker.SwitchWireSet, ProvideDeferredExporter, metrics.WireSet, user.WireSet, dockercompose.NewDockerComposeClient, clockwork.NewRealClock, engine.DeployerWireSet, runtimelog.NewPodLogManager, podlogstream.NewController, portforward2.NewSubscriber, engine.NewBuildController, local.NewServerController, kubernetesdiscovery.NewContainerRestartDetector, k8swatch.NewManifestSubscriber, k8swatch.NewServiceWatcher, k8swatch.NewEventWatchManager, uisession2.NewSubscriber, uiresource2.NewSubscriber, configs.NewConfigsController, telemetry.NewController, dcwatch.NewEventWatcher, runtimelog.NewDockerComposeLogManager, cloud.WireSet, cloudurl.ProvideAddress, k8srollout.NewPodMonitor, telemetry.NewStartTracker, session.NewController, build.ProvideClock, provideClock, hud.WireSet, prompt.WireSet, wire.Value(openurl.OpenURL(openurl.BrowserOpen)), provideLogActions, store.NewStore, wire.Bind(new(store.RStore), new(*store.Store)), dockerprune.NewDockerPruner, provideTiltInfo, engine.NewUpper, analytics2.NewAnalyticsUpdater, analytics2.ProvideAnalyticsReporter, provideUpdateModeFlag, fswatch.NewManifestSubscriber, fsevent.ProvideWatcherMaker, fsevent.ProvideTimerMaker, controllers.WireSet, provideWebVersion,
+	K8sWireSet, tiltfile.WireSet, git.ProvideGitRemote, docker.SwitchWireSet, ProvideDeferredExporter, metrics.WireSet, user.WireSet, dockercompose.NewDockerComposeClient, clockwork.NewRealClock, engine.DeployerWireSet, runtimelog.NewPodLogManager, podlogstream.NewController, portforward2.NewSubscriber, engine.NewBuildController, local.NewServerController, kubernetesdiscovery.NewContainerRestartDetector, k8swatch.NewManifestSubscriber, k8swatch.NewServiceWatcher, k8swatch.NewEventWatchManager, uisession2.NewSubscriber, uiresource2.NewSubscriber, configs.NewConfigsController, telemetry.NewController, cloud.WireSet, cloudurl.ProvideAddress, k8srollout.NewPodMonitor, telemetry.NewStartTracker, session.NewController, build.ProvideClock, provideClock, hud.WireSet, prompt.WireSet, wire.Value(openurl.OpenURL(openurl.BrowserOpen)), provideLogActions, store.NewStore, wire.Bind(new(store.RStore), new(*store.Store)), dockerprune.NewDockerPruner, provideTiltInfo, engine.NewUpper, analytics2.NewAnalyticsUpdater, analytics2.ProvideAnalyticsReporter, provideUpdateModeFlag, fswatch.NewManifestSubscriber, fsevent.ProvideWatcherMaker, fsevent.ProvideTimerMaker, controllers.WireSet, provideWebVersion,
 	provideWebMode,
 	provideWebURL,
 	provideWebPort,
@@ -1,9 +1,10 @@
 package cloud
 
 import (
+	"bytes"
 	"context"
 	"io"
-	"os"
+	"io/ioutil"
 
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -34,13 +35,18 @@ func (s *Snapshotter) WriteSnapshot(ctx context.Context, path string) {
 		return
 	}
 
-	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	buf := &bytes.Buffer{}
+	err = WriteSnapshotTo(ctx, &proto_webview.Snapshot{View: view}, buf)
 	if err != nil {
 		logger.Get(ctx).Errorf("Writing snapshot to file: %v", err)
 		return
 	}
 
-	err = WriteSnapshotTo(ctx, &proto_webview.Snapshot{View: view}, f)
+	state := s.st.RLockState()
+	b := state.Secrets.ScrubJSON(buf.Bytes())
+	s.st.RUnlockState()
+
+	err = ioutil.WriteFile(path, b, 0644)
 	if err != nil {
 		logger.Get(ctx).Errorf("Writing snapshot to file: %v", err)
 		return
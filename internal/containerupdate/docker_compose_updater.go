@@ -0,0 +1,106 @@
+package containerupdate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Updates the container of a Docker Compose service with `docker-compose exec`.
+//
+// Unlike the DockerUpdater, this doesn't talk to the Docker API directly, so it
+// works with whatever daemon or context docker-compose is configured to use.
+type DockerComposeUpdater struct {
+	dcc         dockercompose.DockerComposeClient
+	configPaths []string
+	serviceName model.TargetName
+}
+
+var _ ContainerUpdater = &DockerComposeUpdater{}
+
+func NewDockerComposeUpdater(dcc dockercompose.DockerComposeClient) *DockerComposeUpdater {
+	return &DockerComposeUpdater{dcc: dcc}
+}
+
+// Returns an updater for the container of the given service.
+func (cu *DockerComposeUpdater) ForTarget(dcTarget model.DockerComposeTarget) *DockerComposeUpdater {
+	return &DockerComposeUpdater{
+		dcc:         cu.dcc,
+		configPaths: dcTarget.ConfigPaths,
+		serviceName: dcTarget.Name,
+	}
+}
+
+func (cu *DockerComposeUpdater) UpdateContainer(ctx context.Context, cInfo store.ContainerInfo,
+	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
+	if cu.serviceName == "" {
+		return fmt.Errorf("internal error: DockerComposeUpdater has no service. Please report to https://github.com/tilt-dev/tilt/issues")
+	}
+
+	l := logger.Get(ctx)
+	w := logger.Get(ctx).Writer(logger.InfoLvl)
+
+	// delete files (if any)
+	if len(filesToDelete) > 0 {
+		buf := bytes.NewBuffer(nil)
+		rmWriter := io.MultiWriter(w, buf)
+		err := cu.dcc.Exec(ctx, cu.configPaths, cu.serviceName,
+			append([]string{"rm", "-rf"}, filesToDelete...), nil, rmWriter, rmWriter)
+		if err != nil {
+			return fmt.Errorf("removing old files: %v", handleDCExecError(buf, err))
+		}
+	}
+
+	// copy files to container
+	buf := bytes.NewBuffer(nil)
+	tarWriter := io.MultiWriter(w, buf)
+	err := cu.dcc.Exec(ctx, cu.configPaths, cu.serviceName, tarArgv(), archiveToCopy, tarWriter, tarWriter)
+	if err != nil {
+		return fmt.Errorf("copying changed files: %v", handleDCExecError(buf, err))
+	}
+
+	// run commands
+	for i, c := range cmds {
+		l.Infof("[CMD %d/%d] %s", i+1, len(cmds), strings.Join(c.Argv, " "))
+		err := cu.dcc.Exec(ctx, cu.configPaths, cu.serviceName, c.Argv, nil, w, w)
+		if err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return build.RunStepFailure{Cmd: c, ExitCode: exitErr.ExitCode()}
+			}
+			return build.WrapCodeExitError(err, cInfo.ContainerID, c)
+		}
+	}
+
+	if hotReload {
+		l.Debugf("Hot reload on, skipping container restart: %s", cu.serviceName)
+		return nil
+	}
+
+	l.Debugf("Restarting container: %s", cu.serviceName)
+	err = cu.dcc.Restart(ctx, cu.configPaths, cu.serviceName, w, w)
+	if err != nil {
+		return fmt.Errorf("restarting service %s: %v", cu.serviceName, err)
+	}
+	return nil
+}
+
+func handleDCExecError(out *bytes.Buffer, err error) error {
+	msg := strings.ToLower(fmt.Sprintf("%s\n%s", out.String(), err.Error()))
+	if strings.Contains(msg, "permission denied") || strings.Contains(msg, "cannot open") {
+		return fmt.Errorf("%v\n"+
+			"This usually means the container filesystem denied access. Please check:\n"+
+			"  1) That the container image has writable files\n"+
+			"  2) That the container image default user has write access to the files",
+			err)
+	}
+	return err
+}
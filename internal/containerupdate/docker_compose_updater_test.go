@@ -0,0 +1,87 @@
+package containerupdate
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestDockerComposeUpdateContainer(t *testing.T) {
+	f := newDCUpdaterFixture(t)
+
+	err := f.dccu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), toDelete, cmds, true)
+	require.NoError(t, err)
+
+	if assert.Len(t, f.dcCli.ExecCalls, 4) {
+		assert.Equal(t, []string{"rm", "-rf", "/foo/delete_me", "/bar/me_too"}, f.dcCli.ExecCalls[0].Cmd)
+		assert.Equal(t, []string{"tar", "-C", "/", "-x", "-f", "-"}, f.dcCli.ExecCalls[1].Cmd)
+		assert.Equal(t, []byte("hello world"), f.dcCli.ExecCalls[1].Stdin)
+		assert.Equal(t, cmdA.Argv, f.dcCli.ExecCalls[2].Cmd)
+		assert.Equal(t, cmdB.Argv, f.dcCli.ExecCalls[3].Cmd)
+
+		for _, call := range f.dcCli.ExecCalls {
+			assert.Equal(t, model.TargetName("web"), call.ServiceName)
+			assert.Equal(t, []string{"docker-compose.yml"}, call.PathToConfig)
+		}
+	}
+	assert.Len(t, f.dcCli.RestartCalls, 0)
+}
+
+func TestDockerComposeUpdateContainerRestart(t *testing.T) {
+	f := newDCUpdaterFixture(t)
+
+	err := f.dccu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), nil, nil, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []dockercompose.RestartCall{
+		{PathToConfig: []string{"docker-compose.yml"}, ServiceName: "web"},
+	}, f.dcCli.RestartCalls)
+}
+
+func TestDockerComposeUpdateContainerRunFailure(t *testing.T) {
+	f := newDCUpdaterFixture(t)
+
+	// The first exec() call is a copy, so won't trigger a RunStepFailure
+	f.dcCli.ExecErrors = []error{nil, exitError(t, 1)}
+
+	err := f.dccu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), nil, cmds, true)
+	if assert.True(t, build.IsRunStepFailure(err)) {
+		assert.Equal(t, "Run step \"a\" failed with exit code: 1", err.Error())
+	}
+	assert.Len(t, f.dcCli.ExecCalls, 2)
+}
+
+// Get a real *exec.ExitError with the given code.
+func exitError(t *testing.T, code int) error {
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	exitErr, ok := err.(*exec.ExitError)
+	require.True(t, ok, "expected an ExitError, got: %v", err)
+	require.Equal(t, code, exitErr.ExitCode())
+	return exitErr
+}
+
+type dcUpdaterFixture struct {
+	ctx   context.Context
+	dcCli *dockercompose.FakeDCClient
+	dccu  *DockerComposeUpdater
+}
+
+func newDCUpdaterFixture(t *testing.T) *dcUpdaterFixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	dcCli := dockercompose.NewFakeDockerComposeClient(t, ctx)
+	dcTarget := model.DockerComposeTarget{Name: "web", ConfigPaths: []string{"docker-compose.yml"}}
+	return &dcUpdaterFixture{
+		ctx:   ctx,
+		dcCli: dcCli,
+		dccu:  NewDockerComposeUpdater(dcCli).ForTarget(dcTarget),
+	}
+}
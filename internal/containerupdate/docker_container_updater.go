@@ -27,6 +27,11 @@ func NewDockerUpdater(dCli docker.Client) *DockerUpdater {
 	return &DockerUpdater{dCli: dCli}
 }
 
+// Returns an updater that execs in containers on the Docker daemon for the given orchestrator.
+func (cu *DockerUpdater) ForOrchestrator(orc model.Orchestrator) *DockerUpdater {
+	return NewDockerUpdater(cu.dCli.ForOrchestrator(orc))
+}
+
 func (cu *DockerUpdater) WillBuildToKubeContext(kctx k8s.KubeContext) bool {
 	return cu.dCli.Env().WillBuildToKubeContext(kctx)
 }
@@ -168,7 +168,7 @@ func (c *Controller) reconcile(ctx context.Context, name types.NamespacedName) e
 	if spec.ReadinessProbe != nil {
 		statusChangeFunc := c.processReadinessProbeStatusChange(ctx, name, stillHasSameProcNum)
 		resultLoggerFunc := processReadinessProbeResultLogger(ctx, stillHasSameProcNum)
-		probeWorker, err := probeWorkerFromSpec(
+		probeWorker, err := ProbeWorkerFromSpec(
 			c.proberManager,
 			spec.ReadinessProbe,
 			statusChangeFunc,
@@ -210,7 +210,7 @@ func (c *Controller) processReadinessProbeStatusChange(ctx context.Context, name
 
 		if status == prober.Success {
 			// successful probes are ONLY logged on status change to reduce chattiness
-			logProbeOutput(ctx, status, output, nil)
+			LogProbeOutput(ctx, status, output, nil)
 		}
 
 		ready := status == prober.Success || status == prober.Warning
@@ -221,7 +221,8 @@ func (c *Controller) processReadinessProbeStatusChange(ctx context.Context, name
 	}
 }
 
-func logProbeOutput(ctx context.Context, result prober.Result, output string, err error) {
+// LogProbeOutput logs the output of a readiness probe at verbose level.
+func LogProbeOutput(ctx context.Context, result prober.Result, output string, err error) {
 	l := logger.Get(ctx)
 	if !l.Level().ShouldDisplay(logger.VerboseLvl) {
 		return
@@ -252,7 +253,7 @@ func processReadinessProbeResultLogger(ctx context.Context, stillHasSameProcNum
 
 		// successful probes are ONLY logged on status change to reduce chattiness
 		if result != prober.Success {
-			logProbeOutput(ctx, result, output, err)
+			LogProbeOutput(ctx, result, output, err)
 		}
 	}
 }
@@ -116,6 +116,25 @@ func TestServe(t *testing.T) {
 	f.assertLogMessage("foo", "Starting cmd sleep 60")
 }
 
+func TestServeSecretEnv(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	st := f.st.LockMutableStateForTesting()
+	st.Secrets.AddSecret("api-token", "", []byte("abc123-token"))
+	f.st.UnlockMutableState()
+
+	c := model.ToHostCmd("sleep 60")
+	c.Env = []string{"TOKEN=abc123-token", "PORT=8080", "URL=https://abc123-token@example.com"}
+	localTarget := model.NewLocalTarget(model.TargetName("foo"), model.Cmd{}, c, nil)
+	f.resourceFromTarget("foo", localTarget, time.Unix(1, 0))
+	f.step()
+
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Annotations[v1alpha1.AnnotationSecretEnv] == "TOKEN,URL"
+	})
+}
+
 func TestServeReadinessProbe(t *testing.T) {
 	f := newFixture(t)
 	defer f.teardown()
@@ -29,7 +29,10 @@ type ProberManager interface {
 	Exec(name string, args ...string) prober.ProberFunc
 }
 
-func probeWorkerFromSpec(manager ProberManager, probeSpec *v1alpha1.Probe, changedFunc probe.StatusChangedFunc, resultFunc probe.ResultFunc) (*probe.Worker, error) {
+// ProbeWorkerFromSpec creates a worker that runs the probe on an interval.
+//
+// Also used by resources other than local_resource (see k8s_resource and dc_resource).
+func ProbeWorkerFromSpec(manager ProberManager, probeSpec *v1alpha1.Probe, changedFunc probe.StatusChangedFunc, resultFunc probe.ResultFunc) (*probe.Worker, error) {
 	probeFunc, err := proberFromSpec(manager, probeSpec)
 	if err != nil {
 		return nil, err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"sync"
 	"sync/atomic"
 
 	"github.com/tilt-dev/probe/pkg/prober"
@@ -24,33 +25,50 @@ type FakeProberManager struct {
 
 	execName string
 	execArgs []string
+
+	mu     sync.Mutex
+	result prober.Result
 }
 
 func (m *FakeProberManager) HTTPGet(u *url.URL, headers http.Header) prober.ProberFunc {
 	m.httpURL = u
 	m.httpHeaders = headers
 	atomic.AddInt32(&m.probeCount, 1)
-	return successProbe
+	return m.probe
 }
 
 func (m *FakeProberManager) TCPSocket(host string, port int) prober.ProberFunc {
 	m.tcpHost = host
 	m.tcpPort = port
 	atomic.AddInt32(&m.probeCount, 1)
-	return successProbe
+	return m.probe
 }
 
 func (m *FakeProberManager) Exec(name string, args ...string) prober.ProberFunc {
 	m.execName = name
 	m.execArgs = args
 	atomic.AddInt32(&m.probeCount, 1)
-	return successProbe
+	return m.probe
 }
 
 func (m *FakeProberManager) ProbeCount() int {
 	return int(atomic.LoadInt32(&m.probeCount))
 }
 
-func successProbe(_ context.Context) (prober.Result, string, error) {
-	return prober.Success, "fake probe succeeded!", nil
+// SetResult changes the result of all probes created by this manager.
+// Probes succeed by default.
+func (m *FakeProberManager) SetResult(result prober.Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.result = result
+}
+
+func (m *FakeProberManager) probe(_ context.Context) (prober.Result, string, error) {
+	m.mu.Lock()
+	result := m.result
+	m.mu.Unlock()
+	if result == "" || result == prober.Success {
+		return prober.Success, "fake probe succeeded!", nil
+	}
+	return result, "fake probe failed!", nil
 }
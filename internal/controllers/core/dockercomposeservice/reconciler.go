@@ -0,0 +1,610 @@
+package dockercomposeservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/engine/dcwatch"
+	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// A controller that reads DockerComposeServiceSpec and writes DockerComposeServiceStatus.
+//
+// Owns `docker-compose up` for the service, the state of its container,
+// and its log stream.
+type Reconciler struct {
+	globalCtx  context.Context
+	dcc        dockercompose.DockerComposeClient
+	dc         docker.LocalClient
+	st         store.RStore
+	ctrlClient ctrlclient.Client
+
+	mu       sync.Mutex
+	results  map[ktypes.NamespacedName]*result
+	projects map[string]*projectWatch
+
+	// Serializes status writes to the API server, so that they land
+	// in the same order that we recorded them.
+	statusMu sync.Mutex
+}
+
+var _ store.TearDowner = &Reconciler{}
+var _ reconcile.Reconciler = &Reconciler{}
+
+func NewReconciler(ctx context.Context, dcc dockercompose.DockerComposeClient, dc docker.LocalClient, st store.RStore) *Reconciler {
+	return &Reconciler{
+		globalCtx: ctx,
+		dcc:       dcc,
+		dc:        dc,
+		st:        st,
+		results:   make(map[ktypes.NamespacedName]*result),
+		projects:  make(map[string]*projectWatch),
+	}
+}
+
+func (r *Reconciler) SetClient(client ctrlclient.Client) {
+	r.ctrlClient = client
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.DockerComposeService{}).Complete(r)
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	err := r.reconcile(ctx, req.NamespacedName)
+	return ctrl.Result{}, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, name ktypes.NamespacedName) error {
+	obj := &v1alpha1.DockerComposeService{}
+	err := r.ctrlClient.Get(ctx, name, obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("dockercomposeservice reconcile: %v", err)
+	}
+
+	if apierrors.IsNotFound(err) || obj.ObjectMeta.DeletionTimestamp != nil {
+		r.rm(name)
+		return nil
+	}
+
+	res, changed := r.track(obj)
+	if !changed {
+		// Nothing has changed since the last time we brought the service up.
+		return nil
+	}
+
+	// Errors are recorded on the status.
+	_, _ = r.apply(res.logCtx, name, obj.Spec, true)
+	return nil
+}
+
+// ForceApply brings up the service, creating or updating its
+// DockerComposeService object first.
+//
+// The build controller calls this after it builds the service's images,
+// so shouldBuild determines whether docker-compose should build them itself.
+//
+// Returns the status after the apply. Apply errors are also recorded on the status.
+func (r *Reconciler) ForceApply(ctx context.Context, obj *v1alpha1.DockerComposeService, shouldBuild bool) (v1alpha1.DockerComposeServiceStatus, error) {
+	name := ktypes.NamespacedName{Name: obj.Name}
+
+	// Track the spec before the object appears in the API server,
+	// so that reconcile() doesn't bring the service up a second time.
+	r.track(obj)
+
+	err := r.upsert(ctx, obj)
+	if err != nil {
+		return v1alpha1.DockerComposeServiceStatus{}, err
+	}
+	return r.apply(ctx, name, obj.Spec, shouldBuild)
+}
+
+// Create or update the object in the API server.
+func (r *Reconciler) upsert(ctx context.Context, obj *v1alpha1.DockerComposeService) error {
+	var existing v1alpha1.DockerComposeService
+	err := r.ctrlClient.Get(ctx, ktypes.NamespacedName{Name: obj.Name}, &existing)
+	if apierrors.IsNotFound(err) {
+		err = r.ctrlClient.Create(ctx, obj.DeepCopy())
+		if err != nil {
+			return fmt.Errorf("creating DockerComposeService on apiserver: %v", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting existing DockerComposeService: %v", err)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Spec, obj.Spec) &&
+		equality.Semantic.DeepEqual(existing.ObjectMeta.Annotations, obj.ObjectMeta.Annotations) {
+		return nil
+	}
+
+	existing.ObjectMeta.Annotations = obj.ObjectMeta.Annotations
+	existing.Spec = obj.Spec
+	err = r.ctrlClient.Update(ctx, &existing)
+	if err != nil {
+		return fmt.Errorf("updating DockerComposeService %s on apiserver: %v", obj.Name, err)
+	}
+	return nil
+}
+
+// Starts tracking the object if it's new or its spec has changed,
+// and starts watching its project's container events.
+//
+// Returns true if the service needs to be brought up with the new spec.
+func (r *Reconciler) track(obj *v1alpha1.DockerComposeService) (*result, bool) {
+	name := ktypes.NamespacedName{Name: obj.Name}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.results[name]
+	if ok {
+		if equality.Semantic.DeepEqual(existing.spec, obj.Spec) {
+			return existing, false
+		}
+		existing.cancel()
+		r.unwatchProject(existing)
+	}
+
+	res := newResult(r.globalCtx, r.st, obj)
+	r.results[name] = res
+	r.watchProject(res)
+	return res, true
+}
+
+// Adds the service to the watcher for its project,
+// starting a watcher if this is the project's first service.
+//
+// Must be called while holding r.mu.
+func (r *Reconciler) watchProject(res *result) {
+	key := projectKey(res.spec.Project)
+	pw, ok := r.projects[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(r.globalCtx)
+		pw = &projectWatch{
+			project:  *res.spec.Project.DeepCopy(),
+			services: make(map[ktypes.NamespacedName]bool),
+			ctx:      ctx,
+			cancel:   cancel,
+		}
+		r.projects[key] = pw
+		go r.watchEvents(pw)
+	}
+	pw.services[res.name] = true
+}
+
+// Removes the service from the watcher for its project,
+// stopping the watcher if this was the project's last service.
+//
+// Must be called while holding r.mu.
+func (r *Reconciler) unwatchProject(res *result) {
+	key := projectKey(res.spec.Project)
+	pw, ok := r.projects[key]
+	if !ok {
+		return
+	}
+	delete(pw.services, res.name)
+	if len(pw.services) == 0 {
+		pw.cancel()
+		delete(r.projects, key)
+	}
+}
+
+// Runs `docker-compose up` for the service, and records the state of its container.
+func (r *Reconciler) apply(ctx context.Context, name ktypes.NamespacedName, spec v1alpha1.DockerComposeServiceSpec, shouldBuild bool) (v1alpha1.DockerComposeServiceStatus, error) {
+	startTime := apis.NowMicro()
+	serviceName := model.TargetName(spec.Service)
+
+	out := logger.Get(ctx).Writer(logger.InfoLvl)
+	err := r.checkPortsAvailable(ctx, spec)
+	if err == nil {
+		err = r.dcc.Up(ctx, spec.Project.ConfigPaths, serviceName, shouldBuild, out, out)
+	}
+
+	var cid container.ID
+	if err == nil {
+		cid, err = r.dcc.ContainerID(ctx, spec.Project.ConfigPaths, serviceName)
+	}
+
+	var containerJSON types.ContainerJSON
+	if cid != "" {
+		var inspectErr error
+		containerJSON, inspectErr = r.dc.ContainerInspect(ctx, string(cid))
+		if inspectErr != nil {
+			logger.Get(ctx).Debugf("Error inspecting container %s: %v", cid, inspectErr)
+		}
+	}
+
+	status := r.updateStatus(name, func(status *v1alpha1.DockerComposeServiceStatus) {
+		status.LastApplyStartTime = startTime
+		status.LastApplyFinishTime = apis.NowMicro()
+		status.ApplyError = ""
+		if err != nil {
+			status.ApplyError = err.Error()
+		}
+		if cid != "" {
+			status.ContainerID = string(cid)
+			status.ContainerName = containerName(containerJSON)
+			status.ContainerState = ToContainerState(containerState(containerJSON))
+		}
+	})
+
+	r.ensureLogStream(name, cid)
+	return status, err
+}
+
+// Checks that nothing else on this machine is bound to the ports the service publishes,
+// so that we can report the conflict before `docker-compose up` fails on it.
+//
+// If the service's container is already running, it holds the ports itself,
+// and docker-compose releases them when it recreates the container.
+//
+// We can only check ports on this machine, so we skip the check
+// when the Docker daemon is remote (e.g., DOCKER_HOST=ssh://...).
+func (r *Reconciler) checkPortsAvailable(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec) error {
+	if !r.dc.Env().IsLocalHost() {
+		return nil
+	}
+
+	cid, err := r.dcc.ContainerID(ctx, spec.Project.ConfigPaths, model.TargetName(spec.Service))
+	if err == nil && cid != "" {
+		containerJSON, err := r.dc.ContainerInspect(ctx, string(cid))
+		if err == nil {
+			state := containerState(containerJSON)
+			if state != nil && state.Running {
+				return nil
+			}
+		}
+	}
+
+	config, err := dockercompose.ReadConfig(ctx, r.dcc, spec.Project.ConfigPaths)
+	if err != nil {
+		// Let `docker-compose up` report any problems with the config.
+		return nil
+	}
+
+	unavailable := dockercompose.UnavailablePorts(config.Services[spec.Service].Ports)
+	if len(unavailable) == 0 {
+		return nil
+	}
+
+	var ports []string
+	for _, p := range unavailable {
+		ports = append(ports, p.String())
+	}
+	return fmt.Errorf("Cannot start service %q: port(s) %s already in use on this machine.\n"+
+		"Stop whatever is listening there, or change the ports the service publishes in its docker-compose config",
+		spec.Service, strings.Join(ports, ", "))
+}
+
+// Watches the docker-compose events of a project, and hands each container
+// event to the services it belongs to.
+//
+// We run one watcher per project rather than one per service, because
+// each watcher is a `docker-compose events` process.
+func (r *Reconciler) watchEvents(pw *projectWatch) {
+	ctx := pw.ctx
+	ch, err := r.dcc.StreamEvents(ctx, pw.project.ConfigPaths)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Get(ctx).Infof("Error watching docker-compose events: %v", err)
+		}
+		return
+	}
+
+	for {
+		select {
+		case evtJson, ok := <-ch:
+			if !ok {
+				return
+			}
+			evt, err := dockercompose.EventFromJsonStr(evtJson)
+			if err != nil {
+				logger.Get(ctx).Debugf("[dcwatch] failed to unmarshal dc event '%s' with err: %v", evtJson, err)
+				continue
+			}
+
+			if evt.Type != dockercompose.TypeContainer {
+				continue
+			}
+
+			for _, res := range r.servicesForEvent(pw, evt) {
+				r.handleContainerEvent(res, evt)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// The services in the project watch that the event belongs to.
+func (r *Reconciler) servicesForEvent(pw *projectWatch, evt dockercompose.Event) []*result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var results []*result
+	for name := range pw.services {
+		res, ok := r.results[name]
+		if ok && res.spec.Service == evt.Service {
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+// Updates the service's status with the state of the container in the event.
+func (r *Reconciler) handleContainerEvent(res *result, evt dockercompose.Event) {
+	ctx := res.ctx
+	if ctx.Err() != nil {
+		return
+	}
+
+	containerJSON, err := r.dc.ContainerInspect(ctx, evt.ID)
+	if err != nil {
+		logger.Get(ctx).Debugf("[dcwatch] inspecting container: %v", err)
+		return
+	}
+
+	cState := containerState(containerJSON)
+	if cState == nil {
+		logger.Get(ctx).Debugf("[dcwatch] inspecting container: no state found")
+		return
+	}
+
+	r.updateStatus(res.name, func(status *v1alpha1.DockerComposeServiceStatus) {
+		status.ContainerID = evt.ID
+		status.ContainerName = containerName(containerJSON)
+		status.ContainerState = ToContainerState(cState)
+	})
+	r.ensureLogStream(res.name, container.ID(evt.ID))
+
+	if res.manifestName != "" {
+		r.st.Dispatch(dcwatch.NewEventAction(res.manifestName, evt, *cState))
+	}
+}
+
+// Starts streaming the logs of the given container, unless we're already streaming them.
+func (r *Reconciler) ensureLogStream(name ktypes.NamespacedName, cid container.ID) {
+	if cid == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res, ok := r.results[name]
+	if !ok || res.logContainerID == cid {
+		return
+	}
+
+	if res.logCancel != nil {
+		res.logCancel()
+	}
+
+	ctx, cancel := context.WithCancel(res.ctx)
+	res.logContainerID = cid
+	res.logCancel = cancel
+	go r.consumeLogs(ctx, res)
+}
+
+func (r *Reconciler) consumeLogs(ctx context.Context, res *result) {
+	readCloser, err := r.dcc.StreamLogs(ctx, res.spec.Project.ConfigPaths, model.TargetName(res.spec.Service))
+	if err != nil {
+		logger.Get(ctx).Debugf("Error streaming %s logs: %v", res.spec.Service, err)
+		return
+	}
+	defer func() {
+		_ = readCloser.Close()
+	}()
+
+	actionWriter := runtimelog.NewDockerComposeLogActionWriter(r.st, res.manifestName)
+	_, err = io.Copy(actionWriter, runtimelog.NewHardCancelReader(ctx, readCloser))
+	if err != nil && ctx.Err() == nil {
+		logger.Get(ctx).Debugf("Error streaming %s logs: %v", res.spec.Service, err)
+	}
+}
+
+// Applies the update to the status we've recorded, and writes it to the API server.
+//
+// Returns the updated status.
+func (r *Reconciler) updateStatus(name ktypes.NamespacedName, update func(status *v1alpha1.DockerComposeServiceStatus)) v1alpha1.DockerComposeServiceStatus {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+
+	r.mu.Lock()
+	res, ok := r.results[name]
+	if !ok {
+		r.mu.Unlock()
+
+		// The object was deleted while we were working on it.
+		status := v1alpha1.DockerComposeServiceStatus{}
+		update(&status)
+		return status
+	}
+
+	update(&res.status)
+	status := *res.status.DeepCopy()
+	r.mu.Unlock()
+
+	// Don't hold r.mu while we talk to the API server.
+	obj := &v1alpha1.DockerComposeService{}
+	err := r.ctrlClient.Get(r.globalCtx, name, obj)
+	if err == nil {
+		obj.Status = status
+		err = r.ctrlClient.Status().Update(r.globalCtx, obj)
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.st.Dispatch(store.NewErrorAction(fmt.Errorf("syncing to apiserver: %v", err)))
+	}
+	return status
+}
+
+// ForceDelete removes the service's container and deletes its
+// DockerComposeService object.
+//
+// The disable controller calls this when the user disables the service.
+// The container is removed even if this reconciler never brought it up.
+func (r *Reconciler) ForceDelete(ctx context.Context, obj *v1alpha1.DockerComposeService) error {
+	name := ktypes.NamespacedName{Name: obj.Name}
+
+	// Stop tracking the service before deleting the object,
+	// so that reconcile() doesn't remove the container a second time.
+	r.untrack(name)
+
+	err := r.ctrlClient.Delete(ctx, &v1alpha1.DockerComposeService{ObjectMeta: metav1.ObjectMeta{Name: obj.Name}})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting DockerComposeService %s on apiserver: %v", obj.Name, err)
+	}
+
+	out := logger.Get(ctx).Writer(logger.InfoLvl)
+	return r.dcc.Rm(ctx, obj.Spec.Project.ConfigPaths, model.TargetName(obj.Spec.Service), out, out)
+}
+
+// Stops tracking the service and watching its project.
+//
+// Returns the result we were tracking, if any.
+func (r *Reconciler) untrack(name ktypes.NamespacedName) (*result, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res, ok := r.results[name]
+	delete(r.results, name)
+	if ok {
+		r.unwatchProject(res)
+		res.cancel()
+	}
+	return res, ok
+}
+
+// Stops watching the service, and removes its container.
+func (r *Reconciler) rm(name ktypes.NamespacedName) {
+	res, ok := r.untrack(name)
+	if !ok {
+		return
+	}
+
+	ctx := res.logCtx
+	out := logger.Get(ctx).Writer(logger.InfoLvl)
+	err := r.dcc.Rm(ctx, res.spec.Project.ConfigPaths, model.TargetName(res.spec.Service), out, out)
+	if err != nil {
+		logger.Get(ctx).Infof("Error removing docker-compose service %s: %v", res.spec.Service, err)
+	}
+}
+
+// Stops all the watches. Leaves the containers running.
+func (r *Reconciler) TearDown(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, res := range r.results {
+		res.cancel()
+	}
+	for _, pw := range r.projects {
+		pw.cancel()
+	}
+}
+
+func containerState(containerJSON types.ContainerJSON) *types.ContainerState {
+	if containerJSON.ContainerJSONBase == nil {
+		return nil
+	}
+	return containerJSON.ContainerJSONBase.State
+}
+
+func containerName(containerJSON types.ContainerJSON) string {
+	if containerJSON.ContainerJSONBase == nil {
+		return ""
+	}
+	return strings.TrimPrefix(containerJSON.ContainerJSONBase.Name, "/")
+}
+
+// Converts the Docker API container state to the Tilt API container state.
+func ToContainerState(state *types.ContainerState) *v1alpha1.DockerContainerState {
+	if state == nil {
+		return nil
+	}
+	return &v1alpha1.DockerContainerState{
+		Status:     state.Status,
+		Running:    state.Running,
+		Error:      state.Error,
+		ExitCode:   int32(state.ExitCode),
+		StartedAt:  parseDockerTime(state.StartedAt),
+		FinishedAt: parseDockerTime(state.FinishedAt),
+	}
+}
+
+// Docker reports unset times as the zero time, and set times in RFC 3339.
+func parseDockerTime(s string) metav1.MicroTime {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || t.IsZero() {
+		return metav1.MicroTime{}
+	}
+	return apis.NewMicroTime(t)
+}
+
+type result struct {
+	name         ktypes.NamespacedName
+	spec         v1alpha1.DockerComposeServiceSpec
+	manifestName model.ManifestName
+	status       v1alpha1.DockerComposeServiceStatus
+
+	// Logs about the service go to the resource it belongs to.
+	logCtx context.Context
+
+	// Canceled when the spec changes or the object is deleted.
+	ctx    context.Context
+	cancel func()
+
+	// The container whose logs we're streaming.
+	logContainerID container.ID
+	logCancel      func()
+}
+
+// A `docker-compose events` watcher, shared by all the services in a project.
+type projectWatch struct {
+	project  v1alpha1.DockerComposeProject
+	services map[ktypes.NamespacedName]bool
+
+	ctx    context.Context
+	cancel func()
+}
+
+// Services with the same config paths are in the same project.
+func projectKey(project v1alpha1.DockerComposeProject) string {
+	return strings.Join(project.ConfigPaths, "\n")
+}
+
+func newResult(globalCtx context.Context, st store.RStore, obj *v1alpha1.DockerComposeService) *result {
+	logCtx := store.MustObjectLogHandler(globalCtx, st, obj)
+	ctx, cancel := context.WithCancel(logCtx)
+	return &result{
+		name:         ktypes.NamespacedName{Name: obj.Name},
+		spec:         *obj.Spec.DeepCopy(),
+		manifestName: model.ManifestName(obj.ObjectMeta.Annotations[v1alpha1.AnnotationManifest]),
+		logCtx:       logCtx,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
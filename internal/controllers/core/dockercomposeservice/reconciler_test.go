@@ -0,0 +1,290 @@
+package dockercomposeservice
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/engine/dcwatch"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestCreateBringsUpService(t *testing.T) {
+	f := newFixture(t)
+
+	f.Create(f.service("fe"))
+
+	require.Len(t, f.dcc.UpCalls, 1)
+	assert.Equal(t, dockercompose.UpCall{
+		PathToConfig: []string{"docker-compose.yml"},
+		ServiceName:  "fe",
+		ShouldBuild:  true,
+	}, f.dcc.UpCalls[0])
+
+	var dcs v1alpha1.DockerComposeService
+	f.MustGet(ktypes.NamespacedName{Name: "fe"}, &dcs)
+	assert.Equal(t, "fe-container", dcs.Status.ContainerID)
+	assert.Equal(t, "", dcs.Status.ApplyError)
+	assert.False(t, dcs.Status.LastApplyFinishTime.IsZero())
+	require.NotNil(t, dcs.Status.ContainerState)
+	assert.True(t, dcs.Status.ContainerState.Running)
+}
+
+func TestUnchangedSpecDoesNotBringUpAgain(t *testing.T) {
+	f := newFixture(t)
+	key := ktypes.NamespacedName{Name: "fe"}
+
+	f.Create(f.service("fe"))
+	f.MustReconcile(key)
+	assert.Len(t, f.dcc.UpCalls, 1)
+
+	var dcs v1alpha1.DockerComposeService
+	f.MustGet(key, &dcs)
+	dcs.Spec.Project.ConfigPaths = []string{"docker-compose.yml", "docker-compose.override.yml"}
+	f.Update(&dcs)
+
+	require.Len(t, f.dcc.UpCalls, 2)
+	assert.Equal(t, dcs.Spec.Project.ConfigPaths, f.dcc.UpCalls[1].PathToConfig)
+}
+
+func TestPortConflictBeforeUp(t *testing.T) {
+	f := newFixture(t)
+	key := ktypes.NamespacedName{Name: "fe"}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	f.dcc.ContainerIdOutput = ""
+	f.dcc.ConfigOutput = fmt.Sprintf(`services:
+  fe:
+    ports:
+    - "127.0.0.1:%d:80"
+`, port)
+
+	f.Create(f.service("fe"))
+
+	assert.Len(t, f.dcc.UpCalls, 0)
+
+	var dcs v1alpha1.DockerComposeService
+	f.MustGet(key, &dcs)
+	assert.Contains(t, dcs.Status.ApplyError,
+		fmt.Sprintf(`Cannot start service "fe": port(s) 127.0.0.1:%d already in use`, port))
+}
+
+func TestNoPortCheckOnRemoteDaemon(t *testing.T) {
+	f := newFixture(t)
+	f.dc.FakeEnv = docker.Env{Host: "ssh://me@remote-docker-host"}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	f.dcc.ContainerIdOutput = ""
+	f.dcc.ConfigOutput = fmt.Sprintf(`services:
+  fe:
+    ports:
+    - "127.0.0.1:%d:80"
+`, port)
+
+	f.Create(f.service("fe"))
+
+	// The port is only in use on this machine, not on the remote daemon.
+	assert.Len(t, f.dcc.UpCalls, 1)
+}
+
+func TestDeleteRemovesContainer(t *testing.T) {
+	f := newFixture(t)
+
+	dcs := f.service("fe")
+	f.Create(dcs)
+	f.Delete(dcs)
+
+	require.Len(t, f.dcc.RmCalls, 1)
+	assert.Equal(t, model.TargetName("fe"), f.dcc.RmCalls[0].ServiceName)
+}
+
+func TestForceApplyCreatesObject(t *testing.T) {
+	f := newFixture(t)
+	key := ktypes.NamespacedName{Name: "fe"}
+
+	dcs := f.service("fe")
+	dcs.Annotations = map[string]string{v1alpha1.AnnotationManifest: "fe"}
+	status, err := f.r.ForceApply(f.ctx, dcs, false)
+	require.NoError(t, err)
+	assert.Equal(t, "fe-container", status.ContainerID)
+
+	// The controller sees the object, but shouldn't bring it up a second time.
+	f.MustReconcile(key)
+	require.Len(t, f.dcc.UpCalls, 1)
+	assert.False(t, f.dcc.UpCalls[0].ShouldBuild)
+
+	var actual v1alpha1.DockerComposeService
+	f.MustGet(key, &actual)
+	assert.Equal(t, "fe", actual.Annotations[v1alpha1.AnnotationManifest])
+	assert.Equal(t, "fe-container", actual.Status.ContainerID)
+}
+
+func TestForceDeleteRemovesObject(t *testing.T) {
+	f := newFixture(t)
+	key := ktypes.NamespacedName{Name: "fe"}
+
+	dcs := f.service("fe")
+	_, err := f.r.ForceApply(f.ctx, dcs, false)
+	require.NoError(t, err)
+
+	err = f.r.ForceDelete(f.ctx, dcs)
+	require.NoError(t, err)
+	require.Len(t, f.dcc.RmCalls, 1)
+	assert.Equal(t, model.TargetName("fe"), f.dcc.RmCalls[0].ServiceName)
+	assert.False(t, f.Get(key, &v1alpha1.DockerComposeService{}))
+
+	// The controller sees the deletion, but shouldn't remove the container a second time.
+	f.MustReconcile(key)
+	assert.Len(t, f.dcc.RmCalls, 1)
+}
+
+func TestRecordsRunLogs(t *testing.T) {
+	f := newFixture(t)
+
+	output := make(chan string, 1)
+	output <- "Attaching to fe\nfe    | hello world\n"
+	defer close(output)
+	f.dcc.RunLogOutput["fe"] = output
+
+	dcs := f.service("fe")
+	dcs.Annotations = map[string]string{v1alpha1.AnnotationManifest: "fe"}
+	f.Create(dcs)
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(f.runLog("fe"), "hello world")
+	}, time.Second, 10*time.Millisecond)
+	assert.NotContains(t, f.runLog("fe"), "Attaching to")
+}
+
+func TestEventsUpdateStatus(t *testing.T) {
+	f := newFixture(t)
+	key := ktypes.NamespacedName{Name: "fe"}
+
+	dcs := f.service("fe")
+	dcs.Annotations = map[string]string{v1alpha1.AnnotationManifest: "fe"}
+	f.Create(dcs)
+
+	err := f.dcc.SendEvent(dockercompose.Event{
+		Type:    dockercompose.TypeContainer,
+		Action:  dockercompose.ActionStart,
+		ID:      "fe-container-2",
+		Service: "fe",
+	})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		var actual v1alpha1.DockerComposeService
+		f.MustGet(key, &actual)
+		return actual.Status.ContainerID == "fe-container-2"
+	}, time.Second, 10*time.Millisecond)
+
+	action := f.st.WaitForAction(t, reflect.TypeOf(dcwatch.EventAction{})).(dcwatch.EventAction)
+	assert.Equal(t, model.ManifestName("fe"), action.ManifestName)
+	assert.Equal(t, "fe-container-2", action.Event.ID)
+}
+
+func TestOneEventWatcherPerProject(t *testing.T) {
+	f := newFixture(t)
+
+	f.Create(f.service("fe"))
+	f.Create(f.service("be"))
+
+	err := f.dcc.SendEvent(dockercompose.Event{
+		Type:    dockercompose.TypeContainer,
+		Action:  dockercompose.ActionStart,
+		ID:      "be-container-2",
+		Service: "be",
+	})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		var actual v1alpha1.DockerComposeService
+		f.MustGet(ktypes.NamespacedName{Name: "be"}, &actual)
+		return actual.Status.ContainerID == "be-container-2"
+	}, time.Second, 10*time.Millisecond)
+
+	var fe v1alpha1.DockerComposeService
+	f.MustGet(ktypes.NamespacedName{Name: "fe"}, &fe)
+	assert.Equal(t, "fe-container", fe.Status.ContainerID)
+	assert.Equal(t, 1, f.dcc.StreamEventsCallCount())
+}
+
+type fixture struct {
+	*fake.ControllerFixture
+	ctx context.Context
+	r   *Reconciler
+	dcc *dockercompose.FakeDCClient
+	dc  *docker.FakeClient
+	st  *store.TestingStore
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	ctx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+
+	st := store.NewTestingStore()
+	dcc := dockercompose.NewFakeDockerComposeClient(t, ctx)
+	dcc.ContainerIdOutput = container.ID("fe-container")
+	dc := docker.NewFakeClient()
+
+	r := NewReconciler(ctx, dcc, dc, st)
+	t.Cleanup(func() { r.TearDown(ctx) })
+
+	return &fixture{
+		ControllerFixture: fake.NewControllerFixture(t, r),
+		ctx:               ctx,
+		r:                 r,
+		dcc:               dcc,
+		dc:                dc,
+		st:                st,
+	}
+}
+
+func (f *fixture) service(name string) *v1alpha1.DockerComposeService {
+	return &v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.DockerComposeServiceSpec{
+			Service: name,
+			Project: v1alpha1.DockerComposeProject{
+				ConfigPaths: []string{"docker-compose.yml"},
+			},
+		},
+	}
+}
+
+// All the run logs written for the given manifest.
+func (f *fixture) runLog(mn model.ManifestName) string {
+	var sb strings.Builder
+	for _, action := range f.st.Actions() {
+		la, ok := action.(store.LogAction)
+		if ok && la.ManifestName() == mn {
+			sb.Write(la.Message())
+		}
+	}
+	return sb.String()
+}
@@ -5,6 +5,7 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesdiscovery"
 	"github.com/tilt-dev/tilt/internal/controllers/core/podlogstream"
@@ -19,6 +20,7 @@ var controllerSet = wire.NewSet(
 	filewatch.NewController,
 	kubernetesdiscovery.NewReconciler,
 	portforward.NewReconciler,
+	dockercomposeservice.NewReconciler,
 
 	ProvideControllers,
 )
@@ -31,7 +33,8 @@ func ProvideControllers(
 	uis *uisession.Reconciler,
 	uir *uiresource.Reconciler,
 	uib *uibutton.Reconciler,
-	pfr *portforward.Reconciler) []Controller {
+	pfr *portforward.Reconciler,
+	dcsr *dockercomposeservice.Reconciler) []Controller {
 	return []Controller{
 		fileWatch,
 		cmds,
@@ -41,6 +44,7 @@ func ProvideControllers(
 		uir,
 		uib,
 		pfr,
+		dcsr,
 	}
 }
 
@@ -22,6 +22,7 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/registry"
 	"github.com/docker/go-connections/tlsconfig"
@@ -79,6 +80,11 @@ type Client interface {
 	// which can talk to either the Local or in-cluster docker daemon.
 	SetOrchestrator(orc model.Orchestrator)
 
+	// Returns a client that always talks to the Docker daemon for the given orchestrator,
+	// regardless of what SetOrchestrator was last called with. This is only relevant
+	// to switchClient; all other clients return themselves.
+	ForOrchestrator(orc model.Orchestrator) Client
+
 	ContainerInspect(ctx context.Context, contianerID string) (types.ContainerJSON, error)
 	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
 	ContainerRestartNoWait(ctx context.Context, containerID string) error
@@ -88,6 +94,7 @@ type Client interface {
 	ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error
 
 	ImagePush(ctx context.Context, image reference.NamedTagged) (io.ReadCloser, error)
+	DistributionInspect(ctx context.Context, image reference.NamedTagged) (registrytypes.DistributionInspect, error)
 	ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error)
 	ImageTag(ctx context.Context, source, target string) error
 	ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error)
@@ -372,6 +379,9 @@ func (c *Cli) initAuthConfigs(ctx context.Context) {
 
 func (c *Cli) CheckConnected() error                  { return nil }
 func (c *Cli) SetOrchestrator(orc model.Orchestrator) {}
+func (c *Cli) ForOrchestrator(orc model.Orchestrator) Client {
+	return c
+}
 func (c *Cli) Env() Env {
 	return c.env
 }
@@ -385,43 +395,62 @@ func (c *Cli) ServerVersion() types.Version {
 }
 
 func (c *Cli) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
+	logger.Get(ctx).Infof("Authenticating to image repo: %s", reference.Domain(ref))
+	encodedAuth, requestPrivilege, err := c.registryAuth(ctx, ref, "push")
+	if err != nil {
+		return nil, errors.Wrap(err, "ImagePush")
+	}
+
+	options := types.ImagePushOptions{
+		RegistryAuth:  encodedAuth,
+		PrivilegeFunc: requestPrivilege,
+	}
+
+	if reference.Domain(ref) == "" {
+		return nil, errors.Wrap(err, "ImagePush: no domain in container name")
+	}
+	logger.Get(ctx).Infof("Sending image data")
+	return c.Client.ImagePush(ctx, ref.String(), options)
+}
+
+// Asks the registry for the image's manifest, without pulling the image.
+func (c *Cli) DistributionInspect(ctx context.Context, ref reference.NamedTagged) (registrytypes.DistributionInspect, error) {
+	encodedAuth, _, err := c.registryAuth(ctx, ref, "pull")
+	if err != nil {
+		return registrytypes.DistributionInspect{}, errors.Wrap(err, "DistributionInspect")
+	}
+	return c.Client.DistributionInspect(ctx, ref.String(), encodedAuth)
+}
+
+// Resolves the credentials for the registry that hosts the given ref,
+// the same way the docker CLI does.
+func (c *Cli) registryAuth(ctx context.Context, ref reference.Named, action string) (string, types.RequestPrivilegeFunc, error) {
 	repoInfo, err := registry.ParseRepositoryInfo(ref)
 	if err != nil {
-		return nil, errors.Wrap(err, "ImagePush#ParseRepositoryInfo")
+		return "", nil, errors.Wrap(err, "ParseRepositoryInfo")
 	}
 
-	logger.Get(ctx).Infof("Authenticating to image repo: %s", repoInfo.Index.Name)
 	infoWriter := logger.Get(ctx).Writer(logger.InfoLvl)
 	cli, err := command.NewDockerCli(
 		command.WithCombinedStreams(infoWriter),
 		command.WithContentTrust(true),
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "ImagePush#NewDockerCli")
+		return "", nil, errors.Wrap(err, "NewDockerCli")
 	}
 
 	err = cli.Initialize(cliflags.NewClientOptions())
 	if err != nil {
-		return nil, errors.Wrap(err, "ImagePush#InitializeCLI")
+		return "", nil, errors.Wrap(err, "InitializeCLI")
 	}
 	authConfig := command.ResolveAuthConfig(ctx, cli, repoInfo.Index)
-	requestPrivilege := command.RegistryAuthenticationPrivilegedFunc(cli, repoInfo.Index, "push")
+	requestPrivilege := command.RegistryAuthenticationPrivilegedFunc(cli, repoInfo.Index, action)
 
 	encodedAuth, err := command.EncodeAuthToBase64(authConfig)
 	if err != nil {
-		return nil, errors.Wrap(err, "ImagePush#EncodeAuthToBase64")
-	}
-
-	options := types.ImagePushOptions{
-		RegistryAuth:  encodedAuth,
-		PrivilegeFunc: requestPrivilege,
+		return "", nil, errors.Wrap(err, "EncodeAuthToBase64")
 	}
-
-	if reference.Domain(ref) == "" {
-		return nil, errors.Wrap(err, "ImagePush: no domain in container name")
-	}
-	logger.Get(ctx).Infof("Sending image data")
-	return c.Client.ImagePush(ctx, ref.String(), options)
+	return encodedAuth, requestPrivilege, nil
 }
 
 func (c *Cli) ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error) {
@@ -469,8 +498,16 @@ func (c *Cli) ImageBuild(ctx context.Context, buildContext io.Reader, options Bu
 	opts.NetworkMode = options.Network
 	opts.CacheFrom = options.CacheFrom
 	opts.PullParent = options.PullParent
+	opts.Platform = options.Platform
 
-	opts.Labels = BuiltByTiltLabel // label all images as built by us
+	// label all images as built by us
+	opts.Labels = make(map[string]string, len(BuiltByTiltLabel)+len(options.Labels))
+	for k, v := range options.Labels {
+		opts.Labels[k] = v
+	}
+	for k, v := range BuiltByTiltLabel {
+		opts.Labels[k] = v
+	}
 
 	response, err := c.Client.ImageBuild(ctx, buildContext, opts)
 	if err != nil {
@@ -291,3 +291,25 @@ func TestProvideEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestIsLocalHost(t *testing.T) {
+	cases := []struct {
+		host     string
+		expected bool
+	}{
+		{"", true},
+		{"unix:///var/run/docker.sock", true},
+		{"npipe:////./pipe/docker_engine", true},
+		{"tcp://localhost:2375", true},
+		{"tcp://127.0.0.1:2375", true},
+		{"tcp://[::1]:2375", true},
+		{"tcp://192.168.99.100:2376", false},
+		{"ssh://me@remote-docker-host", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.host, func(t *testing.T) {
+			assert.Equal(t, c.expected, Env{Host: c.host}.IsLocalHost())
+		})
+	}
+}
@@ -3,6 +3,8 @@ package docker
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 
 	"github.com/blang/semver"
@@ -166,6 +168,35 @@ func isOldMinikube(ctx context.Context, minikubeClient k8s.MinikubeClient) bool
 	return minMinikubeVersionBuildkit.GTE(vParsed)
 }
 
+// Whether the Docker daemon runs on this machine, so that the ports
+// its containers publish are bound here.
+//
+// A unix socket or named pipe is local, and so is a tcp host on a loopback
+// address. We assume anything else (including ssh://) is remote.
+func (e Env) IsLocalHost() bool {
+	if isDefaultHost(e) {
+		return true
+	}
+
+	u, err := url.Parse(e.Host)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "unix", "npipe":
+		return true
+	case "tcp", "http", "https":
+		host := u.Hostname()
+		if host == "localhost" {
+			return true
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	}
+	return false
+}
+
 func isDefaultHost(e Env) bool {
 	if e.Host == "" {
 		return true
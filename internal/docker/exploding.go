@@ -7,6 +7,7 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	registrytypes "github.com/docker/docker/api/types/registry"
 
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -26,6 +27,9 @@ func newExplodingClient(err error) explodingClient {
 
 func (c explodingClient) SetOrchestrator(orc model.Orchestrator) {
 }
+func (c explodingClient) ForOrchestrator(orc model.Orchestrator) Client {
+	return c
+}
 func (c explodingClient) CheckConnected() error {
 	return c.err
 }
@@ -53,6 +57,9 @@ func (c explodingClient) ExecInContainer(ctx context.Context, cID container.ID,
 func (c explodingClient) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
 	return nil, c.err
 }
+func (c explodingClient) DistributionInspect(ctx context.Context, ref reference.NamedTagged) (registrytypes.DistributionInspect, error) {
+	return registrytypes.DistributionInspect{}, c.err
+}
 func (c explodingClient) ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error) {
 	return types.ImageBuildResponse{}, c.err
 }
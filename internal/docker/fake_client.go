@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/docker/go-units"
@@ -15,6 +16,7 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	registrytypes "github.com/docker/docker/api/types/registry"
 
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -82,6 +84,9 @@ type ExecCall struct {
 }
 
 type FakeClient struct {
+	// Guards the build, push and tag fields, since images may build in parallel.
+	mu sync.Mutex
+
 	FakeEnv Env
 
 	PushCount   int
@@ -89,6 +94,9 @@ type FakeClient struct {
 	PushOptions types.ImagePushOptions
 	PushOutput  string
 
+	DistributionInspectCount int
+	DistributionInspectImage string
+
 	BuildCount        int
 	BuildOptions      BuildOptions
 	BuildContext      *bytes.Buffer
@@ -146,6 +154,9 @@ func NewFakeClient() *FakeClient {
 func (c *FakeClient) SetOrchestrator(orc model.Orchestrator) {
 	c.Orchestrator = orc
 }
+func (c *FakeClient) ForOrchestrator(orc model.Orchestrator) Client {
+	return c
+}
 func (c *FakeClient) CheckConnected() error {
 	return c.CheckConnectedErr
 }
@@ -229,12 +240,28 @@ func (c *FakeClient) ExecInContainer(ctx context.Context, cID container.ID, cmd
 }
 
 func (c *FakeClient) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.PushCount++
 	c.PushImage = ref.String()
 	return NewFakeDockerResponse(c.PushOutput), nil
 }
 
+// The registry has a manifest for the image if it's been pre-loaded into Images.
+func (c *FakeClient) DistributionInspect(ctx context.Context, ref reference.NamedTagged) (registrytypes.DistributionInspect, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.DistributionInspectCount++
+	c.DistributionInspectImage = ref.String()
+	if _, ok := c.Images[ref.String()]; !ok {
+		return registrytypes.DistributionInspect{}, newNotFoundErrorf("fakeClient.Images key: %s", ref.String())
+	}
+	return registrytypes.DistributionInspect{}, nil
+}
+
 func (c *FakeClient) ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.BuildCount++
 	c.BuildOptions = options
 
@@ -256,6 +283,8 @@ func (c *FakeClient) ImageBuild(ctx context.Context, buildContext io.Reader, opt
 }
 
 func (c *FakeClient) ImageTag(ctx context.Context, source, target string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.TagCount++
 	c.TagSource = source
 	c.TagTarget = target
@@ -14,5 +14,7 @@ type BuildOptions struct {
 	CacheFrom          []string
 	PullParent         bool
 	ExtraTags          []string
+	Labels             map[string]string
+	Platform           string
 	ForceLegacyBuilder bool
 }
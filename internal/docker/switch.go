@@ -8,6 +8,7 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	registrytypes "github.com/docker/docker/api/types/registry"
 
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -45,6 +46,19 @@ func (c *switchCli) SetOrchestrator(orc model.Orchestrator) {
 	defer c.mu.Unlock()
 	c.orc = orc
 }
+
+// A Tiltfile may have both K8s and Docker Compose resources, so builders that
+// only deal with one of them need a client that won't switch out from under them.
+func (c *switchCli) ForOrchestrator(orc model.Orchestrator) Client {
+	switch orc {
+	case model.OrchestratorK8s:
+		return c.clusterCli
+	case model.OrchestratorDC:
+		return c.localCli
+	default:
+		return c
+	}
+}
 func (c *switchCli) CheckConnected() error {
 	return c.client().CheckConnected()
 }
@@ -72,6 +86,9 @@ func (c *switchCli) ExecInContainer(ctx context.Context, cID container.ID, cmd m
 func (c *switchCli) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
 	return c.client().ImagePush(ctx, ref)
 }
+func (c *switchCli) DistributionInspect(ctx context.Context, ref reference.NamedTagged) (registrytypes.DistributionInspect, error) {
+	return c.client().DistributionInspect(ctx, ref)
+}
 func (c *switchCli) ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error) {
 	return c.client().ImageBuild(ctx, buildContext, options)
 }
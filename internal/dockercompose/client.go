@@ -22,6 +22,9 @@ import (
 type DockerComposeClient interface {
 	Up(ctx context.Context, configPaths []string, serviceName model.TargetName, shouldBuild bool, stdout, stderr io.Writer) error
 	Down(ctx context.Context, configPaths []string, stdout, stderr io.Writer) error
+	Rm(ctx context.Context, configPaths []string, serviceName model.TargetName, stdout, stderr io.Writer) error
+	Exec(ctx context.Context, configPaths []string, serviceName model.TargetName, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error
+	Restart(ctx context.Context, configPaths []string, serviceName model.TargetName, stdout, stderr io.Writer) error
 	StreamLogs(ctx context.Context, configPaths []string, serviceName model.TargetName) (io.ReadCloser, error)
 	StreamEvents(ctx context.Context, configPaths []string) (<-chan string, error)
 	Config(ctx context.Context, configPaths []string) (string, error)
@@ -120,6 +123,66 @@ func (c *cmdDCClient) Down(ctx context.Context, configPaths []string, stdout, st
 	return nil
 }
 
+// Stops and removes the containers of a single service.
+func (c *cmdDCClient) Rm(ctx context.Context, configPaths []string, serviceName model.TargetName, stdout, stderr io.Writer) error {
+	// Like down, don't run rm in parallel with up.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var args []string
+	if logger.Get(ctx).Level().ShouldDisplay(logger.VerboseLvl) {
+		args = []string{"--verbose"}
+	}
+	for _, config := range configPaths {
+		args = append(args, "-f", config)
+	}
+
+	args = append(args, "rm", "--stop", "--force", serviceName.String())
+	cmd := c.dcCommand(ctx, args)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return FormatError(cmd, nil, err)
+	}
+
+	return nil
+}
+
+// Runs a command in the service's container.
+//
+// Goes through docker-compose (rather than the Docker API) so that it works
+// against whatever daemon or context docker-compose is configured for.
+func (c *cmdDCClient) Exec(ctx context.Context, configPaths []string, serviceName model.TargetName, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var args []string
+	for _, config := range configPaths {
+		args = append(args, "-f", config)
+	}
+
+	// -T disables pseudo-tty allocation, so that we can pipe to stdin.
+	args = append(args, "exec", "-T", serviceName.String())
+	args = append(args, cmd...)
+	execCmd := c.dcCommand(ctx, args)
+	execCmd.Stdin = stdin
+	execCmd.Stdout = stdout
+	execCmd.Stderr = stderr
+	return execCmd.Run()
+}
+
+func (c *cmdDCClient) Restart(ctx context.Context, configPaths []string, serviceName model.TargetName, stdout, stderr io.Writer) error {
+	var args []string
+	for _, config := range configPaths {
+		args = append(args, "-f", config)
+	}
+
+	args = append(args, "restart", serviceName.String())
+	cmd := c.dcCommand(ctx, args)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return FormatError(cmd, nil, cmd.Run())
+}
+
 func (c *cmdDCClient) StreamLogs(ctx context.Context, configPaths []string, serviceName model.TargetName) (io.ReadCloser, error) {
 	// TODO(maia): --since time
 	// (may need to implement with `docker log <cID>` instead since `d-c log` doesn't support `--since`
@@ -99,7 +99,17 @@ func (v *Volumes) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 type Ports []Port
 type Port struct {
+	// The port on the host machine.
 	Published int `yaml:"published"`
+
+	// The port inside the container.
+	Target int `yaml:"target"`
+
+	// The host interface the port is published on. Empty means all interfaces.
+	HostIP string `yaml:"host_ip"`
+
+	// "tcp" or "udp". Empty means "tcp".
+	Protocol string `yaml:"protocol"`
 }
 
 func (p *Ports) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -119,18 +129,29 @@ func (p *Ports) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		// is always included.
 		switch portSpec := portSpec.(type) {
 		case string:
-			withoutProtocol := strings.Split(portSpec, "/")[0]
-			parts := strings.Split(withoutProtocol, ":")
+			protocolParts := strings.Split(portSpec, "/")
+			protocol := ""
+			if len(protocolParts) > 1 {
+				protocol = protocolParts[1]
+			}
+			parts := strings.Split(protocolParts[0], ":")
+			hostIP := ""
 			publishedPart := parts[0]
+			targetPart := parts[len(parts)-1]
 			if len(parts) == 3 {
 				// For "127.0.0.1:3000:3000"
+				hostIP = parts[0]
 				publishedPart = parts[1]
 			}
 			port, err := strconv.Atoi(publishedPart)
 			if err != nil {
 				continue
 			}
-			*p = append(*p, Port{Published: port})
+			target, err := strconv.Atoi(targetPart)
+			if err != nil {
+				continue
+			}
+			*p = append(*p, Port{Published: port, Target: target, HostIP: hostIP, Protocol: protocol})
 		case map[interface{}]interface{}:
 			var portStruct Port
 			b, err := yaml.Marshal(portSpec) // so we can unmarshal it again
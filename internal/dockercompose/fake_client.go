@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sync"
 	"testing"
 
 	"github.com/tilt-dev/tilt/internal/container"
@@ -21,8 +23,35 @@ type FakeDCClient struct {
 	ConfigOutput      string
 	ServicesOutput    string
 
-	UpCalls   []UpCall
-	DownError error
+	UpCalls      []UpCall
+	DownError    error
+	RmCalls      []RmCall
+	ExecCalls    []ExecCall
+	ExecErrors   []error // returned by successive calls to Exec
+	RestartCalls []RestartCall
+
+	mu                sync.Mutex
+	streamEventsCalls int
+}
+
+// Represents a single call to Restart
+type RestartCall struct {
+	PathToConfig []string
+	ServiceName  model.TargetName
+}
+
+// Represents a single call to Exec
+type ExecCall struct {
+	PathToConfig []string
+	ServiceName  model.TargetName
+	Cmd          []string
+	Stdin        []byte
+}
+
+// Represents a single call to Rm
+type RmCall struct {
+	PathToConfig []string
+	ServiceName  model.TargetName
 }
 
 // Represents a single call to Up
@@ -56,6 +85,35 @@ func (c *FakeDCClient) Down(ctx context.Context, configPaths []string, stdout, s
 	return nil
 }
 
+func (c *FakeDCClient) Rm(ctx context.Context, configPaths []string, serviceName model.TargetName, stdout, stderr io.Writer) error {
+	c.RmCalls = append(c.RmCalls, RmCall{configPaths, serviceName})
+	return nil
+}
+
+func (c *FakeDCClient) Exec(ctx context.Context, configPaths []string, serviceName model.TargetName, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	call := ExecCall{PathToConfig: configPaths, ServiceName: serviceName, Cmd: cmd}
+	if stdin != nil {
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		call.Stdin = b
+	}
+	c.ExecCalls = append(c.ExecCalls, call)
+
+	if len(c.ExecErrors) > 0 {
+		err := c.ExecErrors[0]
+		c.ExecErrors = c.ExecErrors[1:]
+		return err
+	}
+	return nil
+}
+
+func (c *FakeDCClient) Restart(ctx context.Context, configPaths []string, serviceName model.TargetName, stdout, stderr io.Writer) error {
+	c.RestartCalls = append(c.RestartCalls, RestartCall{configPaths, serviceName})
+	return nil
+}
+
 func (c *FakeDCClient) StreamLogs(ctx context.Context, configPaths []string, serviceName model.TargetName) (io.ReadCloser, error) {
 	output := c.RunLogOutput[serviceName]
 	reader, writer := io.Pipe()
@@ -79,6 +137,10 @@ func (c *FakeDCClient) StreamLogs(ctx context.Context, configPaths []string, ser
 }
 
 func (c *FakeDCClient) StreamEvents(ctx context.Context, configPaths []string) (<-chan string, error) {
+	c.mu.Lock()
+	c.streamEventsCalls++
+	c.mu.Unlock()
+
 	events := make(chan string, 10)
 	go func() {
 		for {
@@ -99,6 +161,13 @@ func (c *FakeDCClient) StreamEvents(ctx context.Context, configPaths []string) (
 	return events, nil
 }
 
+// The number of times StreamEvents has been called.
+func (c *FakeDCClient) StreamEventsCallCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.streamEventsCalls
+}
+
 func (c *FakeDCClient) SendEvent(evt Event) error {
 	j, err := json.Marshal(evt)
 	if err != nil {
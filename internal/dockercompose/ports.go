@@ -0,0 +1,72 @@
+package dockercompose
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+func (p Port) String() string {
+	s := strconv.Itoa(p.Published)
+	if p.HostIP != "" {
+		s = net.JoinHostPort(p.HostIP, s)
+	}
+	if p.Protocol != "" && p.Protocol != "tcp" {
+		s = fmt.Sprintf("%s/%s", s, p.Protocol)
+	}
+	return s
+}
+
+// Whether the two ports would try to bind the same address on the host.
+func (p Port) ConflictsWith(other Port) bool {
+	if p.Published == 0 || p.Published != other.Published {
+		return false
+	}
+	if p.protocol() != other.protocol() {
+		return false
+	}
+	return p.allInterfaces() || other.allInterfaces() || p.HostIP == other.HostIP
+}
+
+func (p Port) protocol() string {
+	if p.Protocol == "" {
+		return "tcp"
+	}
+	return p.Protocol
+}
+
+func (p Port) allInterfaces() bool {
+	return p.HostIP == "" || p.HostIP == "0.0.0.0" || p.HostIP == "::"
+}
+
+// Returns the published ports that something on this machine is already bound to.
+//
+// docker-compose reports these as an opaque error from the Docker daemon
+// halfway through `up`, so we check them ourselves first.
+func UnavailablePorts(ports []Port) []Port {
+	var result []Port
+	for _, p := range ports {
+		if p.Published == 0 {
+			continue
+		}
+
+		addr := net.JoinHostPort(p.HostIP, strconv.Itoa(p.Published))
+		if p.protocol() == "udp" {
+			conn, err := net.ListenPacket("udp", addr)
+			if err != nil {
+				result = append(result, p)
+				continue
+			}
+			_ = conn.Close()
+			continue
+		}
+
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			result = append(result, p)
+			continue
+		}
+		_ = l.Close()
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package dockercompose
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParsePorts(t *testing.T) {
+	var conf Config
+	err := yaml.Unmarshal([]byte(`services:
+  foo:
+    ports:
+    - "8000:80"
+    - "127.0.0.1:8001:81/udp"
+    - published: 8002
+      target: 82
+      protocol: tcp
+`), &conf)
+	require.NoError(t, err)
+
+	assert.Equal(t, Ports{
+		{Published: 8000, Target: 80},
+		{Published: 8001, Target: 81, HostIP: "127.0.0.1", Protocol: "udp"},
+		{Published: 8002, Target: 82, Protocol: "tcp"},
+	}, conf.Services["foo"].Ports)
+}
+
+func TestPortConflicts(t *testing.T) {
+	assert.True(t, Port{Published: 8000}.ConflictsWith(Port{Published: 8000, HostIP: "127.0.0.1"}))
+	assert.False(t, Port{Published: 8000}.ConflictsWith(Port{Published: 8001}))
+	assert.False(t, Port{Published: 8000}.ConflictsWith(Port{Published: 8000, Protocol: "udp"}))
+	assert.False(t, Port{Published: 8000, HostIP: "127.0.0.1"}.ConflictsWith(Port{Published: 8000, HostIP: "127.0.0.2"}))
+}
+
+func TestUnavailablePorts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+
+	used := Port{Published: l.Addr().(*net.TCPAddr).Port, HostIP: "127.0.0.1"}
+	free := freePort(t)
+
+	assert.Equal(t, []Port{used}, UnavailablePorts([]Port{used, free, {Target: 80}}))
+}
+
+func freePort(t *testing.T) Port {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+	return Port{Published: port, HostIP: "127.0.0.1"}
+}
@@ -15,17 +15,9 @@ func ReadConfigAndServiceNames(ctx context.Context, dcc DockerComposeClient,
 	g, ctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
-
-		configOut, err := dcc.Config(ctx, configPaths)
-		if err != nil {
-			return err
-		}
-
-		err = yaml.Unmarshal([]byte(configOut), &conf)
-		if err != nil {
-			return err
-		}
-		return nil
+		var err error
+		conf, err = ReadConfig(ctx, dcc, configPaths)
+		return err
 	})
 
 	g.Go(func() error {
@@ -41,6 +33,20 @@ func ReadConfigAndServiceNames(ctx context.Context, dcc DockerComposeClient,
 	return conf, svcNames, err
 }
 
+func ReadConfig(ctx context.Context, dcc DockerComposeClient, configPaths []string) (Config, error) {
+	var conf Config
+	configOut, err := dcc.Config(ctx, configPaths)
+	if err != nil {
+		return conf, err
+	}
+
+	err = yaml.Unmarshal([]byte(configOut), &conf)
+	if err != nil {
+		return conf, err
+	}
+	return conf, nil
+}
+
 func serviceNames(ctx context.Context, dcc DockerComposeClient, configPaths []string) ([]string, error) {
 	servicesText, err := dcc.Services(ctx, configPaths)
 	if err != nil {
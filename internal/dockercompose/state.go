@@ -31,12 +31,26 @@ type State struct {
 
 	LastReadyTime time.Time
 
+	// Whether the resource has a Tilt-side readiness probe (see
+	// dc_resource(readiness_probe=...)), and its most recent result.
+	HasReadinessProbe  bool
+	ProbeReady         bool
+	LastProbeReadyTime time.Time
+
 	SpanID model.LogSpanID
 }
 
 func (State) RuntimeState() {}
 
 func (s State) RuntimeStatus() v1alpha1.RuntimeStatus {
+	status := s.containerRuntimeStatus()
+	if status == v1alpha1.RuntimeStatusOK && s.HasReadinessProbe && !s.ProbeReady {
+		return v1alpha1.RuntimeStatusPending
+	}
+	return status
+}
+
+func (s State) containerRuntimeStatus() v1alpha1.RuntimeStatus {
 	if s.ContainerState.Error != "" || s.ContainerState.ExitCode != 0 {
 		return v1alpha1.RuntimeStatusError
 	}
@@ -92,6 +106,22 @@ func (s State) WithLastReadyTime(time time.Time) State {
 	return s
 }
 
+func (s State) WithReadinessProbe(hasProbe bool) State {
+	s.HasReadinessProbe = hasProbe
+	return s
+}
+
+func (s State) WithProbeReady(ready bool, t time.Time) State {
+	s.ProbeReady = ready
+	if ready {
+		s.LastProbeReadyTime = t
+	}
+	return s
+}
+
 func (s State) HasEverBeenReadyOrSucceeded() bool {
+	if s.HasReadinessProbe && s.LastProbeReadyTime.IsZero() {
+		return false
+	}
 	return !s.LastReadyTime.IsZero()
 }
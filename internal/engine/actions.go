@@ -11,6 +11,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/token"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
 func NewErrorAction(err error) store.ErrorAction {
@@ -31,6 +32,9 @@ type InitAction struct {
 	CloudAddress string
 	Token        token.Token
 	TerminalMode store.TerminalMode
+
+	// If non-nil, all logs are also written to this on-disk archive.
+	LogArchive *logstore.Archive
 }
 
 func (InitAction) Action() {}
@@ -24,6 +24,8 @@ import (
 	"github.com/tilt-dev/wmclient/pkg/dirs"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/ospath"
@@ -490,6 +492,54 @@ func TestDockerComposeLiveUpdate(t *testing.T) {
 	f.assertContainerRestarts(1)
 }
 
+func TestDockerComposeBuiltServiceLiveUpdate(t *testing.T) {
+	f := newBDFixture(t, k8s.EnvGKE, container.RuntimeDocker)
+	defer f.TearDown()
+
+	lu := NewSanchoLiveUpdate(f)
+	manifest := NewSanchoDCBuiltManifest(f, lu)
+	targets := buildcontrol.BuildTargets(manifest)
+	changed := f.WriteFile("a.txt", "a")
+	bs := f.dcBuiltStateSet(manifest, changed)
+
+	_, err := f.bd.BuildAndDeploy(f.ctx, f.st, targets, bs)
+	require.NoError(t, err)
+
+	// Files are synced and commands run through docker-compose, not the Docker API.
+	assert.Equal(t, 0, f.docker.BuildCount)
+	assert.Equal(t, 0, f.docker.CopyCount)
+	assert.Equal(t, 0, len(f.docker.ExecCalls))
+	assert.Empty(t, f.dcCli.UpCalls)
+	if assert.Len(t, f.dcCli.ExecCalls, 2) {
+		assert.Equal(t, []string{"tar", "-C", "/", "-x", "-f", "-"}, f.dcCli.ExecCalls[0].Cmd)
+		assert.Equal(t, []string{"go", "install", "github.com/tilt-dev/sancho"}, f.dcCli.ExecCalls[1].Cmd)
+		assert.Equal(t, model.TargetName("sancho"), f.dcCli.ExecCalls[1].ServiceName)
+	}
+	assert.Len(t, f.dcCli.RestartCalls, 1)
+}
+
+func TestDockerComposeBuiltServiceFallBackOn(t *testing.T) {
+	f := newBDFixture(t, k8s.EnvGKE, container.RuntimeDocker)
+	defer f.TearDown()
+
+	lu := assembleLiveUpdate(
+		[]model.LiveUpdateSyncStep{{Source: f.Path(), Dest: "/go/src/github.com/tilt-dev/sancho"}},
+		nil, false, []string{f.JoinPath("requirements.txt")}, f)
+	manifest := NewSanchoDCBuiltManifest(f, lu)
+	targets := buildcontrol.BuildTargets(manifest)
+	changed := f.WriteFile("requirements.txt", "flask")
+	bs := f.dcBuiltStateSet(manifest, changed)
+
+	_, err := f.bd.BuildAndDeploy(f.ctx, f.st, targets, bs)
+	require.NoError(t, err)
+
+	// Falls back to `docker-compose up --build` for just this service.
+	assert.Empty(t, f.dcCli.ExecCalls)
+	assert.Equal(t, []dockercompose.UpCall{
+		{PathToConfig: manifest.DockerComposeTarget().ConfigPaths, ServiceName: "sancho", ShouldBuild: true},
+	}, f.dcCli.UpCalls)
+}
+
 func TestReturnLastUnexpectedError(t *testing.T) {
 	f := newBDFixture(t, k8s.EnvDockerDesktop, container.RuntimeDocker)
 	defer f.TearDown()
@@ -787,14 +837,15 @@ func newBDFixtureWithUpdateMode(t *testing.T, env k8s.Env, runtime container.Run
 	k8s.Runtime = runtime
 	mode := buildcontrol.UpdateModeFlag(um)
 	dcc := dockercompose.NewFakeDockerComposeClient(t, ctx)
+	st := store.NewTestingStore()
+	dcsr := dockercomposeservice.NewReconciler(ctx, dcc, dockerClient, st)
+	dcsr.SetClient(fake.NewTiltClient())
 	kl := &fakeKINDLoader{}
-	bd, err := provideFakeBuildAndDeployer(ctx, dockerClient, k8s, dir, env, mode, dcc, fakeClock{now: time.Unix(1551202573, 0)}, kl, ta)
+	bd, err := provideFakeBuildAndDeployer(ctx, dockerClient, k8s, dir, env, mode, dcc, dcsr, fakeClock{now: time.Unix(1551202573, 0)}, kl, ta)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	st := store.NewTestingStore()
-
 	return &bdFixture{
 		TempDirFixture: f,
 		ctx:            ctx,
@@ -887,6 +938,15 @@ func (f *bdFixture) createBuildStateSet(manifest model.Manifest, changedFiles []
 	return bs
 }
 
+// A build state where docker-compose has already built and started the service.
+func (f *bdFixture) dcBuiltStateSet(manifest model.Manifest, changedFiles ...string) store.BuildStateSet {
+	dcTarget := manifest.DockerComposeTarget()
+	result := store.NewDockerComposeDeployResult(dcTarget.ID(), "dc-container-id", nil)
+	state := store.NewBuildState(result, changedFiles, nil).
+		WithRunningContainers([]store.ContainerInfo{{ContainerID: "dc-container-id"}})
+	return store.BuildStateSet{dcTarget.ID(): state}
+}
+
 func resultToStateSet(resultSet store.BuildResultSet, files []string, cInfo store.ContainerInfo) store.BuildStateSet {
 	stateSet := store.BuildStateSet{}
 	for id, result := range resultSet {
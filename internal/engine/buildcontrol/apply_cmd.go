@@ -0,0 +1,79 @@
+package buildcontrol
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The max number of bytes of stdout we echo back to the user
+// when the apply command gives us something we can't parse.
+const applyCmdStdoutPreviewLen = 2000
+
+// RunKubernetesApplyCmd runs a custom apply or delete command from a KubernetesApplySpec,
+// streaming the command's output to stdout and stderr.
+func RunKubernetesApplyCmd(ctx context.Context, applyCmd *v1alpha1.KubernetesApplyCmd, stdout io.Writer, stderr io.Writer) error {
+	if applyCmd == nil || len(applyCmd.Args) == 0 {
+		return fmt.Errorf("empty command")
+	}
+
+	cmd := exec.CommandContext(ctx, applyCmd.Args[0], applyCmd.Args[1:]...)
+	cmd.Dir = applyCmd.Dir
+	cmd.Env = append(logger.DefaultEnv(ctx), applyCmd.Env...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("command %q failed: %v", model.Cmd{Argv: applyCmd.Args}.String(), err)
+	}
+	return nil
+}
+
+// Runs the ApplyCmd of a K8sTarget, and parses the objects it deployed from
+// its stdout.
+//
+// The command's stderr goes to the build log. Its stdout must be the YAML of the
+// applied objects (e.g., as printed by `kubectl apply -o yaml`), so that
+// Tilt can find the pods they create.
+func runApplyCmdAndParseEntities(ctx context.Context, applyCmd *v1alpha1.KubernetesApplyCmd) ([]k8s.K8sEntity, error) {
+	l := logger.Get(ctx)
+	l.Infof("Running cmd: %s", model.Cmd{Argv: applyCmd.Args}.String())
+
+	stdout := &bytes.Buffer{}
+	err := RunKubernetesApplyCmd(ctx, applyCmd, stdout, l.Writer(logger.InfoLvl))
+	if err != nil {
+		return nil, fmt.Errorf("apply %v", err)
+	}
+
+	entities, err := k8s.ParseYAMLFromString(stdout.String())
+	if err != nil {
+		return nil, fmt.Errorf("apply command returned malformed YAML: %v\nstdout:\n%s\n",
+			err, truncateApplyCmdStdout(stdout.String()))
+	}
+
+	for _, e := range entities {
+		if e.UID() == "" {
+			return nil, fmt.Errorf("apply command returned an object without a UID: %s\n"+
+				"The apply command must print the YAML of the objects it deployed "+
+				"(e.g., with `kubectl apply -o yaml`)", e.Name())
+		}
+	}
+	return entities, nil
+}
+
+func truncateApplyCmdStdout(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > applyCmdStdoutPreviewLen {
+		return s[:applyCmdStdoutPreviewLen] + "…"
+	}
+	return s
+}
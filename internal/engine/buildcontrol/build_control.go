@@ -18,6 +18,9 @@ func NextTargetToBuild(state store.EngineState) (*store.ManifestTarget, HoldSet)
 	holds := HoldSet{}
 	targets := state.Targets()
 
+	// Resources that the user turned off never build, no matter what else is going on.
+	HoldDisabledTargets(state, targets, holds)
+
 	// Don't build anything if there are pending config file changes.
 	// We want the Tiltfile to re-run first.
 	tiltfileHasPendingChanges, _ := state.TiltfileState.HasPendingChanges()
@@ -69,7 +72,7 @@ func NextTargetToBuild(state store.EngineState) (*store.ManifestTarget, HoldSet)
 	if len(state.TriggerQueue) > 0 {
 		mn := state.TriggerQueue[0]
 		mt, ok := state.ManifestTargets[mn]
-		if ok {
+		if ok && !state.IsResourceDisabled(mn) {
 			return mt, holds
 		}
 	}
@@ -151,6 +154,14 @@ func canReuseImageTargetHeuristic(spec model.TargetSpec, status store.BuildStatu
 	return false
 }
 
+func HoldDisabledTargets(state store.EngineState, mts []*store.ManifestTarget, holds HoldSet) {
+	for _, mt := range mts {
+		if state.IsResourceDisabled(mt.Manifest.Name) {
+			holds.AddHold(mt, store.HoldDisabled)
+		}
+	}
+}
+
 func HoldTargetsWithBuildingComponents(mts []*store.ManifestTarget, holds HoldSet) {
 	building := make(map[model.TargetID]bool)
 
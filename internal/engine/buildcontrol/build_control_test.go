@@ -38,6 +38,27 @@ func TestNextTargetToBuildDoesntReturnCurrentlyBuildingTarget(t *testing.T) {
 	f.assertNoTargetNextToBuild()
 }
 
+func TestHoldDisabled(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	f.upsertK8sManifest("k8s1")
+	f.upsertLocalManifest("local1")
+	f.st.DisableResource("local1")
+
+	f.assertNextTargetToBuild("k8s1")
+	f.assertHold("local1", store.HoldDisabled)
+
+	// A manual trigger doesn't get around the hold.
+	f.st.TriggerQueue = append(f.st.TriggerQueue, "local1")
+	f.st.DisableResource("k8s1")
+	f.assertNoTargetNextToBuild()
+	f.assertHold("k8s1", store.HoldDisabled)
+
+	f.st.EnableResource("local1")
+	f.assertNextTargetToBuild("local1")
+}
+
 func TestCurrentlyBuildingK8sResourceDisablesLocalScheduling(t *testing.T) {
 	f := newTestFixture(t)
 	defer f.TearDown()
@@ -122,6 +143,38 @@ func TestLocalDependsOnNonWorkloadK8s(t *testing.T) {
 	_ = k8s2
 }
 
+func TestLocalDependsOnK8sWithReadinessProbe(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	f.upsertLocalManifest("local1", withResourceDeps("k8s1"))
+	k8s1 := f.upsertK8sManifest("k8s1", withK8sPodReadiness(model.PodReadinessIgnore))
+
+	f.assertNextTargetToBuild("k8s1")
+
+	k8s1.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now(),
+		FinishTime: time.Now(),
+	})
+	k8s1.State.RuntimeState = store.K8sRuntimeState{
+		PodReadinessMode:            model.PodReadinessIgnore,
+		HasEverDeployedSuccessfully: true,
+		HasReadinessProbe:           true,
+	}
+
+	// Wait for the probe to succeed.
+	f.assertNoTargetNextToBuild()
+
+	k8s1.State.RuntimeState = store.K8sRuntimeState{
+		PodReadinessMode:            model.PodReadinessIgnore,
+		HasEverDeployedSuccessfully: true,
+		HasReadinessProbe:           true,
+		ProbeReady:                  true,
+		LastProbeReadyTime:          time.Now(),
+	}
+	f.assertNextTargetToBuild("local1")
+}
+
 func TestCurrentlyBuildingLocalResourceDisablesK8sScheduling(t *testing.T) {
 	f := newTestFixture(t)
 	defer f.TearDown()
@@ -7,20 +7,23 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
 	"github.com/tilt-dev/tilt/internal/docker"
-	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type DockerComposeBuildAndDeployer struct {
-	dcc   dockercompose.DockerComposeClient
+	dcsr  *dockercomposeservice.Reconciler
 	dc    docker.Client
 	ib    *ImageBuilder
 	clock build.Clock
@@ -28,12 +31,14 @@ type DockerComposeBuildAndDeployer struct {
 
 var _ BuildAndDeployer = &DockerComposeBuildAndDeployer{}
 
-func NewDockerComposeBuildAndDeployer(dcc dockercompose.DockerComposeClient, dc docker.Client,
+// Docker Compose services always run against the local Docker daemon,
+// even if the Tiltfile also has K8s resources that build in-cluster.
+func NewDockerComposeBuildAndDeployer(dcsr *dockercomposeservice.Reconciler, dc docker.Client,
 	ib *ImageBuilder, c build.Clock) *DockerComposeBuildAndDeployer {
 	return &DockerComposeBuildAndDeployer{
-		dcc:   dcc,
-		dc:    dc,
-		ib:    ib,
+		dcsr:  dcsr,
+		dc:    dc.ForOrchestrator(model.OrchestratorDC),
+		ib:    ib.ForOrchestrator(model.OrchestratorDC),
 		clock: c,
 	}
 }
@@ -100,7 +105,7 @@ func (bd *DockerComposeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 	}
 
 	iTargetMap := model.ImageTargetsByID(iTargets)
-	err = q.RunBuilds(func(target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
+	err = q.RunBuilds(ctx, func(ctx context.Context, target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
 		iTarget, ok := target.(model.ImageTarget)
 		if !ok {
 			return nil, fmt.Errorf("Not an image target: %T", target)
@@ -116,7 +121,7 @@ func (bd *DockerComposeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 		// NOTE(maia): we assume that this func takes one DC target and up to one image target
 		// corresponding to that service. If this func ever supports specs for more than one
 		// service at once, we'll have to match up image build results to DC target by ref.
-		refs, err := bd.ib.Build(ctx, iTarget, ps)
+		refs, err := bd.ib.Build(ctx, iTarget, ps, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -134,21 +139,13 @@ func (bd *DockerComposeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 		return newResults, err
 	}
 
-	stdout := logger.Get(ctx).Writer(logger.InfoLvl)
-	stderr := logger.Get(ctx).Writer(logger.InfoLvl)
-	err = bd.dcc.Up(ctx, dcTarget.ConfigPaths, dcTarget.Name, !haveImage, stdout, stderr)
-	if err != nil {
-		return newResults, err
-	}
-
-	// NOTE(dmiller): right now we only need this the first time. In the future
-	// it might be worth it to move this somewhere else
-	cid, err := bd.dcc.ContainerID(ctx, dcTarget.ConfigPaths, dcTarget.Name)
+	status, err := bd.dcsr.ForceApply(ctx, DockerComposeServiceObject(dcTarget), !haveImage)
 	if err != nil {
 		return newResults, err
 	}
 
 	// grab the initial container state
+	cid := container.ID(status.ContainerID)
 	containerJSON, err := bd.dc.ContainerInspect(ctx, string(cid))
 	if err != nil {
 		logger.Get(ctx).Debugf("Error inspecting container %s: %v", cid, err)
@@ -163,6 +160,26 @@ func (bd *DockerComposeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 	return newResults, nil
 }
 
+// The API object that runs the service for this target.
+func DockerComposeServiceObject(dcTarget model.DockerComposeTarget) *v1alpha1.DockerComposeService {
+	mn := model.ManifestName(dcTarget.Name)
+	return &v1alpha1.DockerComposeService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: dcTarget.Name.String(),
+			Annotations: map[string]string{
+				v1alpha1.AnnotationManifest: mn.String(),
+				v1alpha1.AnnotationSpanID:   string(runtimelog.SpanIDForDCService(mn)),
+			},
+		},
+		Spec: v1alpha1.DockerComposeServiceSpec{
+			Service: dcTarget.Name.String(),
+			Project: v1alpha1.DockerComposeProject{
+				ConfigPaths: dcTarget.ConfigPaths,
+			},
+		},
+	}
+}
+
 // tagWithExpected tags the given ref as whatever Docker Compose expects, i.e. as
 // the `image` value given in docker-compose.yaml. (If DC yaml specifies an image
 // with a tag, use that name + tag; otherwise, tag as latest.)
@@ -7,16 +7,22 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tilt-dev/wmclient/pkg/dirs"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -43,6 +49,15 @@ func TestDockerComposeTargetBuilt(t *testing.T) {
 
 	dRes := res[dcTarg.ID()].(store.DockerComposeBuildResult)
 	assert.Equal(t, expectedContainerID, dRes.DockerComposeContainerID.String())
+
+	var obj v1alpha1.DockerComposeService
+	err = f.ctrlClient.Get(f.ctx, types.NamespacedName{Name: "fe"}, &obj)
+	require.NoError(t, err)
+	assert.Equal(t, "fe", obj.Spec.Service)
+	assert.Equal(t, dcTarg.ConfigPaths, obj.Spec.Project.ConfigPaths)
+	assert.Equal(t, "fe", obj.Annotations[v1alpha1.AnnotationManifest])
+	assert.Equal(t, expectedContainerID, obj.Status.ContainerID)
+	assert.True(t, obj.Status.ContainerState.Running)
 }
 
 func TestTiltBuildsImage(t *testing.T) {
@@ -173,6 +188,8 @@ type dcbdFixture struct {
 	dCli  *docker.FakeClient
 	dcbad *DockerComposeBuildAndDeployer
 	st    *store.TestingStore
+
+	ctrlClient ctrlclient.Client
 }
 
 func newDCBDFixture(t *testing.T) *dcbdFixture {
@@ -188,11 +205,15 @@ func newDCBDFixture(t *testing.T) *dcbdFixture {
 	// when testing the BuildAndDeployers.
 	dCli.ImageAlwaysExists = true
 
-	dcbad, err := ProvideDockerComposeBuildAndDeployer(ctx, dcCli, dCli, dir)
+	st := store.NewTestingStore()
+	ctrlClient := fake.NewTiltClient()
+	dcsr := dockercomposeservice.NewReconciler(ctx, dcCli, dCli, st)
+	dcsr.SetClient(ctrlClient)
+
+	dcbad, err := ProvideDockerComposeBuildAndDeployer(ctx, dcsr, dCli, dir)
 	if err != nil {
 		t.Fatal(err)
 	}
-	st := store.NewTestingStore()
 	return &dcbdFixture{
 		TempDirFixture: f,
 		ctx:            ctx,
@@ -200,6 +221,7 @@ func newDCBDFixture(t *testing.T) *dcbdFixture {
 		dCli:           dCli,
 		dcbad:          dcbad,
 		st:             st,
+		ctrlClient:     ctrlClient,
 	}
 }
 
@@ -99,6 +99,49 @@ func extractImageTargetsForLiveUpdates(specs []model.TargetSpec, stateSet store.
 	return result, nil
 }
 
+// If there are docker-compose services built by docker-compose that can be
+// updated in-place in a container, return a state tree of what needs to be updated.
+//
+// (Services whose images Tilt builds are live-updated through their image targets.)
+func extractDockerComposeTargetsForLiveUpdates(specs []model.TargetSpec, stateSet store.BuildStateSet) ([]liveUpdateStateTree, error) {
+	result := make([]liveUpdateStateTree, 0)
+	for _, dcTarget := range model.ExtractDockerComposeTargets(specs) {
+		luInfo := dcTarget.LiveUpdateInfo()
+		if luInfo.Empty() {
+			continue
+		}
+
+		state := stateSet[dcTarget.ID()]
+		if len(state.FilesChangedSet) == 0 {
+			continue
+		}
+
+		if state.IsEmpty() {
+			return nil, SilentRedirectToNextBuilderf("In-place build does not support initial deploy")
+		}
+
+		if state.FullBuildTriggered {
+			return nil, SilentRedirectToNextBuilderf("Force update (triggered manually, not automatically, with no dirty files)")
+		}
+
+		if len(state.DepsChangedSet) > 0 {
+			return nil, SilentRedirectToNextBuilderf("Pending dependencies")
+		}
+
+		if len(state.RunningContainers) == 0 || state.RunningContainers[0].ContainerID == "" {
+			return nil, RedirectToNextBuilderInfof("Don't have info for running container of service %q", dcTarget.Name)
+		}
+
+		result = append(result, liveUpdateStateTree{
+			iTarget:           dcTarget,
+			filesChanged:      state.FilesChanged(),
+			iTargetState:      state,
+			hasFileChangesIDs: []model.TargetID{dcTarget.ID()},
+		})
+	}
+	return result, nil
+}
+
 // Returns true if the given image is deployed to one of the given k8s targets.
 // Note that some images are injected into other images, so may never be deployed.
 func IsImageDeployedToK8s(iTarget model.ImageTarget, kTarget model.K8sTarget) bool {
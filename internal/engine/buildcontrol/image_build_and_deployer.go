@@ -17,6 +17,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
+	"github.com/tilt-dev/tilt/internal/ignore"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -67,6 +68,7 @@ type ImageBuildAndDeployer struct {
 	analytics   *analytics.TiltAnalytics
 	clock       build.Clock
 	kl          KINDLoader
+	rbc         build.RemoteBuildCache
 }
 
 func NewImageBuildAndDeployer(
@@ -79,6 +81,7 @@ func NewImageBuildAndDeployer(
 	updMode UpdateMode,
 	c build.Clock,
 	kl KINDLoader,
+	rbc build.RemoteBuildCache,
 ) *ImageBuildAndDeployer {
 	return &ImageBuildAndDeployer{
 		db:          db,
@@ -89,6 +92,7 @@ func NewImageBuildAndDeployer(
 		analytics:   analytics,
 		clock:       c,
 		kl:          kl,
+		rbc:         rbc,
 	}
 }
 
@@ -111,9 +115,16 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 	if err != nil {
 		return store.BuildResultSet{}, err
 	}
+	q = q.WithMaxParallel(maxParallelUpdates(st))
+	parallel := q.HasParallelBuilds()
 
 	// each image target has two stages: one for build, and one for push
 	numStages := q.CountBuilds()*2 + 1
+	if parallel {
+		// Images that build in parallel each get their own pipeline,
+		// under a single stage.
+		numStages = 2
+	}
 
 	reused := q.ReusedResults()
 	hasReusedStep := len(reused) > 0
@@ -147,31 +158,77 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 		ps.EndPipelineStep(ctx)
 	}
 
+	if parallel {
+		ps.StartPipelineStep(ctx, "Building %d images in parallel", q.CountBuilds())
+	}
+
 	iTargetMap := model.ImageTargetsByID(iTargets)
-	err = q.RunBuilds(func(target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
+	err = q.RunBuilds(ctx, func(ctx context.Context, target model.TargetSpec, depResults []store.BuildResult) (result store.BuildResult, err error) {
 		iTarget, ok := target.(model.ImageTarget)
 		if !ok {
 			return nil, fmt.Errorf("Not an image target: %T", target)
 		}
 
-		iTarget, err := InjectImageDependencies(iTarget, iTargetMap, depResults)
+		iTarget, err = InjectImageDependencies(iTarget, iTargetMap, depResults)
 		if err != nil {
 			return nil, err
 		}
 
-		refs, err := ibd.ib.Build(ctx, iTarget, ps)
+		ips := ps
+		if parallel {
+			ips = build.NewPipelineState(ctx, 2, ibd.clock)
+			defer func() { ips.End(ctx, err) }()
+		}
+
+		hash := ""
+		if ibd.canUseRemoteBuildCache(ctx, iTarget) {
+			hash, err = build.BuildHash(ctx, iTarget.DockerBuildInfo(), ignore.CreateBuildContextFilter(iTarget))
+			if err != nil {
+				return nil, err
+			}
+
+			refs, ok := ibd.lookupRemoteBuildCache(ctx, ips, iTarget, hash)
+			if ok {
+				return store.NewImageBuildResult(iTarget.ID(), refs.LocalRef, refs.ClusterRef), nil
+			}
+		}
+
+		var labels dockerfile.Labels
+		if hash != "" {
+			labels = dockerfile.Labels{build.BuildHashLabel: dockerfile.LabelValue(hash)}
+		}
+
+		refs, err := ibd.ib.Build(ctx, iTarget, ips, labels)
 		if err != nil {
 			return nil, err
 		}
 
-		err = ibd.push(ctx, refs.LocalRef, ps, iTarget, kTarget)
+		err = ibd.push(ctx, refs.LocalRef, ips, iTarget, kTarget)
 		if err != nil {
 			return nil, err
 		}
 
+		if hash != "" {
+			cacheRefs, err := ibd.pushToRemoteBuildCache(ips.AttachLogger(ctx), iTarget, refs, hash)
+			if err != nil {
+				// The image is already deployable, so this shouldn't fail the build.
+				logger.Get(ctx).Warnf("Error adding %s to the remote build cache: %v",
+					container.FamiliarString(refs.LocalRef), err)
+			} else if !IsImageDeployedToK8s(iTarget, kTarget) {
+				// Images that build on a base image refer to it by this tag,
+				// so that they hash the same as on a machine that found
+				// the base image in the cache.
+				refs = cacheRefs
+			}
+		}
+
 		return store.NewImageBuildResult(iTarget.ID(), refs.LocalRef, refs.ClusterRef), nil
 	})
 
+	if parallel && err == nil {
+		ps.EndPipelineStep(ctx)
+	}
+
 	newResults := q.NewResults()
 	if err != nil {
 		return newResults, WrapDontFallBackError(err)
@@ -208,6 +265,12 @@ func (ibd *ImageBuildAndDeployer) push(ctx context.Context, ref reference.NamedT
 	} else if !IsImageDeployedToK8s(iTarget, kTarget) {
 		ps.Printf(ctx, "Skipping push: base image does not need deploy")
 		return nil
+	} else if isBuiltOnBuilder(iTarget) {
+		// The image never lands in the local container runtime, so even on
+		// clusters that share it (or that we'd KIND load to), the cluster
+		// pulls the image from the registry. See createEntitiesToDeploy.
+		ps.Printf(ctx, "Skipping push: image was pushed by builder %s", iTarget.DockerBuildInfo().Builder)
+		return nil
 	} else if ibd.db.WillBuildToKubeContext(ibd.kubeContext) {
 		ps.Printf(ctx, "Skipping push: building on cluster's container runtime")
 		return nil
@@ -224,13 +287,89 @@ func (ibd *ImageBuildAndDeployer) push(ctx context.Context, ref reference.NamedT
 		ps.Printf(ctx, "Pushing with Docker client")
 		err = ibd.db.PushImage(ps.AttachLogger(ctx), ref)
 		if err != nil {
-			return err
+			return ibd.wrapLocalRegistryPushError(ctx, ref, err)
 		}
 	}
 
 	return nil
 }
 
+// If a push to the cluster's local registry fails, KEP-1755 asks us
+// to show the help URL that the cluster advertises.
+func (ibd *ImageBuildAndDeployer) wrapLocalRegistryPushError(ctx context.Context, ref reference.Named, err error) error {
+	registry := ibd.k8sClient.LocalRegistry(ctx)
+	if registry.Empty() || reference.Domain(ref) != registry.Host {
+		return err
+	}
+
+	help := ibd.k8sClient.LocalRegistryHosting(ctx).Help
+	if help == "" {
+		return err
+	}
+	return fmt.Errorf("%v\nPushing to the cluster's local registry (%s) failed. "+
+		"For help diagnosing the registry, see: %s", err, registry.Host, help)
+}
+
+// We can only share images through the remote build cache when
+// we're pushing Dockerfile builds to a registry.
+//
+// This includes base images that aren't deployed, so that the images
+// that build on them can be found in the cache too.
+func (ibd *ImageBuildAndDeployer) canUseRemoteBuildCache(ctx context.Context, iTarget model.ImageTarget) bool {
+	if ibd.rbc == nil || !iTarget.IsDockerBuild() {
+		return false
+	}
+
+	// Images built on a remote builder never land in the local daemon,
+	// so we can't tag and push them.
+	db := iTarget.DockerBuildInfo()
+	if !db.RemoteCache || db.Builder != "" {
+		return false
+	}
+	return !ibd.db.WillBuildToKubeContext(ibd.kubeContext) &&
+		!ibd.shouldUseKINDLoad(ctx, iTarget)
+}
+
+// Looks for an image in the remote build cache that was built from the same inputs.
+//
+// Takes the place of both the build and the push steps.
+func (ibd *ImageBuildAndDeployer) lookupRemoteBuildCache(ctx context.Context, ps *build.PipelineState, iTarget model.ImageTarget, hash string) (container.TaggedRefs, bool) {
+	refs, ok, err := ibd.rbc.Lookup(ctx, iTarget.Refs, hash)
+	if err != nil {
+		logger.Get(ctx).Debugf("Looking up %s in the remote build cache: %v",
+			container.FamiliarString(iTarget.Refs.ConfigurationRef), err)
+		return container.TaggedRefs{}, false
+	}
+	if !ok {
+		return container.TaggedRefs{}, false
+	}
+
+	ps.StartPipelineStep(ctx, "Loading from remote build cache: [%s]", container.FamiliarString(iTarget.Refs.ConfigurationRef))
+	ps.Printf(ctx, "Found %s, built from the same inputs", container.FamiliarString(refs.LocalRef))
+	ps.EndPipelineStep(ctx)
+
+	ps.StartPipelineStep(ctx, "Pushing %s", container.FamiliarString(refs.LocalRef))
+	ps.Printf(ctx, "Skipping push: image is already in the registry")
+	ps.EndPipelineStep(ctx)
+	return refs, true
+}
+
+// Pushes the image under its content-addressed tag, so that the next
+// build from the same inputs can find it.
+//
+// Returns the refs it pushed.
+func (ibd *ImageBuildAndDeployer) pushToRemoteBuildCache(ctx context.Context, iTarget model.ImageTarget, refs container.TaggedRefs, hash string) (container.TaggedRefs, error) {
+	cacheRefs, err := ibd.rbc.Tag(ctx, iTarget.Refs, refs, hash)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+	err = ibd.db.PushImage(ctx, cacheRefs.LocalRef)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+	return cacheRefs, nil
+}
+
 func (ibd *ImageBuildAndDeployer) shouldUseKINDLoad(ctx context.Context, iTarg model.ImageTarget) bool {
 	isKIND := ibd.env == k8s.EnvKIND5 || ibd.env == k8s.EnvKIND6
 	if !isKIND {
@@ -258,10 +397,61 @@ func (ibd *ImageBuildAndDeployer) deploy(ctx context.Context, st store.RStore, p
 	ps.StartPipelineStep(ctx, "Deploying")
 	defer ps.EndPipelineStep(ctx)
 
+	if kTarget.HasCustomDeploy() {
+		ps.StartBuildStep(ctx, "Applying via custom command")
+		deployed, err := runApplyCmdAndParseEntities(ibd.indentLogger(ctx), kTarget.ApplyCmd)
+		if err != nil {
+			return nil, err
+		}
+		return k8sDeployResult(kTarget, deployed)
+	}
+
 	ps.StartBuildStep(ctx, "Injecting images into Kubernetes YAML")
 
 	// Create API objects.
 	spec := kTarget.KubernetesApplySpec
+	imageMaps, err := imageMapsForDeploy(spec, iTargetMap, results)
+	if err != nil {
+		return nil, fmt.Errorf("Internal error: %v", err)
+	}
+
+	newK8sEntities, err := ibd.createEntitiesToDeploy(ctx, imageMaps, imageMapsBuiltOnBuilder(spec, iTargetMap), spec)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = ibd.indentLogger(ctx)
+	l := logger.Get(ctx)
+
+	timeout := kTarget.Timeout.Duration
+	if timeout == 0 {
+		timeout = v1alpha1.KubernetesApplyTimeoutDefault
+	}
+
+	var deployed []k8s.K8sEntity
+	if spec.ServerSideApply {
+		l.Infof("Applying via server-side apply:")
+		for _, displayName := range kTarget.DisplayNames {
+			l.Infof("→ %s", displayName)
+		}
+		deployed, err = ibd.k8sClient.ServerSideApply(ctx, newK8sEntities, timeout)
+	} else {
+		l.Infof("Applying via kubectl:")
+		for _, displayName := range kTarget.DisplayNames {
+			l.Infof("→ %s", displayName)
+		}
+		deployed, err = ibd.k8sClient.Upsert(ctx, newK8sEntities, timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return k8sDeployResult(kTarget, deployed)
+}
+
+// Resolves the image maps that the spec depends on to the images in the build results.
+func imageMapsForDeploy(spec v1alpha1.KubernetesApplySpec, iTargetMap map[model.TargetID]model.ImageTarget,
+	results store.BuildResultSet) (map[string]*v1alpha1.ImageMap, error) {
 	imageMaps := make(map[string]*v1alpha1.ImageMap)
 	for _, imageMapName := range spec.ImageMaps {
 		depID := model.TargetID{
@@ -271,12 +461,12 @@ func (ibd *ImageBuildAndDeployer) deploy(ctx context.Context, st store.RStore, p
 
 		iTarget, ok := iTargetMap[depID]
 		if !ok {
-			return nil, fmt.Errorf("Internal error: missing image target for dependency ID: %s", depID)
+			return nil, fmt.Errorf("missing image target for dependency ID: %s", depID)
 		}
 
 		ref := store.ClusterImageRefFromBuildResult(results[depID])
 		if ref == nil {
-			return nil, fmt.Errorf("Internal error: missing image build result for dependency ID: %s", depID)
+			return nil, fmt.Errorf("missing image build result for dependency ID: %s", depID)
 		}
 
 		name := string(depID.Name)
@@ -288,30 +478,59 @@ func (ibd *ImageBuildAndDeployer) deploy(ctx context.Context, st store.RStore, p
 			},
 		}
 	}
+	return imageMaps, nil
+}
 
-	newK8sEntities, err := ibd.createEntitiesToDeploy(ctx, imageMaps, spec)
-	if err != nil {
-		return nil, err
+// Whether the image is built on a remote builder, which pushes it
+// straight to the registry.
+func isBuiltOnBuilder(iTarget model.ImageTarget) bool {
+	return iTarget.IsDockerBuild() && iTarget.DockerBuildInfo().Builder != ""
+}
+
+// The image maps whose images were built on a remote builder, and so
+// are only in the registry.
+func imageMapsBuiltOnBuilder(spec v1alpha1.KubernetesApplySpec, iTargetMap map[model.TargetID]model.ImageTarget) map[string]bool {
+	result := make(map[string]bool)
+	for _, imageMapName := range spec.ImageMaps {
+		iTarget, ok := iTargetMap[model.TargetID{Type: model.TargetTypeImage, Name: model.TargetName(imageMapName)}]
+		if ok && isBuiltOnBuilder(iTarget) {
+			result[imageMapName] = true
+		}
 	}
+	return result
+}
 
-	ctx = ibd.indentLogger(ctx)
-	l := logger.Get(ctx)
+// Shows what the next deploy of the manifest would change in the cluster,
+// as a unified diff.
+//
+// Injects the images from each image target's last build, so the manifest's
+// images must have been built at least once.
+func (ibd *ImageBuildAndDeployer) Diff(ctx context.Context, manifest model.Manifest, results store.BuildResultSet) (string, error) {
+	if !manifest.IsK8s() {
+		return "", fmt.Errorf("resource %q is not a Kubernetes resource", manifest.Name)
+	}
 
-	l.Infof("Applying via kubectl:")
-	for _, displayName := range kTarget.DisplayNames {
-		l.Infof("→ %s", displayName)
+	kTarget := manifest.K8sTarget()
+	if kTarget.HasCustomDeploy() {
+		return "", fmt.Errorf("resource %q is deployed with a custom command, and can't be diffed", manifest.Name)
 	}
 
-	timeout := kTarget.Timeout.Duration
-	if timeout == 0 {
-		timeout = v1alpha1.KubernetesApplyTimeoutDefault
+	spec := kTarget.KubernetesApplySpec
+	imageMaps, err := imageMapsForDeploy(spec, model.ImageTargetsByID(manifest.ImageTargets), results)
+	if err != nil {
+		return "", fmt.Errorf("resource %q has no images to deploy yet: %v", manifest.Name, err)
 	}
 
-	deployed, err := ibd.k8sClient.Upsert(ctx, newK8sEntities, timeout)
+	builtOnBuilder := imageMapsBuiltOnBuilder(spec, model.ImageTargetsByID(manifest.ImageTargets))
+	entities, err := ibd.createEntitiesToDeploy(ctx, imageMaps, builtOnBuilder, spec)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
+	return ibd.k8sClient.Diff(ctx, entities)
+}
+
+func k8sDeployResult(kTarget model.K8sTarget, deployed []k8s.K8sEntity) (store.BuildResult, error) {
 	podTemplateSpecHashes := []k8s.PodTemplateSpecHash{}
 	for _, entity := range deployed {
 		if entity.UID() == "" {
@@ -338,7 +557,26 @@ func (ibd *ImageBuildAndDeployer) indentLogger(ctx context.Context) context.Cont
 //
 // Namespaces are not deleted by default. Similar to `tilt down`, deleting namespaces
 // is likely to be more destructive than most users want from this operation.
+//
+// Targets with a custom deploy command are deleted with their delete command, if any.
 func (ibd *ImageBuildAndDeployer) delete(ctx context.Context, k8sTarget model.K8sTarget) error {
+	return DeleteK8sTarget(ctx, ibd.k8sClient, k8sTarget)
+}
+
+// Deletes the objects that a K8sTarget deployed, leaving its namespaces alone.
+//
+// For a custom deploy, runs the target's delete command instead.
+func DeleteK8sTarget(ctx context.Context, kCli k8s.Client, k8sTarget model.K8sTarget) error {
+	if k8sTarget.HasCustomDeploy() {
+		if k8sTarget.DeleteCmd == nil {
+			return nil
+		}
+		l := logger.Get(ctx)
+		l.Infof("Running cmd: %s", model.Cmd{Argv: k8sTarget.DeleteCmd.Args}.String())
+		w := l.Writer(logger.InfoLvl)
+		return RunKubernetesApplyCmd(ctx, k8sTarget.DeleteCmd, w, w)
+	}
+
 	entities, err := k8s.ParseYAMLFromString(k8sTarget.YAML)
 	if err != nil {
 		return err
@@ -353,11 +591,12 @@ func (ibd *ImageBuildAndDeployer) delete(ctx context.Context, k8sTarget model.K8
 
 	entities = k8s.ReverseSortedEntities(entities)
 
-	return ibd.k8sClient.Delete(ctx, entities)
+	return kCli.Delete(ctx, entities)
 }
 
 func (ibd *ImageBuildAndDeployer) createEntitiesToDeploy(ctx context.Context,
 	imageMaps map[string]*v1alpha1.ImageMap,
+	builtOnBuilder map[string]bool,
 	spec v1alpha1.KubernetesApplySpec) ([]k8s.K8sEntity, error) {
 	newK8sEntities := []k8s.K8sEntity{}
 
@@ -401,14 +640,17 @@ func (ibd *ImageBuildAndDeployer) createEntitiesToDeploy(ctx context.Context,
 			e = k8s.InjectParallelPodManagementPolicy(e)
 		}
 
-		// When working with a local k8s cluster, we set the pull policy to Never,
-		// to ensure that k8s fails hard if the image is missing from docker.
-		policy := v1.PullIfNotPresent
-		if ibd.db.WillBuildToKubeContext(ibd.kubeContext) {
-			policy = v1.PullNever
-		}
-
 		for _, imageMapName := range imageMapNames {
+			// When working with a local k8s cluster, we set the pull policy to Never,
+			// to ensure that k8s fails hard if the image is missing from docker.
+			//
+			// Images built on a remote builder are never in docker,
+			// so the cluster has to pull them from the registry.
+			policy := v1.PullIfNotPresent
+			if ibd.db.WillBuildToKubeContext(ibd.kubeContext) && !builtOnBuilder[imageMapName] {
+				policy = v1.PullNever
+			}
+
 			imageMap := imageMaps[imageMapName]
 			imageMapSpec := imageMap.Spec
 			selector, err := container.SelectorFromImageMap(imageMapSpec)
@@ -502,3 +744,15 @@ func InjectImageDependencies(iTarget model.ImageTarget, iTargetMap map[model.Tar
 
 	return iTarget, nil
 }
+
+// The max number of image targets to build at once within a single manifest.
+//
+// This limit is applied per manifest. It is not shared with the build
+// controller, which separately runs up to max_parallel_updates manifests
+// at once, so up to max_parallel_updates * max_parallel_updates image
+// builds may run concurrently across all manifests.
+func maxParallelUpdates(st store.RStore) int {
+	state := st.RLockState()
+	defer st.RUnlockState()
+	return state.UpdateSettings.MaxParallelUpdates()
+}
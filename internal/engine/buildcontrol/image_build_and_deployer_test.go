@@ -14,13 +14,16 @@ import (
 	digest "github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tilt-dev/localregistry-go"
 	"github.com/tilt-dev/wmclient/pkg/dirs"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/ignore"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -147,6 +150,50 @@ func TestDeployPodWithMultipleImages(t *testing.T) {
 		"Expected image to appear once in YAML: %s", f.k8s.Yaml)
 }
 
+func TestDeployPodWithMultipleImagesInParallel(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	iTarget1 := NewSanchoDockerBuildImageTarget(f)
+	iTarget2 := NewSanchoSidecarDockerBuildImageTarget(f)
+	kTarget := k8s.MustTarget("sancho", testyaml.SanchoSidecarYAML).
+		WithDependencyIDs([]model.TargetID{iTarget1.ID(), iTarget2.ID()})
+	targets := []model.TargetSpec{iTarget1, iTarget2, kTarget}
+
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, targets, store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, f.docker.BuildCount)
+	out := f.out.String()
+	assert.Contains(t, out, "STEP 1/2 — Building 2 images in parallel")
+	assert.Contains(t, out, "[gcr.io/some-project-162817/sancho] STEP 1/2 — Building Dockerfile")
+	assert.Contains(t, out, "[gcr.io/some-project-162817/sancho-sidecar] STEP 1/2 — Building Dockerfile")
+	assert.Contains(t, out, "STEP 2/2 — Deploying")
+}
+
+func TestDeployPodWithMultipleImagesMaxParallelOne(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpdateSettings = state.UpdateSettings.WithMaxParallelUpdates(1)
+	})
+
+	iTarget1 := NewSanchoDockerBuildImageTarget(f)
+	iTarget2 := NewSanchoSidecarDockerBuildImageTarget(f)
+	kTarget := k8s.MustTarget("sancho", testyaml.SanchoSidecarYAML).
+		WithDependencyIDs([]model.TargetID{iTarget1.ID(), iTarget2.ID()})
+	targets := []model.TargetSpec{iTarget1, iTarget2, kTarget}
+
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, targets, store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, f.docker.BuildCount)
+	out := f.out.String()
+	assert.NotContains(t, out, "in parallel")
+	assert.Contains(t, out, "STEP 5/5 — Deploying")
+}
+
 func TestDeployPodWithMultipleLiveUpdateImages(t *testing.T) {
 	f := newIBDFixture(t, k8s.EnvGKE)
 	defer f.TearDown()
@@ -448,6 +495,57 @@ func TestK8sUpsertTimeout(t *testing.T) {
 	assert.Equal(t, f.k8s.UpsertTimeout, timeout)
 }
 
+func TestK8sServerSideApply(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	k8sTarget := manifest.DeployTarget.(model.K8sTarget)
+	k8sTarget.ServerSideApply = true
+	manifest.DeployTarget = k8sTarget
+
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(manifest), nil)
+	require.NoError(t, err)
+
+	assert.True(t, f.k8s.LastUpsertServerSide)
+	assert.Contains(t, f.out.String(), "Applying via server-side apply")
+}
+
+func TestDiff(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarget := manifest.ImageTargetAt(0)
+	ref := container.MustParseNamedTagged("gcr.io/some-project-162817/sancho:tilt-11cd0b38bc3ceb95")
+	results := store.BuildResultSet{
+		iTarget.ID(): store.NewImageBuildResultSingleRef(iTarget.ID(), ref),
+	}
+
+	f.k8s.DiffOutput = "some diff"
+	diff, err := f.ibd.Diff(f.ctx, manifest, results)
+	require.NoError(t, err)
+	assert.Equal(t, "some diff", diff)
+
+	// The diff should be against the YAML with the last built image injected.
+	require.Len(t, f.k8s.LastDiffEntities, 1)
+	yaml, err := k8s.SerializeSpecYAML(f.k8s.LastDiffEntities)
+	require.NoError(t, err)
+	assert.Contains(t, yaml, ref.String())
+	assert.Equal(t, "", f.k8s.Yaml, "diff should not apply anything")
+}
+
+func TestDiffNeedsImages(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.ibd.Diff(f.ctx, manifest, store.BuildResultSet{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `resource "sancho" has no images to deploy yet`)
+	}
+}
+
 func TestKINDLoad(t *testing.T) {
 	f := newIBDFixture(t, k8s.EnvKIND6)
 	defer f.TearDown()
@@ -487,6 +585,184 @@ func TestDockerPushIfKINDAndClusterRef(t *testing.T) {
 	assert.NotContains(t, yaml, iTarg.Refs.LocalRef().String(), "LocalRef was NOT injected into applied YAML")
 }
 
+func TestLocalRegistryPushErrorShowsHelp(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvKIND6)
+	defer f.TearDown()
+
+	registry := container.MustNewRegistryWithHostFromCluster("localhost:1234", "registry:1234")
+	f.k8s.Registry = registry
+	f.k8s.RegistryHosting = localregistry.LocalRegistryHostingV1{
+		Host:                     "localhost:1234",
+		HostFromContainerRuntime: "registry:1234",
+		Help:                     "https://fake-domain.tilt.dev/local-registry-help",
+	}
+	f.docker.PushOutput = `{"errorDetail":{"message":"connection refused"},"error":"connection refused"}`
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarg := manifest.ImageTargetAt(0)
+	iTarg.Refs = iTarg.Refs.MustWithRegistry(registry)
+	manifest = manifest.WithImageTarget(iTarg)
+
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(manifest), store.BuildStateSet{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Contains(t, err.Error(), "Pushing to the cluster's local registry (localhost:1234) failed")
+	assert.Contains(t, err.Error(), "https://fake-domain.tilt.dev/local-registry-help")
+}
+
+func TestRemoteBuildCacheMissPushesContentTag(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := f.remoteCacheManifest()
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	iTarg := manifest.ImageTargetAt(0)
+	cacheRefs := f.remoteCacheRefs(iTarg)
+	assert.Equal(t, cacheRefs.LocalRef.String(), f.docker.DistributionInspectImage)
+	assert.Equal(t, 1, f.docker.BuildCount)
+	assert.Equal(t, f.buildHash(iTarg), f.docker.BuildOptions.Labels[string(build.BuildHashLabel)])
+
+	// We push the digest tag that we deploy, then the content tag for the cache.
+	assert.Equal(t, 2, f.docker.PushCount)
+	assert.Equal(t, cacheRefs.LocalRef.String(), f.docker.PushImage)
+	assert.Contains(t, f.k8s.Yaml, "gcr.io/some-project-162817/sancho:tilt-11cd0b38bc3ceb95")
+}
+
+func TestRemoteBuildCacheHitSkipsBuild(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := f.remoteCacheManifest()
+	iTarg := manifest.ImageTargetAt(0)
+	cacheRefs := f.remoteCacheRefs(iTarg)
+	f.docker.Images[cacheRefs.LocalRef.String()] = types.ImageInspect{}
+
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	// We only ask the registry for the manifest. The cluster pulls the image.
+	assert.Equal(t, 1, f.docker.DistributionInspectCount)
+	assert.Equal(t, 0, f.docker.BuildCount)
+	assert.Equal(t, 0, f.docker.PushCount)
+	assert.Contains(t, f.k8s.Yaml, cacheRefs.ClusterRef.String())
+	assert.Contains(t, f.out.String(), "Loading from remote build cache")
+}
+
+func TestRemoteBuildCacheHitOnBaseImage(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := f.remoteCacheMultiStageManifest()
+	baseTarg := manifest.ImageTargetAt(0)
+	baseRefs := f.remoteCacheRefs(baseTarg)
+	f.docker.Images[baseRefs.LocalRef.String()] = types.ImageInspect{}
+
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	// Only the image that builds on the base image is built.
+	assert.Equal(t, 1, f.docker.BuildCount)
+	assert.Contains(t, f.docker.BuildOptions.Labels, string(build.BuildHashLabel))
+	expected := testutils.ExpectedFile{
+		Path: "Dockerfile",
+		Contents: fmt.Sprintf(`
+FROM %s
+ADD . .
+RUN go install github.com/tilt-dev/sancho
+ENTRYPOINT /go/bin/sancho
+`, container.FamiliarString(baseRefs.LocalRef)),
+	}
+	testutils.AssertFileInTar(t, tar.NewReader(f.docker.BuildContext), expected)
+}
+
+func TestRemoteBuildCacheMissOnBaseImagePushesContentTag(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := f.remoteCacheMultiStageManifest()
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	// Both images are added to the cache, and the deployed image is pushed for deploy.
+	assert.Equal(t, 2, f.docker.BuildCount)
+	assert.Equal(t, 3, f.docker.PushCount)
+
+	// The image that builds on the base image refers to it by its content tag.
+	baseRefs := f.remoteCacheRefs(manifest.ImageTargetAt(0))
+	expected := testutils.ExpectedFile{
+		Path: "Dockerfile",
+		Contents: fmt.Sprintf(`
+FROM %s
+ADD . .
+RUN go install github.com/tilt-dev/sancho
+ENTRYPOINT /go/bin/sancho
+`, container.FamiliarString(baseRefs.LocalRef)),
+	}
+	testutils.AssertFileInTar(t, tar.NewReader(f.docker.BuildContext), expected)
+}
+
+func TestRemoteBuildCacheOffByDefault(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, f.docker.DistributionInspectCount)
+	assert.Equal(t, 1, f.docker.PushCount)
+	assert.Equal(t, "", f.docker.BuildOptions.Labels[string(build.BuildHashLabel)])
+}
+
+func TestDockerBuildPlatform(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarg := manifest.ImageTargetAt(0)
+	db := iTarg.DockerBuildInfo()
+	db.Platform = "linux/arm64"
+	manifest = manifest.WithImageTarget(iTarg.WithBuildDetails(db))
+
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "linux/arm64", f.docker.BuildOptions.Platform)
+}
+
+func TestBuilderImagesArePulledOnLocalCluster(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvDockerDesktop)
+	defer f.TearDown()
+	f.docker.FakeEnv.BuildToKubeContexts = []string{string(f.ibd.kubeContext)}
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarg := manifest.ImageTargetAt(0)
+	spec := manifest.K8sTarget().KubernetesApplySpec
+	name := spec.ImageMaps[0]
+	imageMaps := map[string]*v1alpha1.ImageMap{
+		name: {
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       iTarg.ImageMapSpec,
+			Status:     v1alpha1.ImageMapStatus{Image: "gcr.io/some-project-162817/sancho:tilt-11cd0b38bc3ceb95"},
+		},
+	}
+
+	entities, err := f.ibd.createEntitiesToDeploy(f.ctx, imageMaps, nil, spec)
+	require.NoError(t, err)
+	yaml, err := k8s.SerializeSpecYAML(entities)
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "imagePullPolicy: Never")
+
+	entities, err = f.ibd.createEntitiesToDeploy(f.ctx, imageMaps, map[string]bool{name: true}, spec)
+	require.NoError(t, err)
+	yaml, err = k8s.SerializeSpecYAML(entities)
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "imagePullPolicy: IfNotPresent")
+	assert.NotContains(t, yaml, "imagePullPolicy: Never")
+}
+
 func TestCustomBuildDisablePush(t *testing.T) {
 	f := newIBDFixture(t, k8s.EnvKIND6)
 	defer f.TearDown()
@@ -870,6 +1146,92 @@ func TestIBDDeployUIDs(t *testing.T) {
 	assert.True(t, deployed.ContainsUID(f.k8s.LastUpsertResult[0].UID()))
 }
 
+func TestCustomDeploy(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	f.WriteFile("deployed.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+  uid: abc-123
+`)
+	m := newCustomDeployManifest(f, "cat deployed.yaml", "echo deleted")
+	result, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(m), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	deployed := result.DeployedEntities()
+	require.Equal(t, 1, len(deployed))
+	assert.Equal(t, "my-config", deployed[0].Name)
+	assert.True(t, deployed.ContainsUID("abc-123"))
+
+	// Tilt shouldn't apply anything itself.
+	assert.Equal(t, "", f.k8s.Yaml)
+}
+
+func TestCustomDeployMissingUID(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	f.WriteFile("deployed.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+`)
+	m := newCustomDeployManifest(f, "cat deployed.yaml", "echo deleted")
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(m), store.BuildStateSet{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "apply command returned an object without a UID: my-config")
+	}
+}
+
+func TestCustomDeployCmdFails(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	m := newCustomDeployManifest(f, "echo oops >&2 && exit 1", "echo deleted")
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(m), store.BuildStateSet{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "apply command")
+	}
+	assert.Contains(t, f.out.String(), "oops")
+}
+
+func TestForceUpdateCustomDeploy(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	f.WriteFile("deployed.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+  uid: abc-123
+`)
+	m := newCustomDeployManifest(f, "cat deployed.yaml", "echo custom-deleted")
+	stateSet := store.BuildStateSet{
+		m.K8sTarget().ID(): store.BuildState{FullBuildTriggered: true},
+	}
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, BuildTargets(m), stateSet)
+	require.NoError(t, err)
+
+	assert.Contains(t, f.out.String(), "custom-deleted")
+	assert.Equal(t, "", f.k8s.DeletedYaml)
+}
+
+func newCustomDeployManifest(f manifestbuilder.Fixture, applyCmd string, deleteCmd string) model.Manifest {
+	toSpecCmd := func(s string) *v1alpha1.KubernetesApplyCmd {
+		return &v1alpha1.KubernetesApplyCmd{Args: model.ToHostCmd(s).Argv, Dir: f.Path()}
+	}
+	kTarget := model.K8sTarget{
+		Name: "custom",
+		KubernetesApplySpec: v1alpha1.KubernetesApplySpec{
+			ApplyCmd:  toSpecCmd(applyCmd),
+			DeleteCmd: toSpecCmd(deleteCmd),
+		},
+	}
+	return model.Manifest{Name: "custom"}.WithDeployTarget(kTarget)
+}
+
 func TestDockerBuildTargetStage(t *testing.T) {
 	f := newIBDFixture(t, k8s.EnvGKE)
 	defer f.TearDown()
@@ -1027,6 +1389,39 @@ func newIBDFixture(t *testing.T, env k8s.Env) *ibdFixture {
 	}
 }
 
+func (f *ibdFixture) remoteCacheManifest() model.Manifest {
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarg := manifest.ImageTargetAt(0)
+	db := iTarg.DockerBuildInfo()
+	db.RemoteCache = true
+	return manifest.WithImageTarget(iTarg.WithBuildDetails(db))
+}
+
+func (f *ibdFixture) remoteCacheMultiStageManifest() model.Manifest {
+	manifest := NewSanchoDockerBuildMultiStageManifest(f)
+	var iTargets []model.ImageTarget
+	for _, iTarg := range manifest.ImageTargets {
+		db := iTarg.DockerBuildInfo()
+		db.RemoteCache = true
+		iTargets = append(iTargets, iTarg.WithBuildDetails(db))
+	}
+	return manifest.WithImageTargets(iTargets)
+}
+
+func (f *ibdFixture) buildHash(iTarg model.ImageTarget) string {
+	hash, err := build.BuildHash(f.ctx, iTarg.DockerBuildInfo(), ignore.CreateBuildContextFilter(iTarg))
+	require.NoError(f.T(), err)
+	return hash
+}
+
+func (f *ibdFixture) remoteCacheRefs(iTarg model.ImageTarget) container.TaggedRefs {
+	tag, err := build.BuildHashTag(f.buildHash(iTarg))
+	require.NoError(f.T(), err)
+	refs, err := iTarg.Refs.AddTagSuffix(tag)
+	require.NoError(f.T(), err)
+	return refs
+}
+
 func (f *ibdFixture) TearDown() {
 	f.k8s.TearDown()
 	f.TempDirFixture.TearDown()
@@ -12,6 +12,7 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/dockerfile"
 	"github.com/tilt-dev/tilt/internal/ignore"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -66,6 +67,11 @@ func NewImageBuilder(db build.DockerBuilder, custb build.CustomBuilder, updateMo
 	}
 }
 
+// Returns an ImageBuilder that builds with the Docker daemon for the given orchestrator.
+func (icb *ImageBuilder) ForOrchestrator(orc model.Orchestrator) *ImageBuilder {
+	return NewImageBuilder(icb.db.ForOrchestrator(orc), icb.custb.ForOrchestrator(orc), icb.updateMode)
+}
+
 func (icb *ImageBuilder) CanReuseRef(ctx context.Context, iTarget model.ImageTarget, ref reference.NamedTagged) (bool, error) {
 	switch iTarget.BuildDetails.(type) {
 	case model.DockerBuild:
@@ -79,8 +85,9 @@ func (icb *ImageBuilder) CanReuseRef(ctx context.Context, iTarget model.ImageTar
 		"DockerBuild nor CustomBuild)", iTarget.Refs.ConfigurationRef)
 }
 
+// Builds the image target. Docker builds are labeled with the given labels.
 func (icb *ImageBuilder) Build(ctx context.Context, iTarget model.ImageTarget,
-	ps *build.PipelineState) (refs container.TaggedRefs, err error) {
+	ps *build.PipelineState, labels dockerfile.Labels) (refs container.TaggedRefs, err error) {
 	userFacingRefName := container.FamiliarString(iTarget.Refs.ConfigurationRef)
 	startTime := time.Now()
 	ctx, err = tag.New(ctx, tag.Upsert(KeyImageRef, userFacingRefName))
@@ -108,7 +115,7 @@ func (icb *ImageBuilder) Build(ctx context.Context, iTarget model.ImageTarget,
 		defer ps.EndPipelineStep(ctx)
 
 		refs, err = icb.db.BuildImage(ctx, ps, iTarget.Refs, bd,
-			ignore.CreateBuildContextFilter(iTarget))
+			ignore.CreateBuildContextFilter(iTarget), labels)
 
 		if err != nil {
 			return container.TaggedRefs{}, err
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/docker/distribution/reference"
 	"github.com/pkg/errors"
 
 	"github.com/tilt-dev/tilt/internal/ospath"
@@ -28,6 +27,7 @@ var _ BuildAndDeployer = &LiveUpdateBuildAndDeployer{}
 type LiveUpdateBuildAndDeployer struct {
 	dcu         *containerupdate.DockerUpdater
 	ecu         *containerupdate.ExecUpdater
+	dccu        *containerupdate.DockerComposeUpdater
 	updMode     UpdateMode
 	kubeContext k8s.KubeContext
 	clock       build.Clock
@@ -35,12 +35,14 @@ type LiveUpdateBuildAndDeployer struct {
 
 func NewLiveUpdateBuildAndDeployer(dcu *containerupdate.DockerUpdater,
 	ecu *containerupdate.ExecUpdater,
+	dccu *containerupdate.DockerComposeUpdater,
 	updMode UpdateMode,
 	kubeContext k8s.KubeContext,
 	c build.Clock) *LiveUpdateBuildAndDeployer {
 	return &LiveUpdateBuildAndDeployer{
 		dcu:         dcu,
 		ecu:         ecu,
+		dccu:        dccu,
 		updMode:     updMode,
 		kubeContext: kubeContext,
 		clock:       c,
@@ -49,14 +51,14 @@ func NewLiveUpdateBuildAndDeployer(dcu *containerupdate.DockerUpdater,
 
 // Info needed to perform a live update
 type liveUpdInfo struct {
-	iTarget      model.ImageTarget
+	iTarget      liveUpdateTarget
 	state        store.BuildState
 	changedFiles []build.PathMapping
 	runs         []model.Run
 	hotReload    bool
 }
 
-func (lui liveUpdInfo) Empty() bool { return lui.iTarget.ID() == model.ImageTarget{}.ID() }
+func (lui liveUpdInfo) Empty() bool { return lui.iTarget == nil }
 
 func (lubad *LiveUpdateBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.RStore, specs []model.TargetSpec, stateSet store.BuildStateSet) (store.BuildResultSet, error) {
 	liveUpdateStateSet, err := extractImageTargetsForLiveUpdates(specs, stateSet)
@@ -64,6 +66,12 @@ func (lubad *LiveUpdateBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 		return store.BuildResultSet{}, err
 	}
 
+	dcStateSet, err := extractDockerComposeTargetsForLiveUpdates(specs, stateSet)
+	if err != nil {
+		return store.BuildResultSet{}, err
+	}
+	liveUpdateStateSet = append(liveUpdateStateSet, dcStateSet...)
+
 	containerUpdater := lubad.containerUpdaterForSpecs(specs)
 	liveUpdInfos := make([]liveUpdInfo, 0, len(liveUpdateStateSet))
 
@@ -90,7 +98,7 @@ func (lubad *LiveUpdateBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 
 	var dontFallBackErr error
 	for _, info := range liveUpdInfos {
-		ps.StartPipelineStep(ctx, "updating image %s", reference.FamiliarName(info.iTarget.Refs.ClusterRef()))
+		ps.StartPipelineStep(ctx, "updating %s", liveUpdateTargetName(info.iTarget))
 		err = lubad.buildAndDeploy(ctx, ps, containerUpdater, info.iTarget, info.state, info.changedFiles, info.runs, info.hotReload)
 		if err != nil {
 			if !IsDontFallBackError(err) {
@@ -111,7 +119,7 @@ func (lubad *LiveUpdateBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 	return createResultSet(liveUpdateStateSet, liveUpdInfos), err
 }
 
-func (lubad *LiveUpdateBuildAndDeployer) buildAndDeploy(ctx context.Context, ps *build.PipelineState, cu containerupdate.ContainerUpdater, iTarget model.ImageTarget, state store.BuildState, changedFiles []build.PathMapping, runs []model.Run, hotReload bool) (err error) {
+func (lubad *LiveUpdateBuildAndDeployer) buildAndDeploy(ctx context.Context, ps *build.PipelineState, cu containerupdate.ContainerUpdater, iTarget liveUpdateTarget, state store.BuildState, changedFiles []build.PathMapping, runs []model.Run, hotReload bool) (err error) {
 	startTime := time.Now()
 	defer func() {
 		analytics.Get(ctx).Timer("build.container", time.Since(startTime), map[string]string{
@@ -245,8 +253,17 @@ func liveUpdateInfoForStateTree(stateTree liveUpdateStateTree) (liveUpdInfo, err
 }
 
 func (lubad *LiveUpdateBuildAndDeployer) containerUpdaterForSpecs(specs []model.TargetSpec) containerupdate.ContainerUpdater {
-	isDC := len(model.ExtractDockerComposeTargets(specs)) > 0
-	if isDC || lubad.updMode == UpdateModeContainer {
+	dcTargets := model.ExtractDockerComposeTargets(specs)
+	if len(dcTargets) > 0 {
+		// If docker-compose built the image, it also owns the container,
+		// so update it through docker-compose.
+		if !dcTargets[0].LiveUpdateInfo().Empty() {
+			return lubad.dccu.ForTarget(dcTargets[0])
+		}
+		return lubad.dcu.ForOrchestrator(model.OrchestratorDC)
+	}
+
+	if lubad.updMode == UpdateModeContainer {
 		return lubad.dcu
 	}
 
@@ -1,15 +1,44 @@
 package buildcontrol
 
 import (
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
-// A helper data structure that represents a live-update image and
+// A target that we can live-update in place: either an image, or a
+// docker-compose service that docker-compose builds itself.
+type liveUpdateTarget interface {
+	model.TargetSpec
+	LiveUpdateInfo() model.LiveUpdate
+	LocalPaths() []string
+	TiltFilename() string
+	LocalRepos() []model.LocalGitRepo
+	Dockerignores() []model.Dockerignore
+}
+
+var _ liveUpdateTarget = model.ImageTarget{}
+var _ liveUpdateTarget = model.DockerComposeTarget{}
+
+// A human-readable name for the target, for logging.
+func liveUpdateTargetName(t liveUpdateTarget) string {
+	switch t := t.(type) {
+	case model.ImageTarget:
+		return fmt.Sprintf("image %s", reference.FamiliarName(t.Refs.ClusterRef()))
+	case model.DockerComposeTarget:
+		return fmt.Sprintf("service %s", t.Name)
+	}
+	return t.ID().String()
+}
+
+// A helper data structure that represents a live-update target and
 // the files changed in all of its dependencies.
 type liveUpdateStateTree struct {
-	iTarget           model.ImageTarget
+	iTarget           liveUpdateTarget
 	filesChanged      []string
 	iTargetState      store.BuildState
 	hasFileChangesIDs []model.TargetID
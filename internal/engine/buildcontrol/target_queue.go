@@ -3,6 +3,7 @@ package buildcontrol
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/docker/distribution/reference"
 	"github.com/pkg/errors"
@@ -14,7 +15,11 @@ import (
 )
 
 // Allows the caller to inject its own build strategy for dirty targets.
+//
+// When targets build in parallel, the handler may be called concurrently,
+// and ctx has a logger prefixed with the target's name.
 type BuildHandler func(
+	ctx context.Context,
 	target model.TargetSpec,
 	depResults []store.BuildResult) (store.BuildResult, error)
 
@@ -42,6 +47,12 @@ type TargetQueue struct {
 	// A target that depends on a dirty target should never use its previous
 	// result to build the next result.
 	depsNeedBuild map[model.TargetID]bool
+
+	// The maximum number of targets to build at once.
+	maxParallel int
+
+	// Guards results while builds are running.
+	mu sync.Mutex
 }
 
 func NewImageTargetQueue(ctx context.Context, iTargets []model.ImageTarget, state store.BuildStateSet, canReuseRef ReuseRefChecker) (*TargetQueue, error) {
@@ -90,6 +101,7 @@ func NewImageTargetQueue(ctx context.Context, iTargets []model.ImageTarget, stat
 		results:       results,
 		needsOwnBuild: needsOwnBuild,
 		depsNeedBuild: depsNeedBuild,
+		maxParallel:   1,
 	}
 	err = queue.backfillExistingResults()
 	if err != nil {
@@ -164,18 +176,132 @@ func (q *TargetQueue) backfillExistingResults() error {
 	return nil
 }
 
-func (q *TargetQueue) RunBuilds(handler BuildHandler) error {
+// Build up to n independent targets at once. Targets still wait
+// for the targets they depend on.
+func (q *TargetQueue) WithMaxParallel(n int) *TargetQueue {
+	if n < 1 {
+		n = 1
+	}
+	q.maxParallel = n
+	return q
+}
+
+// Whether RunBuilds will build more than one target at once, i.e., whether
+// there are at least two targets to build that don't depend on each other.
+func (q *TargetQueue) HasParallelBuilds() bool {
+	if q.maxParallel < 2 {
+		return false
+	}
+
+	// Group the targets to build by their depth in the build graph.
+	// Targets at the same depth can't depend on each other.
+	depth := make(map[model.TargetID]int)
+	countAtDepth := make(map[int]int)
 	for _, target := range q.sortedTargets {
 		id := target.ID()
-		if q.isBuilding(id) {
-			result, err := handler(target, q.dependencyResults(target))
-			if err != nil {
-				return err
+		if !q.isBuilding(id) {
+			continue
+		}
+		d := 0
+		for _, depID := range target.DependencyIDs() {
+			if q.isBuilding(depID) && depth[depID]+1 > d {
+				d = depth[depID] + 1
 			}
-			q.results[id] = result
+		}
+		depth[id] = d
+		countAtDepth[d]++
+		if countAtDepth[d] > 1 {
+			return true
 		}
 	}
-	return nil
+	return false
+}
+
+func (q *TargetQueue) RunBuilds(ctx context.Context, handler BuildHandler) error {
+	if !q.HasParallelBuilds() {
+		for _, target := range q.sortedTargets {
+			id := target.ID()
+			if q.isBuilding(id) {
+				result, err := handler(ctx, target, q.dependencyResults(target))
+				if err != nil {
+					return err
+				}
+				q.results[id] = result
+			}
+		}
+		return nil
+	}
+	return q.runParallelBuilds(ctx, handler)
+}
+
+// Starts each target as soon as everything it depends on has built,
+// with at most maxParallel builds at once. Stops starting new builds
+// after the first failure, and returns that failure.
+func (q *TargetQueue) runParallelBuilds(ctx context.Context, handler BuildHandler) error {
+	done := make(map[model.TargetID]chan struct{})
+	for _, target := range q.sortedTargets {
+		if q.isBuilding(target.ID()) {
+			done[target.ID()] = make(chan struct{})
+		}
+	}
+
+	sem := make(chan struct{}, q.maxParallel)
+	var wg sync.WaitGroup
+	var firstErr error
+	for _, target := range q.sortedTargets {
+		id := target.ID()
+		if !q.isBuilding(id) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(target model.TargetSpec) {
+			defer wg.Done()
+			defer close(done[target.ID()])
+
+			for _, depID := range target.DependencyIDs() {
+				if ch, ok := done[depID]; ok {
+					<-ch
+				}
+			}
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			q.mu.Lock()
+			failed := firstErr != nil
+			depResults := q.dependencyResults(target)
+			q.mu.Unlock()
+			if failed {
+				return
+			}
+
+			result, err := handler(q.prefixLogger(ctx, target), target, depResults)
+
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			q.results[target.ID()] = result
+		}(target)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// Prefix each log line with the name of the target, so that
+// parallel build logs can be told apart.
+func (q *TargetQueue) prefixLogger(ctx context.Context, target model.TargetSpec) context.Context {
+	name := target.ID().Name.String()
+	if iTarget, ok := target.(model.ImageTarget); ok {
+		name = container.FamiliarString(iTarget.Refs.ConfigurationRef)
+	}
+	l := logger.NewPrefixedLogger(fmt.Sprintf("[%s] ", name), logger.Get(ctx))
+	return logger.WithLogger(ctx, l)
 }
 
 func (q *TargetQueue) dependencyResults(target model.TargetSpec) []store.BuildResult {
@@ -3,12 +3,17 @@ package buildcontrol
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
+	"github.com/tilt-dev/tilt/pkg/logger"
 
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -167,6 +172,100 @@ func TestTargetQueue_DepsBuiltButReaped(t *testing.T) {
 	assert.Equal(t, expectedCalls, f.handler.calls)
 }
 
+func TestTargetQueue_HasParallelBuilds(t *testing.T) {
+	f := newTargetQueueFixture(t)
+
+	base := model.MustNewImageTarget(container.MustParseSelector("base"))
+	a := model.MustNewImageTarget(container.MustParseSelector("a")).WithDependencyIDs([]model.TargetID{base.ID()})
+	b := model.MustNewImageTarget(container.MustParseSelector("b")).WithDependencyIDs([]model.TargetID{base.ID()})
+
+	// A chain of images can't build in parallel
+	q := f.newQueue([]model.ImageTarget{base, a}).WithMaxParallel(3)
+	assert.False(t, q.HasParallelBuilds())
+
+	// Siblings can, unless we only build one at a time
+	q = f.newQueue([]model.ImageTarget{base, a, b}).WithMaxParallel(3)
+	assert.True(t, q.HasParallelBuilds())
+	q = f.newQueue([]model.ImageTarget{base, a, b}).WithMaxParallel(1)
+	assert.False(t, q.HasParallelBuilds())
+}
+
+func TestTargetQueue_ParallelBuilds(t *testing.T) {
+	f := newTargetQueueFixture(t)
+
+	base := model.MustNewImageTarget(container.MustParseSelector("base"))
+	var sidecars []model.ImageTarget
+	for i := 0; i < 4; i++ {
+		sidecars = append(sidecars, model.MustNewImageTarget(container.MustParseSelector(fmt.Sprintf("sidecar-%d", i))).
+			WithDependencyIDs([]model.TargetID{base.ID()}))
+	}
+	targets := append([]model.ImageTarget{base}, sidecars...)
+
+	var mu sync.Mutex
+	running := 0
+	maxRunning := 0
+	builtBase := false
+	q := f.newQueue(targets).WithMaxParallel(2)
+	err := q.RunBuilds(f.ctx, func(ctx context.Context, target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
+		mu.Lock()
+		if target.ID() != base.ID() {
+			assert.True(t, builtBase, "sidecar %s built before base", target.ID())
+			assert.Len(t, depResults, 1)
+		}
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		logger.Get(ctx).Infof("building")
+
+		mu.Lock()
+		running--
+		if target.ID() == base.ID() {
+			builtBase = true
+		}
+		mu.Unlock()
+
+		iTarget := target.(model.ImageTarget)
+		ref := container.MustParseNamedTagged(fmt.Sprintf("%s:tilt-1", iTarget.Refs.ConfigurationRef))
+		return store.NewImageBuildResultSingleRef(target.ID(), ref), nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, maxRunning)
+	assert.Len(t, q.NewResults(), 5)
+	assert.Contains(t, f.out.String(), "[sidecar-0] building")
+}
+
+func TestTargetQueue_ParallelBuildsStopOnError(t *testing.T) {
+	f := newTargetQueueFixture(t)
+
+	a := model.MustNewImageTarget(container.MustParseSelector("a"))
+	b := model.MustNewImageTarget(container.MustParseSelector("b"))
+	c := model.MustNewImageTarget(container.MustParseSelector("c")).WithDependencyIDs([]model.TargetID{a.ID()})
+
+	var mu sync.Mutex
+	var built []model.TargetID
+	q := f.newQueue([]model.ImageTarget{a, b, c}).WithMaxParallel(2)
+	err := q.RunBuilds(f.ctx, func(ctx context.Context, target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
+		mu.Lock()
+		built = append(built, target.ID())
+		mu.Unlock()
+		if target.ID() == a.ID() {
+			return nil, fmt.Errorf("a is broken")
+		}
+		return store.NewImageBuildResultSingleRef(target.ID(), container.MustParseNamedTagged("b:tilt-1")), nil
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "a is broken")
+	}
+
+	// c depends on a, so it never starts.
+	assert.NotContains(t, built, c.ID())
+}
+
 func newFakeBuildHandlerCall(target model.ImageTarget, num int, depResults []store.BuildResult) fakeBuildHandlerCall {
 	return fakeBuildHandlerCall{
 		target: target,
@@ -195,7 +294,7 @@ func newFakeBuildHandler() *fakeBuildHandler {
 	}
 }
 
-func (fbh *fakeBuildHandler) handle(target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
+func (fbh *fakeBuildHandler) handle(ctx context.Context, target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
 	iTarget := target.(model.ImageTarget)
 	fbh.buildNum++
 	namedTagged := container.MustParseNamedTagged(fmt.Sprintf("%s:%d", iTarget.Refs.ConfigurationRef, fbh.buildNum))
@@ -207,15 +306,18 @@ func (fbh *fakeBuildHandler) handle(target model.TargetSpec, depResults []store.
 type targetQueueFixture struct {
 	t             *testing.T
 	ctx           context.Context
+	out           *bufsync.ThreadSafeBuffer
 	handler       *fakeBuildHandler
 	missingImages []reference.NamedTagged
 }
 
 func newTargetQueueFixture(t *testing.T) *targetQueueFixture {
-	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	out := bufsync.NewThreadSafeBuffer()
+	ctx, _, _ := testutils.ForkedCtxAndAnalyticsForTest(out)
 	return &targetQueueFixture{
 		t:       t,
 		ctx:     ctx,
+		out:     out,
 		handler: newFakeBuildHandler(),
 	}
 }
@@ -233,13 +335,19 @@ func (f *targetQueueFixture) setMissingImage(namedTagged reference.NamedTagged)
 	f.missingImages = append(f.missingImages, namedTagged)
 }
 
+func (f *targetQueueFixture) newQueue(targets []model.ImageTarget) *TargetQueue {
+	tq, err := NewImageTargetQueue(f.ctx, targets, store.BuildStateSet{}, f.imageExists)
+	require.NoError(f.t, err)
+	return tq
+}
+
 func (f *targetQueueFixture) run(targets []model.ImageTarget, buildStateSet store.BuildStateSet) {
 	tq, err := NewImageTargetQueue(f.ctx, targets, buildStateSet, f.imageExists)
 	if err != nil {
 		f.t.Fatal(err)
 	}
 
-	err = tq.RunBuilds(f.handler.handle)
+	err = tq.RunBuilds(f.ctx, f.handler.handle)
 	if err != nil {
 		f.t.Fatal(err)
 	}
@@ -12,8 +12,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
 	"github.com/tilt-dev/tilt/internal/docker"
-	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/tracer"
@@ -28,6 +28,8 @@ var BaseWireSet = wire.NewSet(
 	build.NewDockerImageBuilder,
 	build.NewExecCustomBuilder,
 	wire.Bind(new(build.CustomBuilder), new(*build.ExecCustomBuilder)),
+	build.NewDockerRemoteBuildCache,
+	wire.Bind(new(build.RemoteBuildCache), new(*build.DockerRemoteBuildCache)),
 
 	// BuildOrder
 	NewDockerComposeBuildAndDeployer,
@@ -36,6 +38,7 @@ var BaseWireSet = wire.NewSet(
 	NewLocalTargetBuildAndDeployer,
 	containerupdate.NewDockerUpdater,
 	containerupdate.NewExecUpdater,
+	containerupdate.NewDockerComposeUpdater,
 	NewImageBuilder,
 
 	tracer.InitOpenTelemetry,
@@ -64,7 +67,7 @@ func ProvideImageBuildAndDeployer(
 
 func ProvideDockerComposeBuildAndDeployer(
 	ctx context.Context,
-	dcCli dockercompose.DockerComposeClient,
+	dcsr *dockercomposeservice.Reconciler,
 	dCli docker.Client,
 	dir *dirs.TiltDevDir) (*DockerComposeBuildAndDeployer, error) {
 	wire.Build(
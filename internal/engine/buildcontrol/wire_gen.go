@@ -14,8 +14,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
 	"github.com/tilt-dev/tilt/internal/docker"
-	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/tracer"
@@ -33,7 +33,8 @@ func ProvideImageBuildAndDeployer(ctx context.Context, docker2 docker.Client, kC
 	if err != nil {
 		return nil, err
 	}
-	imageBuildAndDeployer := NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, kClient, env, kubeContext, analytics2, updateMode, clock, kp)
+	dockerRemoteBuildCache := build.NewDockerRemoteBuildCache(docker2)
+	imageBuildAndDeployer := NewImageBuildAndDeployer(dockerBuilder, execCustomBuilder, kClient, env, kubeContext, analytics2, updateMode, clock, kp, dockerRemoteBuildCache)
 	return imageBuildAndDeployer, nil
 }
 
@@ -42,7 +43,7 @@ var (
 	_wireUpdateModeFlagValue = UpdateModeFlag(UpdateModeAuto)
 )
 
-func ProvideDockerComposeBuildAndDeployer(ctx context.Context, dcCli dockercompose.DockerComposeClient, dCli docker.Client, dir *dirs.TiltDevDir) (*DockerComposeBuildAndDeployer, error) {
+func ProvideDockerComposeBuildAndDeployer(ctx context.Context, dcsr *dockercomposeservice.Reconciler, dCli docker.Client, dir *dirs.TiltDevDir) (*DockerComposeBuildAndDeployer, error) {
 	labels := _wireLabelsValue
 	dockerImageBuilder := build.NewDockerImageBuilder(dCli, labels)
 	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
@@ -65,7 +66,7 @@ func ProvideDockerComposeBuildAndDeployer(ctx context.Context, dcCli dockercompo
 		return nil, err
 	}
 	imageBuilder := NewImageBuilder(dockerBuilder, execCustomBuilder, updateMode)
-	dockerComposeBuildAndDeployer := NewDockerComposeBuildAndDeployer(dcCli, dCli, imageBuilder, clock)
+	dockerComposeBuildAndDeployer := NewDockerComposeBuildAndDeployer(dcsr, dCli, imageBuilder, clock)
 	return dockerComposeBuildAndDeployer, nil
 }
 
@@ -77,8 +78,8 @@ var (
 
 // wire.go:
 
-var BaseWireSet = wire.NewSet(wire.Value(dockerfile.Labels{}), k8s.ProvideMinikubeClient, build.DefaultDockerBuilder, build.NewDockerImageBuilder, build.NewExecCustomBuilder, wire.Bind(new(build.CustomBuilder), new(*build.ExecCustomBuilder)), NewDockerComposeBuildAndDeployer,
+var BaseWireSet = wire.NewSet(wire.Value(dockerfile.Labels{}), k8s.ProvideMinikubeClient, build.DefaultDockerBuilder, build.NewDockerImageBuilder, build.NewExecCustomBuilder, wire.Bind(new(build.CustomBuilder), new(*build.ExecCustomBuilder)), build.NewDockerRemoteBuildCache, wire.Bind(new(build.RemoteBuildCache), new(*build.DockerRemoteBuildCache)), NewDockerComposeBuildAndDeployer,
 	NewImageBuildAndDeployer,
 	NewLiveUpdateBuildAndDeployer,
-	NewLocalTargetBuildAndDeployer, containerupdate.NewDockerUpdater, containerupdate.NewExecUpdater, NewImageBuilder, tracer.InitOpenTelemetry, ProvideUpdateMode,
+	NewLocalTargetBuildAndDeployer, containerupdate.NewDockerUpdater, containerupdate.NewExecUpdater, containerupdate.NewDockerComposeUpdater, NewImageBuilder, tracer.InitOpenTelemetry, ProvideUpdateMode,
 )
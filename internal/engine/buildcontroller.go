@@ -17,6 +17,10 @@ type BuildController struct {
 	b                  buildcontrol.BuildAndDeployer
 	buildsStartedCount int // used to synchronize with state
 	disabledForTesting bool
+
+	// The number of builds started for each manifest, so that each build
+	// gets its own span ID.
+	manifestBuildCounts map[model.ManifestName]int
 }
 
 type buildEntry struct {
@@ -34,7 +38,8 @@ func (e buildEntry) BuildReason() model.BuildReason { return e.buildReason }
 
 func NewBuildController(b buildcontrol.BuildAndDeployer) *BuildController {
 	return &BuildController{
-		b: b,
+		b:                   b,
+		manifestBuildCounts: make(map[model.ManifestName]int),
 	}
 }
 
@@ -61,6 +66,7 @@ func (c *BuildController) needsBuild(ctx context.Context, st store.RStore) (buil
 	c.buildsStartedCount += 1
 	ms := mt.State
 	manifest := mt.Manifest
+	c.manifestBuildCounts[manifest.Name] += 1
 
 	buildReason := mt.NextBuildReason()
 	targets := buildcontrol.BuildTargets(manifest)
@@ -72,7 +78,7 @@ func (c *BuildController) needsBuild(ctx context.Context, st store.RStore) (buil
 		buildReason:   buildReason,
 		buildStateSet: buildStateSet,
 		filesChanged:  append(ms.ConfigFilesThatCausedChange, buildStateSet.FilesChanged()...),
-		spanID:        SpanIDForBuildLog(c.buildsStartedCount),
+		spanID:        SpanIDForBuildLog(manifest.Name, c.manifestBuildCounts[manifest.Name]),
 	}, true
 }
 
@@ -138,8 +144,9 @@ func (w BuildLogActionWriter) Write(level logger.Level, fields logger.Fields, p
 	return nil
 }
 
-func SpanIDForBuildLog(buildCount int) logstore.SpanID {
-	return logstore.SpanID(fmt.Sprintf("build:%d", buildCount))
+// Build spans are numbered per manifest, e.g., "build:fe:3" is the third build of fe.
+func SpanIDForBuildLog(mn model.ManifestName, buildCount int) logstore.SpanID {
+	return logstore.SpanID(fmt.Sprintf("build:%s:%d", mn, buildCount))
 }
 
 // Extract a set of build states from a manifest for BuildAndDeploy.
@@ -188,6 +195,12 @@ func buildStateSet(ctx context.Context, manifest model.Manifest, specs []model.T
 					buildState = buildState.WithRunningContainers(store.RunningContainersForDC(ms.DCRuntimeState()))
 				}
 			}
+
+			// Services that docker-compose builds are live-updated through the service itself.
+			dcTarget, ok := spec.(model.DockerComposeTarget)
+			if ok && !dcTarget.LiveUpdateInfo().Empty() {
+				buildState = buildState.WithRunningContainers(store.RunningContainersForDC(ms.DCRuntimeState()))
+			}
 		}
 		result[id] = buildState
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
@@ -37,6 +38,8 @@ type ConfigsReloadedAction struct {
 	VersionSettings      model.VersionSettings
 	UpdateSettings       model.UpdateSettings
 	WatchSettings        model.WatchSettings
+	ExtensionRepos       []*v1alpha1.ExtensionRepo
+	UserConfigSettings   []model.UserConfigSetting
 
 	// A checkpoint into the logstore when Tiltfile execution started.
 	// Useful for knowing how far back in time we have to scrub secrets.
@@ -180,6 +180,8 @@ func (cc *ConfigsController) loadTiltfile(ctx context.Context, st store.RStore,
 		VersionSettings:       tlr.VersionSettings,
 		UpdateSettings:        tlr.UpdateSettings,
 		WatchSettings:         tlr.WatchSettings,
+		ExtensionRepos:        tlr.ExtensionRepos,
+		UserConfigSettings:    tlr.UserConfigSettings,
 	})
 }
 
@@ -198,7 +200,7 @@ func (cc *ConfigsController) OnChange(ctx context.Context, st store.RStore, _ st
 }
 
 func requiresDocker(tlr tiltfile.TiltfileLoadResult) bool {
-	if tlr.Orchestrator() == model.OrchestratorDC {
+	if tlr.HasDockerCompose() {
 		return true
 	}
 
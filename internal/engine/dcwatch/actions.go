@@ -6,12 +6,25 @@ import (
 	"github.com/docker/docker/api/types"
 
 	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
+// Reports a container event for a Docker Compose service
+// that belongs to a manifest.
 type EventAction struct {
+	ManifestName   model.ManifestName
 	Event          dockercompose.Event
 	Time           time.Time
 	ContainerState types.ContainerState
 }
 
 func (EventAction) Action() {}
+
+func NewEventAction(mn model.ManifestName, evt dockercompose.Event, state types.ContainerState) EventAction {
+	return EventAction{
+		ManifestName:   mn,
+		Event:          evt,
+		Time:           time.Now(),
+		ContainerState: state,
+	}
+}
@@ -0,0 +1,109 @@
+package disable
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
+	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Controller tears down the Kubernetes objects and Docker Compose
+// containers of resources that the user has disabled.
+//
+// Servers of disabled local resources are stopped by the local.ServerController.
+type Controller struct {
+	kCli k8s.Client
+	dcsr *dockercomposeservice.Reconciler
+
+	// Resources we've already torn down, so that we only do it once
+	// per disable.
+	tornDown map[model.ManifestName]bool
+}
+
+var _ store.Subscriber = &Controller{}
+
+func NewController(kCli k8s.Client, dcsr *dockercomposeservice.Reconciler) *Controller {
+	return &Controller{
+		kCli:     kCli,
+		dcsr:     dcsr,
+		tornDown: make(map[model.ManifestName]bool),
+	}
+}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	toTearDown := c.determineTeardowns(st)
+	for _, m := range toTearDown {
+		c.tornDown[m.Name] = true
+		c.tearDown(ctx, st, m)
+	}
+	return nil
+}
+
+func (c *Controller) determineTeardowns(st store.RStore) []model.Manifest {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	for mn := range c.tornDown {
+		if !state.IsResourceDisabled(mn) {
+			delete(c.tornDown, mn)
+		}
+	}
+
+	var result []model.Manifest
+	for _, mt := range state.Targets() {
+		mn := mt.Manifest.Name
+		if !state.IsResourceDisabled(mn) || c.tornDown[mn] {
+			continue
+		}
+
+		// Wait for any in-flight build to finish, so that we don't
+		// race it to the cluster.
+		if mt.State.IsBuilding() {
+			continue
+		}
+
+		// If we never deployed it, there's nothing to tear down.
+		if !mt.State.StartedFirstBuild() {
+			c.tornDown[mn] = true
+			continue
+		}
+
+		result = append(result, mt.Manifest)
+	}
+	return result
+}
+
+func (c *Controller) tearDown(ctx context.Context, st store.RStore, m model.Manifest) {
+	ctx = store.WithManifestLogHandler(ctx, st, m.Name, SpanIDForDisable(m.Name))
+	l := logger.Get(ctx)
+
+	var err error
+	switch {
+	case m.IsK8s():
+		l.Infof("Resource disabled. Deleting its Kubernetes objects…")
+		err = buildcontrol.DeleteK8sTarget(ctx, c.kCli, m.K8sTarget())
+	case m.IsDC():
+		l.Infof("Resource disabled. Removing its Docker Compose container…")
+		err = c.dcsr.ForceDelete(ctx, buildcontrol.DockerComposeServiceObject(m.DockerComposeTarget()))
+	default:
+		return
+	}
+
+	if err != nil {
+		l.Errorf("Error tearing down %s: %v", m.Name, err)
+	}
+}
+
+func SpanIDForDisable(mn model.ManifestName) logstore.SpanID {
+	return logstore.SpanID(fmt.Sprintf("disable:%s", mn))
+}
@@ -0,0 +1,147 @@
+package disable
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestDisableK8s(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := manifestbuilder.New(f, "sancho").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.upsertBuiltManifest(m)
+
+	f.onChange()
+	assert.Empty(t, f.kCli.DeletedYaml)
+
+	f.setDisabled(m.Name, true)
+	f.onChange()
+	assert.Contains(t, f.kCli.DeletedYaml, "name: sancho")
+
+	// Only tear down once per disable.
+	f.kCli.DeletedYaml = ""
+	f.onChange()
+	assert.Empty(t, f.kCli.DeletedYaml)
+
+	// Re-enabling and re-disabling tears down again.
+	f.setDisabled(m.Name, false)
+	f.onChange()
+	f.upsertBuiltManifest(m)
+	f.setDisabled(m.Name, true)
+	f.onChange()
+	assert.Contains(t, f.kCli.DeletedYaml, "name: sancho")
+}
+
+func TestDisableDockerCompose(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := manifestbuilder.New(f, "fe").WithDockerCompose().Build()
+	f.upsertBuiltManifest(m)
+	f.setDisabled(m.Name, true)
+	f.onChange()
+
+	require.Len(t, f.dcCli.RmCalls, 1)
+	assert.Equal(t, m.DockerComposeTarget().Name, f.dcCli.RmCalls[0].ServiceName)
+}
+
+func TestDisableWaitsForBuild(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := manifestbuilder.New(f, "sancho").WithK8sYAML(testyaml.SanchoYAML).Build()
+	mt := f.upsertBuiltManifest(m)
+	f.setDisabled(m.Name, true)
+
+	f.st.WithState(func(state *store.EngineState) {
+		mt.State.CurrentBuild = model.BuildRecord{StartTime: time.Now()}
+	})
+	f.onChange()
+	assert.Empty(t, f.kCli.DeletedYaml)
+
+	f.st.WithState(func(state *store.EngineState) {
+		mt.State.CurrentBuild = model.BuildRecord{}
+	})
+	f.onChange()
+	assert.Contains(t, f.kCli.DeletedYaml, "name: sancho")
+}
+
+func TestDisableNeverBuilt(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := manifestbuilder.New(f, "sancho").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+	f.setDisabled(m.Name, true)
+	f.onChange()
+	assert.Empty(t, f.kCli.DeletedYaml)
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx   context.Context
+	st    *store.TestingStore
+	kCli  *k8s.FakeK8sClient
+	dcCli *dockercompose.FakeDCClient
+	c     *Controller
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	kCli := k8s.NewFakeK8sClient(t)
+	dcCli := dockercompose.NewFakeDockerComposeClient(t, ctx)
+	st := store.NewTestingStore()
+	dcsr := dockercomposeservice.NewReconciler(ctx, dcCli, docker.NewFakeClient(), st)
+	dcsr.SetClient(fake.NewTiltClient())
+	return &fixture{
+		TempDirFixture: tempdir.NewTempDirFixture(t),
+		ctx:            ctx,
+		st:             st,
+		kCli:           kCli,
+		dcCli:          dcCli,
+		c:              NewController(kCli, dcsr),
+	}
+}
+
+func (f *fixture) upsertBuiltManifest(m model.Manifest) *store.ManifestTarget {
+	mt := store.NewManifestTarget(m)
+	mt.State.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpsertManifestTarget(mt)
+	})
+	return mt
+}
+
+func (f *fixture) setDisabled(mn model.ManifestName, disabled bool) {
+	f.st.WithState(func(state *store.EngineState) {
+		if disabled {
+			state.DisableResource(mn)
+		} else {
+			state.EnableResource(mn)
+		}
+	})
+}
+
+func (f *fixture) onChange() {
+	err := f.c.OnChange(f.ctx, f.st, store.LegacyChangeSummary())
+	require.NoError(f.T(), err)
+}
@@ -0,0 +1,129 @@
+package extensionrepo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Creates ExtensionRepo objects for the repos declared in the Tiltfile.
+type Subscriber struct {
+	client ctrlclient.Client
+
+	// Repos that the Tiltfile declares, but that someone else already
+	// created, so that we only warn about each one once.
+	conflicts map[string]bool
+}
+
+func NewSubscriber(client ctrlclient.Client) *Subscriber {
+	return &Subscriber{
+		client:    client,
+		conflicts: make(map[string]bool),
+	}
+}
+
+func (s *Subscriber) currentRepos(st store.RStore) map[string]*v1alpha1.ExtensionRepo {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	result := make(map[string]*v1alpha1.ExtensionRepo, len(state.ExtensionRepos))
+	for _, repo := range state.ExtensionRepos {
+		result[repo.Name] = repo.DeepCopy()
+	}
+	return result
+}
+
+func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	current := s.currentRepos(st)
+
+	storedList := &v1alpha1.ExtensionRepoList{}
+	err := s.client.List(ctx, storedList)
+	if err != nil {
+		// If the cache hasn't started yet, that's OK.
+		// We'll get it on the next OnChange()
+		if strings.Contains(err.Error(), "cache not started") {
+			return nil
+		}
+
+		logger.Get(ctx).Infof("listing extensionrepo: %v", err)
+		return nil
+	}
+
+	conflicts := make(map[string]bool)
+	for _, stored := range storedList.Items {
+		repo, ok := current[stored.Name]
+
+		// Leave alone any repos that someone else created, even if the
+		// Tiltfile declares a repo with the same name.
+		if stored.Annotations[v1alpha1.AnnotationOwnerTiltfile] == "" {
+			if ok {
+				delete(current, stored.Name)
+				conflicts[stored.Name] = true
+				if !s.conflicts[stored.Name] {
+					logger.Get(ctx).Warnf("extension_repo(%q): an ExtensionRepo with that name already exists "+
+						"and wasn't created by the Tiltfile, so Tilt won't update it", stored.Name)
+				}
+			}
+			continue
+		}
+
+		if !ok {
+			// If the Tiltfile no longer declares the repo, delete it.
+			err := s.client.Delete(ctx, &stored)
+			if err != nil && !apierrors.IsNotFound(err) {
+				st.Dispatch(store.NewErrorAction(fmt.Errorf("deleting extensionrepo %s: %v", stored.Name, err)))
+				return nil
+			}
+			continue
+		}
+		delete(current, stored.Name)
+
+		if !apicmp.DeepEqual(repo.Spec, stored.Spec) {
+			update := stored.DeepCopy()
+			update.Spec = repo.Spec
+			err = s.client.Update(ctx, update)
+			if err != nil {
+				logger.Get(ctx).Infof("updating extensionrepo %s: %v", stored.Name, err)
+				return nil
+			}
+			stored = *update
+		}
+
+		if !apicmp.DeepEqual(repo.Status, stored.Status) {
+			update := stored.DeepCopy()
+			update.Status = repo.Status
+			err = s.client.Status().Update(ctx, update)
+			if err != nil {
+				logger.Get(ctx).Infof("updating extensionrepo %s: %v", stored.Name, err)
+				return nil
+			}
+		}
+	}
+
+	s.conflicts = conflicts
+
+	// Whatever's left hasn't been created yet.
+	for name, repo := range current {
+		err := s.client.Create(ctx, repo)
+		if err != nil {
+			logger.Get(ctx).Infof("creating extensionrepo %s: %v", name, err)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+var _ store.Subscriber = &Subscriber{}
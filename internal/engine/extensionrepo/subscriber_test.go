@@ -0,0 +1,151 @@
+package extensionrepo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestCreate(t *testing.T) {
+	f := newFixture(t)
+
+	f.setRepos(newRepo("myorg", "https://github.com/myorg/tilt-extensions", ""))
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	r := f.repo("myorg")
+	require.NotNil(t, r)
+	assert.Equal(t, "https://github.com/myorg/tilt-extensions", r.Spec.URL)
+	assert.Equal(t, "1", r.ObjectMeta.ResourceVersion)
+
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	r = f.repo("myorg")
+	assert.Equal(t, "1", r.ObjectMeta.ResourceVersion)
+}
+
+func TestUpdateSpecAndStatus(t *testing.T) {
+	f := newFixture(t)
+
+	f.setRepos(newRepo("myorg", "https://github.com/myorg/tilt-extensions", ""))
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	repo := newRepo("myorg", "https://github.com/myorg/tilt-extensions", "v1")
+	repo.Status.CheckoutRef = "abc123"
+	f.setRepos(repo)
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	r := f.repo("myorg")
+	require.NotNil(t, r)
+	assert.Equal(t, "v1", r.Spec.Ref)
+	assert.Equal(t, "abc123", r.Status.CheckoutRef)
+}
+
+func TestDelete(t *testing.T) {
+	f := newFixture(t)
+
+	f.setRepos(
+		newRepo("myorg", "https://github.com/myorg/tilt-extensions", ""),
+		newRepo("local", "file:///src/extensions", ""))
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	require.NotNil(t, f.repo("local"))
+
+	f.setRepos(newRepo("myorg", "https://github.com/myorg/tilt-extensions", ""))
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	assert.Nil(t, f.repo("local"))
+	assert.NotNil(t, f.repo("myorg"))
+}
+
+func TestDeleteOnlyTiltfileRepos(t *testing.T) {
+	f := newFixture(t)
+
+	other := newRepo("other", "https://github.com/other/tilt-extensions", "")
+	other.Annotations = nil
+	require.NoError(t, f.tc.Create(f.ctx, other))
+
+	f.setRepos(newRepo("myorg", "https://github.com/myorg/tilt-extensions", ""))
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	f.setRepos()
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	assert.Nil(t, f.repo("myorg"))
+	assert.NotNil(t, f.repo("other"))
+}
+
+func TestDontUpdateReposFromElsewhere(t *testing.T) {
+	f := newFixture(t)
+
+	other := newRepo("myorg", "https://github.com/other/tilt-extensions", "")
+	other.Annotations = nil
+	require.NoError(t, f.tc.Create(f.ctx, other))
+
+	f.setRepos(newRepo("myorg", "https://github.com/myorg/tilt-extensions", "v1"))
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	r := f.repo("myorg")
+	require.NotNil(t, r)
+	assert.Equal(t, "https://github.com/other/tilt-extensions", r.Spec.URL)
+	assert.Empty(t, r.Annotations[v1alpha1.AnnotationOwnerTiltfile])
+	assert.Equal(t, 1, strings.Count(f.out.String(), `extension_repo("myorg"): an ExtensionRepo with that name already exists`))
+}
+
+type fixture struct {
+	t     *testing.T
+	out   *bytes.Buffer
+	ctx   context.Context
+	store *store.TestingStore
+	tc    ctrlclient.Client
+	sub   *Subscriber
+}
+
+func newFixture(t *testing.T) *fixture {
+	tc := fake.NewTiltClient()
+	out := bytes.NewBuffer(nil)
+	return &fixture{
+		t:     t,
+		out:   out,
+		ctx:   logger.WithLogger(context.Background(), logger.NewTestLogger(out)),
+		tc:    tc,
+		sub:   NewSubscriber(tc),
+		store: store.NewTestingStore(),
+	}
+}
+
+func newRepo(name, url, ref string) *v1alpha1.ExtensionRepo {
+	return &v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{v1alpha1.AnnotationOwnerTiltfile: "true"},
+		},
+		Spec: v1alpha1.ExtensionRepoSpec{URL: url, Ref: ref},
+	}
+}
+
+func (f *fixture) setRepos(repos ...*v1alpha1.ExtensionRepo) {
+	f.store.WithState(func(state *store.EngineState) {
+		state.ExtensionRepos = repos
+	})
+}
+
+func (f *fixture) repo(name string) *v1alpha1.ExtensionRepo {
+	r := &v1alpha1.ExtensionRepo{}
+	err := f.tc.Get(f.ctx, types.NamespacedName{Name: name}, r)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	require.NoError(f.t, err)
+	return r
+}
@@ -35,6 +35,7 @@ type WatchableTarget interface {
 var _ WatchableTarget = model.ImageTarget{}
 var _ WatchableTarget = model.LocalTarget{}
 var _ WatchableTarget = model.DockerComposeTarget{}
+var _ WatchableTarget = model.K8sTarget{}
 
 // ManifestSubscriber watches the store for changes to manifests and creates/updates/deletes FileWatch objects.
 type ManifestSubscriber struct {
@@ -147,6 +148,11 @@ func FileWatchesFromManifests(state store.EngineState) []*filewatches.FileWatch
 	var fileWatches []*filewatches.FileWatch
 	processedTargets := make(map[model.TargetID]bool)
 	for _, m := range state.Manifests() {
+		// Disabled resources don't build, so there's no point watching their files.
+		if state.IsResourceDisabled(m.Name) {
+			continue
+		}
+
 		for _, t := range m.TargetSpecs() {
 			targetID := t.ID()
 			// ignore targets that have already been processed or aren't watchable
@@ -173,8 +173,11 @@ func (m *ManifestSubscriber) kubernetesDiscoveryFromManifest(_ context.Context,
 	kt := mt.Manifest.K8sTarget()
 
 	krs := mt.State.K8sRuntimeState()
-	if len(kt.ObjectRefs) == 0 {
+	if len(kt.ObjectRefs) == 0 && len(krs.DeployedEntities) == 0 {
 		// there is nothing to discover
+		//
+		// (targets with a custom deploy command don't know their objects
+		// until the command has run, so we rely on the deployed entities)
 		return nil
 	}
 
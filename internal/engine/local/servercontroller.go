@@ -3,6 +3,8 @@ package local
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
@@ -96,13 +99,23 @@ func (c *ServerController) determineServers(ctx context.Context, st store.RStore
 			continue
 		}
 
+		// Leaving out the server of a disabled resource orphans its Cmd,
+		// which stops the process.
+		if state.IsResourceDisabled(mt.Manifest.Name) {
+			continue
+		}
+
 		name := mt.Manifest.Name.String()
+		annotations := map[string]string{
+			AnnotationDepStatus: string(mt.UpdateStatus()),
+		}
+		if secretEnv := secretEnvNames(lt.ServeCmd.Env, state.Secrets); len(secretEnv) > 0 {
+			annotations[v1alpha1.AnnotationSecretEnv] = strings.Join(secretEnv, ",")
+		}
 		cmdServer := CmdServer{
 			ObjectMeta: ObjectMeta{
-				Name: name,
-				Annotations: map[string]string{
-					AnnotationDepStatus: string(mt.UpdateStatus()),
-				},
+				Name:        name,
+				Annotations: annotations,
 			},
 			Spec: CmdServerSpec{
 				Args:           lt.ServeCmd.Argv,
@@ -130,6 +143,19 @@ func (c *ServerController) determineServers(ctx context.Context, st store.RStore
 	return servers, owned, orphaned
 }
 
+// The names of the environment variables whose values contain secrets.
+func secretEnvNames(env []string, secrets model.SecretSet) []string {
+	var result []string
+	for _, e := range env {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) == 2 && secrets.Contains([]byte(parts[1])) {
+			result = append(result, parts[0])
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 // Find the most recent command in a collection
 func (c *ServerController) mostRecentCmd(cmds []*Cmd) *Cmd {
 	var mostRecentCmd *Cmd
@@ -254,6 +280,9 @@ func (c *ServerController) reconcile(ctx context.Context, server CmdServer, owne
 		},
 		Spec: cmdSpec,
 	}
+	if secretEnv := server.Annotations[v1alpha1.AnnotationSecretEnv]; secretEnv != "" {
+		cmd.Annotations[v1alpha1.AnnotationSecretEnv] = secretEnv
+	}
 	c.recentlyCreatedCmd[name] = cmdName
 
 	err := c.client.Create(ctx, cmd)
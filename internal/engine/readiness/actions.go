@@ -0,0 +1,17 @@
+package readiness
+
+import (
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Dispatched when the readiness probe of a Kubernetes or Docker Compose
+// resource changes status.
+type ProbeStatusAction struct {
+	ManifestName model.ManifestName
+	Ready        bool
+	Time         time.Time
+}
+
+func (ProbeStatusAction) Action() {}
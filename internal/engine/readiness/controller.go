@@ -0,0 +1,148 @@
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"github.com/tilt-dev/probe/pkg/prober"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Controller runs the Tilt-side readiness probes of Kubernetes and
+// Docker Compose resources.
+//
+// A probe starts running once its resource has deployed, and stops
+// when the resource is disabled or the probe is removed from the Tiltfile.
+//
+// Probes also stop while their resource is building, so that each deploy
+// gets a fresh probe that reports its first result.
+//
+// Readiness probes of local resources are run by the Cmd controller.
+type Controller struct {
+	proberManager cmd.ProberManager
+	probes        map[model.ManifestName]*runningProbe
+}
+
+type runningProbe struct {
+	spec   *v1alpha1.Probe
+	cancel context.CancelFunc
+}
+
+var _ store.Subscriber = &Controller{}
+var _ store.TearDowner = &Controller{}
+
+func NewController(proberManager cmd.ProberManager) *Controller {
+	return &Controller{
+		proberManager: proberManager,
+		probes:        make(map[model.ManifestName]*runningProbe),
+	}
+}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	specs := c.determineProbes(st)
+	for mn, p := range c.probes {
+		spec, ok := specs[mn]
+		if !ok || !equality.Semantic.DeepEqual(spec, p.spec) {
+			p.cancel()
+			delete(c.probes, mn)
+		}
+	}
+
+	for mn, spec := range specs {
+		if _, ok := c.probes[mn]; ok {
+			continue
+		}
+		c.probes[mn] = c.startProbe(ctx, st, mn, spec)
+	}
+	return nil
+}
+
+func (c *Controller) TearDown(ctx context.Context) {
+	for mn, p := range c.probes {
+		p.cancel()
+		delete(c.probes, mn)
+	}
+}
+
+// Returns the probes that should be running, by resource.
+func (c *Controller) determineProbes(st store.RStore) map[model.ManifestName]*v1alpha1.Probe {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	result := make(map[model.ManifestName]*v1alpha1.Probe)
+	for _, mt := range state.Targets() {
+		mn := mt.Manifest.Name
+		if state.IsResourceDisabled(mn) || mt.State.LastSuccessfulDeployTime.IsZero() ||
+			state.CurrentlyBuilding[mn] {
+			continue
+		}
+
+		var spec *v1alpha1.Probe
+		if mt.Manifest.IsK8s() {
+			spec = mt.Manifest.K8sTarget().ReadinessProbe
+		} else if mt.Manifest.IsDC() {
+			spec = mt.Manifest.DockerComposeTarget().ReadinessProbe
+		}
+		if spec != nil {
+			result[mn] = spec
+		}
+	}
+	return result
+}
+
+func (c *Controller) startProbe(ctx context.Context, st store.RStore, mn model.ManifestName, spec *v1alpha1.Probe) *runningProbe {
+	ctx, cancel := context.WithCancel(ctx)
+	ctx = store.WithManifestLogHandler(ctx, st, mn, SpanIDForReadinessProbe(mn))
+
+	statusChangeFunc := func(status prober.Result, output string) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if status == prober.Success {
+			// successful probes are ONLY logged on status change to reduce chattiness
+			cmd.LogProbeOutput(ctx, status, output, nil)
+		}
+
+		st.Dispatch(ProbeStatusAction{
+			ManifestName: mn,
+			Ready:        status == prober.Success || status == prober.Warning,
+			Time:         time.Now(),
+		})
+	}
+	resultLoggerFunc := func(result prober.Result, output string, err error) {
+		if ctx.Err() != nil {
+			return
+		}
+		if result != prober.Success {
+			cmd.LogProbeOutput(ctx, result, output, err)
+		}
+	}
+
+	worker, err := cmd.ProbeWorkerFromSpec(c.proberManager, spec, statusChangeFunc, resultLoggerFunc)
+	if err != nil {
+		// Don't retry until the spec changes.
+		logger.Get(ctx).Errorf("Invalid readiness probe: %v", err)
+		return &runningProbe{spec: spec, cancel: cancel}
+	}
+
+	go worker.Run(ctx)
+	return &runningProbe{spec: spec, cancel: cancel}
+}
+
+func SpanIDForReadinessProbe(mn model.ManifestName) logstore.SpanID {
+	return logstore.SpanID(fmt.Sprintf("readiness:%s", mn))
+}
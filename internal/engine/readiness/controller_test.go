@@ -0,0 +1,185 @@
+package readiness
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+var tcpProbe = &v1alpha1.Probe{
+	Handler: v1alpha1.Handler{TCPSocket: &v1alpha1.TCPSocketAction{Port: 8000}},
+}
+
+func TestProbeStartsAfterDeploy(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := f.k8sManifestWithProbe("sancho", tcpProbe)
+	mt := store.NewManifestTarget(m)
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpsertManifestTarget(mt)
+	})
+
+	f.onChange()
+	assert.Empty(t, f.c.probes)
+
+	f.st.WithState(func(state *store.EngineState) {
+		mt.State.LastSuccessfulDeployTime = time.Now()
+	})
+	f.onChange()
+
+	a := f.st.WaitForAction(t, reflect.TypeOf(ProbeStatusAction{})).(ProbeStatusAction)
+	assert.Equal(t, m.Name, a.ManifestName)
+	assert.True(t, a.Ready)
+	assert.Equal(t, 1, f.fpm.ProbeCount())
+}
+
+func TestProbeStopsWhenDisabled(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := f.k8sManifestWithProbe("sancho", tcpProbe)
+	f.upsertDeployedManifest(m)
+	f.onChange()
+	require.Len(t, f.c.probes, 1)
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.DisableResource(m.Name)
+	})
+	f.onChange()
+	assert.Empty(t, f.c.probes)
+}
+
+func TestProbeRestartsAfterBuild(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := f.k8sManifestWithProbe("sancho", tcpProbe)
+	f.upsertDeployedManifest(m)
+	f.onChange()
+	require.Len(t, f.c.probes, 1)
+	f.st.WaitForAction(t, reflect.TypeOf(ProbeStatusAction{}))
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.CurrentlyBuilding[m.Name] = true
+	})
+	f.onChange()
+	assert.Empty(t, f.c.probes)
+
+	f.st.ClearActions()
+	f.st.WithState(func(state *store.EngineState) {
+		delete(state.CurrentlyBuilding, m.Name)
+	})
+	f.onChange()
+	require.Len(t, f.c.probes, 1)
+
+	// The new probe reports even though the result hasn't changed.
+	a := f.st.WaitForAction(t, reflect.TypeOf(ProbeStatusAction{})).(ProbeStatusAction)
+	assert.True(t, a.Ready)
+	assert.Equal(t, 2, f.fpm.ProbeCount())
+}
+
+func TestProbeRestartsWhenSpecChanges(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := f.k8sManifestWithProbe("sancho", tcpProbe)
+	f.upsertDeployedManifest(m)
+	f.onChange()
+	require.Len(t, f.c.probes, 1)
+	assert.Equal(t, tcpProbe, f.c.probes[m.Name].spec)
+
+	newProbe := tcpProbe.DeepCopy()
+	newProbe.TCPSocket.Port = 9000
+	m = f.k8sManifestWithProbe("sancho", newProbe)
+	f.upsertDeployedManifest(m)
+	f.onChange()
+	require.Len(t, f.c.probes, 1)
+	assert.Equal(t, newProbe, f.c.probes[m.Name].spec)
+}
+
+func TestHandleProbeStatusAction(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := f.k8sManifestWithProbe("sancho", tcpProbe)
+	mt := f.upsertDeployedManifest(m)
+	state := f.st.LockMutableStateForTesting()
+	defer f.st.UnlockMutableState()
+
+	krs := mt.State.K8sRuntimeState()
+	krs.HasReadinessProbe = true
+	mt.State.RuntimeState = krs
+
+	now := time.Now()
+	HandleProbeStatusAction(state, ProbeStatusAction{ManifestName: m.Name, Ready: true, Time: now})
+	krs = mt.State.K8sRuntimeState()
+	assert.True(t, krs.ProbeReady)
+	assert.Equal(t, now, krs.LastProbeReadyTime)
+
+	HandleProbeStatusAction(state, ProbeStatusAction{ManifestName: m.Name, Ready: false, Time: now.Add(time.Second)})
+	krs = mt.State.K8sRuntimeState()
+	assert.False(t, krs.ProbeReady)
+	assert.Equal(t, now, krs.LastProbeReadyTime)
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx    context.Context
+	cancel context.CancelFunc
+	st     *store.TestingStore
+	fpm    *cmd.FakeProberManager
+	c      *Controller
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	ctx, cancel := context.WithCancel(ctx)
+	fpm := cmd.NewFakeProberManager()
+	return &fixture{
+		TempDirFixture: tempdir.NewTempDirFixture(t),
+		ctx:            ctx,
+		cancel:         cancel,
+		st:             store.NewTestingStore(),
+		fpm:            fpm,
+		c:              NewController(fpm),
+	}
+}
+
+func (f *fixture) TearDown() {
+	f.c.TearDown(f.ctx)
+	f.cancel()
+	f.TempDirFixture.TearDown()
+}
+
+func (f *fixture) k8sManifestWithProbe(name model.ManifestName, spec *v1alpha1.Probe) model.Manifest {
+	m := manifestbuilder.New(f, name).WithK8sYAML(testyaml.SanchoYAML).Build()
+	return m.WithDeployTarget(m.K8sTarget().WithReadinessProbe(spec))
+}
+
+func (f *fixture) upsertDeployedManifest(m model.Manifest) *store.ManifestTarget {
+	mt := store.NewManifestTarget(m)
+	mt.State.LastSuccessfulDeployTime = time.Now()
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpsertManifestTarget(mt)
+	})
+	return mt
+}
+
+func (f *fixture) onChange() {
+	err := f.c.OnChange(f.ctx, f.st, store.LegacyChangeSummary())
+	require.NoError(f.T(), err)
+}
@@ -0,0 +1,25 @@
+package readiness
+
+import (
+	"github.com/tilt-dev/tilt/internal/store"
+)
+
+// Update the runtime state of the resource with the result of its readiness probe.
+func HandleProbeStatusAction(state *store.EngineState, action ProbeStatusAction) {
+	mt, ok := state.ManifestTargets[action.ManifestName]
+	if !ok {
+		return
+	}
+
+	ms := mt.State
+	if mt.Manifest.IsK8s() {
+		krs := ms.K8sRuntimeState()
+		krs.ProbeReady = action.Ready
+		if action.Ready {
+			krs.LastProbeReadyTime = action.Time
+		}
+		ms.RuntimeState = krs
+	} else if mt.Manifest.IsDC() {
+		ms.RuntimeState = ms.DCRuntimeState().WithProbeReady(action.Ready, action.Time)
+	}
+}
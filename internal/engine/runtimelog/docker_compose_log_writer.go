@@ -0,0 +1,78 @@
+package runtimelog
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Writes the output of `docker-compose logs` to the store,
+// stripping the service name prefix from each line.
+type DockerComposeLogActionWriter struct {
+	store        store.RStore
+	manifestName model.ManifestName
+
+	// If the next Write() call is on a new line. True when the writer is first
+	// created, or when the previous line ends with "\n".
+	isStartingNewLine bool
+}
+
+func NewDockerComposeLogActionWriter(st store.RStore, mn model.ManifestName) *DockerComposeLogActionWriter {
+	return &DockerComposeLogActionWriter{
+		store:             st,
+		manifestName:      mn,
+		isStartingNewLine: true,
+	}
+}
+
+var newlineAsBytes = []byte("\n")
+var dividerAsBytes = []byte(" | ")
+var attachingToLogAsBytes = []byte("Attaching to ")
+
+func (w *DockerComposeLogActionWriter) Write(p []byte) (n int, err error) {
+	lines := bytes.Split(p, newlineAsBytes)
+	if w.shouldFilterDCLog(lines) {
+		lines = lines[1:]
+	}
+
+	start := 1
+	if w.isStartingNewLine {
+		start = 0
+	}
+
+	for i := start; i < len(lines); i++ {
+		indexOfDivider := bytes.Index(lines[i], dividerAsBytes)
+		if indexOfDivider >= 0 {
+			newStart := indexOfDivider + len(dividerAsBytes)
+			lines[i] = lines[i][newStart:]
+		}
+	}
+
+	if len(lines) == 0 {
+		return len(p), nil
+	}
+
+	// If the last line is empty, then we're starting a newline.
+	w.isStartingNewLine = len(lines[len(lines)-1]) == 0
+	newText := bytes.Join(lines, newlineAsBytes)
+	w.store.Dispatch(store.NewLogAction(w.manifestName, SpanIDForDCService(w.manifestName), logger.InfoLvl, nil, newText))
+	return len(p), nil
+}
+
+func (w *DockerComposeLogActionWriter) shouldFilterDCLog(lines [][]byte) bool {
+	if !w.isStartingNewLine {
+		return false
+	}
+	if len(lines) == 0 {
+		return false
+	}
+	return bytes.HasPrefix(lines[0], attachingToLogAsBytes)
+}
+
+func SpanIDForDCService(mn model.ManifestName) logstore.SpanID {
+	return logstore.SpanID(fmt.Sprintf("dc:%s", mn))
+}
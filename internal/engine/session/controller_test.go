@@ -132,6 +132,92 @@ func TestExitControlCI_FirstRuntimeFailure(t *testing.T) {
 	f.store.requireExitSignalWithError("Pod pod-a in error state due to container c1: ErrImagePull")
 }
 
+func TestExitControlCI_PodWarningEvent(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.store.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+
+		mt := state.ManifestTargets["fe"]
+		mt.State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+		mt.State.RuntimeState = store.NewK8sRuntimeStateWithPods(mt.Manifest, v1alpha1.Pod{
+			Name:   "pod-a",
+			Phase:  string(v1.PodPending),
+			Status: "Pending",
+		})
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		mt := state.ManifestTargets["fe"]
+		krs := mt.State.K8sRuntimeState()
+
+		// Warnings that don't block the pod don't fail CI.
+		krs.AddWarningEvent(&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{UID: "event-1"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pod-a"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "Unhealthy",
+			Message:        "Readiness probe failed",
+		})
+		mt.State.RuntimeState = krs
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		mt := state.ManifestTargets["fe"]
+		krs := mt.State.K8sRuntimeState()
+		krs.AddWarningEvent(&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{UID: "event-2"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pod-a"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "BackOff",
+			Message:        "Back-off pulling image",
+			Count:          10,
+		})
+
+		// Blocking warnings don't fail CI until they persist.
+		krs.AddWarningEvent(&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{UID: "event-3"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pod-a"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedScheduling",
+			Message:        "0/1 nodes are available: 1 Insufficient cpu.",
+			Count:          1,
+		})
+		mt.State.RuntimeState = krs
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireNoExitSignal()
+
+	f.store.WithState(func(state *store.EngineState) {
+		mt := state.ManifestTargets["fe"]
+		krs := mt.State.K8sRuntimeState()
+		krs.AddWarningEvent(&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{UID: "event-3"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pod-a"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "FailedScheduling",
+			Message:        "0/1 nodes are available: 1 Insufficient cpu.",
+			Count:          5,
+		})
+		mt.State.RuntimeState = krs
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithError("Pod pod-a: FailedScheduling: 0/1 nodes are available: 1 Insufficient cpu.")
+}
+
 func TestExitControlCI_PodRunningContainerError(t *testing.T) {
 	f := newFixture(t, store.EngineModeCI)
 	defer f.TearDown()
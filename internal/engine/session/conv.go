@@ -7,6 +7,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
+	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 
 	"github.com/tilt-dev/tilt/internal/store"
@@ -82,6 +83,18 @@ func k8sRuntimeTarget(mt *store.ManifestTarget) *session.Target {
 			return target
 		}
 
+		// Some pods never start because of problems that only show up as
+		// events (e.g., FailedScheduling), so check those before we wait forever.
+		// Warnings only count once they've persisted; until then they're just
+		// shown as status.
+		if w, ok := krs.PodBlockingWarningEvent(k8s.PodID(pod.Name)); ok {
+			target.State.Terminated = &session.TargetStateTerminated{
+				StartTime: apis.NewMicroTime(pod.CreatedAt.Time),
+				Error:     fmt.Sprintf("Pod %s: %s: %s", pod.Name, w.Reason, w.Message),
+			}
+			return target
+		}
+
 		for _, ctr := range store.AllPodContainers(pod) {
 			if k8sconv.ContainerStatusToRuntimeState(ctr) == v1alpha1.RuntimeStatusError {
 				target.State.Terminated = &session.TargetStateTerminated{
@@ -5,14 +5,16 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers"
 	"github.com/tilt-dev/tilt/internal/engine/analytics"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
-	"github.com/tilt-dev/tilt/internal/engine/dcwatch"
+	"github.com/tilt-dev/tilt/internal/engine/disable"
 	"github.com/tilt-dev/tilt/internal/engine/dockerprune"
+	"github.com/tilt-dev/tilt/internal/engine/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/engine/fswatch"
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
 	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	"github.com/tilt-dev/tilt/internal/engine/portforward"
+	"github.com/tilt-dev/tilt/internal/engine/readiness"
 	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
@@ -59,8 +61,6 @@ func ProvideSubscribers(
 	fsms *fswatch.ManifestSubscriber,
 	bc *BuildController,
 	cc *configs.ConfigsController,
-	dcw *dcwatch.EventWatcher,
-	dclm *runtimelog.DockerComposeLogManager,
 	ar *analytics.AnalyticsReporter,
 	au *analytics.AnalyticsUpdater,
 	ewm *k8swatch.EventWatchManager,
@@ -73,6 +73,9 @@ func ProvideSubscribers(
 	mc *metrics.Controller,
 	uss *uisession.Subscriber,
 	urs *uiresource.Subscriber,
+	dsc *disable.Controller,
+	rc *readiness.Controller,
+	ers *extensionrepo.Subscriber,
 ) []store.Subscriber {
 	apiSubscribers := ProvideSubscribersAPIOnly(hudsc, tscm, cb, ts)
 
@@ -86,8 +89,6 @@ func ProvideSubscribers(
 		fsms,
 		bc,
 		cc,
-		dcw,
-		dclm,
 		ar,
 		au,
 		ewm,
@@ -100,6 +101,9 @@ func ProvideSubscribers(
 		mc,
 		uss,
 		urs,
+		dsc,
+		rc,
+		ers,
 	}
 	return append(apiSubscribers, legacySubscribers...)
 }
@@ -55,6 +55,17 @@ func NewSanchoLiveUpdateDCManifest(f Fixture) model.Manifest {
 		Build()
 }
 
+// A docker-compose service that docker-compose builds from its `build:` section.
+func NewSanchoDCBuiltManifest(f Fixture, lu model.LiveUpdate) model.Manifest {
+	m := manifestbuilder.New(f, "sancho").
+		WithDockerCompose().
+		Build()
+	dcTarget := m.DockerComposeTarget().
+		WithBuildPath(f.Path()).
+		WithLiveUpdate(lu)
+	return m.WithDeployTarget(dcTarget)
+}
+
 func NewSanchoCustomBuildManifest(fixture Fixture) model.Manifest {
 	return NewSanchoCustomBuildManifestWithTag(fixture, "")
 }
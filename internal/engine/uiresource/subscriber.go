@@ -93,6 +93,18 @@ func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary stor
 			continue
 		}
 
+		if !apicmp.DeepEqual(resource.Labels, stored.Labels) {
+			// Labels live in the metadata, so they aren't updated by the status update below.
+			update := stored.DeepCopy()
+			update.Labels = resource.Labels
+			err = s.client.Update(ctx, update)
+			if err != nil {
+				logger.Get(ctx).Infof("updating uiresource %s: %v", name.Name, err)
+				return nil
+			}
+			stored = *update
+		}
+
 		if !apicmp.DeepEqual(resource.Status, stored.Status) {
 			// If the current version is different than what's stored, update it.
 			update := &v1alpha1.UIResource{
@@ -79,6 +79,28 @@ func TestDeleteManifest(t *testing.T) {
 	assert.Nil(t, f.resource("fe"))
 }
 
+func TestUpdateLabels(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	m := manifestbuilder.New(f, "fe").
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build()
+	f.store.WithState(func(state *store.EngineState) {
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	assert.Empty(t, f.resource("fe").ObjectMeta.Labels)
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.UpsertManifestTarget(store.NewManifestTarget(m.WithLabels(map[string]string{"frontend": "frontend"})))
+	})
+
+	_ = f.sub.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	assert.Equal(t, map[string]string{"frontend": "frontend"}, f.resource("fe").ObjectMeta.Labels)
+}
+
 type fixture struct {
 	*tempdir.TempDirFixture
 	ctx   context.Context
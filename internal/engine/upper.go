@@ -9,6 +9,8 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/container"
@@ -21,6 +23,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
 	"github.com/tilt-dev/tilt/internal/engine/portforward"
+	"github.com/tilt-dev/tilt/internal/engine/readiness"
 	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/hud"
@@ -34,6 +37,7 @@ import (
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
 // TODO(nick): maybe this should be called 'BuildEngine' or something?
@@ -74,6 +78,7 @@ func (u Upper) Start(
 	analyticsUserOpt analytics.Opt,
 	token token.Token,
 	cloudAddress string,
+	logArchive *logstore.Archive,
 ) error {
 
 	startTime := time.Now()
@@ -96,6 +101,7 @@ func (u Upper) Start(
 		Token:            token,
 		CloudAddress:     cloudAddress,
 		TerminalMode:     initTerminalMode,
+		LogArchive:       logArchive,
 	})
 }
 
@@ -184,12 +190,16 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		handleSwitchTerminalModeAction(state, action)
 	case server.OverrideTriggerModeAction:
 		handleOverrideTriggerModeAction(ctx, state, action)
+	case server.OverrideDisableAction:
+		handleOverrideDisableAction(ctx, state, action)
 	case local.CmdCreateAction:
 		local.HandleCmdCreateAction(state, action)
 	case local.CmdUpdateStatusAction:
 		local.HandleCmdUpdateStatusAction(state, action)
 	case local.CmdDeleteAction:
 		local.HandleCmdDeleteAction(state, action)
+	case readiness.ProbeStatusAction:
+		readiness.HandleProbeStatusAction(state, action)
 	case runtimelog.PodLogStreamCreateAction:
 		runtimelog.HandlePodLogStreamCreateAction(state, action)
 	case runtimelog.PodLogStreamDeleteAction:
@@ -239,10 +249,20 @@ func handleBuildStarted(ctx context.Context, state *store.EngineState, action bu
 				delete(krs.UpdateStartTime, podID)
 			}
 		}
+		// Warnings from before the update are probably stale. If they're
+		// still relevant, Kubernetes will keep bumping them.
+		krs.WarningEvents = make(map[types.UID]*v1.Event)
+		krs.HasReadinessProbe = manifest.K8sTarget().ReadinessProbe != nil
+		// The probe result is for the old deploy. The readiness controller
+		// restarts the probe when the build finishes.
+		krs.ProbeReady = false
+		ms.RuntimeState = krs
 	} else if manifest.IsDC() {
 		// Attach the SpanID and initialize the runtime state if we haven't yet.
 		state, _ := ms.RuntimeState.(dockercompose.State)
-		state = state.WithSpanID(runtimelog.SpanIDForDCService(mn))
+		state = state.WithSpanID(runtimelog.SpanIDForDCService(mn)).
+			WithReadinessProbe(manifest.DockerComposeTarget().ReadinessProbe != nil).
+			WithProbeReady(false, action.StartTime)
 		ms.RuntimeState = state
 	}
 
@@ -468,7 +488,8 @@ func handleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 					state = state.WithStartTime(cb.FinishTime)
 				}
 				if state.LastReadyTime.IsZero() {
-					// NB: this will differ from StartTime once we support DC health checks
+					// NB: if the resource has a readiness probe, it also
+					// needs to pass before we consider the resource ready.
 					state = state.WithLastReadyTime(cb.FinishTime)
 				}
 			}
@@ -563,6 +584,17 @@ func handleConfigsReloaded(
 		state.WatchSettings = event.WatchSettings
 	}
 
+	// Add extension repos if they exist, even if execution failed.
+	if len(event.ExtensionRepos) > 0 || event.Err == nil {
+		state.ExtensionRepos = event.ExtensionRepos
+	}
+
+	// Add the Tiltfile's config settings if they exist, even if execution failed,
+	// so that UIs can still show a form to fix bad args.
+	if len(event.UserConfigSettings) > 0 || event.Err == nil {
+		state.UserConfigSettings = event.UserConfigSettings
+	}
+
 	// Add team id if it exists, even if execution failed.
 	if event.TeamID != "" || event.Err == nil {
 		state.TeamID = event.TeamID
@@ -703,6 +735,19 @@ func handleK8sEvent(ctx context.Context, state *store.EngineState, action store.
 	// - Display Node unready events as part of a health indicator, and display how
 	//   long it takes them to resolve.
 	handleLogAction(state, action.ToLogAction(action.ManifestName))
+
+	if action.Event.Type != v1.EventTypeWarning {
+		return
+	}
+
+	ms, ok := state.ManifestState(action.ManifestName)
+	if !ok || !ms.IsK8s() {
+		return
+	}
+
+	krs := ms.K8sRuntimeState()
+	krs.AddWarningEvent(action.Event)
+	ms.RuntimeState = krs
 }
 
 func handleDumpEngineStateAction(ctx context.Context, engineState *store.EngineState) {
@@ -733,6 +778,13 @@ func handleInitAction(ctx context.Context, engineState *store.EngineState, actio
 	engineState.CloudAddress = action.CloudAddress
 	engineState.Token = action.Token
 	engineState.TerminalMode = action.TerminalMode
+
+	if action.LogArchive != nil {
+		err := engineState.LogStore.SetArchive(action.LogArchive)
+		if err != nil {
+			logger.Get(ctx).Infof("Persisting logs to %s failed: %v", action.LogArchive.Dir(), err)
+		}
+	}
 }
 
 func handleHudExitAction(state *store.EngineState, action hud.ExitAction) {
@@ -753,22 +805,25 @@ func handleSetTiltfileArgsAction(state *store.EngineState, action server.SetTilt
 
 func handleDockerComposeEvent(ctx context.Context, engineState *store.EngineState, action dcwatch.EventAction) {
 	evt := action.Event
-	mn := model.ManifestName(evt.Service)
-	ms, ok := engineState.ManifestState(mn)
+	mn := action.ManifestName
+	mt, ok := engineState.ManifestTargets[mn]
 	if !ok {
 		// No corresponding manifest, nothing to do
 		return
 	}
+	ms := mt.State
 
 	state, _ := ms.RuntimeState.(dockercompose.State)
 
 	state = state.WithContainerID(container.ID(evt.ID)).
 		WithSpanID(runtimelog.SpanIDForDCService(mn)).
-		WithContainerState(action.ContainerState)
+		WithContainerState(action.ContainerState).
+		WithReadinessProbe(mt.Manifest.DockerComposeTarget().ReadinessProbe != nil)
 
 	if evt.IsStartupEvent() {
 		state = state.WithStartTime(action.Time)
-		// NB: this will differ from StartTime once we support DC health checks
+		// NB: if the resource has a readiness probe, it also
+		// needs to pass before we consider the resource ready.
 		state = state.WithLastReadyTime(action.Time)
 	}
 
@@ -835,3 +890,22 @@ func handleOverrideTriggerModeAction(ctx context.Context, state *store.EngineSta
 		mt.Manifest.TriggerMode = action.TriggerMode
 	}
 }
+
+func handleOverrideDisableAction(ctx context.Context, state *store.EngineState,
+	action server.OverrideDisableAction) {
+	for _, mName := range action.ManifestNames {
+		if _, ok := state.ManifestTargets[mName]; !ok {
+			// We validate manifest names when we receive a request, so this should never happen
+			logger.Get(ctx).Errorf("INTERNAL ERROR disabling resource: no such manifest %q", mName)
+			return
+		}
+	}
+
+	for _, mName := range action.ManifestNames {
+		if action.Disabled {
+			state.DisableResource(mName)
+		} else {
+			state.EnableResource(mName)
+		}
+	}
+}
@@ -49,6 +49,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch/fsevent"
 	"github.com/tilt-dev/tilt/internal/controllers/core/podlogstream"
@@ -62,6 +63,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 	"github.com/tilt-dev/tilt/internal/engine/configs"
 	"github.com/tilt-dev/tilt/internal/engine/dcwatch"
+	"github.com/tilt-dev/tilt/internal/engine/disable"
 	"github.com/tilt-dev/tilt/internal/engine/dockerprune"
 	"github.com/tilt-dev/tilt/internal/engine/fswatch"
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
@@ -69,9 +71,11 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/local"
 	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	"github.com/tilt-dev/tilt/internal/engine/portforward"
+	"github.com/tilt-dev/tilt/internal/engine/readiness"
 	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
+	"github.com/tilt-dev/tilt/internal/engine/extensionrepo"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
 	"github.com/tilt-dev/tilt/internal/engine/uisession"
 	"github.com/tilt-dev/tilt/internal/feature"
@@ -322,7 +326,7 @@ func (b *fakeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.RSto
 		return nil, err
 	}
 
-	err = queue.RunBuilds(func(target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
+	err = queue.RunBuilds(ctx, func(ctx context.Context, target model.TargetSpec, depResults []store.BuildResult) (store.BuildResult, error) {
 		iTarget := target.(model.ImageTarget)
 		var deployTarget model.TargetSpec
 		if !call.dc().Empty() {
@@ -1567,7 +1571,7 @@ func TestPodUnexpectedContainerStartsImageBuild(t *testing.T) {
 		ms, _ := st.ManifestState(manifest.Name)
 		return buildcontrol.NextManifestNameToBuild(st) == manifest.Name && ms.HasPendingFileChanges()
 	})
-	spanID0 := SpanIDForBuildLog(0)
+	spanID0 := SpanIDForBuildLog(manifest.Name, 0)
 	f.store.Dispatch(buildcontrol.BuildStartedAction{
 		ManifestName: manifest.Name,
 		StartTime:    f.Now(),
@@ -1625,7 +1629,7 @@ func TestPodUnexpectedContainerStartsImageBuildOutOfOrderEvents(t *testing.T) {
 		ms, _ := st.ManifestState(manifest.Name)
 		return buildcontrol.NextManifestNameToBuild(st) == manifest.Name && ms.HasPendingFileChanges()
 	})
-	spanID0 := SpanIDForBuildLog(0)
+	spanID0 := SpanIDForBuildLog(manifest.Name, 0)
 	f.store.Dispatch(buildcontrol.BuildStartedAction{
 		ManifestName: manifest.Name,
 		StartTime:    f.Now(),
@@ -1666,7 +1670,7 @@ func TestPodUnexpectedContainerAfterSuccessfulUpdate(t *testing.T) {
 		return buildcontrol.NextManifestNameToBuild(st) == manifest.Name && ms.HasPendingFileChanges()
 	})
 
-	spanID0 := SpanIDForBuildLog(0)
+	spanID0 := SpanIDForBuildLog(manifest.Name, 0)
 	f.store.Dispatch(buildcontrol.BuildStartedAction{
 		ManifestName: manifest.Name,
 		StartTime:    f.Now(),
@@ -1699,7 +1703,7 @@ func TestPodUnexpectedContainerAfterSuccessfulUpdate(t *testing.T) {
 		return buildcontrol.NextManifestNameToBuild(st) == manifest.Name
 	})
 
-	spanID1 := SpanIDForBuildLog(1)
+	spanID1 := SpanIDForBuildLog(manifest.Name, 1)
 	f.store.Dispatch(buildcontrol.BuildStartedAction{
 		ManifestName: manifest.Name,
 		StartTime:    f.Now(),
@@ -2519,6 +2523,65 @@ func TestK8sEventGlobalLogAndManifestLog(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestK8sWarningEventsInRuntimeState(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	name := model.ManifestName("fe")
+	manifest := f.newManifest(string(name))
+	pb := f.registerForDeployer(manifest)
+
+	f.Start([]model.Manifest{manifest})
+	f.nextCallComplete()
+
+	objRef := v1.ObjectReference{Kind: "Pod", Name: "fe-pod", UID: f.lastDeployedUID(name)}
+	warnEvt := &v1.Event{
+		InvolvedObject: objRef,
+		Reason:         "FailedScheduling",
+		Message:        "0/1 nodes are available: 1 Insufficient cpu.",
+		Type:           v1.EventTypeWarning,
+		Count:          1,
+		LastTimestamp:  apis.NewTime(f.Now()),
+		ObjectMeta: metav1.ObjectMeta{
+			UID:               "event-1",
+			CreationTimestamp: apis.NewTime(f.Now()),
+			Namespace:         k8s.DefaultNamespace.String(),
+		},
+	}
+	f.kClient.UpsertEvent(warnEvt)
+
+	f.WaitUntilManifestState("warning event recorded", name, func(ms store.ManifestState) bool {
+		return len(ms.K8sRuntimeState().RecentWarningEvents()) == 1
+	})
+
+	// Kubernetes updates the same event as it recurs.
+	updatedEvt := warnEvt.DeepCopy()
+	updatedEvt.Count = 3
+	f.kClient.UpsertEvent(updatedEvt)
+
+	f.WaitUntilManifestState("warning event count updated", name, func(ms store.ManifestState) bool {
+		warnings := ms.K8sRuntimeState().RecentWarningEvents()
+		return len(warnings) == 1 && warnings[0].Count == 3
+	})
+
+	f.withManifestState(name, func(ms store.ManifestState) {
+		w := ms.K8sRuntimeState().RecentWarningEvents()[0]
+		assert.Equal(t, "FailedScheduling", w.Reason)
+		assert.Equal(t, "fe-pod", w.Object.Name)
+	})
+
+	// Updating the resource clears out old warnings.
+	f.podEvent(pb.Build())
+	f.fsWatcher.Events <- watch.NewFileEvent(f.JoinPath("main.go"))
+	f.nextCallComplete()
+	f.withManifestState(name, func(ms store.ManifestState) {
+		assert.Empty(t, ms.K8sRuntimeState().RecentWarningEvents())
+	})
+
+	err := f.Stop()
+	assert.NoError(t, err)
+}
+
 func TestK8sEventNotLoggedIfNoManifestForUID(t *testing.T) {
 	f := newTestFixture(t)
 	defer f.TearDown()
@@ -2697,55 +2760,6 @@ func TestDockerComposeRecordsBuildLogs(t *testing.T) {
 	})
 }
 
-func TestDockerComposeRecordsRunLogs(t *testing.T) {
-	f := newTestFixture(t)
-	defer f.TearDown()
-	m, _ := f.setupDCFixture()
-	expected := "hello world"
-	output := make(chan string, 1)
-	output <- expected
-	defer close(output)
-	f.setDCRunLogOutput(m.DockerComposeTarget(), output)
-
-	f.loadAndStart()
-	f.waitForCompletedBuildCount(2)
-
-	f.WaitUntil("wait until manifest state has a log", func(state store.EngineState) bool {
-		ms, _ := state.ManifestState(m.ManifestName())
-		spanID := ms.DCRuntimeState().SpanID
-		return spanID != "" && state.LogStore.SpanLog(spanID) != ""
-	})
-
-	// recorded on manifest state
-	f.withState(func(es store.EngineState) {
-		ms, _ := es.ManifestState(m.ManifestName())
-		spanID := ms.DCRuntimeState().SpanID
-		assert.Contains(t, es.LogStore.SpanLog(spanID), expected)
-		assert.Equal(t, 1, strings.Count(es.LogStore.ManifestLog(m.ManifestName()), expected))
-	})
-}
-
-func TestDockerComposeFiltersRunLogs(t *testing.T) {
-	f := newTestFixture(t)
-	defer f.TearDown()
-	m, _ := f.setupDCFixture()
-	expected := "Attaching to snack\n"
-	output := make(chan string, 1)
-	output <- expected
-	defer close(output)
-	f.setDCRunLogOutput(m.DockerComposeTarget(), output)
-
-	f.loadAndStart()
-	f.waitForCompletedBuildCount(2)
-
-	// recorded on manifest state
-	f.withState(func(es store.EngineState) {
-		ms, _ := es.ManifestState(m.ManifestName())
-		spanID := ms.DCRuntimeState().SpanID
-		assert.NotContains(t, es.LogStore.SpanLog(spanID), expected)
-	})
-}
-
 // NOTE(nick): The weird structure of this test is vesigial from when
 // we inferred crash from ContainerState rather than sequences of events.
 func TestDockerComposeDetectsCrashes(t *testing.T) {
@@ -2841,7 +2855,7 @@ func TestEmptyTiltfile(t *testing.T) {
 		err := f.upper.Start(f.ctx, []string{}, model.TiltBuild{}, store.EngineModeUp,
 			f.JoinPath("Tiltfile"), store.TerminalModeHUD,
 			analytics.OptIn, token.Token("unit test token"),
-			"nonexistent.example.com")
+			"nonexistent.example.com", nil)
 		closeCh <- err
 	}()
 	f.WaitUntil("build is set", func(st store.EngineState) bool {
@@ -2875,7 +2889,7 @@ func TestUpperStart(t *testing.T) {
 	go func() {
 		err := f.upper.Start(f.ctx, []string{"foo", "bar"}, model.TiltBuild{},
 			store.EngineModeUp, f.JoinPath("Tiltfile"), store.TerminalModeHUD,
-			analytics.OptIn, tok, cloudAddress)
+			analytics.OptIn, tok, cloudAddress, nil)
 		closeCh <- err
 	}()
 	f.WaitUntil("init action processed", func(state store.EngineState) bool {
@@ -3068,6 +3082,51 @@ func TestTeamIDStoredOnState(t *testing.T) {
 	})
 }
 
+func TestDisableResourceSurvivesTiltfileReload(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	foo := f.newManifest("foo")
+	f.Start([]model.Manifest{foo})
+	f.nextCallComplete("initial build")
+
+	f.store.Dispatch(server.OverrideDisableAction{
+		ManifestNames: []model.ManifestName{"foo"},
+		Disabled:      true,
+	})
+	f.WaitUntil("foo disabled", func(state store.EngineState) bool {
+		return state.IsResourceDisabled("foo")
+	})
+
+	// Change the YAML, so that the Tiltfile reload queues a rebuild.
+	kTarget := foo.K8sTarget()
+	kTarget.YAML = testyaml.SanchoYAMLWithCommand
+	foo = foo.WithDeployTarget(kTarget)
+	f.store.Dispatch(configs.ConfigsReloadedAction{
+		FinishTime: f.Now(),
+		Manifests:  []model.Manifest{foo},
+	})
+	f.WaitUntilManifest("YAML updated", "foo", func(mt store.ManifestTarget) bool {
+		return mt.Manifest.K8sTarget().YAML == testyaml.SanchoYAMLWithCommand
+	})
+
+	f.withState(func(state store.EngineState) {
+		assert.True(t, state.IsResourceDisabled("foo"))
+		_, holds := buildcontrol.NextTargetToBuild(state)
+		assert.Equal(t, store.HoldDisabled, holds["foo"])
+	})
+
+	f.store.Dispatch(server.OverrideDisableAction{
+		ManifestNames: []model.ManifestName{"foo"},
+		Disabled:      false,
+	})
+	f.nextCallComplete("build after enable")
+
+	err := f.Stop()
+	assert.NoError(t, err)
+	f.assertAllBuildsConsumed()
+}
+
 func TestBuildLogAction(t *testing.T) {
 	f := newTestFixture(t)
 	defer f.TearDown()
@@ -3079,10 +3138,10 @@ func TestBuildLogAction(t *testing.T) {
 	f.store.Dispatch(buildcontrol.BuildStartedAction{
 		ManifestName: manifest.Name,
 		StartTime:    f.Now(),
-		SpanID:       SpanIDForBuildLog(1),
+		SpanID:       SpanIDForBuildLog(manifest.Name, 1),
 	})
 
-	f.store.Dispatch(store.NewLogAction(manifest.Name, SpanIDForBuildLog(1), logger.InfoLvl, nil, []byte(`a
+	f.store.Dispatch(store.NewLogAction(manifest.Name, SpanIDForBuildLog(manifest.Name, 1), logger.InfoLvl, nil, []byte(`a
 bc
 def
 ghij`)))
@@ -3905,8 +3964,6 @@ func newTestFixture(t *testing.T) *testFixture {
 	configExt := config.NewExtension("up")
 	tfl := tiltfile.ProvideTiltfileLoader(ta, b.kClient, k8sContextExt, versionExt, configExt, fakeDcc, "localhost", feature.MainDefaults, env)
 	cc := configs.NewConfigsController(tfl, dockerClient)
-	dcw := dcwatch.NewEventWatcher(fakeDcc, dockerClient)
-	dclm := runtimelog.NewDockerComposeLogManager(fakeDcc)
 	serverOptions, err := server.ProvideTiltServerOptionsForTesting(ctx)
 	require.NoError(t, err)
 	webListener, err := server.ProvideWebListener("localhost", 0)
@@ -3940,6 +3997,7 @@ func newTestFixture(t *testing.T) *testFixture {
 		controllers.UncachedObjects{&v1alpha1.FileWatch{}})
 	require.NoError(t, err, "Failed to create Tilt API server controller manager")
 	pfr := apiportforward.NewReconciler(st, b.kClient)
+	dcsr := dockercomposeservice.NewReconciler(ctx, fakeDcc, dockerClient, st)
 
 	wsl := server.NewWebsocketList()
 	cb := controllers.NewControllerBuilder(tscm, controllers.ProvideControllers(
@@ -3951,6 +4009,7 @@ func newTestFixture(t *testing.T) *testFixture {
 		ctrluiresource.NewReconciler(wsl),
 		ctrluibutton.NewReconciler(wsl),
 		pfr,
+		dcsr,
 	))
 
 	dp := dockerprune.NewDockerPruner(dockerClient)
@@ -3992,8 +4051,11 @@ func newTestFixture(t *testing.T) *testFixture {
 	mc := metrics.NewController(de, model.TiltBuild{}, "")
 	uss := uisession.NewSubscriber(cdc)
 	urs := uiresource.NewSubscriber(cdc)
+	ers := extensionrepo.NewSubscriber(cdc)
 
-	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, kdms, sw, plm, pfs, fwms, bc, cc, dcw, dclm, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, mc, uss, urs)
+	dsc := disable.NewController(b.kClient, dcsr)
+	rc := readiness.NewController(fpm)
+	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, kdms, sw, plm, pfs, fwms, bc, cc, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, mc, uss, urs, dsc, rc, ers)
 	ret.upper, err = NewUpper(ctx, st, subs)
 	require.NoError(t, err)
 
@@ -4558,10 +4620,6 @@ func (f *testFixture) setBuildLogOutput(id model.TargetID, output string) {
 	f.b.buildLogOutput[id] = output
 }
 
-func (f *testFixture) setDCRunLogOutput(dc model.DockerComposeTarget, output <-chan string) {
-	f.dcc.RunLogOutput[dc.Name] = output
-}
-
 func (f *testFixture) hudResource(name model.ManifestName) view.Resource {
 	res, ok := f.fakeHud().LastView.Resource(name)
 	if !ok {
@@ -4611,6 +4669,7 @@ func (s fixtureSub) OnChange(ctx context.Context, st store.RStore, _ store.Chang
 
 func (f *testFixture) dispatchDCEvent(m model.Manifest, action dockercompose.Action, containerState dockertypes.ContainerState) {
 	f.store.Dispatch(dcwatch.EventAction{
+		ManifestName: m.ManifestName(),
 		Event: dockercompose.Event{
 			ID:      "fake-container-id",
 			Type:    dockercompose.TypeContainer,
@@ -11,6 +11,7 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/core/dockercomposeservice"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
@@ -48,6 +49,7 @@ func provideFakeBuildAndDeployer(
 	env k8s.Env,
 	updateMode buildcontrol.UpdateModeFlag,
 	dcc dockercompose.DockerComposeClient,
+	dcsr *dockercomposeservice.Reconciler,
 	clock build.Clock,
 	kp buildcontrol.KINDLoader,
 	analytics *analytics.TiltAnalytics) (buildcontrol.BuildAndDeployer, error) {
@@ -0,0 +1,242 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+const chartCacheDirName = "helm"
+
+const ociScheme = "oci://"
+
+var unsafeCacheDirChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// ChartCache keeps a copy of every remote chart we've fetched on disk, so
+// that pinned chart versions load without network access.
+type ChartCache struct {
+	rootDir string
+	getters getter.Providers
+}
+
+func NewChartCache(rootDir string) *ChartCache {
+	return &ChartCache{
+		rootDir: rootDir,
+		getters: getter.All(cli.New()),
+	}
+}
+
+// Creates a ChartCache under the Tilt dev dir (usually ~/.tilt-dev).
+func NewTiltDevChartCache() (*ChartCache, error) {
+	dir, err := dirs.GetTiltDevDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewChartCache(filepath.Join(dir, chartCacheDirName)), nil
+}
+
+func IsOCIRef(ref string) bool {
+	return strings.HasPrefix(ref, ociScheme)
+}
+
+// Converts a URL, chart name, or version into a single path element,
+// so that none of them can point outside the cache (e.g., a version
+// of "../../x" in a remote index.yaml).
+func cacheDirName(s string) string {
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+	s = strings.TrimPrefix(s, ociScheme)
+	s = strings.Trim(unsafeCacheDirChars.ReplaceAllString(s, "_"), "_.")
+	if s == "" {
+		return "_"
+	}
+	return s
+}
+
+// The cached archive for a chart version in a chart repository.
+func repoArchiveName(name, version string) string {
+	return fmt.Sprintf("%s-%s.tgz", cacheDirName(name), cacheDirName(version))
+}
+
+// Fetch returns the path to a chart archive for the given chart.
+//
+// If repoURL is empty, chart must be an OCI reference (oci://host/path/name).
+// Otherwise, chart is the name of a chart in the repository at repoURL,
+// which may itself be an OCI registry.
+//
+// An empty version means the latest version. OCI registries have no index
+// to look up the latest version in, so they require a version.
+func (c *ChartCache) Fetch(ctx context.Context, repoURL, chart, version string) (string, error) {
+	if repoURL == "" {
+		if !IsOCIRef(chart) {
+			return "", fmt.Errorf("chart %q: expected an oci:// reference or a chart repository", chart)
+		}
+		return c.fetchFromOCI(ctx, chart, version)
+	}
+	if IsOCIRef(repoURL) {
+		return c.fetchFromOCI(ctx, strings.TrimSuffix(repoURL, "/")+"/"+chart, version)
+	}
+	return c.fetchFromRepo(ctx, repoURL, chart, version)
+}
+
+// Fetches a chart from a chart repository, i.e., a web server
+// with an index.yaml.
+func (c *ChartCache) fetchFromRepo(ctx context.Context, repoURL, name, version string) (string, error) {
+	dir := filepath.Join(c.rootDir, "repos", cacheDirName(repoURL))
+	if version != "" {
+		cached := filepath.Join(dir, repoArchiveName(name, version))
+		if fileExists(cached) {
+			return cached, nil
+		}
+	}
+
+	index, err := c.fetchIndex(ctx, repoURL, dir)
+	if err != nil {
+		return "", err
+	}
+
+	cv, err := index.Get(name, version)
+	if err != nil {
+		if version == "" {
+			return "", fmt.Errorf("chart %q not found in repository %s", name, repoURL)
+		}
+		return "", fmt.Errorf("chart %q version %q not found in repository %s", name, version, repoURL)
+	}
+
+	archive := filepath.Join(dir, repoArchiveName(name, cv.Version))
+	if fileExists(archive) {
+		return archive, nil
+	}
+
+	if len(cv.URLs) == 0 {
+		return "", fmt.Errorf("chart %q version %q in repository %s has no downloadable URLs", name, cv.Version, repoURL)
+	}
+
+	chartURL, err := repo.ResolveReferenceURL(repoURL, cv.URLs[0])
+	if err != nil {
+		return "", err
+	}
+
+	logger.Get(ctx).Infof("Downloading Helm chart %s@%s from %s", name, cv.Version, repoURL)
+	contents, err := c.get(chartURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "downloading chart %s@%s", name, cv.Version)
+	}
+
+	err = writeFileAtomic(archive, contents)
+	if err != nil {
+		return "", err
+	}
+	return archive, nil
+}
+
+// Downloads the repo's index.yaml. If we can't reach the repo,
+// fall back to the last index we downloaded, so that Tilt still works offline.
+func (c *ChartCache) fetchIndex(ctx context.Context, repoURL, dir string) (*repo.IndexFile, error) {
+	indexPath := filepath.Join(dir, "index.yaml")
+	indexURL := strings.TrimSuffix(repoURL, "/") + "/index.yaml"
+	contents, err := c.get(indexURL)
+	if err == nil {
+		err = writeFileAtomic(indexPath, contents)
+		if err != nil {
+			return nil, err
+		}
+	} else if fileExists(indexPath) {
+		logger.Get(ctx).Warnf("Unable to update Helm repository %s, using cached index: %v", repoURL, err)
+	} else {
+		return nil, errors.Wrapf(err, "fetching Helm repository index %s", indexURL)
+	}
+
+	index, err := repo.LoadIndexFile(indexPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading Helm repository index %s", indexURL)
+	}
+	return index, nil
+}
+
+func (c *ChartCache) get(rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	g, err := c.getters.ByScheme(u.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := g.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Fetches a chart stored as an OCI artifact in a container registry.
+func (c *ChartCache) fetchFromOCI(ctx context.Context, ref, version string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("chart %q: a version is required for charts in OCI registries", ref)
+	}
+
+	ref = strings.TrimPrefix(ref, ociScheme)
+	archive := filepath.Join(c.rootDir, "oci", cacheDirName(ref), fmt.Sprintf("%s.tgz", cacheDirName(version)))
+	if fileExists(archive) {
+		return archive, nil
+	}
+
+	logger.Get(ctx).Infof("Pulling Helm chart %s:%s", ref, version)
+	contents, err := pullOCIChart(ctx, fmt.Sprintf("%s:%s", ref, version))
+	if err != nil {
+		return "", errors.Wrapf(err, "pulling chart %s:%s", ref, version)
+	}
+
+	err = writeFileAtomic(archive, contents)
+	if err != nil {
+		return "", err
+	}
+	return archive, nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// Write to a temp file and rename, so that an interrupted download
+// doesn't leave a truncated chart in the cache.
+func writeFileAtomic(path string, contents []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), os.FileMode(0755))
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	_, err = tmp.Write(contents)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
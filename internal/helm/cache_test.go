@@ -0,0 +1,233 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestFetchFromRepo(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	repo := f.newChartRepo("sidecar", "0.1.0", "0.2.0")
+	defer repo.Close()
+
+	path, err := f.cache.Fetch(f.ctx, repo.URL, "sidecar", "0.1.0")
+	require.NoError(t, err)
+	f.assertChartVersion(path, "sidecar", "0.1.0")
+
+	// An empty version means latest.
+	path, err = f.cache.Fetch(f.ctx, repo.URL, "sidecar", "")
+	require.NoError(t, err)
+	f.assertChartVersion(path, "sidecar", "0.2.0")
+
+	path, err = f.cache.Fetch(f.ctx, repo.URL, "sidecar", "~0.1")
+	require.NoError(t, err)
+	f.assertChartVersion(path, "sidecar", "0.1.0")
+
+	_, err = f.cache.Fetch(f.ctx, repo.URL, "sidecar", "0.3.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `chart "sidecar" version "0.3.0" not found in repository`)
+}
+
+func TestFetchFromRepoOffline(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	repo := f.newChartRepo("sidecar", "0.1.0", "0.2.0")
+	_, err := f.cache.Fetch(f.ctx, repo.URL, "sidecar", "")
+	require.NoError(t, err)
+	repo.Close()
+
+	// Pinned versions come straight from the cache.
+	path, err := f.cache.Fetch(f.ctx, repo.URL, "sidecar", "0.2.0")
+	require.NoError(t, err)
+	f.assertChartVersion(path, "sidecar", "0.2.0")
+
+	// Unpinned versions fall back to the cached index.
+	path, err = f.cache.Fetch(f.ctx, repo.URL, "sidecar", "")
+	require.NoError(t, err)
+	f.assertChartVersion(path, "sidecar", "0.2.0")
+
+	// Versions we never downloaded can't be loaded.
+	_, err = f.cache.Fetch(f.ctx, repo.URL, "sidecar", "0.1.0")
+	require.Error(t, err)
+}
+
+func TestLoadChartRepoDependency(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	repo := f.newChartRepo("sidecar", "0.1.0")
+	defer repo.Close()
+
+	f.writeChart("mychart", "mychart", fmt.Sprintf(`
+dependencies:
+- name: sidecar
+  version: 0.1.0
+  repository: %s
+`, repo.URL))
+
+	ch, deps, err := LoadChart(f.ctx, f.JoinPath("mychart"), f.cache)
+	require.NoError(t, err)
+	require.Len(t, deps, 1)
+	assert.True(t, strings.HasPrefix(deps[0], f.JoinPath(".cache")))
+
+	yaml, err := Template(ch, TemplateOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "# Source: mychart/charts/sidecar/templates/configmap.yaml")
+}
+
+func TestFetchFromOCI(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	registry := f.newOCIRegistry("charts/sidecar", "0.1.0")
+	defer registry.Close()
+
+	host := strings.TrimPrefix(registry.URL, "http://")
+	path, err := f.cache.Fetch(f.ctx, "", fmt.Sprintf("oci://%s/charts/sidecar", host), "0.1.0")
+	require.NoError(t, err)
+	f.assertChartVersion(path, "sidecar", "0.1.0")
+
+	// As a dependency, the repository is the registry path, without the chart name.
+	path, err = f.cache.Fetch(f.ctx, fmt.Sprintf("oci://%s/charts", host), "sidecar", "0.1.0")
+	require.NoError(t, err)
+	f.assertChartVersion(path, "sidecar", "0.1.0")
+
+	_, err = f.cache.Fetch(f.ctx, "", fmt.Sprintf("oci://%s/charts/sidecar", host), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a version is required for charts in OCI registries")
+}
+
+// Chart names and versions come from remote index.yaml files,
+// so they must not be able to escape the cache dir.
+func TestCacheNamesStayInCache(t *testing.T) {
+	assert.Equal(t, "sidecar-0.1.0.tgz", repoArchiveName("sidecar", "0.1.0"))
+	assert.Equal(t, "sidecar-evil.tgz", repoArchiveName("sidecar", "../../evil"))
+	assert.Equal(t, "evil-0.1.0.tgz", repoArchiveName("../evil", "0.1.0"))
+	assert.Equal(t, "_", cacheDirName(".."))
+	assert.Equal(t, "charts.example.com_stable", cacheDirName("https://charts.example.com/stable/"))
+}
+
+func TestHelmRegistryLoginCreds(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.WriteFile("registry.json", `{"auths": {"localhost:5000": {"auth": "aGVsbTpzZWNyZXQ="}}}`)
+	oldConfig, hadConfig := os.LookupEnv("HELM_REGISTRY_CONFIG")
+	require.NoError(t, os.Setenv("HELM_REGISTRY_CONFIG", f.JoinPath("registry.json")))
+	defer func() {
+		if hadConfig {
+			_ = os.Setenv("HELM_REGISTRY_CONFIG", oldConfig)
+		} else {
+			_ = os.Unsetenv("HELM_REGISTRY_CONFIG")
+		}
+	}()
+
+	user, password, err := registryCreds("localhost:5000")
+	require.NoError(t, err)
+	assert.Equal(t, "helm", user)
+	assert.Equal(t, "secret", password)
+}
+
+func (f *fixture) packageChart(name, version string) []byte {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       name,
+			Version:    version,
+		},
+		Values: map[string]interface{}{"greeting": "hello"},
+		Templates: []*chart.File{
+			{Name: "templates/configmap.yaml", Data: []byte(configMapTemplate)},
+		},
+	}
+
+	dir := f.TempDir("package")
+	path, err := chartutil.Save(ch, dir)
+	require.NoError(f.t, err)
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(f.t, err)
+	return contents
+}
+
+// Serves an index.yaml and a chart archive for each version.
+func (f *fixture) newChartRepo(name string, versions ...string) *httptest.Server {
+	archives := make(map[string][]byte)
+	index := "apiVersion: v1\nentries:\n  " + name + ":\n"
+	for _, v := range versions {
+		filename := fmt.Sprintf("%s-%s.tgz", name, v)
+		archives["/"+filename] = f.packageChart(name, v)
+		index += fmt.Sprintf("  - apiVersion: v2\n    name: %s\n    version: %s\n    urls:\n    - %s\n", name, v, filename)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.yaml" {
+			_, _ = w.Write([]byte(index))
+			return
+		}
+		contents, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(contents)
+	}))
+}
+
+// Serves just enough of the OCI distribution API to pull one chart.
+func (f *fixture) newOCIRegistry(repo, version string) *httptest.Server {
+	chartContents := f.packageChart(repo[strings.LastIndex(repo, "/")+1:], version)
+	chartDigest := digest.FromBytes(chartContents)
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Layers: []ocispec.Descriptor{
+			{
+				MediaType: "application/tar+gzip",
+				Digest:    chartDigest,
+				Size:      int64(len(chartContents)),
+			},
+		},
+	})
+	require.NoError(f.t, err)
+	manifestDigest := digest.FromBytes(manifest)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case fmt.Sprintf("/v2/%s/manifests/%s", repo, version), fmt.Sprintf("/v2/%s/manifests/%s", repo, manifestDigest):
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", manifestDigest.String())
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(manifest)))
+			if r.Method != http.MethodHead {
+				_, _ = w.Write(manifest)
+			}
+		case fmt.Sprintf("/v2/%s/blobs/%s", repo, chartDigest):
+			_, _ = w.Write(chartContents)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func (f *fixture) assertChartVersion(path, name, version string) {
+	ch, _, err := LoadChart(f.ctx, path, f.cache)
+	require.NoError(f.t, err)
+	assert.Equal(f.t, name, ch.Name())
+	assert.Equal(f.t, version, ch.Metadata.Version)
+}
@@ -0,0 +1,92 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+const fileRepository = "file://"
+
+// LoadChart loads the chart at path (a chart directory or archive), along
+// with any dependencies that haven't been vendored into its charts/ directory.
+//
+// This is the in-memory equivalent of `helm dependency build`: missing local
+// dependencies are loaded from their file:// paths, and missing remote
+// dependencies are fetched into the cache. We never write to the chart
+// directory itself.
+//
+// Also returns the paths of the dependencies that live outside the chart,
+// so that callers can watch them.
+func LoadChart(ctx context.Context, path string, cache *ChartCache) (*chart.Chart, []string, error) {
+	ch, err := loader.Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deps, err := loadDependencies(ctx, ch, path, cache)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, deps, nil
+}
+
+func loadDependencies(ctx context.Context, ch *chart.Chart, chartPath string, cache *ChartCache) ([]string, error) {
+	if ch.Metadata == nil {
+		return nil, nil
+	}
+
+	present := make(map[string]bool, len(ch.Dependencies()))
+	for _, d := range ch.Dependencies() {
+		present[d.Name()] = true
+	}
+
+	var depPaths []string
+	for _, d := range ch.Metadata.Dependencies {
+		if present[d.Name] {
+			continue
+		}
+
+		depPath, err := dependencyPath(ctx, chartPath, d, cache)
+		if err != nil {
+			return nil, errors.Wrapf(err, "chart %s: dependency %s", ch.Name(), d.Name)
+		}
+
+		dep, subdeps, err := LoadChart(ctx, depPath, cache)
+		if err != nil {
+			return nil, errors.Wrapf(err, "chart %s: loading dependency %s", ch.Name(), d.Name)
+		}
+
+		ch.AddDependency(dep)
+		present[d.Name] = true
+		depPaths = append(depPaths, depPath)
+		depPaths = append(depPaths, subdeps...)
+	}
+	return depPaths, nil
+}
+
+func dependencyPath(ctx context.Context, chartPath string, d *chart.Dependency, cache *ChartCache) (string, error) {
+	switch {
+	case d.Repository == "":
+		return "", fmt.Errorf("missing in charts/ directory, and no repository to fetch it from")
+
+	case strings.HasPrefix(d.Repository, fileRepository):
+		p := strings.TrimPrefix(d.Repository, fileRepository)
+		if !filepath.IsAbs(p) {
+			// Archived charts can't have relative file:// dependencies,
+			// so the chart path here is always a directory.
+			p = filepath.Join(chartPath, p)
+		}
+		return p, nil
+
+	case strings.HasPrefix(d.Repository, "@") || strings.HasPrefix(d.Repository, "alias:"):
+		return "", fmt.Errorf("repository %q: named repositories from `helm repo add` aren't supported, use the repository URL instead", d.Repository)
+	}
+
+	return cache.Fetch(ctx, d.Repository, d.Name, d.Version)
+}
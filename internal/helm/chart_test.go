@@ -0,0 +1,123 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestLoadChartFileDependency(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.writeChart("mychart", "mychart", `
+dependencies:
+- name: sidecar
+  version: 0.1.0
+  repository: file://../sidecar
+`)
+	f.writeChart("sidecar", "sidecar")
+	f.WriteFile("sidecar/templates/configmap.yaml", configMapTemplate)
+	f.WriteFile("sidecar/values.yaml", "greeting: hello\n")
+
+	ch, deps, err := LoadChart(f.ctx, f.JoinPath("mychart"), f.cache)
+	require.NoError(t, err)
+	assert.Equal(t, []string{f.JoinPath("sidecar")}, deps)
+
+	yaml, err := Template(ch, TemplateOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "# Source: mychart/charts/sidecar/templates/configmap.yaml")
+
+	// Make sure we didn't vendor the dependency into the chart.
+	assert.NoFileExists(t, f.JoinPath("mychart", "charts", "sidecar"))
+}
+
+func TestLoadChartVendoredDependency(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.writeChart("mychart", "mychart", `
+dependencies:
+- name: sidecar
+  version: 0.1.0
+  repository: https://charts.example.com
+`)
+	f.writeChart("mychart/charts/sidecar", "sidecar")
+
+	_, deps, err := LoadChart(f.ctx, f.JoinPath("mychart"), f.cache)
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+}
+
+func TestLoadChartMissingDependency(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.writeChart("mychart", "mychart", `
+dependencies:
+- name: sidecar
+  version: 0.1.0
+`)
+
+	_, _, err := LoadChart(f.ctx, f.JoinPath("mychart"), f.cache)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chart mychart: dependency sidecar: missing in charts/ directory")
+}
+
+func TestLoadChartNamedRepository(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.writeChart("mychart", "mychart", `
+dependencies:
+- name: sidecar
+  version: 0.1.0
+  repository: "@stable"
+`)
+
+	_, _, err := LoadChart(f.ctx, f.JoinPath("mychart"), f.cache)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "named repositories from `helm repo add` aren't supported")
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	t     *testing.T
+	ctx   context.Context
+	cache *ChartCache
+}
+
+func newFixture(t *testing.T) *fixture {
+	tf := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	return &fixture{
+		TempDirFixture: tf,
+		t:              t,
+		ctx:            ctx,
+		cache:          NewChartCache(tf.JoinPath(".cache")),
+	}
+}
+
+func (f *fixture) writeChart(dir, name string, extra ...string) {
+	chartYAML := fmt.Sprintf("apiVersion: v2\nname: %s\nversion: 0.1.0\n", name)
+	for _, e := range extra {
+		chartYAML += e
+	}
+	f.WriteFile(filepath.Join(dir, "Chart.yaml"), chartYAML)
+}
+
+func (f *fixture) template(dir string, opts TemplateOptions) string {
+	ch, _, err := LoadChart(f.ctx, f.JoinPath(dir), f.cache)
+	require.NoError(f.t, err)
+
+	yaml, err := Template(ch, opts)
+	require.NoError(f.t, err)
+	return yaml
+}
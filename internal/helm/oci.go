@@ -0,0 +1,142 @@
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+// Helm 3.4 and earlier pushed charts with a generic tar+gzip layer.
+// Later versions use a Helm-specific media type.
+var chartLayerMediaTypes = map[string]bool{
+	"application/vnd.cncf.helm.chart.content.v1.tar+gzip": true,
+	"application/tar+gzip":                                true,
+}
+
+// Only fetch the manifest and the one layer we need, so that
+// we don't have to pull the chart's other artifacts.
+func pullOCIChart(ctx context.Context, ref string) ([]byte, error) {
+	resolver := docker.NewResolver(docker.ResolverOptions{
+		Hosts: docker.ConfigureDefaultRegistries(
+			docker.WithAuthorizer(docker.NewDockerAuthorizer(docker.WithAuthCreds(registryCreds))),
+			docker.WithPlainHTTP(docker.MatchLocalhost),
+		),
+	})
+
+	name, desc, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := fetchBlob(ctx, fetcher, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest ocispec.Manifest
+	err = json.Unmarshal(contents, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %v", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if chartLayerMediaTypes[layer.MediaType] {
+			return fetchBlob(ctx, fetcher, layer)
+		}
+	}
+	return nil, fmt.Errorf("no Helm chart found in %s", ref)
+}
+
+func fetchBlob(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]byte, error) {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	contents, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = desc.Digest.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if desc.Digest.Algorithm().FromBytes(contents) != desc.Digest {
+		return nil, fmt.Errorf("digest mismatch for %s", desc.Digest)
+	}
+	return contents, nil
+}
+
+// Reuse the credentials from `helm registry login` or `docker login`.
+//
+// Helm 3.0-3.7 keeps its registry logins in its own file ($HELM_REGISTRY_CONFIG,
+// ~/.config/helm/registry.json by default), in the same format as Docker's
+// config.json, so we check that file first.
+func registryCreds(host string) (string, string, error) {
+	if host == "registry-1.docker.io" {
+		host = "https://index.docker.io/v1/"
+	}
+
+	files := []*configfile.ConfigFile{}
+	helmFile, err := loadHelmRegistryConfig(cli.New().RegistryConfig)
+	if err != nil {
+		return "", "", err
+	}
+	if helmFile != nil {
+		files = append(files, helmFile)
+	}
+	files = append(files, config.LoadDefaultConfigFile(ioutil.Discard))
+
+	for _, cf := range files {
+		auth, err := cf.GetAuthConfig(host)
+		if err != nil {
+			return "", "", err
+		}
+		if auth.IdentityToken != "" {
+			return "", auth.IdentityToken, nil
+		}
+		if auth.Username != "" || auth.Password != "" {
+			return auth.Username, auth.Password, nil
+		}
+	}
+	return "", "", nil
+}
+
+// Returns nil if the user has never logged in with `helm registry login`.
+func loadHelmRegistryConfig(path string) (*configfile.ConfigFile, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	cf := configfile.New(path)
+	err = cf.LoadFromReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading Helm registry config %s: %v", path, err)
+	}
+	return cf, nil
+}
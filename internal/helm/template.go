@@ -0,0 +1,124 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// Use 'chart' as the release name, so that the release name is stable
+// across Tiltfile loads.
+// This looks like what helm does.
+// https://github.com/helm/helm/blob/e672a42efae30d45ddd642a26557dcdbf5a9f5f0/pkg/action/install.go#L562
+const DefaultReleaseName = "chart"
+
+const defaultNamespace = "default"
+
+const notesFileSuffix = "NOTES.txt"
+
+// TemplateOptions are the subset of `helm template` flags that Tilt supports.
+type TemplateOptions struct {
+	ReleaseName string
+	Namespace   string
+
+	// Absolute paths to values files, applied in order.
+	ValueFiles []string
+
+	// Values in the format of `helm template --set`
+	Set []string
+}
+
+// Template renders a chart the same way `helm template --include-crds` does,
+// without talking to a cluster.
+//
+// The chart's dependencies must already be loaded (see LoadChart).
+func Template(ch *chart.Chart, opts TemplateOptions) (string, error) {
+	valueOpts := &values.Options{
+		ValueFiles: opts.ValueFiles,
+		Values:     opts.Set,
+	}
+	vals, err := valueOpts.MergeValues(getter.All(cli.New()))
+	if err != nil {
+		return "", err
+	}
+
+	err = chartutil.ProcessDependencies(ch, vals)
+	if err != nil {
+		return "", err
+	}
+
+	caps := chartutil.DefaultCapabilities
+	if ch.Metadata.KubeVersion != "" && !chartutil.IsCompatibleRange(ch.Metadata.KubeVersion, caps.KubeVersion.String()) {
+		return "", fmt.Errorf("chart requires kubeVersion: %s which is incompatible with Kubernetes %s",
+			ch.Metadata.KubeVersion, caps.KubeVersion.String())
+	}
+
+	name := opts.ReleaseName
+	if name == "" {
+		name = DefaultReleaseName
+	}
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	renderVals, err := chartutil.ToRenderValues(ch, vals, chartutil.ReleaseOptions{
+		Name:      name,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}, caps)
+	if err != nil {
+		return "", err
+	}
+
+	files, err := engine.Render(ch, renderVals)
+	if err != nil {
+		return "", err
+	}
+
+	for k := range files {
+		if strings.HasSuffix(k, notesFileSuffix) {
+			delete(files, k)
+		}
+	}
+
+	hooks, manifests, err := releaseutil.SortManifests(files, caps.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return "", errors.Wrapf(err, "rendering helm chart %s", ch.Name())
+	}
+
+	b := bytes.NewBuffer(nil)
+	for _, crd := range ch.CRDObjects() {
+		fmt.Fprintf(b, "---\n# Source: %s\n%s\n", crd.Name, string(crd.File.Data))
+	}
+	for _, m := range manifests {
+		fmt.Fprintf(b, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	}
+	for _, h := range hooks {
+		if isTestHook(h) {
+			continue
+		}
+		fmt.Fprintf(b, "---\n# Source: %s\n%s\n", h.Path, h.Manifest)
+	}
+	return b.String(), nil
+}
+
+func isTestHook(h *release.Hook) bool {
+	for _, e := range h.Events {
+		if e == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
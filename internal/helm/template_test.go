@@ -0,0 +1,105 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  greeting: {{ .Values.greeting }}
+`
+
+func TestTemplate(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.writeChart("mychart", "mychart")
+	f.WriteFile("mychart/values.yaml", "greeting: hello\n")
+	f.WriteFile("mychart/templates/configmap.yaml", configMapTemplate)
+	f.WriteFile("mychart/templates/NOTES.txt", "Thanks for installing!")
+
+	yaml := f.template("mychart", TemplateOptions{})
+	assert.Contains(t, yaml, "# Source: mychart/templates/configmap.yaml")
+	assert.Contains(t, yaml, "name: chart-config")
+	assert.Contains(t, yaml, "namespace: default")
+	assert.Contains(t, yaml, "greeting: hello")
+	assert.NotContains(t, yaml, "Thanks for installing")
+}
+
+func TestTemplateOptions(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.writeChart("mychart", "mychart")
+	f.WriteFile("mychart/values.yaml", "greeting: hello\n")
+	f.WriteFile("mychart/templates/configmap.yaml", configMapTemplate)
+	f.WriteFile("values-dev.yaml", "greeting: howdy\n")
+
+	yaml := f.template("mychart", TemplateOptions{
+		ReleaseName: "rose-quartz",
+		Namespace:   "garnet",
+		ValueFiles:  []string{f.JoinPath("values-dev.yaml")},
+	})
+	assert.Contains(t, yaml, "name: rose-quartz-config")
+	assert.Contains(t, yaml, "namespace: garnet")
+	assert.Contains(t, yaml, "greeting: howdy")
+
+	yaml = f.template("mychart", TemplateOptions{
+		ValueFiles: []string{f.JoinPath("values-dev.yaml")},
+		Set:        []string{"greeting=hiya"},
+	})
+	assert.Contains(t, yaml, "greeting: hiya")
+}
+
+func TestTemplateCRDsAndHooks(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.writeChart("mychart", "mychart")
+	f.WriteFile("mychart/crds/machine.yaml", `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: machines.tilt.dev
+`)
+	f.WriteFile("mychart/templates/job.yaml", `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    "helm.sh/hook": pre-install
+`)
+	f.WriteFile("mychart/templates/test.yaml", `apiVersion: v1
+kind: Pod
+metadata:
+  name: test-connection
+  annotations:
+    "helm.sh/hook": test
+`)
+
+	yaml := f.template("mychart", TemplateOptions{})
+	assert.Contains(t, yaml, "name: machines.tilt.dev")
+	assert.Contains(t, yaml, "name: migrate")
+	assert.NotContains(t, yaml, "test-connection")
+}
+
+func TestTemplateError(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.writeChart("mychart", "mychart")
+	f.WriteFile("mychart/templates/configmap.yaml", "{{ .Values.missing.field }}")
+
+	ch, _, err := LoadChart(f.ctx, f.JoinPath("mychart"), f.cache)
+	require.NoError(t, err)
+
+	_, err = Template(ch, TemplateOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mychart/templates/configmap.yaml")
+}
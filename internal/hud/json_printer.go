@@ -0,0 +1,63 @@
+package hud
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// Prints logs as JSON, one record per line, so that they can be piped
+// into jq or a log aggregator.
+type JSONPrinter struct {
+	encoder *json.Encoder
+}
+
+type JSONLogRecord struct {
+	Time         time.Time          `json:"time"`
+	ManifestName model.ManifestName `json:"manifestName,omitempty"`
+	SpanID       logstore.SpanID    `json:"spanID,omitempty"`
+	Level        string             `json:"level"`
+	BuildNumber  int                `json:"buildNumber,omitempty"`
+	Text         string             `json:"text"`
+}
+
+func NewJSONPrinter(stdout Stdout) *JSONPrinter {
+	return &JSONPrinter{encoder: json.NewEncoder(stdout)}
+}
+
+func (p *JSONPrinter) Print(lines []logstore.LogLine) {
+	for _, line := range lines {
+		text := strings.TrimSuffix(line.Text, "\n")
+		if text == "" {
+			// Blank lines are only there to make the terminal output readable.
+			continue
+		}
+
+		_ = p.encoder.Encode(JSONLogRecord{
+			Time:         line.Time,
+			ManifestName: line.ManifestName,
+			SpanID:       line.SpanID,
+			Level:        line.Level.String(),
+			BuildNumber:  buildNumber(line.SpanID),
+			Text:         text,
+		})
+	}
+}
+
+// Build logs have span IDs like "build:fe:3", for the third build of fe.
+// Returns 0 for all other spans.
+func buildNumber(spanID logstore.SpanID) int {
+	s := string(spanID)
+	if !strings.HasPrefix(s, "build:") {
+		return 0
+	}
+	n, err := strconv.Atoi(s[strings.LastIndex(s, ":")+1:])
+	if err != nil {
+		return 0
+	}
+	return n
+}
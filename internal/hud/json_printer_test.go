@@ -0,0 +1,31 @@
+package hud
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+func TestJSONPrinter(t *testing.T) {
+	out := &bytes.Buffer{}
+	now := time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC)
+	printer := NewJSONPrinter(Stdout(out))
+
+	printer.Print([]logstore.LogLine{
+		logstore.LogLine{Text: "\n", SpanID: "build:fe:3", ManifestName: "fe", Time: now},
+		logstore.LogLine{Text: "Building fe\n", SpanID: "build:fe:3", ManifestName: "fe", Level: logger.InfoLvl, Time: now},
+		logstore.LogLine{Text: "uh oh\n", SpanID: "pod:fe:abc", ManifestName: "fe", Level: logger.WarnLvl, Time: now},
+		logstore.LogLine{Text: "Loading Tiltfile\n", SpanID: "tiltfile:2", ManifestName: "(Tiltfile)", Level: logger.InfoLvl, Time: now},
+	})
+
+	assert.Equal(t,
+		`{"time":"2021-01-02T15:04:05Z","manifestName":"fe","spanID":"build:fe:3","level":"info","buildNumber":3,"text":"Building fe"}
+{"time":"2021-01-02T15:04:05Z","manifestName":"fe","spanID":"pod:fe:abc","level":"warn","text":"uh oh"}
+{"time":"2021-01-02T15:04:05Z","manifestName":"(Tiltfile)","spanID":"tiltfile:2","level":"info","text":"Loading Tiltfile"}
+`, out.String())
+}
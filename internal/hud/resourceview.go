@@ -152,7 +152,7 @@ func (v *ResourceView) titleTextName() rty.Component {
 	if display.spinner {
 		name = fmt.Sprintf("%s %s", v.res.Name, v.spinner())
 	}
-	if len(v.warnings()) > 0 {
+	if len(v.warnings()) > 0 || (v.res.IsK8s() && len(v.res.K8sInfo().WarningReasons) > 0) {
 		name = fmt.Sprintf("%s %s", v.res.Name, "— Warning ⚠️")
 	}
 	sb.Fg(tcell.ColorDefault).Text(name)
@@ -281,7 +281,7 @@ func (v *ResourceView) endpointsNeedSecondLine() bool {
 	if len(v.res.Endpoints) > 1 {
 		return true
 	}
-	if v.res.IsK8s() && v.res.K8sInfo().PodRestarts > 0 && len(v.res.Endpoints) == 1 {
+	if v.res.IsK8s() && (v.res.K8sInfo().PodRestarts > 0 || len(v.res.K8sInfo().WarningReasons) > 0) && len(v.res.Endpoints) == 1 {
 		return true
 	}
 	return false
@@ -304,6 +304,11 @@ func (v *ResourceView) resourceExpandedK8s() rty.Component {
 		l.Add(middotText())
 	}
 
+	if len(k8sInfo.WarningReasons) > 0 {
+		l.Add(resourceTextWarnings(k8sInfo))
+		l.Add(middotText())
+	}
+
 	if len(v.res.Endpoints) > 0 && !v.endpointsNeedSecondLine() {
 		v.appendEndpoints(l)
 		l.Add(middotText())
@@ -331,6 +336,17 @@ func resourceTextPodRestarts(k8sInfo view.K8sResourceInfo) rty.Component {
 		Build()
 }
 
+func resourceTextWarnings(k8sInfo view.K8sResourceInfo) rty.Component {
+	s := "warnings"
+	if len(k8sInfo.WarningReasons) == 1 {
+		s = "warning"
+	}
+	return rty.NewStringBuilder().
+		Fg(cPending).
+		Textf("%d %s (%s)", len(k8sInfo.WarningReasons), s, k8sInfo.WarningReasons[0]).
+		Build()
+}
+
 func resourceTextAge(t time.Time) rty.Component {
 	sb := rty.NewStringBuilder()
 	sb.Fg(cLightText).Text("AGE ")
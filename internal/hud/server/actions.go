@@ -24,3 +24,11 @@ type OverrideTriggerModeAction struct {
 }
 
 func (OverrideTriggerModeAction) Action() {}
+
+// Turns resources off (tearing down whatever they deployed) or back on.
+type OverrideDisableAction struct {
+	ManifestNames []model.ManifestName
+	Disabled      bool
+}
+
+func (OverrideDisableAction) Action() {}
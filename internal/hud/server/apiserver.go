@@ -136,7 +136,12 @@ func ProvideTiltServerOptions(ctx context.Context,
 		return nil, err
 	}
 
-	return o.Config()
+	config, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+	withLabelFiltering(config.ExtraConfig.APIs)
+	return config, nil
 }
 
 // Generate the server config, removing options that are not needed for testing.
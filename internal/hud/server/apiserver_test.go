@@ -62,6 +62,15 @@ func TestAPIServerDynamicClient(t *testing.T) {
 				"componentID":   "my-resource",
 			},
 		},
+		"DockerComposeService": map[string]interface{}{
+			"service": "my-service",
+			"project": map[string]interface{}{
+				"configPaths": []string{"docker-compose.yml"},
+			},
+		},
+		"ExtensionRepo": map[string]interface{}{
+			"url": "https://github.com/tilt-dev/tilt-extensions",
+		},
 	}
 
 	for _, obj := range v1alpha1.AllResourceObjects() {
@@ -98,6 +107,40 @@ func TestAPIServerDynamicClient(t *testing.T) {
 	}
 }
 
+func TestAPIServerListLabelSelector(t *testing.T) {
+	f := newAPIServerFixture(t)
+	f.start()
+
+	client := f.dynamic.Resource((&v1alpha1.UIResource{}).GetGroupVersionResource())
+	for name, labels := range map[string]map[string]interface{}{
+		"frontend": {"web": "web"},
+		"backend":  {"api": "api"},
+		"db":       nil,
+	} {
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "UIResource",
+				"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+				"metadata": map[string]interface{}{
+					"name":   name,
+					"labels": labels,
+				},
+			},
+		}
+		_, err := client.Create(f.ctx, obj, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	list, err := client.List(f.ctx, metav1.ListOptions{LabelSelector: "web"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "frontend", list.Items[0].GetName())
+
+	list, err = client.List(f.ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 3)
+}
+
 func TestAPIServerProxy(t *testing.T) {
 	f := newAPIServerFixture(t)
 	f.start()
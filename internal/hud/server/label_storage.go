@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/generic"
+	registryrest "k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/apiserver"
+)
+
+// Wraps the storage for each resource so that lists and watches respect
+// label selectors (e.g., `tilt get uiresource -l foo`).
+// The underlying filepath storage ignores them.
+func withLabelFiltering(apis map[schema.GroupVersionResource]apiserver.StorageProvider) {
+	for gvr, provider := range apis {
+		// Subresources (e.g., status) don't support list.
+		if strings.Contains(gvr.Resource, "/") {
+			continue
+		}
+		apis[gvr] = labelFilteringProvider(provider)
+	}
+}
+
+func labelFilteringProvider(provider apiserver.StorageProvider) apiserver.StorageProvider {
+	return func(scheme *runtime.Scheme, getter generic.RESTOptionsGetter) (registryrest.Storage, error) {
+		storage, err := provider(scheme, getter)
+		if err != nil {
+			return nil, err
+		}
+		standard, ok := storage.(filepathStorage)
+		if !ok {
+			return nil, fmt.Errorf("unexpected storage type: %T", storage)
+		}
+		return labelFilteringStorage{filepathStorage: standard}, nil
+	}
+}
+
+// The interfaces implemented by the tilt-apiserver filepath storage.
+type filepathStorage interface {
+	registryrest.StandardStorage
+	registryrest.Scoper
+	registryrest.ShortNamesProvider
+}
+
+// Wraps filepath storage, filtering lists and watches by label.
+type labelFilteringStorage struct {
+	filepathStorage
+}
+
+var _ filepathStorage = labelFilteringStorage{}
+
+func (s labelFilteringStorage) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	list, err := s.filepathStorage.List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labelSelector(options)
+	if selector.Empty() {
+		return list, nil
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		if matchesLabels(selector, item) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	err = meta.SetList(list, filtered)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s labelFilteringStorage) Watch(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+	w, err := s.filepathStorage.Watch(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labelSelector(options)
+	if selector.Empty() {
+		return w, nil
+	}
+
+	return watch.Filter(w, func(ev watch.Event) (watch.Event, bool) {
+		if ev.Type == watch.Error || ev.Type == watch.Bookmark {
+			return ev, true
+		}
+		return ev, matchesLabels(selector, ev.Object)
+	}), nil
+}
+
+func labelSelector(options *metainternalversion.ListOptions) labels.Selector {
+	if options == nil || options.LabelSelector == nil {
+		return labels.Everything()
+	}
+	return options.LabelSelector
+}
+
+func matchesLabels(selector labels.Selector, obj runtime.Object) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(accessor.GetLabels()))
+}
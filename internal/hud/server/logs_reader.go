@@ -2,14 +2,22 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
 
-	"github.com/tilt-dev/tilt/internal/hud"
 	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
@@ -32,9 +40,14 @@ type WebsocketReader struct {
 	handler      ViewHandler
 }
 
-func newWebsocketReaderForLogs(conn WebsocketConn, persistent bool, resources []string, p *hud.IncrementalPrinter) *WebsocketReader {
-	ls := NewLogStreamer(resources, p)
-	return newWebsocketReader(conn, persistent, ls)
+func newWebsocketReaderForLogs(conn WebsocketConn, opts StreamLogsOptions, p LogPrinter, history HistoryFetcher) *WebsocketReader {
+	ls := NewLogStreamer(opts.Resources, p).
+		WithSelector(opts.Selector).
+		WithFilter(opts.Filter).
+		WithTail(opts.Tail).
+		WithSuppressPrefix(opts.SuppressPrefix).
+		WithHistory(history)
+	return newWebsocketReader(conn, opts.Follow, ls)
 }
 
 func newWebsocketReader(conn WebsocketConn, persistent bool, handler ViewHandler) *WebsocketReader {
@@ -51,6 +64,11 @@ type ViewHandler interface {
 	Handle(v proto_webview.View) error
 }
 
+// Prints log lines, e.g., to a terminal (hud.IncrementalPrinter) or as JSON (hud.JSONPrinter).
+type LogPrinter interface {
+	Print(lines []logstore.LogLine)
+}
+
 type LogStreamer struct {
 	logstore *logstore.LogStore
 	// checkpoint tracks the client's latest printed logs.
@@ -62,10 +80,23 @@ type LogStreamer struct {
 	// This value should only be used to compare to other server values, NOT client checkpoints.
 	serverWatermark int32
 	resources       model.ManifestNameSet // if present, resource(s) to stream logs for
-	printer         *hud.IncrementalPrinter
+	selector        labels.Selector       // if present, also stream logs for resources with matching labels
+	selected        model.ManifestNameSet // resources currently matching the selector
+	filter          logstore.Filter       // only print logs that match
+	tail            int                   // if non-negative, only print this many lines of existing logs
+	suppressPrefix  bool                  // if true, never print the resource name prefix
+	printer         LogPrinter
+	handledFirst    bool
+
+	// If present, used to page back through archived logs before the first view.
+	history        HistoryFetcher
+	fetchedHistory bool
 }
 
-func NewLogStreamer(resources []string, p *hud.IncrementalPrinter) *LogStreamer {
+// Fetches a page of archived logs from before the given server checkpoint.
+type HistoryFetcher func(before int32, resources model.ManifestNameSet) (*proto_webview.LogList, error)
+
+func NewLogStreamer(resources []string, p LogPrinter) *LogStreamer {
 	mnSet := make(map[model.ManifestName]bool, len(resources))
 	for _, r := range resources {
 		mnSet[model.ManifestName(r)] = true
@@ -73,19 +104,63 @@ func NewLogStreamer(resources []string, p *hud.IncrementalPrinter) *LogStreamer
 
 	return &LogStreamer{
 		resources: mnSet,
+		selected:  make(map[model.ManifestName]bool),
 		logstore:  logstore.NewLogStore(),
 		printer:   p,
+		tail:      -1,
 	}
 }
 
+func (ls *LogStreamer) WithSelector(selector labels.Selector) *LogStreamer {
+	ls.selector = selector
+	return ls
+}
+
+func (ls *LogStreamer) WithTail(tail int) *LogStreamer {
+	ls.tail = tail
+	return ls
+}
+
+func (ls *LogStreamer) WithFilter(filter logstore.Filter) *LogStreamer {
+	ls.filter = filter
+	return ls
+}
+
+func (ls *LogStreamer) WithSuppressPrefix(suppressPrefix bool) *LogStreamer {
+	ls.suppressPrefix = suppressPrefix
+	return ls
+}
+
+func (ls *LogStreamer) WithHistory(history HistoryFetcher) *LogStreamer {
+	ls.history = history
+	return ls
+}
+
 func (ls *LogStreamer) Handle(v proto_webview.View) error {
-	if v.LogList.FromCheckpoint == -1 {
+	isFirst := !ls.handledFirst
+	ls.handledFirst = true
+
+	// Resources can be added, removed, or relabeled while we stream,
+	// so re-evaluate the selector against every resource the server sends.
+	ls.selectResources(v.UiResources)
+
+	if v.LogList == nil || v.LogList.FromCheckpoint == -1 {
 		// Server has no new logs to send
 		return nil
 	}
 
+	if ls.history != nil && !ls.fetchedHistory {
+		ls.fetchedHistory = true
+		err := ls.appendHistory(v.LogList.FromCheckpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	resources := ls.streamedResources()
+
 	// if printing logs for only one resource, don't need resource name prefix
-	suppressPrefix := len(ls.resources) == 1
+	suppressPrefix := ls.suppressPrefix || len(resources) == 1
 
 	segments := v.LogList.Segments
 	if v.LogList.FromCheckpoint < ls.serverWatermark {
@@ -96,20 +171,171 @@ func (ls *LogStreamer) Handle(v proto_webview.View) error {
 
 	for _, seg := range segments {
 		// TODO(maia): secrets???
-		ls.logstore.Append(webview.LogSegmentToEvent(seg, v.LogList.Spans), model.SecretSet{})
+		ls.append(webview.LogSegmentToEvent(seg, v.LogList.Spans))
 	}
 
-	ls.printer.Print(ls.logstore.ContinuingLinesWithOptions(ls.checkpoint, logstore.LineOptions{
-		ManifestNames:  ls.resources,
-		SuppressPrefix: suppressPrefix,
-	}))
+	lines := []logstore.LogLine{}
+	if !ls.selectsNothing(resources) {
+		lines = ls.logstore.ContinuingLinesWithOptions(ls.checkpoint, logstore.LineOptions{
+			ManifestNames:  resources,
+			SuppressPrefix: suppressPrefix,
+		})
+	}
+	if isFirst && ls.tail >= 0 && len(lines) > ls.tail {
+		lines = lines[len(lines)-ls.tail:]
+	}
+	ls.printer.Print(lines)
 
 	ls.checkpoint = ls.logstore.Checkpoint()
 	ls.serverWatermark = v.LogList.ToCheckpoint
 
 	return nil
 }
-func StreamLogs(ctx context.Context, follow bool, url model.WebURL, resources []string, printer *hud.IncrementalPrinter) error {
+
+// Update the selected set with the given resources: add the ones that match
+// the label selector, and drop the ones that no longer match or were deleted.
+//
+// Views may contain only the resources that changed, so resources absent
+// from the view keep their previous selection.
+func (ls *LogStreamer) selectResources(resources []*v1alpha1.UIResource) {
+	if !ls.hasSelector() {
+		return
+	}
+	for _, r := range resources {
+		mn := model.ManifestName(r.Name)
+		if r.DeletionTimestamp == nil && ls.selector.Matches(labels.Set(r.Labels)) {
+			ls.selected[mn] = true
+		} else {
+			delete(ls.selected, mn)
+		}
+	}
+}
+
+func (ls *LogStreamer) hasSelector() bool {
+	return ls.selector != nil && !ls.selector.Empty()
+}
+
+// The resources named explicitly, plus the ones currently selected by label.
+func (ls *LogStreamer) streamedResources() model.ManifestNameSet {
+	if len(ls.selected) == 0 {
+		return ls.resources
+	}
+	result := make(model.ManifestNameSet, len(ls.resources)+len(ls.selected))
+	for mn := range ls.resources {
+		result[mn] = true
+	}
+	for mn := range ls.selected {
+		result[mn] = true
+	}
+	return result
+}
+
+// An empty resource set means "all resources", unless we were asked to select
+// resources by label and none matched.
+func (ls *LogStreamer) selectsNothing(resources model.ManifestNameSet) bool {
+	return len(resources) == 0 && ls.hasSelector()
+}
+
+// Page back through the archived logs before the given checkpoint,
+// and add them to the logstore, oldest first.
+func (ls *LogStreamer) appendHistory(before int32) error {
+	var pages []*proto_webview.LogList
+	for {
+		page, err := ls.history(before, ls.streamedResources())
+		if err != nil {
+			return errors.Wrap(err, "fetching log history")
+		}
+		if page.FromCheckpoint == -1 || len(page.Segments) == 0 {
+			break
+		}
+		pages = append(pages, page)
+		before = page.FromCheckpoint
+	}
+
+	for i := len(pages) - 1; i >= 0; i-- {
+		page := pages[i]
+		for _, seg := range page.Segments {
+			ls.append(webview.LogSegmentToEvent(seg, page.Spans))
+		}
+	}
+	return nil
+}
+
+// Add the event to the logstore, if it passes the filter.
+func (ls *LogStreamer) append(e store.LogAction) {
+	seg := logstore.LogSegment{
+		SpanID: e.SpanID(),
+		Time:   e.Time(),
+		Text:   e.Message(),
+		Level:  e.Level(),
+	}
+	if !ls.filter.Matches(seg, e.ManifestName()) {
+		return
+	}
+	ls.logstore.Append(e, model.SecretSet{})
+}
+
+// Fetches log history over HTTP from a running Tilt.
+func NewHTTPHistoryFetcher(url model.WebURL) HistoryFetcher {
+	url.Scheme = "http"
+	url.Path = "/api/logs/history"
+	return func(before int32, resources model.ManifestNameSet) (*proto_webview.LogList, error) {
+		query := neturl.Values{}
+		query.Set("before", strconv.Itoa(int(before)))
+		for mn := range resources {
+			query.Add("manifest", mn.String())
+		}
+		url.RawQuery = query.Encode()
+
+		resp, err := http.Get(url.String())
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(resp.Body)
+			return nil, fmt.Errorf("GET %s: %s: %s", url.String(), resp.Status, strings.TrimSpace(string(body)))
+		}
+
+		logList := &proto_webview.LogList{}
+		err = (&jsonpb.Unmarshaler{}).Unmarshal(resp.Body, logList)
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding log history")
+		}
+		return logList, nil
+	}
+}
+
+type StreamLogsOptions struct {
+	// If true, keep streaming logs. Otherwise, print the current logs and exit.
+	Follow bool
+
+	// If true, first print any archived logs that the server has
+	// truncated from memory (or that are from a previous session).
+	History bool
+
+	// Resources to print logs for. If both Resources and Selector are empty,
+	// print logs for all resources.
+	Resources []string
+	Selector  labels.Selector
+
+	Filter logstore.Filter
+
+	// If true, never prefix log lines with the resource name
+	// (e.g., because the printer reports the resource name separately).
+	SuppressPrefix bool
+
+	// If non-negative, only print this many lines of the existing logs.
+	Tail int
+}
+
+func StreamLogs(ctx context.Context, url model.WebURL, opts StreamLogsOptions, printer LogPrinter) error {
+	var historyFetcher HistoryFetcher
+	if opts.History {
+		historyFetcher = NewHTTPHistoryFetcher(url)
+	}
+
 	url.Scheme = "ws"
 	url.Path = "/ws/view"
 	logger.Get(ctx).Debugf("connecting to %s", url.String())
@@ -120,7 +346,7 @@ func StreamLogs(ctx context.Context, follow bool, url model.WebURL, resources []
 	}
 	defer conn.Close()
 
-	wsr := newWebsocketReaderForLogs(conn, follow, resources, printer)
+	wsr := newWebsocketReaderForLogs(conn, opts, printer, historyFetcher)
 	return wsr.Listen(ctx)
 }
 
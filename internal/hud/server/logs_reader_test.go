@@ -3,13 +3,19 @@ package server
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 
 	"github.com/tilt-dev/tilt/internal/hud"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
@@ -125,6 +131,129 @@ func TestLogStreamerCheckpointHandlingWithFiltering(t *testing.T) {
 	f.assertExpectedLogLines(expected)
 }
 
+func TestLogStreamerFilter(t *testing.T) {
+	f := newLogStreamerFixture(t)
+	f.ls.WithFilter(logstore.Filter{
+		MinLevel: logger.WarnLvl,
+		Pattern:  regexp.MustCompile("o$"),
+	})
+
+	view := f.newViewWithLogsForManifest(alphabet[:8], "foo", 0)
+	for i, seg := range view.LogList.Segments {
+		if i%2 == 0 {
+			seg.Level = proto_webview.LogLevel_WARN
+		}
+	}
+	f.handle(view)
+
+	// alpha, charlie, echo and golf are warnings, but only echo ends in "o"
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"echo"}, "foo"))
+}
+
+func TestLogStreamerPrintsHistoryFirst(t *testing.T) {
+	f := newLogStreamerFixture(t)
+
+	// The server has truncated alpha-foxtrot from memory; they're only in the archive,
+	// two segments per page.
+	var requests []int32
+	f.ls.WithHistory(func(before int32, resources model.ManifestNameSet) (*proto_webview.LogList, error) {
+		requests = append(requests, before)
+		if before <= 0 {
+			return &proto_webview.LogList{FromCheckpoint: -1, ToCheckpoint: -1}, nil
+		}
+		view := f.newViewWithLogsForManifest(alphabet[before-2:before], "foo", before-2)
+		return view.LogList, nil
+	})
+
+	view := f.newViewWithLogsForManifest(alphabet[6:8], "foo", 6)
+	f.handle(view)
+
+	view = f.newViewWithLogsForManifest(alphabet[8:9], "foo", view.LogList.ToCheckpoint)
+	f.handle(view)
+
+	assert.Equal(t, []int32{6, 4, 2, 0}, requests)
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix(alphabet[:9], "foo"))
+}
+
+func TestLogStreamerTail(t *testing.T) {
+	f := newLogStreamerFixture(t)
+	f.ls.WithTail(2)
+
+	view := f.newViewWithLogsForManifest(alphabet[:4], "foo", 0)
+	f.handle(view)
+
+	// Tail only applies to the existing logs, not to new logs.
+	view = f.newViewWithLogsForManifest(alphabet[4:7], "foo", view.LogList.ToCheckpoint)
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix(alphabet[2:7], "foo"))
+}
+
+func TestLogStreamerSelector(t *testing.T) {
+	f := newLogStreamerFixture(t)
+	f.ls.WithSelector(labels.SelectorFromSet(labels.Set{"team": "frontend"}))
+
+	view := f.newViewWithLogsForManifests(alphabet[:3], []string{"fe", "be", "fe"}, 0)
+	view.UiResources = []*v1alpha1.UIResource{
+		{ObjectMeta: metav1.ObjectMeta{Name: "fe", Labels: map[string]string{"team": "frontend"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "be", Labels: map[string]string{"team": "backend"}}},
+	}
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix([]string{"alpha", "charlie"}, ""))
+}
+
+func TestLogStreamerSelectorMatchesNothing(t *testing.T) {
+	f := newLogStreamerFixture(t)
+	f.ls.WithSelector(labels.SelectorFromSet(labels.Set{"team": "ops"}))
+
+	view := f.newViewWithLogsForManifests(alphabet[:2], []string{"fe", "be"}, 0)
+	f.handle(view)
+
+	assert.Equal(t, "", f.fakeStdout.String())
+}
+
+func TestLogStreamerSelectorReevaluatedOnEveryView(t *testing.T) {
+	f := newLogStreamerFixture(t)
+	f.ls.WithSelector(labels.SelectorFromSet(labels.Set{"team": "frontend"}))
+
+	view := f.newViewWithLogsForManifests(alphabet[:2], []string{"fe", "be"}, 0)
+	view.UiResources = []*v1alpha1.UIResource{
+		{ObjectMeta: metav1.ObjectMeta{Name: "fe", Labels: map[string]string{"team": "frontend"}}},
+	}
+	f.handle(view)
+
+	// A later view adds a matching resource, without resending the others.
+	view = f.newViewWithLogsForManifests(alphabet[2:4], []string{"fe", "web"}, view.LogList.ToCheckpoint)
+	view.UiResources = []*v1alpha1.UIResource{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "frontend"}}},
+	}
+	f.handle(view)
+
+	// Then "fe" is relabeled, so it no longer matches.
+	view = f.newViewWithLogsForManifests(alphabet[4:6], []string{"fe", "web"}, view.LogList.ToCheckpoint)
+	view.UiResources = []*v1alpha1.UIResource{
+		{ObjectMeta: metav1.ObjectMeta{Name: "fe", Labels: map[string]string{"team": "backend"}}},
+	}
+	f.handle(view)
+
+	f.assertExpectedLogLines(append(append(
+		f.expectedLinesWithPrefix([]string{"alpha"}, ""),
+		f.expectedLinesWithPrefixes([]string{"charlie", "delta"}, []string{"fe", "web"})...),
+		f.expectedLinesWithPrefix([]string{"foxtrot"}, "")...))
+}
+
+func TestLogStreamerSuppressPrefix(t *testing.T) {
+	f := newLogStreamerFixture(t)
+	f.ls.WithSuppressPrefix(true)
+	manifestNames := []string{"foo", "bar"}
+
+	view := f.newViewWithLogsForManifests(alphabet[:2], manifestNames, 0)
+	f.handle(view)
+
+	f.assertExpectedLogLines(f.expectedLinesWithPrefix(alphabet[:2], ""))
+}
+
 type logStreamerFixture struct {
 	t          *testing.T
 	fakeStdout *bytes.Buffer
@@ -9,6 +9,10 @@ import (
 	"log"
 	"net/http"
 	_ "net/http/pprof"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/gorilla/mux"
@@ -23,7 +27,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/hud/webview"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/assets"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 )
 
@@ -49,6 +55,25 @@ type overrideTriggerModePayload struct {
 	TriggerMode   int      `json:"trigger_mode"`
 }
 
+type tiltfileArgsPayload struct {
+	Args     []string                  `json:"args"`
+	Settings []model.UserConfigSetting `json:"settings"`
+}
+
+type setTiltfileValuesPayload struct {
+	Values map[string]interface{} `json:"values"`
+}
+
+type overrideDisablePayload struct {
+	ManifestNames []string `json:"manifest_names"`
+	Disabled      bool     `json:"disabled"`
+}
+
+// Shows what the next deploy of a resource would change in the cluster.
+type ResourceDiffer interface {
+	Diff(ctx context.Context, manifest model.Manifest, results store.BuildResultSet) (string, error)
+}
+
 type HeadsUpServer struct {
 	ctx        context.Context
 	store      *store.Store
@@ -57,6 +82,7 @@ type HeadsUpServer struct {
 	uploader   cloud.SnapshotUploader
 	wsList     *WebsocketList
 	ctrlClient ctrlclient.Client
+	differ     ResourceDiffer
 }
 
 func ProvideHeadsUpServer(
@@ -66,7 +92,8 @@ func ProvideHeadsUpServer(
 	analytics *tiltanalytics.TiltAnalytics,
 	uploader cloud.SnapshotUploader,
 	wsList *WebsocketList,
-	ctrlClient ctrlclient.Client) (*HeadsUpServer, error) {
+	ctrlClient ctrlclient.Client,
+	differ ResourceDiffer) (*HeadsUpServer, error) {
 	r := mux.NewRouter().UseEncodedPath()
 	s := &HeadsUpServer{
 		ctx:        ctx,
@@ -76,6 +103,7 @@ func ProvideHeadsUpServer(
 		uploader:   uploader,
 		wsList:     wsList,
 		ctrlClient: ctrlClient,
+		differ:     differ,
 	}
 
 	r.HandleFunc("/api/view", s.ViewJSON)
@@ -84,12 +112,18 @@ func ProvideHeadsUpServer(
 	r.HandleFunc("/api/analytics_opt", s.HandleAnalyticsOpt)
 	r.HandleFunc("/api/trigger", s.HandleTrigger)
 	r.HandleFunc("/api/override/trigger_mode", s.HandleOverrideTriggerMode)
+	r.HandleFunc("/api/override/disable", s.HandleOverrideDisable)
+	r.HandleFunc("/api/logs", s.HandleLogs).Methods("GET")
+	r.HandleFunc("/api/logs/history", s.HandleLogHistory).Methods("GET")
+	r.HandleFunc("/api/diff", s.HandleDiff).Methods("GET")
 	r.HandleFunc("/api/snapshot/new", s.HandleNewSnapshot).Methods("POST")
 	// this endpoint is only used for testing snapshots in development
 	r.HandleFunc("/api/snapshot/{snapshot_id}", s.SnapshotJSON)
 	r.HandleFunc("/ws/view", s.ViewWebsocket)
 	r.HandleFunc("/api/user_started_tilt_cloud_registration", s.userStartedTiltCloudRegistration)
 	r.HandleFunc("/api/set_tiltfile_args", s.HandleSetTiltfileArgs).Methods("POST")
+	r.HandleFunc("/api/tiltfile_args", s.HandleTiltfileArgs).Methods("GET")
+	r.HandleFunc("/api/set_tiltfile_values", s.HandleSetTiltfileValues).Methods("POST")
 
 	r.PathPrefix("/").Handler(s.cookieWrapper(assetServer))
 
@@ -133,16 +167,230 @@ func (s *HeadsUpServer) ViewJSON(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+const defaultLogLimit = 1000
+
+// Serves a page of logs matching the given filters.
+//
+// Query params:
+// from: the checkpoint to start searching at (default 0)
+// limit: the max number of segments to return
+// manifest: only return logs for this manifest (may be repeated)
+// span: only return logs for this span (may be repeated)
+// level: only return logs at least this severe (debug, verbose, info, warn, error)
+// since, until: only return logs in this time range (RFC3339)
+// pattern: only return logs matching this regular expression
+//
+// To get the next page, query again with from=ToCheckpoint.
+func (s *HeadsUpServer) HandleLogs(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	from := 0
+	if query.Get("from") != "" {
+		var err error
+		from, err = strconv.Atoi(query.Get("from"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid 'from' checkpoint: %q", query.Get("from")), http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit, err := parseLogLimit(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseLogFilter(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := s.store.RLockState()
+	logList, err := state.LogStore.Query(filter, logstore.Checkpoint(from), limit)
+	s.store.RUnlockState()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error querying logs: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	jsEncoder := &runtime.JSONPb{}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = jsEncoder.NewEncoder(w).Encode(logList)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error rendering logs: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func parseLogLimit(query url.Values) (int, error) {
+	if query.Get("limit") == "" {
+		return defaultLogLimit, nil
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid 'limit': %q", query.Get("limit"))
+	}
+	return limit, nil
+}
+
+func parseLogFilter(query url.Values) (logstore.Filter, error) {
+	filter := logstore.Filter{}
+	if len(query["manifest"]) > 0 {
+		filter.ManifestNames = model.ManifestNameSet{}
+		for _, mn := range query["manifest"] {
+			filter.ManifestNames[model.ManifestName(mn)] = true
+		}
+	}
+
+	if len(query["span"]) > 0 {
+		filter.SpanIDs = make(map[logstore.SpanID]bool)
+		for _, spanID := range query["span"] {
+			filter.SpanIDs[logstore.SpanID(spanID)] = true
+		}
+	}
+
+	if query.Get("level") != "" {
+		level, err := logger.ParseLevel(query.Get("level"))
+		if err != nil {
+			return logstore.Filter{}, err
+		}
+		filter.MinLevel = level
+	}
+
+	for _, param := range []struct {
+		name string
+		dest *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		val := query.Get(param.name)
+		if val == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return logstore.Filter{}, fmt.Errorf("invalid '%s' time %q. Must be RFC3339", param.name, val)
+		}
+		*param.dest = t
+	}
+
+	if query.Get("pattern") != "" {
+		pattern, err := regexp.Compile(query.Get("pattern"))
+		if err != nil {
+			return logstore.Filter{}, fmt.Errorf("invalid 'pattern': %v", err)
+		}
+		filter.Pattern = pattern
+	}
+	return filter, nil
+}
+
+// Serves a page of archived logs from before the given checkpoint, so that
+// clients can page back past the in-memory log window.
+//
+// Query params:
+// before: the checkpoint to read backwards from (required)
+// limit: the max number of segments to return
+// manifest: only return logs for this manifest (may be repeated)
+func (s *HeadsUpServer) HandleLogHistory(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	before, err := strconv.Atoi(query.Get("before"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid 'before' checkpoint: %q", query.Get("before")), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := parseLogLimit(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var mns model.ManifestNameSet
+	if len(query["manifest"]) > 0 {
+		mns = model.ManifestNameSet{}
+		for _, mn := range query["manifest"] {
+			mns[model.ManifestName(mn)] = true
+		}
+	}
+
+	// Don't hold the state lock while we read from disk.
+	state := s.store.RLockState()
+	history := state.LogStore.HistoryReader()
+	s.store.RUnlockState()
+
+	logList, err := history.History(logstore.Checkpoint(before), limit, mns)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error reading log history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	jsEncoder := &runtime.JSONPb{}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = jsEncoder.NewEncoder(w).Encode(logList)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error rendering log history: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// Shows what the next deploy of a Kubernetes resource would change in the
+// cluster, as a plain-text unified diff. An empty body means no changes.
+//
+// Query params:
+// resource: the name of the resource (required)
+func (s *HeadsUpServer) HandleDiff(w http.ResponseWriter, req *http.Request) {
+	if s.differ == nil {
+		http.Error(w, "diff is not supported in this mode", http.StatusNotFound)
+		return
+	}
+
+	mn := model.ManifestName(req.URL.Query().Get("resource"))
+	if mn == "" {
+		http.Error(w, "missing 'resource'", http.StatusBadRequest)
+		return
+	}
+
+	state := s.store.RLockState()
+	mt, ok := state.ManifestTargets[mn]
+	var manifest model.Manifest
+	results := store.BuildResultSet{}
+	if ok {
+		manifest = mt.Manifest
+		for id, status := range mt.State.BuildStatuses {
+			if status.LastResult != nil {
+				results[id] = status.LastResult
+			}
+		}
+	}
+	s.store.RUnlockState()
+
+	if !ok {
+		http.Error(w, fmt.Sprintf("no resource named %q", mn), http.StatusNotFound)
+		return
+	}
+
+	diff, err := s.differ.Diff(req.Context(), manifest, results)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error diffing %s: %v", mn, err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(diff))
+}
+
 // Dump the JSON engine over http. Only intended for 'tilt dump engine'.
 func (s *HeadsUpServer) DumpEngineJSON(w http.ResponseWriter, req *http.Request) {
 	state := s.store.RLockState()
 	defer s.store.RUnlockState()
 
-	encoder := store.CreateEngineStateEncoder(w)
+	buf := &bytes.Buffer{}
+	encoder := store.CreateEngineStateEncoder(buf)
 	err := encoder.Encode(state)
 	if err != nil {
 		log.Printf("Error encoding: %v", err)
+		return
 	}
+
+	_, _ = w.Write(state.Secrets.ScrubJSON(buf.Bytes()))
 }
 
 func (s *HeadsUpServer) SnapshotJSON(w http.ResponseWriter, req *http.Request) {
@@ -152,14 +400,25 @@ func (s *HeadsUpServer) SnapshotJSON(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	buf := &bytes.Buffer{}
 	var m jsonpb.Marshaler
-	err = m.Marshal(w, &proto_webview.Snapshot{
+	err = m.Marshal(buf, &proto_webview.Snapshot{
 		View: view,
 	})
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error rendering view payload: %v", err), http.StatusInternalServerError)
+		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(s.scrubSecrets(buf.Bytes()))
+}
+
+// Scrubs secrets from JSON that we're about to share.
+func (s *HeadsUpServer) scrubSecrets(b []byte) []byte {
+	state := s.store.RLockState()
+	defer s.store.RUnlockState()
+	return state.Secrets.ScrubJSON(b)
 }
 
 func (s *HeadsUpServer) HandleAnalyticsOpt(w http.ResponseWriter, req *http.Request) {
@@ -225,6 +484,51 @@ func (s *HeadsUpServer) HandleSetTiltfileArgs(w http.ResponseWriter, req *http.R
 	s.store.Dispatch(SetTiltfileArgsAction{args})
 }
 
+// The current Tiltfile args, and the settings the Tiltfile defined with config.define_*,
+// so that the web UI can show a form for them.
+func (s *HeadsUpServer) HandleTiltfileArgs(w http.ResponseWriter, req *http.Request) {
+	state := s.store.RLockState()
+	payload := tiltfileArgsPayload{
+		Args:     append([]string{}, state.UserConfigState.Args...),
+		Settings: append([]model.UserConfigSetting{}, state.UserConfigSettings...),
+	}
+	s.store.RUnlockState()
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error encoding tiltfile args: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// Updates the Tiltfile args from a map of setting name to value,
+// for UIs that don't want to deal with CLI flags.
+//
+// Settings that aren't in the map keep their current args (e.g., from the
+// command line). A null value clears a setting's args, so that it falls back
+// to tilt_config.json and its default.
+func (s *HeadsUpServer) HandleSetTiltfileValues(w http.ResponseWriter, req *http.Request) {
+	var payload setTiltfileValuesPayload
+	err := json.NewDecoder(req.Body).Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	state := s.store.RLockState()
+	settings := state.UserConfigSettings
+	currentArgs := append([]string{}, state.UserConfigState.Args...)
+	s.store.RUnlockState()
+
+	args, err := mergeTiltfileValues(settings, currentArgs, payload.Values)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.store.Dispatch(SetTiltfileArgsAction{args})
+}
+
 func (s *HeadsUpServer) HandleTrigger(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodPost {
 		http.Error(w, "must be POST request", http.StatusBadRequest)
@@ -297,6 +601,46 @@ func (s *HeadsUpServer) HandleOverrideTriggerMode(w http.ResponseWriter, req *ht
 	})
 }
 
+func (s *HeadsUpServer) HandleOverrideDisable(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "must be POST request", http.StatusBadRequest)
+		return
+	}
+
+	var payload overrideDisablePayload
+
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if len(payload.ManifestNames) == 0 {
+		http.Error(w, "must specify at least one manifest", http.StatusBadRequest)
+		return
+	}
+
+	for _, mName := range payload.ManifestNames {
+		if mName == model.TiltfileManifestName.String() {
+			http.Error(w, "the Tiltfile resource can't be disabled", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = checkManifestsExist(s.store, payload.ManifestNames)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.store.Dispatch(OverrideDisableAction{
+		ManifestNames: model.ManifestNames(payload.ManifestNames),
+		Disabled:      payload.Disabled,
+	})
+}
+
 /* -- SNAPSHOT: SENDING SNAPSHOT TO SERVER -- */
 type snapshotURLJson struct {
 	Url string `json:"url"`
@@ -316,6 +660,10 @@ func (s *HeadsUpServer) HandleNewSnapshot(w http.ResponseWriter, req *http.Reque
 		return
 	}
 
+	// The snapshot comes from the browser, so make sure
+	// we don't upload anything the logs would have scrubbed.
+	b = s.scrubSecrets(b)
+
 	jspb := &runtime.JSONPb{}
 	decoder := jspb.NewDecoder(bytes.NewBuffer(b))
 	var snapshot *proto_webview.Snapshot
@@ -3,6 +3,7 @@ package server_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -27,10 +28,13 @@ import (
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/cloud"
 	"github.com/tilt-dev/tilt/internal/cloud/cloudurl"
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/assets"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 )
 
@@ -324,6 +328,168 @@ func TestHandleOverrideTriggerModeDispatchesEvent(t *testing.T) {
 	assert.Equal(t, expected, action)
 }
 
+func TestHandleOverrideDisableReturnsErrorForBadManifest(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("foo", "baz")
+
+	payload := `{"manifest_names":["foo", "bar", "baz"], "disabled": true}`
+	status, respBody := f.makeReq("/api/override/disable", f.serv.HandleOverrideDisable, http.MethodPost, payload)
+
+	require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
+	require.Contains(t, respBody, "no manifest found with name 'bar'")
+	store.AssertNoActionOfType(t, reflect.TypeOf(server.OverrideDisableAction{}), f.getActions)
+}
+
+func TestHandleOverrideDisableTiltfile(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("foo")
+
+	payload := `{"manifest_names":["(Tiltfile)"], "disabled": true}`
+	status, respBody := f.makeReq("/api/override/disable", f.serv.HandleOverrideDisable, http.MethodPost, payload)
+
+	require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
+	require.Contains(t, respBody, "the Tiltfile resource can't be disabled")
+	store.AssertNoActionOfType(t, reflect.TypeOf(server.OverrideDisableAction{}), f.getActions)
+}
+
+func TestHandleOverrideDisableDispatchesEvent(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("foo", "bar")
+
+	payload := `{"manifest_names":["foo", "bar"], "disabled": true}`
+	status, _ := f.makeReq("/api/override/disable", f.serv.HandleOverrideDisable, http.MethodPost, payload)
+
+	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
+
+	a := store.WaitForAction(t, reflect.TypeOf(server.OverrideDisableAction{}), f.getActions)
+	action, ok := a.(server.OverrideDisableAction)
+	if !ok {
+		t.Fatalf("Action was not of type 'OverrideDisableAction': %+v", action)
+	}
+
+	expected := server.OverrideDisableAction{
+		ManifestNames: []model.ManifestName{"foo", "bar"},
+		Disabled:      true,
+	}
+	assert.Equal(t, expected, action)
+}
+
+func TestHandleLogs(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.Append(store.NewLogAction("fe", "fe-span", logger.InfoLvl, nil, []byte("fe started\n")), nil)
+	state.LogStore.Append(store.NewLogAction("be", "be-span", logger.InfoLvl, nil, []byte("be started\n")), nil)
+	state.LogStore.Append(store.NewLogAction("be", "be-span", logger.WarnLvl, nil, []byte("be is slow\n")), nil)
+	state.LogStore.Append(store.NewLogAction("be", "be-span", logger.ErrorLvl, nil, []byte("be crashed\n")), nil)
+	f.st.UnlockMutableState()
+
+	status, respBody := f.makeReq("/api/logs?manifest=be&level=warn&pattern=cr.sh", f.serv.HandleLogs, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
+
+	var logList proto_webview.LogList
+	jspb := &grpcRuntime.JSONPb{}
+	require.NoError(t, jspb.NewDecoder(strings.NewReader(respBody)).Decode(&logList))
+	require.Len(t, logList.Segments, 1)
+	assert.Equal(t, "be crashed\n", logList.Segments[0].Text)
+	assert.Equal(t, proto_webview.LogLevel_ERROR, logList.Segments[0].Level)
+	assert.Equal(t, "be", logList.Spans["be-span"].ManifestName)
+	assert.Equal(t, int32(4), logList.ToCheckpoint)
+
+	status, respBody = f.makeReq("/api/logs?from=1&limit=1", f.serv.HandleLogs, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
+	require.NoError(t, jspb.NewDecoder(strings.NewReader(respBody)).Decode(&logList))
+	require.Len(t, logList.Segments, 1)
+	assert.Equal(t, "be started\n", logList.Segments[0].Text)
+	assert.Equal(t, int32(2), logList.ToCheckpoint)
+}
+
+func TestHandleLogsBadParams(t *testing.T) {
+	f := newTestFixture(t)
+
+	for _, tc := range []struct {
+		query    string
+		expected string
+	}{
+		{"level=loud", "unknown log level"},
+		{"since=yesterday", "invalid 'since' time"},
+		{"pattern=(", "invalid 'pattern'"},
+		{"limit=0", "invalid 'limit'"},
+	} {
+		status, respBody := f.makeReq("/api/logs?"+tc.query, f.serv.HandleLogs, http.MethodGet, "")
+		require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
+		require.Contains(t, respBody, tc.expected)
+	}
+}
+
+func TestHandleDiff(t *testing.T) {
+	f := newTestFixture(t)
+
+	iTarget := model.MustNewImageTarget(container.MustParseSelector("sancho"))
+	m := model.Manifest{Name: "sancho"}.WithImageTarget(iTarget)
+	state := f.st.LockMutableStateForTesting()
+	mt := store.NewManifestTarget(m)
+	result := store.NewImageBuildResultSingleRef(iTarget.ID(), container.MustParseNamedTagged("sancho:tilt-123"))
+	mt.State.MutableBuildStatus(iTarget.ID()).LastResult = result
+	state.UpsertManifestTarget(mt)
+	f.st.UnlockMutableState()
+
+	f.differ.diff = "--- live/Deployment/sancho\n+++ merged/Deployment/sancho\n"
+	status, respBody := f.makeReq("/api/diff?resource=sancho", f.serv.HandleDiff, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
+	assert.Equal(t, f.differ.diff, respBody)
+	assert.Equal(t, store.BuildResultSet{iTarget.ID(): result}, f.differ.lastResults)
+}
+
+func TestHandleDiffErrors(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("fe")
+
+	status, respBody := f.makeReq("/api/diff", f.serv.HandleDiff, http.MethodGet, "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, respBody, "missing 'resource'")
+
+	status, respBody = f.makeReq("/api/diff?resource=be", f.serv.HandleDiff, http.MethodGet, "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, respBody, `no resource named "be"`)
+
+	f.differ.err = fmt.Errorf("resource \"fe\" is not a Kubernetes resource")
+	status, respBody = f.makeReq("/api/diff?resource=fe", f.serv.HandleDiff, http.MethodGet, "")
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Contains(t, respBody, "is not a Kubernetes resource")
+}
+
+func TestHandleLogHistory(t *testing.T) {
+	f := newTestFixture(t)
+
+	archive, err := logstore.OpenArchive(t.TempDir())
+	require.NoError(t, err)
+	defer func() { _ = archive.Close() }()
+
+	state := f.st.LockMutableStateForTesting()
+	require.NoError(t, state.LogStore.SetArchive(archive))
+	state.LogStore.Append(store.NewLogAction("fe", "fe-span", logger.InfoLvl, nil, []byte("fe log\n")), nil)
+	state.LogStore.Append(store.NewLogAction("be", "be-span", logger.InfoLvl, nil, []byte("be log\n")), nil)
+	checkpoint := state.LogStore.Checkpoint()
+	f.st.UnlockMutableState()
+
+	endpoint := fmt.Sprintf("/api/logs/history?before=%d&manifest=be", checkpoint)
+	status, respBody := f.makeReq(endpoint, f.serv.HandleLogHistory, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
+
+	var logList proto_webview.LogList
+	jspb := &grpcRuntime.JSONPb{}
+	require.NoError(t, jspb.NewDecoder(strings.NewReader(respBody)).Decode(&logList))
+	require.Len(t, logList.Segments, 1)
+	assert.Equal(t, "be log\n", logList.Segments[0].Text)
+	assert.Equal(t, "be", logList.Spans["be-span"].ManifestName)
+	assert.Equal(t, int32(1), logList.FromCheckpoint)
+}
+
+func TestHandleLogHistoryBadCheckpoint(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, respBody := f.makeReq("/api/logs/history?before=foo", f.serv.HandleLogHistory, http.MethodGet, "")
+	require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
+	require.Contains(t, respBody, "invalid 'before' checkpoint")
+}
+
 func TestHandleNewSnapshot(t *testing.T) {
 	f := newTestFixture(t)
 
@@ -381,6 +547,154 @@ func TestSetTiltfileArgs(t *testing.T) {
 	assert.Equal(t, []string{"--foo", "bar", "as df"}, action.Args)
 }
 
+func TestDumpEngineScrubsSecrets(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.Secrets.AddSecret("api-token", "", []byte(`abc"123-token`))
+	state.UserConfigState = model.NewUserConfigState([]string{`--token=abc"123-token`})
+	f.st.UnlockMutableState()
+
+	req, err := http.NewRequest("GET", "/api/dump/engine", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(f.serv.DumpEngineJSON)
+
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `--token=[redacted secret api-token]`)
+	assert.NotContains(t, rr.Body.String(), `123-token`)
+
+	var v interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &v))
+}
+
+func TestTiltfileArgs(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.UserConfigState = model.NewUserConfigState([]string{"--env=prod", "frontend"})
+	state.UserConfigSettings = []model.UserConfigSetting{
+		{Name: "env", Type: "enum", Choices: []string{"dev", "prod"}, Default: "dev", Value: "prod"},
+		{Name: "to-run", Type: "list[string]", Positional: true, Value: []interface{}{"frontend"}},
+	}
+	f.st.UnlockMutableState()
+
+	req, err := http.NewRequest("GET", "/api/tiltfile_args", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(f.serv.HandleTiltfileArgs)
+
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+  "args": ["--env=prod", "frontend"],
+  "settings": [
+    {"name": "env", "type": "enum", "choices": ["dev", "prod"], "default": "dev", "value": "prod"},
+    {"name": "to-run", "type": "list[string]", "positional": true, "value": ["frontend"]}
+  ]
+}`, rr.Body.String())
+}
+
+func TestSetTiltfileValues(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.UserConfigSettings = []model.UserConfigSetting{
+		{Name: "debug", Type: "bool"},
+		{Name: "env", Type: "enum", Choices: []string{"dev", "prod"}},
+		{Name: "port", Type: "int"},
+		{Name: "tags", Type: "list[string]"},
+		{Name: "to-run", Type: "list[string]", Positional: true},
+	}
+	f.st.UnlockMutableState()
+
+	json := `{"values": {"debug": false, "env": "prod", "port": 8080, "tags": ["a", "b"], "to-run": ["frontend", "backend"]}}`
+	req, err := http.NewRequest("POST", "/api/set_tiltfile_values", strings.NewReader(json))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(f.serv.HandleSetTiltfileValues)
+
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	a := store.WaitForAction(t, reflect.TypeOf(server.SetTiltfileArgsAction{}), f.getActions)
+	action, ok := a.(server.SetTiltfileArgsAction)
+	if !ok {
+		t.Fatalf("Action was not of type '%T': %+v", server.SetTiltfileArgsAction{}, action)
+	}
+	assert.Equal(t, []string{
+		"--debug=false", "--env=prod", "--port=8080", "--tags=a", "--tags=b", "frontend", "backend",
+	}, action.Args)
+}
+
+func TestSetTiltfileValuesMergesArgs(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.UserConfigSettings = []model.UserConfigSetting{
+		{Name: "debug", Type: "bool"},
+		{Name: "env", Type: "enum", Choices: []string{"dev", "prod"}},
+		{Name: "port", Type: "int"},
+		{Name: "to-run", Type: "list[string]", Positional: true},
+	}
+	state.UserConfigState = model.NewUserConfigState([]string{"--debug", "--env=dev", "--port", "9000", "frontend"})
+	f.st.UnlockMutableState()
+
+	// Settings not in the payload keep their args, and null clears a setting.
+	json := `{"values": {"port": 8080, "env": null}}`
+	req, err := http.NewRequest("POST", "/api/set_tiltfile_values", strings.NewReader(json))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(f.serv.HandleSetTiltfileValues)
+
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	a := store.WaitForAction(t, reflect.TypeOf(server.SetTiltfileArgsAction{}), f.getActions)
+	action, ok := a.(server.SetTiltfileArgsAction)
+	if !ok {
+		t.Fatalf("Action was not of type '%T': %+v", server.SetTiltfileArgsAction{}, action)
+	}
+	assert.Equal(t, []string{"--debug=true", "--port=8080", "frontend"}, action.Args)
+}
+
+func TestSetTiltfileValuesErrors(t *testing.T) {
+	f := newTestFixture(t)
+
+	state := f.st.LockMutableStateForTesting()
+	state.UserConfigSettings = []model.UserConfigSetting{
+		{Name: "env", Type: "enum", Choices: []string{"dev", "prod"}},
+		{Name: "port", Type: "int"},
+	}
+	f.st.UnlockMutableState()
+
+	for _, tc := range []struct {
+		values   string
+		expected string
+	}{
+		{`{"foo": "bar"}`, `Tiltfile does not define setting "foo"`},
+		{`{"env": "staging"}`, `setting "env": "staging" is not one of the allowed values: dev, prod`},
+		{`{"port": "abc"}`, `setting "port": expected int, found abc`},
+	} {
+		t.Run(tc.values, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/api/set_tiltfile_values", strings.NewReader(fmt.Sprintf(`{"values": %s}`, tc.values)))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(f.serv.HandleSetTiltfileValues)
+
+			handler.ServeHTTP(rr, req)
+			require.Equal(t, http.StatusBadRequest, rr.Code)
+			require.Contains(t, rr.Body.String(), tc.expected)
+		})
+	}
+}
+
 type serverFixture struct {
 	t            *testing.T
 	serv         *server.HeadsUpServer
@@ -390,6 +704,7 @@ type serverFixture struct {
 	getActions   func() []store.Action
 	snapshotHTTP *fakeHTTPClient
 	up           *user.FakePrefs
+	differ       *fakeDiffer
 }
 
 func newTestFixture(t *testing.T) *serverFixture {
@@ -406,7 +721,8 @@ func newTestFixture(t *testing.T) *serverFixture {
 	up := user.NewFakePrefs()
 	wsl := server.NewWebsocketList()
 	ctrlClient := fake.NewTiltClient()
-	serv, err := server.ProvideHeadsUpServer(context.Background(), st, assets.NewFakeServer(), ta, uploader, wsl, ctrlClient)
+	differ := &fakeDiffer{}
+	serv, err := server.ProvideHeadsUpServer(context.Background(), st, assets.NewFakeServer(), ta, uploader, wsl, ctrlClient, differ)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -420,6 +736,7 @@ func newTestFixture(t *testing.T) *serverFixture {
 		getActions:   getActions,
 		snapshotHTTP: snapshotHTTP,
 		up:           up,
+		differ:       differ,
 	}
 }
 
@@ -452,6 +769,17 @@ func (f *serverFixture) withDummyManifests(mNames ...string) *serverFixture {
 	return f
 }
 
+type fakeDiffer struct {
+	diff        string
+	err         error
+	lastResults store.BuildResultSet
+}
+
+func (d *fakeDiffer) Diff(ctx context.Context, manifest model.Manifest, results store.BuildResultSet) (string, error) {
+	d.lastResults = results
+	return d.diff, d.err
+}
+
 type fakeHTTPClient struct {
 	lastReq *http.Request
 }
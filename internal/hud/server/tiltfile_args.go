@@ -0,0 +1,180 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Merges values for the Tiltfile's config settings into its current args,
+// and returns the new args for config.parse().
+//
+// Settings that aren't in values keep their current args. A null value
+// removes the setting's args, so that it falls back to tilt_config.json
+// and its default.
+func mergeTiltfileValues(settings []model.UserConfigSetting, args []string, values map[string]interface{}) ([]string, error) {
+	byName := make(map[string]model.UserConfigSetting, len(settings))
+	for _, s := range settings {
+		byName[s.Name] = s
+	}
+
+	for name := range values {
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("Tiltfile does not define setting %q", name)
+		}
+	}
+
+	current, err := tiltfileArgsByName(settings, args)
+	if err != nil {
+		return nil, fmt.Errorf("parsing current Tiltfile args: %v", err)
+	}
+
+	for name, v := range values {
+		if v == nil {
+			delete(current, name)
+			continue
+		}
+		strs, err := tiltfileArgsFromValue(byName[name], v)
+		if err != nil {
+			return nil, fmt.Errorf("setting %q: %v", name, err)
+		}
+		current[name] = strs
+	}
+
+	var result, positional []string
+	for _, setting := range settings {
+		strs := current[setting.Name]
+		if setting.Positional {
+			positional = append(positional, strs...)
+			continue
+		}
+		for _, str := range strs {
+			result = append(result, fmt.Sprintf("--%s=%s", setting.Name, str))
+		}
+	}
+
+	for _, p := range positional {
+		if strings.HasPrefix(p, "-") {
+			// make sure the positional args aren't mistaken for flags
+			result = append(result, "--")
+			break
+		}
+	}
+	return append(result, positional...), nil
+}
+
+// Splits the Tiltfile's args into the raw values for each setting,
+// the same way config.parse() reads them.
+func tiltfileArgsByName(settings []model.UserConfigSetting, args []string) (map[string][]string, error) {
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	result := make(map[string][]string)
+	positionalName := ""
+	for _, setting := range settings {
+		if setting.Positional {
+			positionalName = setting.Name
+			continue
+		}
+		fs.Var(&rawArgs{name: setting.Name, values: result}, setting.Name, "")
+		if setting.Type == "bool" {
+			fs.Lookup(setting.Name).NoOptDefVal = "true"
+		}
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		if positionalName == "" {
+			return nil, fmt.Errorf("unexpected positional args: %v", fs.Args())
+		}
+		result[positionalName] = fs.Args()
+	}
+	return result, nil
+}
+
+// Collects the raw values of a flag, so that we can write them back out.
+type rawArgs struct {
+	name   string
+	values map[string][]string
+}
+
+func (a *rawArgs) String() string { return strings.Join(a.values[a.name], ",") }
+func (a *rawArgs) Type() string   { return "string" }
+func (a *rawArgs) Set(v string) error {
+	a.values[a.name] = append(a.values[a.name], v)
+	return nil
+}
+
+func tiltfileArgsFromValue(setting model.UserConfigSetting, v interface{}) ([]string, error) {
+	if setting.Type != "list[string]" {
+		str, err := tiltfileArgFromValue(setting, v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{str}, nil
+	}
+
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected list, found %T", v)
+	}
+	strs := []string{}
+	for _, elem := range list {
+		str, ok := elem.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, found %T", elem)
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
+
+func tiltfileArgFromValue(setting model.UserConfigSetting, v interface{}) (string, error) {
+	switch setting.Type {
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("expected string, found %T", v)
+		}
+		return str, nil
+	case "enum":
+		str, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("expected string, found %T", v)
+		}
+		for _, c := range setting.Choices {
+			if c == str {
+				return str, nil
+			}
+		}
+		return "", fmt.Errorf("%q is not one of the allowed values: %s", str, strings.Join(setting.Choices, ", "))
+	case "bool":
+		b, ok := v.(bool)
+		if !ok {
+			return "", fmt.Errorf("expected bool, found %T", v)
+		}
+		return strconv.FormatBool(b), nil
+	case "int":
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) {
+			return "", fmt.Errorf("expected int, found %v", v)
+		}
+		return strconv.FormatInt(int64(f), 10), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
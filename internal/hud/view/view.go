@@ -59,6 +59,9 @@ type K8sResourceInfo struct {
 	SpanID             logstore.SpanID
 	RunStatus          v1alpha1.RuntimeStatus
 	DisplayNames       []string
+
+	// Reasons for recent Kubernetes Warning events, most recent first.
+	WarningReasons []string
 }
 
 var _ ResourceInfoView = K8sResourceInfo{}
@@ -179,7 +182,8 @@ func (r Resource) LastBuild() model.BuildRecord {
 func (r Resource) DefaultCollapse() bool {
 	autoExpand := false
 	if k8sInfo, ok := r.ResourceInfo.(K8sResourceInfo); ok {
-		autoExpand = k8sInfo.PodRestarts > 0 || k8sInfo.PodStatus == "CrashLoopBackOff" || k8sInfo.PodStatus == "Error"
+		autoExpand = k8sInfo.PodRestarts > 0 || k8sInfo.PodStatus == "CrashLoopBackOff" || k8sInfo.PodStatus == "Error" ||
+			len(k8sInfo.WarningReasons) > 0
 	}
 
 	if r.IsDC() && r.DockerComposeTarget().RuntimeStatus() == v1alpha1.RuntimeStatusError {
@@ -228,7 +228,8 @@ func ToUIResource(mt *store.ManifestTarget, s store.EngineState) (*v1alpha1.UIRe
 	name := mt.Manifest.Name
 	r := &v1alpha1.UIResource{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name.String(),
+			Name:   name.String(),
+			Labels: mt.Manifest.Labels,
 		},
 		Status: v1alpha1.UIResourceStatus{
 			LastDeployTime:    lastDeploy,
@@ -240,6 +241,7 @@ func ToUIResource(mt *store.ManifestTarget, s store.EngineState) (*v1alpha1.UIRe
 			TriggerMode:       int32(mt.Manifest.TriggerMode),
 			HasPendingChanges: hasPendingChanges,
 			Queued:            s.ManifestInTriggerQueue(name),
+			Disabled:          s.IsResourceDisabled(name),
 		},
 	}
 
@@ -285,6 +287,10 @@ func populateResourceInfoView(mt *store.ManifestTarget, r *v1alpha1.UIResource)
 	r.Status.RuntimeStatus = v1alpha1.RuntimeStatusNotApplicable
 
 	if mt.Manifest.PodReadinessMode() == model.PodReadinessIgnore {
+		// Resources without pods can still have a Tilt-side readiness probe.
+		if mt.Manifest.K8sTarget().ReadinessProbe != nil {
+			r.Status.RuntimeStatus = mt.State.K8sRuntimeState().RuntimeStatus()
+		}
 		return nil
 	}
 
@@ -312,6 +318,7 @@ func populateResourceInfoView(mt *store.ManifestTarget, r *v1alpha1.UIResource)
 			AllContainersReady: store.AllPodContainersReady(pod),
 			PodRestarts:        kState.VisiblePodContainerRestarts(podID),
 			DisplayNames:       mt.Manifest.K8sTarget().DisplayNames,
+			WarningEvents:      toUIResourceKubernetesEvents(kState.RecentWarningEvents()),
 		}
 
 		r.Status.RuntimeStatus = v1alpha1.RuntimeStatus(kState.RuntimeStatus())
@@ -321,6 +328,26 @@ func populateResourceInfoView(mt *store.ManifestTarget, r *v1alpha1.UIResource)
 	panic("Unrecognized manifest type (not one of: k8s, DC, local)")
 }
 
+func toUIResourceKubernetesEvents(warnings []store.K8sWarningEvent) []v1alpha1.UIResourceKubernetesEvent {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	result := make([]v1alpha1.UIResourceKubernetesEvent, 0, len(warnings))
+	for _, w := range warnings {
+		result = append(result, v1alpha1.UIResourceKubernetesEvent{
+			ObjectKind:     w.Object.Kind,
+			ObjectName:     w.Object.Name,
+			Reason:         w.Reason,
+			Message:        w.Message,
+			Count:          w.Count,
+			FirstTimestamp: apis.NewTime(w.FirstTimestamp),
+			LastTimestamp:  apis.NewTime(w.LastTimestamp),
+		})
+	}
+	return result
+}
+
 func LogSegmentToEvent(seg *proto_webview.LogSegment, spans map[string]*proto_webview.LogSpan) store.LogAction {
 	span, ok := spans[seg.SpanId]
 	if !ok {
@@ -328,7 +355,13 @@ func LogSegmentToEvent(seg *proto_webview.LogSegment, spans map[string]*proto_we
 		return store.LogAction{}
 	}
 
-	// TODO(maia): actually get level (just spoofing for now)
-	spoofedLevel := logger.InfoLvl
-	return store.NewLogAction(model.ManifestName(span.ManifestName), logstore.SpanID(seg.SpanId), spoofedLevel, seg.Fields, []byte(seg.Text))
+	level := logger.LevelFromProtoID(int32(seg.Level))
+	action := store.NewLogAction(model.ManifestName(span.ManifestName), logstore.SpanID(seg.SpanId), level, seg.Fields, []byte(seg.Text))
+	if seg.Time != nil {
+		t, err := ptypes.Timestamp(seg.Time)
+		if err == nil {
+			action = action.WithTime(t)
+		}
+	}
+	return action
 }
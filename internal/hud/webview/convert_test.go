@@ -10,6 +10,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/internal/engine/configs"
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -161,6 +163,68 @@ func TestStateToViewK8sTargetsIncludeDisplayNames(t *testing.T) {
 	assert.Equal(t, r.K8sResourceInfo.DisplayNames, displayNames)
 }
 
+func TestStateToViewK8sWarningEvents(t *testing.T) {
+	m := model.Manifest{Name: "foo"}.WithDeployTarget(model.K8sTarget{})
+	state := newState([]model.Manifest{m})
+
+	t1 := time.Now().Add(-time.Minute).Truncate(time.Second)
+	t2 := t1.Add(30 * time.Second)
+	pod := v1.ObjectReference{Kind: "Pod", Name: "foo-abc123"}
+	krs := state.ManifestTargets["foo"].State.K8sRuntimeState()
+	krs.AddWarningEvent(&v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{UID: "event-1"},
+		InvolvedObject: pod,
+		Type:           v1.EventTypeWarning,
+		Reason:         "FailedMount",
+		Message:        `secret "foo-creds" not found`,
+		Count:          2,
+		FirstTimestamp: metav1.NewTime(t1),
+		LastTimestamp:  metav1.NewTime(t1),
+	})
+	krs.AddWarningEvent(&v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{UID: "event-2"},
+		InvolvedObject: pod,
+		Type:           v1.EventTypeWarning,
+		Reason:         "FailedMount",
+		Message:        "timed out waiting for the condition",
+		Count:          1,
+		FirstTimestamp: metav1.NewTime(t2),
+		LastTimestamp:  metav1.NewTime(t2),
+	})
+	state.ManifestTargets["foo"].State.RuntimeState = krs
+
+	v := completeProtoView(t, *state)
+	r, _ := findResource(m.Name, v)
+
+	require.Len(t, r.K8sResourceInfo.WarningEvents, 1)
+	w := r.K8sResourceInfo.WarningEvents[0]
+	assert.Equal(t, "Pod", w.ObjectKind)
+	assert.Equal(t, "foo-abc123", w.ObjectName)
+	assert.Equal(t, "FailedMount", w.Reason)
+	assert.Equal(t, "timed out waiting for the condition", w.Message)
+	assert.Equal(t, int32(3), w.Count)
+	timecmp.AssertTimeEqual(t, t1, w.FirstTimestamp)
+	timecmp.AssertTimeEqual(t, t2, w.LastTimestamp)
+}
+
+func TestStateToViewLabels(t *testing.T) {
+	m := model.Manifest{Name: "foo"}.
+		WithDeployTarget(model.K8sTarget{}).
+		WithLabels(map[string]string{"frontend": "frontend"})
+	state := newState([]model.Manifest{m})
+
+	resources, err := ToUIResourceList(*state)
+	require.NoError(t, err)
+
+	for _, r := range resources {
+		if r.Name == "foo" {
+			assert.Equal(t, map[string]string{"frontend": "frontend"}, r.Labels)
+		} else {
+			assert.Empty(t, r.Labels)
+		}
+	}
+}
+
 func TestStateToViewTiltfileLog(t *testing.T) {
 	es := newState([]model.Manifest{})
 	spanID := configs.SpanIDForLoadCount(1)
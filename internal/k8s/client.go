@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tilt-dev/localregistry-go"
 	"helm.sh/helm/v3/pkg/kube"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -78,6 +79,20 @@ type Client interface {
 	// than they were passed in) and with UUIDs from the Kube API
 	Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error)
 
+	// Updates the entities with server-side apply, creating them if necessary.
+	//
+	// Unlike Upsert, never deletes and re-creates a mutable entity to update it.
+	// If another field manager owns a field that Tilt wants to set, returns
+	// an ApplyConflictError.
+	ServerSideApply(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error)
+
+	// Shows what applying the entities would change in the cluster, as a unified diff
+	// of YAML. Returns an empty string if nothing would change.
+	//
+	// Uses a server-side dry run, so the diff includes any defaults and mutations
+	// that the server applies.
+	Diff(ctx context.Context, entities []K8sEntity) (string, error)
+
 	// Deletes all given entities.
 	//
 	// Currently ignores any "not found" errors, because that seems like the correct
@@ -100,6 +115,10 @@ type Client interface {
 	// Some clusters support a local image registry that we can push to.
 	LocalRegistry(ctx context.Context) container.Registry
 
+	// The local registry that the cluster advertises, as described in KEP-1755.
+	// Empty if the cluster doesn't advertise one.
+	LocalRegistryHosting(ctx context.Context) localregistry.LocalRegistryHostingV1
+
 	// Some clusters support a node IP where all servers are reachable.
 	NodeIP(ctx context.Context) NodeIP
 
@@ -359,8 +378,11 @@ func (k *K8sClient) helmResultToEntities(result *kube.Result) ([]K8sEntity, erro
 	for _, info := range result.Updated {
 		entities = append(entities, NewK8sEntity(info.Object))
 	}
+	return reparseEntities(entities)
+}
 
-	// Helm parses the results as unstructured info, but Tilt needs them parsed with the current
+func reparseEntities(entities []K8sEntity) ([]K8sEntity, error) {
+	// Helm and the dynamic client parse the results as unstructured info, but Tilt needs them parsed with the current
 	// API scheme. The easiest way to do this is to serialize them to yaml and re-parse again.
 	buf, err := SerializeSpecYAMLToBuffer(entities)
 	if err != nil {
@@ -523,6 +545,14 @@ func (k *K8sClient) Delete(ctx context.Context, entities []K8sEntity) error {
 }
 
 func (k *K8sClient) forceDiscovery(ctx context.Context, gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	rm, err := k.forceMapping(ctx, gvk)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return rm.Resource, nil
+}
+
+func (k *K8sClient) forceMapping(ctx context.Context, gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
 	rm, err := k.drm.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
 		// The REST mapper doesn't have any sort of internal invalidation
@@ -538,10 +568,10 @@ func (k *K8sClient) forceDiscovery(ctx context.Context, gvk schema.GroupVersionK
 
 		rm, err = k.drm.RESTMapping(gvk.GroupKind(), gvk.Version)
 		if err != nil {
-			return schema.GroupVersionResource{}, errors.Wrapf(err, "error mapping %s/%s", gvk.Group, gvk.Kind)
+			return nil, errors.Wrapf(err, "error mapping %s/%s", gvk.Group, gvk.Kind)
 		}
 	}
-	return rm.Resource, nil
+	return rm, nil
 }
 
 func (k *K8sClient) ListMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]ObjectMeta, error) {
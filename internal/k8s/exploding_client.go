@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/pkg/errors"
+	"github.com/tilt-dev/localregistry-go"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -25,6 +26,14 @@ func (ec *explodingClient) Upsert(ctx context.Context, entities []K8sEntity, tim
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
 
+func (ec *explodingClient) ServerSideApply(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
+func (ec *explodingClient) Diff(ctx context.Context, entities []K8sEntity) (string, error) {
+	return "", errors.Wrap(ec.err, "could not set up k8s client")
+}
+
 func (ec *explodingClient) Delete(ctx context.Context, entities []K8sEntity) error {
 	return errors.Wrap(ec.err, "could not set up k8s client")
 }
@@ -81,6 +90,10 @@ func (ec *explodingClient) LocalRegistry(ctx context.Context) container.Registry
 	return container.Registry{}
 }
 
+func (ec *explodingClient) LocalRegistryHosting(ctx context.Context) localregistry.LocalRegistryHostingV1 {
+	return localregistry.LocalRegistryHostingV1{}
+}
+
 func (ec *explodingClient) NodeIP(ctx context.Context) NodeIP {
 	return ""
 }
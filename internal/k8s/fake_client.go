@@ -13,6 +13,7 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"github.com/tilt-dev/localregistry-go"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -67,9 +68,17 @@ type FakeK8sClient struct {
 	LastUpsertResult []K8sEntity
 	UpsertTimeout    time.Duration
 
-	Runtime    container.Runtime
-	Registry   container.Registry
-	FakeNodeIP NodeIP
+	// Whether the last upsert used server-side apply.
+	LastUpsertServerSide bool
+
+	DiffOutput       string
+	DiffError        error
+	LastDiffEntities []K8sEntity
+
+	Runtime         container.Runtime
+	Registry        container.Registry
+	RegistryHosting localregistry.LocalRegistryHostingV1
+	FakeNodeIP      NodeIP
 
 	// entities are injected objects keyed by UID.
 	entities map[types.UID]K8sEntity
@@ -342,6 +351,18 @@ func (c *FakeK8sClient) TearDown() {
 func (c *FakeK8sClient) Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.LastUpsertServerSide = false
+	return c.upsert(entities, timeout)
+}
+
+func (c *FakeK8sClient) ServerSideApply(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.LastUpsertServerSide = true
+	return c.upsert(entities, timeout)
+}
+
+func (c *FakeK8sClient) upsert(entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
 
 	if c.UpsertError != nil {
 		return nil, c.UpsertError
@@ -368,6 +389,17 @@ func (c *FakeK8sClient) Upsert(ctx context.Context, entities []K8sEntity, timeou
 	return result, nil
 }
 
+func (c *FakeK8sClient) Diff(ctx context.Context, entities []K8sEntity) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.LastDiffEntities = entities
+	if c.DiffError != nil {
+		return "", c.DiffError
+	}
+	return c.DiffOutput, nil
+}
+
 func (c *FakeK8sClient) Delete(ctx context.Context, entities []K8sEntity) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -534,6 +566,13 @@ func (c *FakeK8sClient) LocalRegistry(ctx context.Context) container.Registry {
 	return c.Registry
 }
 
+func (c *FakeK8sClient) LocalRegistryHosting(ctx context.Context) localregistry.LocalRegistryHostingV1 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.RegistryHosting
+}
+
 func (c *FakeK8sClient) NodeIP(ctx context.Context) NodeIP {
 	c.mu.Lock()
 	defer c.mu.Unlock()
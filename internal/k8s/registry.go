@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 
 	"github.com/tilt-dev/localregistry-go"
@@ -48,6 +49,7 @@ type registryAsync struct {
 	core          apiv1.CoreV1Interface
 	runtimeSource RuntimeSource
 	registry      container.Registry
+	hosting       localregistry.LocalRegistryHostingV1
 	once          sync.Once
 }
 
@@ -145,29 +147,46 @@ func (r *registryAsync) inferRegistryFromNodeAnnotations(ctx context.Context) co
 }
 
 // Implements the local registry discovery standard.
-func (r *registryAsync) inferRegistryFromConfigMap(ctx context.Context) (registry container.Registry, help string) {
+//
+// https://github.com/kubernetes/enhancements/tree/master/keps/sig-cluster-lifecycle/generic/1755-communicating-a-local-registry
+func (r *registryAsync) inferRegistryFromConfigMap(ctx context.Context) (container.Registry, localregistry.LocalRegistryHostingV1) {
 	hosting, err := localregistry.Discover(ctx, r.core)
 	if err != nil {
 		logger.Get(ctx).Debugf("Local registry discovery error: %v", err)
-		return container.Registry{}, ""
+		return container.Registry{}, localregistry.LocalRegistryHostingV1{}
 	}
 
 	if hosting.Host == "" {
-		return container.Registry{}, hosting.Help
+		return container.Registry{}, hosting
 	}
 
-	registry, err = container.NewRegistryWithHostFromCluster(
+	// Images in Kubernetes YAML are pulled by the container runtime,
+	// so that's the host we use for image refs in the cluster.
+	registry, err := container.NewRegistryWithHostFromCluster(
 		hosting.Host, hosting.HostFromContainerRuntime)
 	if err != nil {
-		logger.Get(ctx).Debugf("Local registry discovery error: %v", err)
-		return container.Registry{}, hosting.Help
+		logger.Get(ctx).Warnf("Local registry advertised by the cluster failed to parse: %v\n"+
+			"Instructions: %s", err, hosting.Help)
+		return container.Registry{}, hosting
 	}
-	return registry, hosting.Help
+	return registry, hosting
 }
 
 func (r *registryAsync) Registry(ctx context.Context) container.Registry {
+	r.init(ctx)
+	return r.registry
+}
+
+func (r *registryAsync) Hosting(ctx context.Context) localregistry.LocalRegistryHostingV1 {
+	r.init(ctx)
+	return r.hosting
+}
+
+func (r *registryAsync) init(ctx context.Context) {
 	r.once.Do(func() {
-		reg, help := r.inferRegistryFromConfigMap(ctx)
+		reg, hosting := r.inferRegistryFromConfigMap(ctx)
+		r.hosting = hosting
+		help := hosting.Help
 		if !reg.Empty() {
 			r.registry = reg
 			return
@@ -199,9 +218,99 @@ func (r *registryAsync) Registry(ctx context.Context) container.Registry {
 			}
 		}
 	})
-	return r.registry
 }
 
 func (c K8sClient) LocalRegistry(ctx context.Context) container.Registry {
 	return c.registryAsync.Registry(ctx)
 }
+
+func (c K8sClient) LocalRegistryHosting(ctx context.Context) localregistry.LocalRegistryHostingV1 {
+	return c.registryAsync.Hosting(ctx)
+}
+
+// Step-by-step instructions for connecting a local registry to clusters
+// that don't have one, for clusters where we know how.
+//
+// Returns the empty string if we don't have instructions for this cluster.
+func LocalRegistrySetupInstructions(env Env, clusterName ClusterName) string {
+	switch env {
+	case EnvKIND6:
+		name := strings.TrimPrefix(string(clusterName), "kind-")
+		return fmt.Sprintf(`Tilt is copying every image into every node of your cluster with `+"`kind load`"+`, which is slow.
+A local registry lets Tilt push only the layers that changed. To set one up:
+
+1. Start a registry container:
+     docker run -d --restart=always -p 127.0.0.1:5000:5000 --name kind-registry registry:2
+
+2. Re-create the cluster so that its container runtime pulls from the registry:
+     kind delete cluster --name %[1]s
+     cat <<EOF | kind create cluster --name %[1]s --config=-
+     kind: Cluster
+     apiVersion: kind.x-k8s.io/v1alpha4
+     containerdConfigPatches:
+     - |-
+       [plugins."io.containerd.grpc.v1.cri".registry.mirrors."localhost:5000"]
+         endpoint = ["http://kind-registry:5000"]
+     EOF
+
+3. Connect the registry to the cluster's network:
+     docker network connect kind kind-registry
+
+4. Advertise the registry, so that Tilt and other tools can find it:
+%[2]s
+
+Or do all of this with one command:
+     ctlptl create cluster kind --name %[3]s --registry=ctlptl-registry
+
+More info: https://kind.sigs.k8s.io/docs/user/local-registry/`,
+			name, indent(registryConfigMapCommand("localhost:5000", "kind-registry:5000"), "     "), clusterName)
+
+	case EnvK3D:
+		name := strings.TrimPrefix(string(clusterName), "k3d-")
+		return fmt.Sprintf(`Tilt is importing every image into your cluster without a registry, which is slow.
+A local registry lets Tilt push only the layers that changed. To set one up:
+
+1. Create a registry:
+     k3d registry create registry.localhost --port 5000
+
+2. Re-create the cluster so that it pulls from the registry:
+     k3d cluster delete %[1]s
+     k3d cluster create %[1]s --registry-use k3d-registry.localhost:5000
+
+3. Check that k3d advertised the registry, so that Tilt and other tools can find it:
+     kubectl get configmap local-registry-hosting -n kube-public -o yaml
+   If it's missing (k3d before v4), create it yourself:
+%[2]s
+
+Or do all of this with one command:
+     ctlptl create cluster k3d --registry=ctlptl-registry
+
+More info: https://k3d.io/usage/guides/registries/`,
+			name, indent(registryConfigMapCommand("localhost:5000", "k3d-registry.localhost:5000"), "     "))
+	}
+	return ""
+}
+
+// A command that creates the ConfigMap described in KEP-1755.
+func registryConfigMapCommand(host, hostFromClusterNetwork string) string {
+	return fmt.Sprintf(`cat <<EOF | kubectl apply -f -
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  namespace: %s
+data:
+  %s: |
+    host: "%s"
+    hostFromClusterNetwork: "%s"
+EOF`, localregistry.ConfigMapName, localregistry.ConfigMapNamespace, localregistry.ConfigMapField,
+		host, hostFromClusterNetwork)
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -129,6 +129,7 @@ data:
   localRegistryHosting.v1: |
     host: "localhost:5000"
     hostFromContainerRuntime: "registry:5000"
+    hostFromClusterNetwork: "kind-registry:5000"
     help: "https://fake-domain.tilt.dev/local-registry-help"
 `)
 	require.NoError(t, err)
@@ -139,6 +140,23 @@ data:
 	registry := registryAsync.Registry(newLoggerCtx(os.Stdout))
 	assert.Equal(t, "localhost:5000", registry.Host)
 	assert.Equal(t, "registry:5000", registry.HostFromCluster())
+
+	hosting := registryAsync.Hosting(newLoggerCtx(os.Stdout))
+	assert.Equal(t, "kind-registry:5000", hosting.HostFromClusterNetwork)
+	assert.Equal(t, "https://fake-domain.tilt.dev/local-registry-help", hosting.Help)
+}
+
+func TestLocalRegistrySetupInstructions(t *testing.T) {
+	kind := LocalRegistrySetupInstructions(EnvKIND6, "kind-my-cluster")
+	assert.Contains(t, kind, "kind create cluster --name my-cluster")
+	assert.Contains(t, kind, "docker network connect kind kind-registry")
+	assert.Contains(t, kind, "name: local-registry-hosting")
+	assert.Contains(t, kind, `hostFromClusterNetwork: "kind-registry:5000"`)
+
+	k3d := LocalRegistrySetupInstructions(EnvK3D, "k3d-my-cluster")
+	assert.Contains(t, k3d, "k3d cluster create my-cluster --registry-use k3d-registry.localhost:5000")
+
+	assert.Equal(t, "", LocalRegistrySetupInstructions(EnvGKE, "gke_project_zone_cluster"))
 }
 
 func TestKINDWarning(t *testing.T) {
@@ -0,0 +1,231 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// The field manager that Tilt uses for server-side apply.
+const FieldManagerTilt = "tilt"
+
+// ApplyConflictError reports the fields that server-side apply couldn't
+// take ownership of, because other field managers own them.
+type ApplyConflictError struct {
+	// The display name of the object, e.g., "Deployment/my-app".
+	Object string
+
+	Conflicts []metav1.StatusCause
+}
+
+func (e ApplyConflictError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Server-side apply of %s conflicts with other field managers:\n", e.Object))
+	for _, c := range e.Conflicts {
+		sb.WriteString(fmt.Sprintf("  - %s: %s\n", c.Field, c.Message))
+	}
+	sb.WriteString("Remove the conflicting fields from the other manager, or take ownership of them with:\n")
+	sb.WriteString(fmt.Sprintf("  kubectl apply --server-side --field-manager=%s --force-conflicts", FieldManagerTilt))
+	return sb.String()
+}
+
+func IsApplyConflictError(err error) bool {
+	_, ok := errors.Cause(err).(ApplyConflictError)
+	return ok
+}
+
+func (k *K8sClient) ServerSideApply(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
+	result := make([]K8sEntity, 0, len(entities))
+
+	mutable, immutable := MutableAndImmutableEntities(entities)
+
+	for _, e := range mutable {
+		innerCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		newEntity, err := k.serverSideApplyEntity(innerCtx, e, false)
+		if err != nil {
+			if innerCtx.Err() == context.DeadlineExceeded {
+				return nil, timeoutError(timeout)
+			}
+			return nil, err
+		}
+		result = append(result, newEntity)
+	}
+
+	// Objects like Jobs and Pods can't be updated in-place at all,
+	// so we replace them the same way that Upsert does.
+	for _, e := range immutable {
+		innerCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		newEntities, err := k.forceReplaceEntity(innerCtx, e)
+		if err != nil {
+			if innerCtx.Err() == context.DeadlineExceeded {
+				return nil, timeoutError(timeout)
+			}
+			return nil, err
+		}
+		result = append(result, newEntities...)
+	}
+
+	return reparseEntities(result)
+}
+
+func (k *K8sClient) Diff(ctx context.Context, entities []K8sEntity) (string, error) {
+	var sb strings.Builder
+	for _, e := range entities {
+		rc, err := k.resourceClient(ctx, e)
+		if err != nil {
+			return "", errors.Wrap(err, "kubernetes diff")
+		}
+
+		liveYAML := ""
+		live, err := rc.Get(ctx, e.Name(), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", errors.Wrap(err, "kubernetes diff")
+		} else if err == nil {
+			liveYAML, err = yamlForDiff(live)
+			if err != nil {
+				return "", errors.Wrap(err, "kubernetes diff")
+			}
+		}
+
+		merged, err := k.serverSideApplyEntity(ctx, e, true)
+		if err != nil {
+			return "", err
+		}
+		mergedYAML, err := yamlForDiff(merged.Obj)
+		if err != nil {
+			return "", errors.Wrap(err, "kubernetes diff")
+		}
+
+		name := diffName(e)
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(liveYAML),
+			B:        difflib.SplitLines(mergedYAML),
+			FromFile: "live/" + name,
+			ToFile:   "merged/" + name,
+			Context:  3,
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "kubernetes diff")
+		}
+		sb.WriteString(diff)
+	}
+	return sb.String(), nil
+}
+
+// Applies a single entity with server-side apply.
+//
+// In dry-run mode, forces conflicts, so that the result shows what
+// Tilt's config would look like if it owned all its fields.
+func (k *K8sClient) serverSideApplyEntity(ctx context.Context, e K8sEntity, dryRun bool) (K8sEntity, error) {
+	rc, err := k.resourceClient(ctx, e)
+	if err != nil {
+		return K8sEntity{}, errors.Wrap(err, "kubernetes apply")
+	}
+
+	data, err := applyPatchJSON(e)
+	if err != nil {
+		return K8sEntity{}, errors.Wrap(err, "kubernetes apply")
+	}
+
+	force := dryRun
+	opts := metav1.PatchOptions{FieldManager: FieldManagerTilt, Force: &force}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	obj, err := rc.Patch(ctx, e.Name(), types.ApplyPatchType, data, opts)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			var causes []metav1.StatusCause
+			if status, ok := err.(apierrors.APIStatus); ok && status.Status().Details != nil {
+				causes = status.Status().Details.Causes
+			}
+			return K8sEntity{}, ApplyConflictError{
+				Object:    fmt.Sprintf("%s/%s", e.GVK().Kind, e.Name()),
+				Conflicts: causes,
+			}
+		}
+		if maybeImmutableFieldStderr(err.Error()) {
+			return K8sEntity{}, errors.Wrapf(err,
+				"kubernetes apply: %s/%s has changes to immutable fields. "+
+					"Server-side apply never deletes objects to update them. To re-create it, delete it first",
+				e.GVK().Kind, e.Name())
+		}
+		return K8sEntity{}, errors.Wrap(err, "kubernetes apply")
+	}
+	return NewK8sEntity(obj), nil
+}
+
+// Returns a dynamic client for the entity's type, in the entity's namespace.
+func (k *K8sClient) resourceClient(ctx context.Context, e K8sEntity) (dynamic.ResourceInterface, error) {
+	rm, err := k.forceMapping(ctx, e.GVK())
+	if err != nil {
+		return nil, err
+	}
+
+	if rm.Scope.Name() == meta.RESTScopeNameNamespace {
+		ns := e.NamespaceOrDefault(k.configNamespace.String())
+		return k.dynamic.Resource(rm.Resource).Namespace(ns), nil
+	}
+	return k.dynamic.Resource(rm.Resource), nil
+}
+
+// Server-side apply expects the full object config, including its type.
+func applyPatchJSON(e K8sEntity) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(e.Obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(e.GVK())
+
+	// Typed objects serialize these as empty values, which would claim
+	// ownership of fields that Tilt doesn't care about.
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return u.MarshalJSON()
+}
+
+// Serializes an object for diffing, without the fields that the server
+// changes on every write.
+func yamlForDiff(obj runtime.Object) (string, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", err
+	}
+
+	for _, field := range []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp", "selfLink"} {
+		unstructured.RemoveNestedField(content, "metadata", field)
+	}
+	unstructured.RemoveNestedField(content, "status")
+
+	out, err := yaml.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func diffName(e K8sEntity) string {
+	ns := e.meta().GetNamespace()
+	if ns == "" {
+		return fmt.Sprintf("%s/%s", e.GVK().Kind, e.Name())
+	}
+	return fmt.Sprintf("%s/%s/%s", e.GVK().Kind, ns, e.Name())
+}
@@ -0,0 +1,151 @@
+package k8s
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+)
+
+func TestServerSideApply(t *testing.T) {
+	f := newClientTestFixture(t)
+	patches := f.reactToApplyPatches(nil)
+
+	sancho := MustParseYAMLFromString(t, testyaml.SanchoYAML)
+	result, err := f.client.ServerSideApply(f.ctx, sancho, time.Minute)
+	require.NoError(t, err)
+
+	require.Len(t, *patches, 1)
+	patch := (*patches)[0]
+	assert.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+	assert.Equal(t, "sancho", patch.GetName())
+	assert.Equal(t, "default", patch.GetNamespace())
+	assert.Contains(t, string(patch.GetPatch()), `"kind":"Deployment"`)
+	assert.NotContains(t, string(patch.GetPatch()), `"status"`)
+
+	require.Len(t, result, 1)
+	assert.Equal(t, "sancho", result[0].Name())
+	assert.Equal(t, types.UID("fake-uid"), result[0].UID())
+
+	// Server-side apply never goes through the client-side apply path.
+	assert.Len(t, f.helmKube.updates, 0)
+}
+
+func TestServerSideApplyConflict(t *testing.T) {
+	f := newClientTestFixture(t)
+	f.reactToApplyPatches(apierrors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kubectl-edit" using apps/v1`,
+			Field:   ".spec.replicas",
+		},
+	}, "Apply failed with 1 conflict"))
+
+	sancho := MustParseYAMLFromString(t, testyaml.SanchoYAML)
+	_, err := f.client.ServerSideApply(f.ctx, sancho, time.Minute)
+	require.Error(t, err)
+	assert.True(t, IsApplyConflictError(err))
+	assert.Contains(t, err.Error(), "Server-side apply of Deployment/sancho conflicts with other field managers")
+	assert.Contains(t, err.Error(), `- .spec.replicas: conflict with "kubectl-edit" using apps/v1`)
+	assert.Contains(t, err.Error(), "--force-conflicts")
+}
+
+func TestServerSideApplyImmutableFieldDoesNotRecreate(t *testing.T) {
+	f := newClientTestFixture(t)
+	f.reactToApplyPatches(fmt.Errorf(`StatefulSet.apps "postgres" is invalid: spec: Forbidden: updates to statefulset spec for fields other than 'replicas', 'template', and 'updateStrategy' are forbidden`))
+
+	postgres := MustParseYAMLFromString(t, testyaml.PostgresYAML)
+	_, err := f.client.ServerSideApply(f.ctx, postgres, time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has changes to immutable fields")
+
+	assert.Len(t, f.helmKube.deletes, 0)
+	assert.Len(t, f.helmKube.creates, 0)
+}
+
+func TestServerSideApplyReplacesImmutableKinds(t *testing.T) {
+	f := newClientTestFixture(t)
+	patches := f.reactToApplyPatches(nil)
+
+	eDeploy := MustParseYAMLFromString(t, testyaml.SanchoYAML)[0]
+	eJob := MustParseYAMLFromString(t, testyaml.JobYAML)[0]
+	_, err := f.client.ServerSideApply(f.ctx, []K8sEntity{eDeploy, eJob}, time.Minute)
+	require.NoError(t, err)
+
+	assert.Len(t, *patches, 1)
+	require.Len(t, f.helmKube.creates, 1)
+	assert.Equal(t, eJob, NewK8sEntity(f.helmKube.creates[0].Object))
+}
+
+func TestDiffNewObject(t *testing.T) {
+	f := newClientTestFixture(t)
+	f.reactToApplyPatches(nil)
+
+	sancho := MustParseYAMLFromString(t, testyaml.SanchoYAML)
+	diff, err := f.client.Diff(f.ctx, sancho)
+	require.NoError(t, err)
+
+	assert.Contains(t, diff, "--- live/Deployment/sancho\n")
+	assert.Contains(t, diff, "+++ merged/Deployment/sancho\n")
+	assert.Contains(t, diff, "+  name: sancho\n")
+	assert.NotContains(t, diff, "uid")
+}
+
+func TestDiffUnchangedObject(t *testing.T) {
+	f := newClientTestFixture(t)
+	f.reactToApplyPatches(nil)
+
+	sancho := MustParseYAMLFromString(t, testyaml.SanchoYAML)
+	live, err := applyPatchJSON(sancho[0])
+	require.NoError(t, err)
+	f.dynamic().PrependReactor("get", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		obj := &unstructured.Unstructured{}
+		err := obj.UnmarshalJSON(live)
+		if err != nil {
+			return true, nil, err
+		}
+		obj.SetUID("live-uid")
+		obj.SetResourceVersion("123")
+		return true, obj, nil
+	})
+
+	diff, err := f.client.Diff(f.ctx, sancho)
+	require.NoError(t, err)
+	assert.Equal(t, "", diff)
+}
+
+func (f *clientTestFixture) dynamic() *dynfake.FakeDynamicClient {
+	return f.client.dynamic.(*dynfake.FakeDynamicClient)
+}
+
+// Responds to server-side apply patches with the applied object, or with the given error.
+func (f *clientTestFixture) reactToApplyPatches(err error) *[]ktesting.PatchAction {
+	patches := []ktesting.PatchAction{}
+	f.dynamic().PrependReactor("patch", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+		patch := action.(ktesting.PatchAction)
+		patches = append(patches, patch)
+		if err != nil {
+			return true, nil, err
+		}
+
+		obj := &unstructured.Unstructured{}
+		err := obj.UnmarshalJSON(patch.GetPatch())
+		if err != nil {
+			return true, nil, err
+		}
+		obj.SetUID("fake-uid")
+		return true, obj, nil
+	})
+	return &patches
+}
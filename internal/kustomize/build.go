@@ -0,0 +1,207 @@
+package kustomize
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/tilt-dev/tilt/internal/ospath"
+)
+
+const defaultHelmCommand = "helm"
+
+// ParseFlags converts `kustomize build` flags into kustomizer options.
+//
+// Defaults match the kustomize CLI, so that kustomize() renders the same
+// YAML as `kustomize build`.
+func ParseFlags(flags []string) (*krusty.Options, error) {
+	fs := pflag.NewFlagSet("kustomize", pflag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	// kustomize has used both dashes and underscores in flag names over time.
+	fs.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		return pflag.NormalizedName(strings.ReplaceAll(name, "_", "-"))
+	})
+
+	loadRestrictor := fs.String("load-restrictor", types.LoadRestrictionsRootOnly.String(), "")
+	reorder := fs.String("reorder", "legacy", "")
+	enableAlphaPlugins := fs.Bool("enable-alpha-plugins", false, "")
+	enableHelm := fs.Bool("enable-helm", false, "")
+	helmCommand := fs.String("helm-command", defaultHelmCommand, "")
+	enableManagedByLabel := fs.Bool("enable-managedby-label", false, "")
+
+	err := fs.Parse(flags)
+	if err != nil {
+		return nil, fmt.Errorf("kustomize flags: %v", err)
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("kustomize flags: unexpected arguments: %v", fs.Args())
+	}
+
+	opts := krusty.MakeDefaultOptions()
+	opts.AddManagedbyLabel = *enableManagedByLabel
+
+	switch *loadRestrictor {
+	case types.LoadRestrictionsRootOnly.String():
+		opts.LoadRestrictions = types.LoadRestrictionsRootOnly
+	case types.LoadRestrictionsNone.String():
+		opts.LoadRestrictions = types.LoadRestrictionsNone
+	default:
+		return nil, fmt.Errorf("kustomize flags: --load-restrictor must be one of %s, %s",
+			types.LoadRestrictionsRootOnly, types.LoadRestrictionsNone)
+	}
+
+	switch *reorder {
+	case "legacy":
+		opts.DoLegacyResourceSort = true
+	case "none":
+		opts.DoLegacyResourceSort = false
+	default:
+		return nil, fmt.Errorf("kustomize flags: --reorder must be one of legacy, none")
+	}
+
+	if *enableAlphaPlugins {
+		opts.PluginConfig = types.EnabledPluginConfig(types.BploUseStaticallyLinked)
+		opts.PluginConfig.HelmConfig.Enabled = false
+	}
+	if *enableHelm {
+		opts.PluginConfig.HelmConfig.Enabled = true
+	}
+	opts.PluginConfig.HelmConfig.Command = *helmCommand
+
+	return opts, nil
+}
+
+// Build renders the kustomization in dir, like `kustomize build`.
+//
+// Also returns every file that kustomize read along the way
+// (kustomization files, resources, patches, generator inputs, etc),
+// even if the build fails, so that the caller can watch them.
+//
+// kustomize clones remote bases into temp dirs that it deletes when it's done.
+// If cacheDir is set, we keep a copy of each clone there and return the files
+// it read from the copy. Otherwise, files from remote bases are not returned.
+func Build(dir string, flags []string, cacheDir string) (string, []string, error) {
+	opts, err := ParseFlags(flags)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fs := newRecordingFS(filesys.MakeFsOnDisk(), cacheDir)
+	m, err := krusty.MakeKustomizer(opts).Run(fs, dir)
+	deps := fs.existingPaths()
+	if err != nil {
+		return "", deps, err
+	}
+
+	yaml, err := m.AsYaml()
+	if err != nil {
+		return "", deps, err
+	}
+	return string(yaml), deps, nil
+}
+
+// Wraps a filesystem to record which files get read.
+type recordingFS struct {
+	filesys.FileSystem
+	cacheDir string
+
+	mu     sync.Mutex
+	paths  map[string]bool
+	clones map[string]string // temp clone dir -> cached copy
+}
+
+func newRecordingFS(fs filesys.FileSystem, cacheDir string) *recordingFS {
+	return &recordingFS{
+		FileSystem: fs,
+		cacheDir:   cacheDir,
+		paths:      make(map[string]bool),
+		clones:     make(map[string]string),
+	}
+}
+
+func (fs *recordingFS) record(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.paths[abs] = true
+}
+
+func (fs *recordingFS) ReadFile(path string) ([]byte, error) {
+	fs.record(path)
+	return fs.FileSystem.ReadFile(path)
+}
+
+func (fs *recordingFS) Open(path string) (filesys.File, error) {
+	fs.record(path)
+	return fs.FileSystem.Open(path)
+}
+
+// kustomize removes the clone of a remote base once it has loaded the base.
+// Copy the clone into the cache first, so that there's something to watch.
+func (fs *recordingFS) RemoveAll(path string) error {
+	if fs.cacheDir != "" && isTempPath(path) {
+		cached, err := cacheClone(fs.cacheDir, path)
+		if err == nil {
+			fs.mu.Lock()
+			fs.clones[path] = cached
+			fs.mu.Unlock()
+		}
+	}
+	return fs.FileSystem.RemoveAll(path)
+}
+
+func (fs *recordingFS) existingPaths() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	result := make([]string, 0, len(fs.paths))
+	for p := range fs.paths {
+		p = fs.cachedPath(p)
+		_, err := os.Stat(p)
+		if os.IsNotExist(err) && isTempPath(p) {
+			continue
+		}
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// If the path is in a clone of a remote base, returns the path
+// in our copy of the clone.
+func (fs *recordingFS) cachedPath(p string) string {
+	for clone, cached := range fs.clones {
+		rel, ok := ospath.Child(clone, p)
+		if ok {
+			return filepath.Join(cached, rel)
+		}
+	}
+	return p
+}
+
+// Whether the path is in the system temp dir.
+//
+// kustomize resolves symlinks in the temp dirs it creates (on macOS, /var
+// is a symlink to /private/var), so check the resolved temp dir too.
+func isTempPath(p string) bool {
+	tmp := os.TempDir()
+	if ospath.IsChild(tmp, p) {
+		return true
+	}
+	resolved, err := filepath.EvalSymlinks(tmp)
+	return err == nil && ospath.IsChild(resolved, p)
+}
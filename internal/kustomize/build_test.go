@@ -0,0 +1,440 @@
+package kustomize
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/types"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+const deploymentYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  template:
+    spec:
+      containers:
+      - name: hello
+        image: hello:v1
+`
+
+const serviceYAML = `apiVersion: v1
+kind: Service
+metadata:
+  name: hello
+spec:
+  ports:
+  - port: 80
+`
+
+const configMapYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello-config
+data:
+  greeting: hello
+`
+
+func TestNoFile(t *testing.T) {
+	f := newKustomizeFixture(t)
+
+	f.assertErrorContains("unable to find one of 'kustomization.yaml', 'kustomization.yml' or 'Kustomization' in directory")
+}
+
+func TestTooManyFiles(t *testing.T) {
+	f := newKustomizeFixture(t)
+	f.tempdir.WriteFile("kustomization.yml", "")
+	f.tempdir.WriteFile("kustomization.yaml", "")
+
+	f.assertErrorContains("Found multiple kustomization files under")
+}
+
+func TestEmpty(t *testing.T) {
+	f := newKustomizeFixture(t)
+	kustomizeFile := ""
+	f.writeRootKustomize(kustomizeFile)
+
+	expected := []string{"kustomization.yaml"}
+
+	f.assertDeps(expected)
+}
+
+func TestSimple(t *testing.T) {
+	f := newKustomizeFixture(t)
+	kustomizeFile := `# Example configuration for the webserver
+# at https://github.com/monopole/hello
+commonLabels:
+  app: my-hello
+
+resources:
+  - deployment.yaml
+  - service.yaml
+  - configMap.yaml`
+	f.writeRootKustomize(kustomizeFile)
+	f.tempdir.WriteFile("deployment.yaml", deploymentYAML)
+	f.tempdir.WriteFile("service.yaml", serviceYAML)
+	f.tempdir.WriteFile("configMap.yaml", configMapYAML)
+
+	expected := []string{"kustomization.yaml", "deployment.yaml", "service.yaml", "configMap.yaml"}
+	f.assertDeps(expected)
+
+	yaml := f.build()
+	assert.Contains(t, yaml, "app: my-hello")
+	assert.Contains(t, yaml, "kind: Deployment")
+}
+
+func TestComplex(t *testing.T) {
+	f := newKustomizeFixture(t)
+	kustomizeFile := `
+# declare ConfigMap as a resource
+resources:
+- configmap.yaml
+- ingress.yaml
+
+# declare ConfigMap from a ConfigMapGenerator
+configMapGenerator:
+- name: a-configmap
+  files:
+    - configs/configfile
+    - configs/another_configfile
+
+patchesJson6902:
+  - target:
+      group: networking.k8s.io
+      version: v1
+      kind: Ingress
+      name: my-ingress
+    path: ingress_patch.yaml`
+	f.writeRootKustomize(kustomizeFile)
+	f.tempdir.WriteFile("configmap.yaml", configMapYAML)
+	f.tempdir.WriteFile("ingress.yaml", `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: my-ingress
+spec:
+  rules:
+  - host: example.com
+`)
+	f.tempdir.WriteFile("ingress_patch.yaml", `- op: replace
+  path: /spec/rules/0/host
+  value: tilt.dev
+`)
+	f.tempdir.WriteFile("configs/configfile", "foo")
+	f.tempdir.WriteFile("configs/another_configfile", "bar")
+
+	expected := []string{"kustomization.yaml", "configmap.yaml", "ingress.yaml", "ingress_patch.yaml", "configs/configfile", "configs/another_configfile"}
+	f.assertDeps(expected)
+
+	yaml := f.build()
+	assert.Contains(t, yaml, "host: tilt.dev")
+	assert.Contains(t, yaml, "name: a-configmap-")
+}
+
+func TestRecursive(t *testing.T) {
+	f := newKustomizeFixture(t)
+
+	// these used to be only specified under "bases", but now that's deprecated and "resources"
+	// that purpose. for now, test both.
+	// https://github.com/tilt-dev/tilt/blob/15d0c94ccc08230d3a528b14cb0a3455b947d13c/vendor/sigs.k8s.io/kustomize/api/types/kustomization.go#L102
+	kustomize := `bases:
+- ./dev
+resources:
+- ./staging
+- ./production
+
+namePrefix: cluster-a-`
+
+	f.writeRootKustomize(kustomize)
+
+	base := `resources:
+- pod.yaml`
+	f.writeBaseKustomize("base", base)
+	basePod := `apiVersion: v1
+kind: Pod
+metadata:
+  name: myapp-pod
+  labels:
+    app: myapp
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.7.9`
+	f.writeBaseFile("base", "pod.yaml", basePod)
+
+	dev := `bases:
+- ./../base
+namePrefix: dev-`
+	f.writeBaseKustomize("dev", dev)
+
+	staging := `bases:
+- ./../base
+namePrefix: stag-`
+	f.writeBaseKustomize("staging", staging)
+
+	production := `bases:
+- ./../base
+namePrefix: prod-`
+	f.writeBaseKustomize("production", production)
+
+	expected := []string{
+		"base/kustomization.yaml",
+		"base/pod.yaml",
+		"dev/kustomization.yaml",
+		"staging/kustomization.yaml",
+		"production/kustomization.yaml",
+		"kustomization.yaml",
+	}
+	f.assertDeps(expected)
+
+	yaml := f.build()
+	assert.Contains(t, yaml, "name: cluster-a-dev-myapp-pod")
+	assert.Contains(t, yaml, "name: cluster-a-stag-myapp-pod")
+	assert.Contains(t, yaml, "name: cluster-a-prod-myapp-pod")
+}
+
+// Overlays outside the kustomization root are watched too.
+func TestOverlayOutsideRoot(t *testing.T) {
+	f := newKustomizeFixture(t)
+
+	f.writeBaseKustomize("base", `resources:
+- deployment.yaml`)
+	f.writeBaseFile("base", "deployment.yaml", deploymentYAML)
+	f.writeBaseKustomize("overlays/dev", `resources:
+- ../../base
+patchesStrategicMerge:
+- replicas.yaml`)
+	f.writeBaseFile("overlays/dev", "replicas.yaml", `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  replicas: 3
+`)
+
+	yaml, deps, err := Build(f.tempdir.JoinPath("overlays", "dev"), nil, "")
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "replicas: 3")
+	assert.ElementsMatch(t, f.tempdir.JoinPaths([]string{
+		"base/kustomization.yaml",
+		"base/deployment.yaml",
+		"overlays/dev/kustomization.yaml",
+		"overlays/dev/replicas.yaml",
+	}), deps)
+}
+
+// patches was deprecated and then re-added with a different meaning
+// https://github.com/tilt-dev/tilt/issues/4081
+func TestPatches(t *testing.T) {
+	f := newKustomizeFixture(t)
+	kustomizeFile := `# Example configuration for the webserver
+# at https://github.com/monopole/hello
+commonLabels:
+  app: my-hello
+
+resources:
+  - deployment.yaml
+  - service.yaml
+  - configMap.yaml
+
+patches:
+  - path: patch.yaml
+    target:
+      kind: Deployment
+      name: hello
+`
+	f.writeRootKustomize(kustomizeFile)
+	f.tempdir.WriteFile("deployment.yaml", deploymentYAML)
+	f.tempdir.WriteFile("service.yaml", serviceYAML)
+	f.tempdir.WriteFile("configMap.yaml", configMapYAML)
+	f.tempdir.WriteFile("patch.yaml", `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  replicas: 2
+`)
+
+	expected := []string{"kustomization.yaml", "deployment.yaml", "service.yaml", "configMap.yaml", "patch.yaml"}
+	f.assertDeps(expected)
+	assert.Contains(t, f.build(), "replicas: 2")
+}
+
+// Even if the build fails, we want to watch the files we read,
+// so that fixing them triggers a rebuild.
+func TestDepsOnError(t *testing.T) {
+	f := newKustomizeFixture(t)
+	f.writeRootKustomize(`resources:
+- deployment.yaml`)
+	f.tempdir.WriteFile("deployment.yaml", "this is not yaml: [")
+
+	_, deps, err := Build(f.tempdir.Path(), nil, "")
+	require.Error(t, err)
+	assert.ElementsMatch(t, f.tempdir.JoinPaths([]string{"kustomization.yaml", "deployment.yaml"}), deps)
+}
+
+// kustomize removes its clones of remote bases when it's done with them.
+// We keep a copy in the cache, and watch the files there instead.
+func TestRemoteBaseCached(t *testing.T) {
+	f := newKustomizeFixture(t)
+	clone := f.tempdir.JoinPath("clone")
+	f.tempdir.WriteFile(filepath.Join("clone", "base", "kustomization.yaml"), "resources:\n- deployment.yaml\n")
+	f.tempdir.WriteFile(filepath.Join("clone", "base", "deployment.yaml"), deploymentYAML)
+	for _, args := range [][]string{
+		{"init"},
+		{"remote", "add", "origin", "https://github.com/tilt-dev/remote-base.git"},
+		{"add", "."},
+		{"-c", "user.name=tilt", "-c", "user.email=tilt@example.com", "commit", "-m", "base"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = clone
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	cacheDir := f.tempdir.JoinPath("cache")
+	fs := newRecordingFS(filesys.MakeFsOnDisk(), cacheDir)
+	_, err := fs.ReadFile(filepath.Join(clone, "base", "deployment.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, fs.RemoveAll(clone))
+
+	assert.NoDirExists(t, clone)
+	deps := fs.existingPaths()
+	require.Len(t, deps, 1)
+
+	// The copy is named for the origin and commit.
+	cached := filepath.Dir(filepath.Dir(deps[0]))
+	assert.Equal(t, filepath.Join(cacheDir, "github.com_tilt-dev_remote-base"), filepath.Dir(cached))
+	assert.Equal(t, filepath.Join(cached, "base", "deployment.yaml"), deps[0])
+	assert.NoDirExists(t, filepath.Join(cached, ".git"))
+
+	contents, err := ioutil.ReadFile(deps[0])
+	require.NoError(t, err)
+	assert.Equal(t, deploymentYAML, string(contents))
+}
+
+// On macOS, the temp dir is behind a symlink that kustomize resolves.
+func TestIsTempPathResolvesSymlinks(t *testing.T) {
+	f := newKustomizeFixture(t)
+	real := f.tempdir.JoinPath("real")
+	require.NoError(t, os.MkdirAll(real, 0755))
+	link := f.tempdir.JoinPath("link")
+	require.NoError(t, os.Symlink(real, link))
+
+	oldTmpDir, hadTmpDir := os.LookupEnv("TMPDIR")
+	require.NoError(t, os.Setenv("TMPDIR", link))
+	defer func() {
+		if hadTmpDir {
+			_ = os.Setenv("TMPDIR", oldTmpDir)
+		} else {
+			_ = os.Unsetenv("TMPDIR")
+		}
+	}()
+
+	assert.True(t, isTempPath(filepath.Join(link, "kustomize-123", "kustomization.yaml")))
+	assert.True(t, isTempPath(filepath.Join(real, "kustomize-123", "kustomization.yaml")))
+	assert.False(t, isTempPath(f.tempdir.JoinPath("kustomization.yaml")))
+}
+
+func TestParseFlags(t *testing.T) {
+	opts, err := ParseFlags(nil)
+	require.NoError(t, err)
+	assert.True(t, opts.DoLegacyResourceSort)
+	assert.Equal(t, types.LoadRestrictionsRootOnly, opts.LoadRestrictions)
+	assert.False(t, opts.PluginConfig.HelmConfig.Enabled)
+
+	opts, err = ParseFlags([]string{
+		"--reorder=none",
+		"--load_restrictor", "LoadRestrictionsNone",
+		"--enable-helm",
+		"--helm-command=helm3",
+	})
+	require.NoError(t, err)
+	assert.False(t, opts.DoLegacyResourceSort)
+	assert.Equal(t, types.LoadRestrictionsNone, opts.LoadRestrictions)
+	assert.True(t, opts.PluginConfig.HelmConfig.Enabled)
+	assert.Equal(t, "helm3", opts.PluginConfig.HelmConfig.Command)
+
+	_, err = ParseFlags([]string{"--reorder=sideways"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--reorder must be one of legacy, none")
+
+	_, err = ParseFlags([]string{"--output=foo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown flag: --output")
+}
+
+func TestLoadRestrictorFlag(t *testing.T) {
+	f := newKustomizeFixture(t)
+	f.writeBaseKustomize("app", `resources:
+- ../shared/configMap.yaml`)
+	f.writeBaseFile("shared", "configMap.yaml", configMapYAML)
+
+	_, _, err := Build(f.tempdir.JoinPath("app"), nil, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "security; file")
+
+	yaml, _, err := Build(f.tempdir.JoinPath("app"), []string{"--load-restrictor=LoadRestrictionsNone"}, "")
+	require.NoError(t, err)
+	assert.Contains(t, yaml, "name: hello-config")
+}
+
+type kustomizeFixture struct {
+	t       *testing.T
+	tempdir *tempdir.TempDirFixture
+}
+
+func newKustomizeFixture(t *testing.T) *kustomizeFixture {
+
+	return &kustomizeFixture{
+		t:       t,
+		tempdir: tempdir.NewTempDirFixture(t),
+	}
+}
+
+func (f *kustomizeFixture) writeRootKustomize(contents string) {
+	f.tempdir.WriteFile("kustomization.yaml", contents)
+}
+
+func (f *kustomizeFixture) writeBaseKustomize(pathToContainingDirectory, contents string) {
+	f.tempdir.WriteFile(filepath.Join(pathToContainingDirectory, "kustomization.yaml"), contents)
+}
+
+func (f *kustomizeFixture) writeBaseFile(pathToContainingDirectory, name, contents string) {
+	f.tempdir.WriteFile(filepath.Join(pathToContainingDirectory, name), contents)
+}
+
+func (f *kustomizeFixture) build() string {
+	yaml, _, err := Build(f.tempdir.Path(), nil, "")
+	require.NoError(f.t, err)
+	return yaml
+}
+
+func (f *kustomizeFixture) assertDeps(expected []string) {
+	fullExpected := f.tempdir.JoinPaths(expected)
+
+	_, actual, err := Build(f.tempdir.Path(), nil, "")
+	require.NoError(f.t, err)
+
+	require.ElementsMatch(f.t, fullExpected, actual)
+}
+
+func (f *kustomizeFixture) assertErrorContains(expected string) {
+	_, _, err := Build(f.tempdir.Path(), nil, "")
+	if err == nil {
+		f.t.Fatal("Expected an error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), expected) {
+		f.t.Errorf("Expected %s to contain %s", err.Error(), expected)
+	}
+}
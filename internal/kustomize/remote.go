@@ -0,0 +1,150 @@
+package kustomize
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+)
+
+const remoteBaseCacheDirName = "kustomize"
+
+var unsafeCacheDirChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Returns the directory under the Tilt dev dir (usually ~/.tilt-dev)
+// where we keep copies of remote bases.
+func TiltDevRemoteBaseCacheDir() (string, error) {
+	dir, err := dirs.GetTiltDevDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, remoteBaseCacheDirName), nil
+}
+
+func cacheDirName(s string) string {
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git@"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.TrimSuffix(s, ".git")
+	return strings.Trim(unsafeCacheDirChars.ReplaceAllString(s, "_"), "_")
+}
+
+// Copies a git clone of a remote base into the cache, under a directory
+// named for its origin and commit, and returns that directory.
+//
+// A commit never changes, so if we already have a copy, we keep it.
+func cacheClone(cacheDir, cloneDir string) (string, error) {
+	origin, err := gitOutput(cloneDir, "remote", "get-url", "origin")
+	if err != nil {
+		return "", err
+	}
+	commit, err := gitOutput(cloneDir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	dest := filepath.Join(cacheDir, cacheDirName(origin), cacheDirName(commit))
+	if isDir(dest) {
+		return dest, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(dest), os.FileMode(0755))
+	if err != nil {
+		return "", err
+	}
+
+	// Copy to a temp dir and rename, so that an interrupted copy
+	// doesn't leave a partial base in the cache.
+	tmp, err := ioutil.TempDir(filepath.Dir(dest), filepath.Base(dest)+".tmp")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmp)
+	}()
+
+	err = copyTree(cloneDir, tmp)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Rename(tmp, dest)
+	if err != nil && !isDir(dest) {
+		return "", err
+	}
+	return dest, nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Copies the files in src to dest, leaving out git metadata.
+func copyTree(src, dest string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		target := filepath.Join(dest, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+func copyFile(src, dest string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
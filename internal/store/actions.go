@@ -61,6 +61,11 @@ func (le LogAction) SpanID() logstore.SpanID {
 	return le.spanID
 }
 
+func (le LogAction) WithTime(t time.Time) LogAction {
+	le.timestamp = t
+	return le
+}
+
 func (le LogAction) String() string {
 	return fmt.Sprintf("manifest: %s, spanID: %s, msg: %q", le.mn, le.spanID, le.msg)
 }
@@ -87,8 +87,17 @@ type EngineState struct {
 	Tiltignore    model.Dockerignore
 	WatchSettings model.WatchSettings
 
+	// The repos declared with extension_repo() in the Tiltfile.
+	ExtensionRepos []*v1alpha1.ExtensionRepo `json:"-"`
+
 	TriggerQueue []model.ManifestName
 
+	// Resources that the user turned off with `tilt disable`.
+	//
+	// Keyed by name rather than stored on the ManifestState,
+	// so that the setting survives Tiltfile reloads.
+	DisabledResources map[model.ManifestName]bool
+
 	IsProfiling bool
 
 	TiltfileState *ManifestState
@@ -104,7 +113,8 @@ type EngineState struct {
 
 	Features map[string]bool
 
-	Secrets model.SecretSet
+	// Keyed by the secret values, so never serialized.
+	Secrets model.SecretSet `json:"-"`
 
 	CloudAddress string
 	Token        token.Token
@@ -121,6 +131,9 @@ type EngineState struct {
 
 	UserConfigState model.UserConfigState
 
+	// The settings that the Tiltfile defined with config.define_*.
+	UserConfigSettings []model.UserConfigSetting
+
 	// API-server-based data models. Stored in EngineState
 	// to assist in migration.
 	Cmds                  map[string]*Cmd                               `json:"-"`
@@ -310,7 +323,7 @@ func (e *EngineState) TiltfileInTriggerQueue() bool {
 
 func (e *EngineState) AppendToTriggerQueue(mn model.ManifestName, reason model.BuildReason) {
 	ms, ok := e.ManifestState(mn)
-	if !ok {
+	if !ok || e.IsResourceDisabled(mn) {
 		return
 	}
 
@@ -342,6 +355,33 @@ func (e *EngineState) RemoveFromTriggerQueue(mn model.ManifestName) {
 	}
 }
 
+func (e EngineState) IsResourceDisabled(mn model.ManifestName) bool {
+	return e.DisabledResources[mn]
+}
+
+// Turns off a resource. Its builds are held, and a subscriber tears down
+// whatever it deployed.
+func (e *EngineState) DisableResource(mn model.ManifestName) {
+	e.DisabledResources[mn] = true
+	e.RemoveFromTriggerQueue(mn)
+}
+
+// Turns a resource back on.
+//
+// Everything we knew about the resource's builds and runtime is stale
+// after a teardown, so we start it over as if it had just been loaded.
+func (e *EngineState) EnableResource(mn model.ManifestName) {
+	if !e.DisabledResources[mn] {
+		return
+	}
+	delete(e.DisabledResources, mn)
+
+	mt, ok := e.ManifestTargets[mn]
+	if ok {
+		mt.State = newManifestState(mt.Manifest)
+	}
+}
+
 func (e EngineState) RelativeTiltfilePath() (string, error) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -482,6 +522,7 @@ func NewState() *EngineState {
 	}
 	ret.UpdateSettings = model.DefaultUpdateSettings()
 	ret.CurrentlyBuilding = make(map[model.ManifestName]bool)
+	ret.DisabledResources = make(map[model.ManifestName]bool)
 	ret.TiltfileState = &ManifestState{
 		Name:          model.TiltfileManifestName,
 		BuildStatuses: make(map[model.TargetID]*BuildStatus),
@@ -782,14 +823,20 @@ func ManifestTargetEndpoints(mt *ManifestTarget) (endpoints []model.Link) {
 		return localResourceLinks
 	}
 
-	publishedPorts := mt.Manifest.DockerComposeTarget().PublishedPorts()
-	if len(publishedPorts) > 0 {
-		for _, p := range publishedPorts {
+	dcTarget := mt.Manifest.DockerComposeTarget()
+	if len(dcTarget.PortForwards) > 0 {
+		// Like with K8s port-forwards, if the user picked out ports in the Tiltfile,
+		// we assume those are the ones they want to see.
+		for _, pf := range dcTarget.PortForwards {
+			endpoints = append(endpoints, pf.ToLink())
+		}
+	} else {
+		for _, p := range dcTarget.PublishedPorts() {
 			endpoints = append(endpoints, model.MustNewLink(fmt.Sprintf("http://localhost:%d/", p), ""))
 		}
 	}
 
-	endpoints = append(endpoints, mt.Manifest.DockerComposeTarget().Links...)
+	endpoints = append(endpoints, dcTarget.Links...)
 	return endpoints
 }
 
@@ -923,6 +970,7 @@ func resourceInfoView(mt *ManifestTarget) view.ResourceInfoView {
 			SpanID:             k8sconv.SpanIDForPod(mt.Manifest.Name, podID),
 			RunStatus:          runStatus,
 			DisplayNames:       mt.Manifest.K8sTarget().DisplayNames,
+			WarningReasons:     warningReasons(state.RecentWarningEvents()),
 		}
 	case LocalRuntimeState:
 		return view.NewLocalResourceInfo(runStatus, state.PID, state.SpanID)
@@ -932,6 +980,14 @@ func resourceInfoView(mt *ManifestTarget) view.ResourceInfoView {
 	}
 }
 
+func warningReasons(warnings []K8sWarningEvent) []string {
+	var result []string
+	for _, w := range warnings {
+		result = append(result, w.Reason)
+	}
+	return result
+}
+
 // DockerComposeConfigPath returns the path to the docker-compose yaml file of any
 // docker-compose manifests on this EngineState.
 // NOTE(maia): current assumption is only one d-c.yaml per run, so we take the
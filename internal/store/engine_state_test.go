@@ -15,6 +15,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/hud/view"
@@ -35,6 +38,7 @@ type endpointsCase struct {
 	lbURLs   []string
 
 	dcPublishedPorts []int
+	dcPortFwds       []model.PortForward
 
 	k8sResLinks   []model.Link
 	localResLinks []model.Link
@@ -43,7 +47,7 @@ type endpointsCase struct {
 
 func (c endpointsCase) validate() {
 	if len(c.portFwds) > 0 || len(c.lbURLs) > 0 || len(c.k8sResLinks) > 0 {
-		if len(c.dcPublishedPorts) > 0 || len(c.localResLinks) > 0 {
+		if len(c.dcPublishedPorts) > 0 || len(c.dcPortFwds) > 0 || len(c.localResLinks) > 0 {
 			// portForwards and LoadBalancerURLs are exclusively the province
 			// of k8s resources, so you should never see them paired with
 			// test settings that imply a. a DC resource or b. a local resource
@@ -158,6 +162,33 @@ func TestRuntimeStateNonWorkload(t *testing.T) {
 	assert.Equal(t, v1alpha1.RuntimeStatusOK, runtimeState.RuntimeStatus())
 }
 
+func TestRuntimeStateReadinessProbe(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	m := manifestbuilder.New(f, "secret").
+		WithK8sYAML(testyaml.SecretYaml).
+		WithK8sPodReadiness(model.PodReadinessIgnore).
+		Build()
+	state := newState([]model.Manifest{m})
+	runtimeState := state.ManifestTargets[m.Name].State.K8sRuntimeState()
+	runtimeState.HasEverDeployedSuccessfully = true
+	runtimeState.HasReadinessProbe = true
+
+	assert.Equal(t, v1alpha1.RuntimeStatusPending, runtimeState.RuntimeStatus())
+	assert.False(t, runtimeState.HasEverBeenReadyOrSucceeded())
+
+	runtimeState.ProbeReady = true
+	runtimeState.LastProbeReadyTime = time.Now()
+	assert.Equal(t, v1alpha1.RuntimeStatusOK, runtimeState.RuntimeStatus())
+	assert.True(t, runtimeState.HasEverBeenReadyOrSucceeded())
+
+	// Once the resource has been ready, a failing probe doesn't block dependents.
+	runtimeState.ProbeReady = false
+	assert.Equal(t, v1alpha1.RuntimeStatusPending, runtimeState.RuntimeStatus())
+	assert.True(t, runtimeState.HasEverBeenReadyOrSucceeded())
+}
+
 func TestStateToViewUnresourcedYAMLManifest(t *testing.T) {
 	m, err := k8s.NewK8sOnlyManifestFromYAML(testyaml.SanchoYAML)
 	assert.NoError(t, err)
@@ -203,6 +234,76 @@ func TestMostRecentPod(t *testing.T) {
 	assert.Equal(t, "pod-b", podSet.MostRecentPod().Name)
 }
 
+func TestWarningEvents(t *testing.T) {
+	m := model.Manifest{Name: "fe"}
+	state := NewK8sRuntimeState(m)
+	start := time.Now()
+	for i := 0; i < maxK8sWarningEvents+5; i++ {
+		state.AddWarningEvent(&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{UID: types.UID(fmt.Sprintf("event-%d", i))},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: fmt.Sprintf("pod-%d", i)},
+			Type:           v1.EventTypeWarning,
+			Reason:         "Unhealthy",
+			LastTimestamp:  metav1.NewTime(start.Add(time.Duration(i) * time.Second)),
+		})
+	}
+
+	warnings := state.RecentWarningEvents()
+	require.Len(t, warnings, maxK8sWarningEvents)
+	assert.Equal(t, fmt.Sprintf("pod-%d", maxK8sWarningEvents+4), warnings[0].Object.Name)
+	assert.Equal(t, "pod-5", warnings[len(warnings)-1].Object.Name)
+
+	_, ok := state.PodBlockingWarningEvent("pod-6")
+	assert.False(t, ok)
+
+	// BackOff is reported by the container status, so it never blocks.
+	state.AddWarningEvent(&v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{UID: "event-backoff"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pod-6"},
+		Type:           v1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off pulling image \"fe\"",
+		Count:          20,
+		EventTime:      metav1.NewMicroTime(start.Add(time.Hour)),
+	})
+	_, ok = state.PodBlockingWarningEvent("pod-6")
+	assert.False(t, ok)
+
+	// A blocking warning only blocks once it persists.
+	scheduling := &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{UID: "event-scheduling"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "pod-6"},
+		Type:           v1.EventTypeWarning,
+		Reason:         "FailedScheduling",
+		Message:        "0/1 nodes are available: 1 Insufficient cpu.",
+		EventTime:      metav1.NewMicroTime(start.Add(time.Hour)),
+	}
+	state.AddWarningEvent(scheduling)
+	_, ok = state.PodBlockingWarningEvent("pod-6")
+	assert.False(t, ok)
+
+	scheduling = scheduling.DeepCopy()
+	scheduling.Series = &v1.EventSeries{
+		Count:            blockingK8sWarningMinCount,
+		LastObservedTime: metav1.NewMicroTime(start.Add(time.Hour + time.Minute)),
+	}
+	state.AddWarningEvent(scheduling)
+	w, ok := state.PodBlockingWarningEvent("pod-6")
+	require.True(t, ok)
+	assert.Equal(t, "FailedScheduling", w.Reason)
+	assert.Equal(t, int32(blockingK8sWarningMinCount), w.Count)
+
+	_, ok = state.PodBlockingWarningEvent("pod-7")
+	assert.False(t, ok)
+}
+
+func TestWarningEventPersistent(t *testing.T) {
+	start := time.Now()
+	assert.False(t, K8sWarningEvent{Count: 1, FirstTimestamp: start, LastTimestamp: start}.Persistent())
+	assert.True(t, K8sWarningEvent{Count: blockingK8sWarningMinCount, FirstTimestamp: start, LastTimestamp: start}.Persistent())
+	assert.True(t, K8sWarningEvent{Count: 2, FirstTimestamp: start, LastTimestamp: start.Add(blockingK8sWarningMinDuration)}.Persistent())
+}
+
 func TestRelativeTiltfilePath(t *testing.T) {
 	es := newState([]model.Manifest{})
 	wd, err := os.Getwd()
@@ -243,6 +344,29 @@ func TestNextBuildReason(t *testing.T) {
 		mt.NextBuildReason().String())
 }
 
+func TestDisableResource(t *testing.T) {
+	m, err := k8s.NewK8sOnlyManifestFromYAML(testyaml.SanchoYAML)
+	require.NoError(t, err)
+
+	state := NewState()
+	mt := NewManifestTarget(m)
+	state.UpsertManifestTarget(mt)
+	mt.State.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+
+	state.AppendToTriggerQueue(m.Name, model.BuildReasonFlagTriggerCLI)
+	state.DisableResource(m.Name)
+	assert.True(t, state.IsResourceDisabled(m.Name))
+	assert.Empty(t, state.TriggerQueue)
+
+	state.AppendToTriggerQueue(m.Name, model.BuildReasonFlagTriggerCLI)
+	assert.Empty(t, state.TriggerQueue)
+
+	state.EnableResource(m.Name)
+	assert.False(t, state.IsResourceDisabled(m.Name))
+	assert.False(t, mt.State.StartedFirstBuild())
+	assert.Equal(t, "Initial Build", mt.NextBuildReason().String())
+}
+
 func TestManifestTargetEndpoints(t *testing.T) {
 	cases := []endpointsCase{
 		{
@@ -334,6 +458,16 @@ func TestManifestTargetEndpoints(t *testing.T) {
 				model.MustNewLink("www.zombo.com", "zombo"),
 			},
 		},
+		{
+			name: "docker compose port forwards supercede published ports",
+			expected: []model.Link{
+				model.MustNewLink("http://localhost:8000/status", "web"),
+			},
+			dcPublishedPorts: []int{8000, 7000},
+			dcPortFwds: []model.PortForward{
+				model.MustPortForward(8000, 80, "", "web", "status"),
+			},
+		},
 		{
 			name: "load balancers",
 			expected: []model.Link{
@@ -380,7 +514,9 @@ func TestManifestTargetEndpoints(t *testing.T) {
 			} else if len(c.localResLinks) > 0 {
 				m = m.WithDeployTarget(model.LocalTarget{Links: c.localResLinks})
 			} else if len(c.dcPublishedPorts) > 0 {
-				m = m.WithDeployTarget(model.DockerComposeTarget{}.WithPublishedPorts(c.dcPublishedPorts))
+				m = m.WithDeployTarget(model.DockerComposeTarget{}.
+					WithPublishedPorts(c.dcPublishedPorts).
+					WithPortForwards(c.dcPortFwds))
 			} else if len(c.dcResLinks) > 0 {
 				m = m.WithDeployTarget(model.DockerComposeTarget{Links: c.dcResLinks})
 			}
@@ -43,3 +43,17 @@ func TestToJSON(t *testing.T) {
 		t.Fatalf("Error decoding JSON: %v\nSource:\n%s\n", err, buf.String())
 	}
 }
+
+func TestToJSONOmitsSecrets(t *testing.T) {
+	state := newState(nil)
+	state.Secrets = model.SecretSet{}
+	state.Secrets.AddSecret("api-token", "", []byte("abc123-token"))
+
+	buf := bytes.NewBuffer(nil)
+	err := CreateEngineStateEncoder(buf).Encode(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotContains(t, buf.String(), "abc123-token")
+}
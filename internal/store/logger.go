@@ -49,6 +49,16 @@ func WithObjectLogHandler(ctx context.Context, st RStore, obj runtime.Object) (c
 	return logger.CtxWithLogHandler(ctx, w), nil
 }
 
+// Log to the given span of the manifest's log.
+func WithManifestLogHandler(ctx context.Context, st RStore, mn model.ManifestName, spanID model.LogSpanID) context.Context {
+	w := apiLogWriter{
+		store:        st,
+		manifestName: mn,
+		spanID:       spanID,
+	}
+	return logger.CtxWithLogHandler(ctx, w)
+}
+
 type apiLogWriter struct {
 	store        RStore
 	manifestName model.ManifestName
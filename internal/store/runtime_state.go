@@ -3,6 +3,7 @@ package store
 import (
 	"fmt"
 	"net/url"
+	"sort"
 	"time"
 
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
@@ -84,9 +85,19 @@ type K8sRuntimeState struct {
 
 	PodReadinessMode model.PodReadinessMode
 
+	// Whether the resource has a Tilt-side readiness probe (see
+	// k8s_resource(readiness_probe=...)), and its most recent result.
+	HasReadinessProbe  bool
+	ProbeReady         bool
+	LastProbeReadyTime time.Time
+
 	// BaselineRestarts is used as a floor for container restarts to avoid alerting on restarts
 	// that happened either before Tilt started or before a Live Update change.
 	BaselineRestarts map[k8s.PodID]int32
+
+	// Warning events on objects deployed by this resource since its
+	// most recent update started, by event UID.
+	WarningEvents map[types.UID]*v1.Event
 }
 
 func (K8sRuntimeState) RuntimeState() {}
@@ -111,6 +122,7 @@ func NewK8sRuntimeState(m model.Manifest) K8sRuntimeState {
 		DeployedPodTemplateSpecHashSet: NewPodTemplateSpecHashSet(),
 		UpdateStartTime:                make(map[k8s.PodID]time.Time),
 		BaselineRestarts:               make(map[k8s.PodID]int32),
+		WarningEvents:                  make(map[types.UID]*v1.Event),
 	}
 }
 
@@ -124,6 +136,14 @@ func (s K8sRuntimeState) RuntimeStatusError() error {
 }
 
 func (s K8sRuntimeState) RuntimeStatus() v1alpha1.RuntimeStatus {
+	status := s.podRuntimeStatus()
+	if status == v1alpha1.RuntimeStatusOK && s.HasReadinessProbe && !s.ProbeReady {
+		return v1alpha1.RuntimeStatusPending
+	}
+	return status
+}
+
+func (s K8sRuntimeState) podRuntimeStatus() v1alpha1.RuntimeStatus {
 	if !s.HasEverDeployedSuccessfully {
 		return v1alpha1.RuntimeStatusPending
 	}
@@ -161,6 +181,9 @@ func (s K8sRuntimeState) HasEverBeenReadyOrSucceeded() bool {
 	if !s.HasEverDeployedSuccessfully {
 		return false
 	}
+	if s.HasReadinessProbe && s.LastProbeReadyTime.IsZero() {
+		return false
+	}
 	if s.PodReadinessMode == model.PodReadinessIgnore {
 		return true
 	}
@@ -266,6 +289,166 @@ func (s K8sRuntimeState) VisiblePodContainerRestarts(podID k8s.PodID) int32 {
 	return AllPodContainerRestarts(*p) - s.BaselineRestarts[podID]
 }
 
+// Keep the warning event list from growing without bound if a resource
+// throws off a lot of different warnings.
+const maxK8sWarningEvents = 50
+
+// Warning event reasons that mean a pod won't make progress without
+// intervention, e.g., it can't be scheduled, or its volumes can't be mounted.
+//
+// BackOff and Failed are left out on purpose. They show up for image pulls
+// and crashing containers, which the container status already reports.
+var blockingK8sWarningReasons = map[string]bool{
+	"FailedScheduling":       true,
+	"FailedMount":            true,
+	"FailedAttachVolume":     true,
+	"FailedCreatePodSandBox": true,
+	"ErrImageNeverPull":      true,
+	"InspectFailed":          true,
+}
+
+// Many of the blocking warnings clear up on their own (e.g., FailedScheduling
+// while the cluster autoscaler adds a node), so a warning only blocks a pod
+// once it has repeated this many times, or kept repeating for this long.
+const blockingK8sWarningMinCount = 5
+const blockingK8sWarningMinDuration = 2 * time.Minute
+
+// K8sWarningEvent summarizes the Kubernetes Warning events with the same
+// reason on the same object.
+type K8sWarningEvent struct {
+	Object         v1.ObjectReference
+	Reason         string
+	Message        string
+	Count          int32
+	FirstTimestamp time.Time
+	LastTimestamp  time.Time
+}
+
+// AddWarningEvent records a new Warning event, or an update to an existing one
+// (e.g., with a higher count).
+func (s *K8sRuntimeState) AddWarningEvent(e *v1.Event) {
+	if s.WarningEvents == nil {
+		s.WarningEvents = make(map[types.UID]*v1.Event)
+	}
+	s.WarningEvents[e.UID] = e
+
+	for len(s.WarningEvents) > maxK8sWarningEvents {
+		var oldest *v1.Event
+		for _, e := range s.WarningEvents {
+			if oldest == nil || eventLastTimestamp(e).Before(eventLastTimestamp(oldest)) {
+				oldest = e
+			}
+		}
+		delete(s.WarningEvents, oldest.UID)
+	}
+}
+
+// RecentWarningEvents combines the warning events with the same reason
+// on the same object, and returns them most recent first.
+func (s K8sRuntimeState) RecentWarningEvents() []K8sWarningEvent {
+	type key struct {
+		obj    v1.ObjectReference
+		reason string
+	}
+
+	byKey := make(map[key]*K8sWarningEvent)
+	for _, e := range s.WarningEvents {
+		obj := v1.ObjectReference{
+			Kind:      e.InvolvedObject.Kind,
+			Namespace: e.InvolvedObject.Namespace,
+			Name:      e.InvolvedObject.Name,
+			UID:       e.InvolvedObject.UID,
+		}
+		k := key{obj: obj, reason: e.Reason}
+		first, last := eventFirstTimestamp(e), eventLastTimestamp(e)
+
+		w, ok := byKey[k]
+		if !ok {
+			byKey[k] = &K8sWarningEvent{
+				Object:         obj,
+				Reason:         e.Reason,
+				Message:        e.Message,
+				Count:          eventCount(e),
+				FirstTimestamp: first,
+				LastTimestamp:  last,
+			}
+			continue
+		}
+
+		w.Count += eventCount(e)
+		if first.Before(w.FirstTimestamp) {
+			w.FirstTimestamp = first
+		}
+		if !last.Before(w.LastTimestamp) {
+			w.LastTimestamp = last
+			w.Message = e.Message
+		}
+	}
+
+	result := make([]K8sWarningEvent, 0, len(byKey))
+	for _, w := range byKey {
+		result = append(result, *w)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastTimestamp.Equal(result[j].LastTimestamp) {
+			return result[i].LastTimestamp.After(result[j].LastTimestamp)
+		}
+		if result[i].Object.Name != result[j].Object.Name {
+			return result[i].Object.Name < result[j].Object.Name
+		}
+		return result[i].Reason < result[j].Reason
+	})
+	return result
+}
+
+// Persistent returns true if the warning has kept happening long enough
+// that it's unlikely to clear up on its own.
+func (w K8sWarningEvent) Persistent() bool {
+	return w.Count >= blockingK8sWarningMinCount ||
+		w.LastTimestamp.Sub(w.FirstTimestamp) >= blockingK8sWarningMinDuration
+}
+
+// PodBlockingWarningEvent returns the most recent warning event that's keeping
+// the given pod from running (e.g., FailedScheduling that persists), if any.
+func (s K8sRuntimeState) PodBlockingWarningEvent(podID k8s.PodID) (K8sWarningEvent, bool) {
+	for _, w := range s.RecentWarningEvents() {
+		if w.Object.Kind == "Pod" && w.Object.Name == podID.String() &&
+			blockingK8sWarningReasons[w.Reason] && w.Persistent() {
+			return w, true
+		}
+	}
+	return K8sWarningEvent{}, false
+}
+
+func eventCount(e *v1.Event) int32 {
+	if e.Series != nil && e.Series.Count > 0 {
+		return e.Series.Count
+	}
+	if e.Count > 0 {
+		return e.Count
+	}
+	return 1
+}
+
+// Events created with the events.k8s.io API use EventTime and Series
+// instead of the legacy timestamps.
+func eventFirstTimestamp(e *v1.Event) time.Time {
+	if !e.FirstTimestamp.IsZero() {
+		return e.FirstTimestamp.Time
+	}
+	return e.EventTime.Time
+}
+
+func eventLastTimestamp(e *v1.Event) time.Time {
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		return e.Series.LastObservedTime.Time
+	}
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	return eventFirstTimestamp(e)
+}
+
 func AllPodContainerPorts(p v1alpha1.Pod) []int32 {
 	result := make([]int32, 0)
 	for _, c := range p.Containers {
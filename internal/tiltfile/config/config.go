@@ -3,10 +3,12 @@ package config
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
 
+	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
 	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -21,6 +23,9 @@ type Settings struct {
 	configParseCalled bool
 	userConfigState   model.UserConfigState
 
+	// the values that config.parse returned, as structured data
+	parsedValues map[string]interface{}
+
 	// if parse has been called, the directory containing the Tiltfile that called it
 	seenWorkingDirectory string
 }
@@ -76,6 +81,10 @@ func (e *Extension) OnStart(env *starkit.Environment) error {
 		{"config.define_object", configSettingDefinitionBuiltin(func() configValue {
 			return &objectSetting{}
 		})},
+		{"config.define_int", configSettingDefinitionBuiltin(func() configValue {
+			return &intSetting{}
+		})},
+		{"config.define_enum", defineEnum},
 	} {
 		err := env.AddBuiltin(b.name, b.f)
 		if err != nil {
@@ -149,5 +158,43 @@ func (e *Extension) parse(thread *starlark.Thread, fn *starlark.Builtin, args st
 		return starlark.None, err
 	}
 
+	// save a copy, so that later changes to the returned dict don't show up
+	parsed, err := encoding.ConvertStarlarkToStructuredData(ret)
+	if err != nil {
+		return starlark.None, err
+	}
+	err = starkit.SetState(thread, func(settings Settings) (Settings, error) {
+		settings.parsedValues, _ = parsed.(map[string]interface{})
+		return settings, nil
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
 	return ret, nil
 }
+
+// The settings that the Tiltfile defined, sorted by name.
+func (s Settings) UserConfigSettings() []model.UserConfigSetting {
+	var result []model.UserConfigSetting
+	for name, def := range s.configDef.configSettings {
+		v := def.newValue()
+		setting := model.UserConfigSetting{
+			Name:       name,
+			Type:       v.Type(),
+			Usage:      def.usage,
+			Default:    def.defaultValue,
+			Positional: name == s.configDef.positionalSettingName,
+			Value:      s.parsedValues[name],
+		}
+		if e, ok := v.(*enumSetting); ok {
+			setting.Type = "enum"
+			setting.Choices = e.choices
+		}
+		result = append(result, setting)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"strings"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	flag "github.com/spf13/pflag"
 	"go.starlark.net/starlark"
 
+	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 )
 
+// Returned by config.parse when the args include --help, so that we
+// stop executing the Tiltfile after printing the help.
+var ErrHelp = errors.New("Tiltfile args help requested")
+
 type configValue interface {
 	flag.Value
 	starlark() starlark.Value
@@ -25,6 +32,10 @@ type configMap map[string]configValue
 type configSetting struct {
 	newValue func() configValue
 	usage    string
+
+	// The value to use if the setting isn't in the config file or args.
+	// In the same form as values decoded from the config file. nil if there's no default.
+	defaultValue interface{}
 }
 
 type ConfigDef struct {
@@ -83,6 +94,11 @@ func (cd ConfigDef) parse(configPath string, args []string) (v starlark.Value, o
 		return starlark.None, output, err
 	}
 
+	config, err = cd.applyDefaults(config)
+	if err != nil {
+		return starlark.None, output, err
+	}
+
 	ret, err := config.toStarlark()
 	if err != nil {
 		return nil, output, err
@@ -91,11 +107,32 @@ func (cd ConfigDef) parse(configPath string, args []string) (v starlark.Value, o
 	return ret, output, nil
 }
 
+// fills in defaults for any settings that weren't specified
+func (cd ConfigDef) applyDefaults(config configMap) (configMap, error) {
+	for name, def := range cd.configSettings {
+		if def.defaultValue == nil {
+			continue
+		}
+		if _, ok := config[name]; ok {
+			continue
+		}
+		v := def.newValue()
+		err := v.setFromInterface(def.defaultValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid default for setting %s", name)
+		}
+		config[name] = v
+	}
+	return config, nil
+}
+
 // parse command-line args
 func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err error) {
 	fs := flag.NewFlagSet("", flag.ContinueOnError)
 	w := &bytes.Buffer{}
 	fs.SetOutput(w)
+	// we print our own usage below
+	fs.Usage = func() {}
 
 	ret = make(configMap)
 	for name, def := range cd.configSettings {
@@ -103,7 +140,15 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 		if name == cd.positionalSettingName {
 			continue
 		}
-		fs.Var(ret[name], name, def.usage)
+		usage := def.usage
+		if e, ok := ret[name].(*enumSetting); ok {
+			usage = strings.TrimSpace(fmt.Sprintf("%s (one of: %s)", usage, e.choiceList()))
+		}
+		// pflag only prints defaults that it knows about, so add our own
+		if def.defaultValue != nil {
+			usage = strings.TrimSpace(fmt.Sprintf("%s (default %s)", usage, defaultString(def)))
+		}
+		fs.Var(ret[name], name, usage)
 		// for bools, make "--foo" equal to "--foo true"
 		if _, ok := ret[name].(*boolSetting); ok {
 			fs.Lookup(name).NoOptDefVal = "true"
@@ -111,6 +156,14 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 	}
 
 	err = fs.Parse(args)
+	if err == flag.ErrHelp {
+		_, _ = fmt.Fprintf(w, "Tiltfile args:\n")
+		if cd.positionalSettingName != "" {
+			_, _ = fmt.Fprintf(w, "  [%s...]  %s\n", cd.positionalSettingName, cd.configSettings[cd.positionalSettingName].usage)
+		}
+		fs.PrintDefaults()
+		return nil, w.String(), ErrHelp
+	}
 	if err != nil {
 		_, _ = fmt.Fprintf(w, "Error parsing tiltfile config args: %v\nUsage:\n", err)
 		fs.PrintDefaults()
@@ -133,6 +186,19 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 	return ret, w.String(), nil
 }
 
+// the default formatted for usage
+func defaultString(def configSetting) string {
+	v := def.newValue()
+	err := v.setFromInterface(def.defaultValue)
+	if err != nil {
+		return ""
+	}
+	if v.Type() == "string" {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return v.String()
+}
+
 // parse settings from the config file
 func (cd ConfigDef) readFromFile(tiltConfigPath string) (ret configMap, err error) {
 	ret = make(configMap)
@@ -175,6 +241,7 @@ func configSettingDefinitionBuiltin(newConfigValue func() configValue) starkit.F
 		var name string
 		var isArgs bool
 		var usage string
+		var defaultValue starlark.Value
 		err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 			"name",
 			&name,
@@ -182,43 +249,94 @@ func configSettingDefinitionBuiltin(newConfigValue func() configValue) starkit.F
 			&isArgs,
 			"usage?",
 			&usage,
+			"default?",
+			&defaultValue,
 		)
 		if err != nil {
 			return starlark.None, err
 		}
 
-		if name == "" {
-			return starlark.None, errors.New("'name' is required")
-		}
+		return defineSetting(thread, fn, name, isArgs, usage, defaultValue, newConfigValue)
+	}
+}
 
-		err = starkit.SetState(thread, func(settings Settings) (Settings, error) {
-			if settings.configParseCalled {
-				return settings, fmt.Errorf("%s cannot be called after config.parse is called", fn.Name())
-			}
+func defineEnum(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var choices value.StringOrStringList
+	var isArgs bool
+	var usage string
+	var defaultValue starlark.Value
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"name",
+		&name,
+		"choices",
+		&choices,
+		"args?",
+		&isArgs,
+		"usage?",
+		&usage,
+		"default?",
+		&defaultValue,
+	)
+	if err != nil {
+		return starlark.None, err
+	}
 
-			if _, ok := settings.configDef.configSettings[name]; ok {
-				return settings, fmt.Errorf("%s defined multiple times", name)
-			}
+	if len(choices.Values) == 0 {
+		return starlark.None, fmt.Errorf("%s: 'choices' cannot be empty", fn.Name())
+	}
 
-			if isArgs {
-				if settings.configDef.positionalSettingName != "" {
-					return settings, fmt.Errorf("both %s and %s are defined as positional args", name, settings.configDef.positionalSettingName)
-				}
+	return defineSetting(thread, fn, name, isArgs, usage, defaultValue, func() configValue {
+		return &enumSetting{choices: choices.Values}
+	})
+}
 
-				settings.configDef.positionalSettingName = name
-			}
+func defineSetting(thread *starlark.Thread, fn *starlark.Builtin, name string, isArgs bool, usage string, defaultValue starlark.Value, newConfigValue func() configValue) (starlark.Value, error) {
+	if name == "" {
+		return starlark.None, errors.New("'name' is required")
+	}
 
-			settings.configDef.configSettings[name] = configSetting{
-				newValue: newConfigValue,
-				usage:    usage,
+	var def interface{}
+	if defaultValue != nil && defaultValue != starlark.None {
+		var err error
+		def, err = encoding.ConvertStarlarkToStructuredData(defaultValue)
+		if err != nil {
+			return starlark.None, fmt.Errorf("%s: invalid default for %s: %v", fn.Name(), name, err)
+		}
+		err = newConfigValue().setFromInterface(def)
+		if err != nil {
+			return starlark.None, fmt.Errorf("%s: invalid default for %s: %v", fn.Name(), name, err)
+		}
+	}
+
+	err := starkit.SetState(thread, func(settings Settings) (Settings, error) {
+		if settings.configParseCalled {
+			return settings, fmt.Errorf("%s cannot be called after config.parse is called", fn.Name())
+		}
+
+		if _, ok := settings.configDef.configSettings[name]; ok {
+			return settings, fmt.Errorf("%s defined multiple times", name)
+		}
+
+		if isArgs {
+			if settings.configDef.positionalSettingName != "" {
+				return settings, fmt.Errorf("both %s and %s are defined as positional args", name, settings.configDef.positionalSettingName)
 			}
 
-			return settings, nil
-		})
-		if err != nil {
-			return starlark.None, err
+			settings.configDef.positionalSettingName = name
+		}
+
+		settings.configDef.configSettings[name] = configSetting{
+			newValue:     newConfigValue,
+			usage:        usage,
+			defaultValue: def,
 		}
 
-		return starlark.None, nil
+		return settings, nil
+	})
+	if err != nil {
+		return starlark.None, err
 	}
+
+	return starlark.None, nil
 }
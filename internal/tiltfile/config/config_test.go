@@ -261,6 +261,52 @@ config.parse()
 	require.Contains(t, f.PrintOutput(), "what can I foo for you today")
 }
 
+func TestHelp(t *testing.T) {
+	f := NewFixture(t, model.NewUserConfigState([]string{"--help"}), "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_string_list('resources', usage='which resources to load', args=True)
+config.define_int('port', usage='port to serve on', default=8080)
+config.define_enum('env', choices=['dev', 'prod'], usage='where to deploy', default='dev')
+config.parse()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrHelp.Error())
+
+	out := f.PrintOutput()
+	require.Contains(t, out, "Tiltfile args:")
+	require.Contains(t, out, "[resources...]  which resources to load")
+	require.Contains(t, out, "--port int")
+	require.Contains(t, out, "port to serve on (default 8080)")
+	require.Contains(t, out, `where to deploy (one of: dev, prod) (default "dev")`)
+	require.NotContains(t, out, "Error parsing")
+}
+
+func TestUserConfigSettings(t *testing.T) {
+	f := NewFixture(t, model.NewUserConfigState([]string{"--env", "prod", "frontend"}), "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_string_list('to-run', args=True, usage='resources to run')
+config.define_enum('env', choices=['dev', 'prod'], default='dev')
+config.define_bool('debug')
+cfg = config.parse()
+cfg['to-run'].append('backend')
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	require.Equal(t, []model.UserConfigSetting{
+		{Name: "debug", Type: "bool"},
+		{Name: "env", Type: "enum", Choices: []string{"dev", "prod"}, Default: "dev", Value: "prod"},
+		{Name: "to-run", Type: "list[string]", Usage: "resources to run", Positional: true, Value: []interface{}{"frontend"}},
+	}, MustState(result).UserConfigSettings())
+}
+
 // i.e., tilt up foo bar gets you resources foo and bar
 func TestDefaultTiltBehavior(t *testing.T) {
 	f := NewFixture(t, model.NewUserConfigState([]string{"foo", "bar"}), "")
@@ -571,6 +617,30 @@ func TestTypes(t *testing.T) {
 		newTypeTestCase("obj from config", "config.define_object('foo')").
 			withConfigFile(`{"foo": ["a", "b", "c"]}`).
 			withExpectedVal(`["a", "b", "c"]`),
+
+		newTypeTestCase("int from args", "config.define_int('foo')").withArgs("--foo", "8080").withExpectedVal("8080"),
+		newTypeTestCase("int from config", "config.define_int('foo')").withConfigFile(`{"foo": 8080}`).withExpectedVal("8080"),
+		newTypeTestCase("int defined multiple times", "config.define_int('foo')").withArgs("--foo", "1", "--foo", "2").withExpectedError("int settings can only be specified once"),
+		newTypeTestCase("invalid int from args", "config.define_int('foo')").withArgs("--foo", "abc").withExpectedError(`invalid argument "abc" for "--foo" flag: expected int, found "abc"`),
+		newTypeTestCase("invalid int from config", "config.define_int('foo')").withConfigFile(`{"foo": 1.5}`).withExpectedError("expected int, found 1.5"),
+		newTypeTestCase("int default", "config.define_int('foo', default=3)").withExpectedVal("3"),
+		newTypeTestCase("int default overridden by args", "config.define_int('foo', default=3)").withArgs("--foo", "4").withExpectedVal("4"),
+		newTypeTestCase("invalid int default", "config.define_int('foo', default='bar')").withExpectedError("config.define_int: invalid default for foo: expected int, found string"),
+
+		newTypeTestCase("enum from args", "config.define_enum('foo', choices=['dev', 'prod'])").withArgs("--foo", "prod").withExpectedVal("'prod'"),
+		newTypeTestCase("enum from config", "config.define_enum('foo', choices=['dev', 'prod'])").withConfigFile(`{"foo": "dev"}`).withExpectedVal("'dev'"),
+		newTypeTestCase("invalid enum from args", "config.define_enum('foo', choices=['dev', 'prod'])").withArgs("--foo", "staging").withExpectedError(`"staging" is not one of the allowed values: dev, prod`),
+		newTypeTestCase("invalid enum from config", "config.define_enum('foo', choices=['dev', 'prod'])").withConfigFile(`{"foo": "staging"}`).withExpectedError(`"staging" is not one of the allowed values: dev, prod`),
+		newTypeTestCase("enum default", "config.define_enum('foo', choices=['dev', 'prod'], default='dev')").withExpectedVal("'dev'"),
+		newTypeTestCase("invalid enum default", "config.define_enum('foo', choices=['dev', 'prod'], default='staging')").withExpectedError("config.define_enum: invalid default for foo"),
+		newTypeTestCase("enum without choices", "config.define_enum('foo', choices=[])").withExpectedError("config.define_enum: 'choices' cannot be empty"),
+
+		newTypeTestCase("string default", "config.define_string('foo', default='bar')").withExpectedVal("'bar'"),
+		newTypeTestCase("string default overridden by config", "config.define_string('foo', default='bar')").withConfigFile(`{"foo": "baz"}`).withExpectedVal("'baz'"),
+		newTypeTestCase("string_list default", "config.define_string_list('foo', default=['a', 'b'])").withExpectedVal("['a', 'b']"),
+		newTypeTestCase("string_list default replaced by args", "config.define_string_list('foo', default=['a', 'b'])").withArgs("--foo", "c").withExpectedVal("['c']"),
+		newTypeTestCase("bool default", "config.define_bool('foo', default=True)").withExpectedVal("True"),
+		newTypeTestCase("obj default", "config.define_object('foo', default={'a': 1})").withExpectedVal("{'a': 1}"),
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			f := NewFixture(t, model.UserConfigState{
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+)
+
+// A string setting that must be one of a fixed set of choices.
+type enumSetting struct {
+	choices []string
+	value   string
+	isSet   bool
+}
+
+var _ configValue = &enumSetting{}
+var _ flag.Value = &enumSetting{}
+
+func (s *enumSetting) starlark() starlark.Value {
+	return starlark.String(s.value)
+}
+
+func (s *enumSetting) IsSet() bool {
+	return s.isSet
+}
+
+func (s *enumSetting) Type() string {
+	return "string"
+}
+
+func (s *enumSetting) setFromInterface(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("expected string, found %T", i)
+	}
+	return s.setValue(v)
+}
+
+func (s *enumSetting) Set(v string) error {
+	if s.isSet {
+		return fmt.Errorf("enum settings can only be specified once. multiple values found (last value: %s)", v)
+	}
+	return s.setValue(v)
+}
+
+func (s *enumSetting) setValue(v string) error {
+	for _, c := range s.choices {
+		if c == v {
+			s.value = v
+			s.isSet = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of the allowed values: %s", v, s.choiceList())
+}
+
+func (s *enumSetting) choiceList() string {
+	return strings.Join(s.choices, ", ")
+}
+
+func (s *enumSetting) String() string {
+	return s.value
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
 
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
 	"github.com/tilt-dev/tilt/internal/ospath"
@@ -40,6 +41,10 @@ type dockerImage struct {
 	extraTags        []string // Extra tags added at build-time.
 	cacheFrom        []string
 	pullParent       bool
+	remoteCache      bool
+	platform         string
+	builder          string
+	builderTLS       model.BuilderTLS
 
 	// Overrides the container args. Used as an escape hatch in case people want the old entrypoint behavior.
 	// See discussion here:
@@ -92,7 +97,7 @@ func (d *dockerImage) Type() dockerImageBuildType {
 }
 
 func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var dockerRef, targetStage string
+	var dockerRef, targetStage, platform, builder string
 	var contextVal,
 		dockerfilePathVal,
 		dockerfileContentsVal,
@@ -100,11 +105,14 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		liveUpdateVal,
 		ignoreVal,
 		onlyVal,
-		entrypoint starlark.Value
+		entrypoint,
+		builderCACertVal,
+		builderCertVal,
+		builderKeyVal starlark.Value
 	var buildArgs value.StringStringMap
 	var network value.Stringable
 	var ssh, secret, extraTags, cacheFrom value.StringOrStringList
-	var matchInEnvVars, pullParent bool
+	var matchInEnvVars, pullParent, remoteCache bool
 	var overrideArgsVal starlark.Sequence
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"ref", &dockerRef,
@@ -126,6 +134,12 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		"extra_tag?", &extraTags,
 		"cache_from?", &cacheFrom,
 		"pull?", &pullParent,
+		"remote_cache?", &remoteCache,
+		"platform?", &platform,
+		"builder?", &builder,
+		"builder_ca_cert?", &builderCACertVal,
+		"builder_cert?", &builderCertVal,
+		"builder_key?", &builderKeyVal,
 	); err != nil {
 		return nil, err
 	}
@@ -216,6 +230,49 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		}
 	}
 
+	if platform != "" {
+		err := build.ValidatePlatform(platform)
+		if err != nil {
+			return nil, fmt.Errorf("Argument platform=%q: %v", platform, err)
+		}
+	}
+
+	if builder != "" {
+		err := build.ValidateBuilderAddress(builder)
+		if err != nil {
+			return nil, fmt.Errorf("Argument builder=%q: %v", builder, err)
+		}
+		if len(ssh.Values) > 0 || len(secret.Values) > 0 {
+			return nil, fmt.Errorf("Argument builder=%q: ssh and secret can't be used with a remote builder", builder)
+		}
+	}
+
+	var builderTLS model.BuilderTLS
+	for _, arg := range []struct {
+		name string
+		val  starlark.Value
+		dest *string
+	}{
+		{"builder_ca_cert", builderCACertVal, &builderTLS.CACert},
+		{"builder_cert", builderCertVal, &builderTLS.Cert},
+		{"builder_key", builderKeyVal, &builderTLS.Key},
+	} {
+		if arg.val == nil || arg.val == starlark.None {
+			continue
+		}
+		if builder == "" {
+			return nil, fmt.Errorf("Argument %s: only valid with a builder", arg.name)
+		}
+		*arg.dest, err = value.ValueToAbsPath(thread, arg.val)
+		if err != nil {
+			return nil, fmt.Errorf("Argument %s: %v", arg.name, err)
+		}
+	}
+	err = build.ValidateBuilderTLS(builder, builderTLS)
+	if err != nil {
+		return nil, fmt.Errorf("Argument builder=%q: %v", builder, err)
+	}
+
 	r := &dockerImage{
 		workDir:          starkit.CurrentExecPath(thread),
 		dbDockerfilePath: dockerfilePath,
@@ -236,6 +293,10 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		extraTags:        extraTags.Values,
 		cacheFrom:        cacheFrom.Values,
 		pullParent:       pullParent,
+		remoteCache:      remoteCache,
+		platform:         platform,
+		builder:          builder,
+		builderTLS:       builderTLS,
 	}
 	err = s.buildIndex.addImage(r)
 	if err != nil {
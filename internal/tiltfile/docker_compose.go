@@ -16,8 +16,10 @@ import (
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/links"
+	"github.com/tilt-dev/tilt/internal/tiltfile/probe"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -93,6 +95,10 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 	var triggerMode triggerMode
 	var resourceDepsVal starlark.Sequence
 	var links links.LinkList
+	var portForwardsVal starlark.Value
+	var labels value.LabelSet
+	var readinessProbe probe.Probe
+	var liveUpdateVal starlark.Value
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"name", &name,
@@ -109,6 +115,10 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		"trigger_mode?", &triggerMode,
 		"resource_deps?", &resourceDepsVal,
 		"links?", &links,
+		"port_forwards?", &portForwardsVal,
+		"labels?", &labels,
+		"readiness_probe?", &readinessProbe,
+		"live_update?", &liveUpdateVal,
 	); err != nil {
 		return nil, err
 	}
@@ -132,8 +142,30 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		return nil, err
 	}
 
+	portForwards, err := convertPortForwards(portForwardsVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %q", fn.Name(), name)
+	}
+	portForwards, err = svc.publishedPortForwards(portForwards)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %q", fn.Name(), name)
+	}
+
 	svc.TriggerMode = triggerMode
 	svc.Links = links.Links
+	svc.PortForwards = portForwards
+	svc.Labels = labels.Values
+	svc.ReadinessProbe = readinessProbe.Spec()
+
+	liveUpdate, err := s.liveUpdateFromSteps(thread, liveUpdateVal)
+	if err != nil {
+		return nil, errors.Wrap(err, "live_update")
+	}
+	if !liveUpdate.Empty() && svc.DfPath == "" {
+		return nil, fmt.Errorf("%s: live_update requires that service %q have a `build:` section "+
+			"in its docker-compose config", fn.Name(), name)
+	}
+	svc.LiveUpdate = liveUpdate
 
 	if imageRefAsStr != nil {
 		normalized, err := container.ParseNamed(*imageRefAsStr)
@@ -184,9 +216,19 @@ type dcService struct {
 
 	DependencyIDs  []model.TargetID
 	PublishedPorts []int
+	Ports          dockercompose.Ports
+
+	// Published ports declared with dc_resource(port_forwards).
+	PortForwards []model.PortForward
 
 	TriggerMode triggerMode
 	Links       []model.Link
+	Labels      map[string]string
+
+	ReadinessProbe *v1alpha1.Probe
+
+	// Live Update steps for a service built from its `build:` section.
+	LiveUpdate model.LiveUpdate
 
 	resourceDeps []string
 }
@@ -198,6 +240,52 @@ func (svc dcService) ImageRef() reference.Named {
 	return svc.imageRefFromConfig
 }
 
+// Matches the port forwards declared in the Tiltfile against the ports that
+// the service publishes. docker-compose does the actual forwarding, so each
+// port forward has to refer to a published port.
+func (svc dcService) publishedPortForwards(pfs []model.PortForward) ([]model.PortForward, error) {
+	var result []model.PortForward
+	for _, pf := range pfs {
+		port, ok := svc.publishedPort(pf.LocalPort)
+		if !ok {
+			return nil, fmt.Errorf("port_forwards: service does not publish port %d (published ports: %s). "+
+				"Add it to the service's `ports:` in the docker-compose config",
+				pf.LocalPort, svc.publishedPortsString())
+		}
+
+		if pf.ContainerPort == 0 {
+			pf.ContainerPort = port.Target
+		} else if port.Target != 0 && pf.ContainerPort != port.Target {
+			return nil, fmt.Errorf("port_forwards: service publishes port %d to container port %d, not %d",
+				pf.LocalPort, port.Target, pf.ContainerPort)
+		}
+		result = append(result, pf)
+	}
+	return result, nil
+}
+
+func (svc dcService) publishedPort(published int) (dockercompose.Port, bool) {
+	for _, p := range svc.Ports {
+		if p.Published != 0 && p.Published == published {
+			return p, true
+		}
+	}
+	return dockercompose.Port{}, false
+}
+
+func (svc dcService) publishedPortsString() string {
+	var ports []string
+	for _, p := range svc.Ports {
+		if p.Published != 0 {
+			ports = append(ports, p.String())
+		}
+	}
+	if len(ports) == 0 {
+		return "none"
+	}
+	return strings.Join(ports, ", ")
+}
+
 func DockerComposeConfigToService(c dockercompose.Config, name string) (dcService, error) {
 	svcConfig, ok := c.Services[name]
 	if !ok {
@@ -234,6 +322,7 @@ func DockerComposeConfigToService(c dockercompose.Config, name string) (dcServic
 
 		ServiceConfig:  svcConfig.RawYAML,
 		PublishedPorts: publishedPorts,
+		Ports:          svcConfig.Ports,
 	}
 
 	if svcConfig.Image != "" {
@@ -282,8 +371,10 @@ func (s *tiltfileState) dcServiceToManifest(service *dcService, dcSet dcResource
 		YAMLRaw:     service.ServiceConfig,
 		DfRaw:       service.DfContents,
 		Links:       service.Links,
-	}.WithDependencyIDs(service.DependencyIDs).
+	}.WithReadinessProbe(service.ReadinessProbe).
+		WithDependencyIDs(service.DependencyIDs).
 		WithPublishedPorts(service.PublishedPorts).
+		WithPortForwards(service.PortForwards).
 		WithIgnoredLocalDirectories(service.MountedLocalDirs)
 
 	um, err := starlarkTriggerModeToModel(s.triggerModeForResource(service.TriggerMode), true)
@@ -300,14 +391,15 @@ func (s *tiltfileState) dcServiceToManifest(service *dcService, dcSet dcResource
 		Name:                 model.ManifestName(service.Name),
 		TriggerMode:          um,
 		ResourceDependencies: mds,
-	}.WithDeployTarget(dcInfo)
+	}.WithDeployTarget(dcInfo).WithLabels(service.Labels)
 
 	if service.DfPath == "" {
 		// DC service may not have Dockerfile -- e.g. may be just an image that we pull and run.
 		return m, nil
 	}
 
-	dcInfo = dcInfo.WithBuildPath(service.BuildContext)
+	dcInfo = dcInfo.WithBuildPath(service.BuildContext).
+		WithLiveUpdate(service.LiveUpdate)
 
 	paths := []string{filepath.Dir(service.DfPath)}
 	for _, configPath := range dcSet.configPaths {
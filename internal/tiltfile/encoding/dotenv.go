@@ -0,0 +1,51 @@
+package encoding
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+
+	tiltfile_io "github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+)
+
+// reads a .env file into a dict of variable names to values
+func readDotenv(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var path starlark.String
+	var defaultValue starlark.Value
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "paths", &path, "default?", &defaultValue); err != nil {
+		return nil, err
+	}
+
+	localPath, err := value.ValueToAbsPath(thread, path)
+	if err != nil {
+		return nil, fmt.Errorf("Argument 0 (paths): %v", err)
+	}
+
+	contents, err := tiltfile_io.ReadFile(thread, localPath)
+	if err != nil {
+		// Return the default value if the file doesn't exist AND a default value was given
+		if os.IsNotExist(err) && defaultValue != nil {
+			return defaultValue, nil
+		}
+		return nil, err
+	}
+
+	env, err := godotenv.Unmarshal(string(contents))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing dotenv from %s", path.GoString())
+	}
+
+	ret := starlark.NewDict(len(env))
+	for k, v := range env {
+		err := ret.SetKey(starlark.String(k), starlark.String(v))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
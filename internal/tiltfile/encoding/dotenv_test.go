@@ -0,0 +1,80 @@
+package encoding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
+)
+
+func TestReadDotenv(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File(".env", `
+# database settings
+DB_HOST=localhost
+DB_PORT=5432
+export GREETING="hello world"
+EMPTY=
+`)
+	f.File("Tiltfile", `
+observed = read_dotenv(".env")
+
+expected = {
+  'DB_HOST': 'localhost',
+  'DB_PORT': '5432',
+  'GREETING': 'hello world',
+  'EMPTY': '',
+}
+
+load('assert.tilt', 'assert')
+assert.equals(expected, observed)
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	if err != nil {
+		fmt.Println(f.PrintOutput())
+	}
+	require.NoError(t, err)
+
+	rs, err := io.GetState(result)
+	require.NoError(t, err)
+	require.Contains(t, rs.Paths, f.JoinPath(".env"))
+}
+
+func TestReadDotenvDefaultValue(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+result = read_dotenv("dne.env", {})
+
+load('assert.tilt', 'assert')
+assert.equals({}, result)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	if err != nil {
+		fmt.Println(f.PrintOutput())
+	}
+	require.NoError(t, err)
+}
+
+func TestDotenvDoesNotExist(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `result = read_dotenv("dne.env")`)
+	result, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dne.env")
+	testutils.AssertIsNotExist(t, err)
+
+	rs, err := io.GetState(result)
+	require.NoError(t, err)
+	require.Contains(t, rs.Paths, f.JoinPath("dne.env"))
+}
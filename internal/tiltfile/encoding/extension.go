@@ -19,6 +19,12 @@ const (
 	readJSONN   = "read_json"
 	decodeJSONN = "decode_json"
 	encodeJSONN = "encode_json"
+
+	readTOMLN   = "read_toml"
+	decodeTOMLN = "decode_toml"
+	encodeTOMLN = "encode_toml"
+
+	readDotenvN = "read_dotenv"
 )
 
 func (Extension) OnStart(env *starkit.Environment) error {
@@ -36,6 +42,12 @@ func (Extension) OnStart(env *starkit.Environment) error {
 		{readJSONN, readJSON},
 		{decodeJSONN, decodeJSON},
 		{encodeJSONN, encodeJSON},
+
+		{readTOMLN, readTOML},
+		{decodeTOMLN, decodeTOML},
+		{encodeTOMLN, encodeTOML},
+
+		{readDotenvN, readDotenv},
 	} {
 		err := env.AddBuiltin(b.name, b.f)
 		if err != nil {
@@ -0,0 +1,125 @@
+package encoding
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+
+	tiltfile_io "github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+)
+
+// reads toml from a file
+func readTOML(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var path starlark.String
+	var defaultValue starlark.Value
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "paths", &path, "default?", &defaultValue); err != nil {
+		return nil, err
+	}
+
+	localPath, err := value.ValueToAbsPath(thread, path)
+	if err != nil {
+		return nil, fmt.Errorf("Argument 0 (paths): %v", err)
+	}
+
+	contents, err := tiltfile_io.ReadFile(thread, localPath)
+	if err != nil {
+		// Return the default value if the file doesn't exist AND a default value was given
+		if os.IsNotExist(err) && defaultValue != nil {
+			return defaultValue, nil
+		}
+		return nil, err
+	}
+
+	return tomlStringToStarlark(string(contents), path.GoString())
+}
+
+// reads toml from a string
+func decodeTOML(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var contents value.Stringable
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "toml", &contents); err != nil {
+		return nil, err
+	}
+
+	return tomlStringToStarlark(contents.Value, "")
+}
+
+func tomlStringToStarlark(s string, source string) (starlark.Value, error) {
+	tree, err := toml.Load(s)
+	if err != nil {
+		errmsg := "error parsing TOML"
+		if source != "" {
+			errmsg += fmt.Sprintf(" from %s", source)
+		}
+		return nil, errors.Wrap(err, errmsg)
+	}
+
+	v, err := ConvertStructuredDataToStarlark(normalizeTOML(tree.ToMap()))
+	if err != nil {
+		errmsg := "error converting TOML to Starlark"
+		if source != "" {
+			errmsg += fmt.Sprintf(" from %s", source)
+		}
+		return nil, errors.Wrap(err, errmsg)
+	}
+	return v, nil
+}
+
+// Converts the TOML types that Starlark doesn't have an equivalent for.
+// Datetimes become RFC 3339 strings.
+func normalizeTOML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = normalizeTOML(val)
+		}
+		return v
+	case []interface{}:
+		for i, val := range v {
+			v[i] = normalizeTOML(val)
+		}
+		return v
+	case uint64:
+		return int64(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+func encodeTOML(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var obj *starlark.Dict
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "obj", &obj); err != nil {
+		return nil, err
+	}
+
+	ret, err := starlarkToTOMLString(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return tiltfile_io.NewBlob(ret, "encode_toml"), nil
+}
+
+func starlarkToTOMLString(obj *starlark.Dict) (string, error) {
+	v, err := ConvertStarlarkToStructuredData(obj)
+	if err != nil {
+		return "", errors.Wrap(err, "error converting object from starlark")
+	}
+
+	tree, err := toml.TreeFromMap(v.(map[string]interface{}))
+	if err != nil {
+		return "", errors.Wrap(err, "error serializing object to toml")
+	}
+
+	s, err := tree.ToTomlString()
+	if err != nil {
+		return "", errors.Wrap(err, "error serializing object to toml")
+	}
+	return s, nil
+}
@@ -0,0 +1,195 @@
+package encoding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
+)
+
+func TestReadTOML(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	var document = `
+key1 = "foo"
+key5 = 3
+released = 1979-05-27T07:32:00Z
+
+[key2]
+key3 = "bar"
+key4 = true
+
+[[servers]]
+name = "alpha"
+
+[[servers]]
+name = "beta"
+`
+	f.File("options.toml", document)
+	f.File("Tiltfile", `
+observed = read_toml("options.toml")
+
+expected = {
+  'key1': 'foo',
+  'key2': {
+    'key3': 'bar',
+    'key4': True
+  },
+  'key5': 3,
+  'released': '1979-05-27T07:32:00Z',
+  'servers': [{'name': 'alpha'}, {'name': 'beta'}],
+}
+
+load('assert.tilt', 'assert')
+assert.equals(expected, observed)
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	if err != nil {
+		fmt.Println(f.PrintOutput())
+	}
+	require.NoError(t, err)
+
+	rs, err := io.GetState(result)
+	require.NoError(t, err)
+	require.Contains(t, rs.Paths, f.JoinPath("options.toml"))
+}
+
+func TestReadTOMLDefaultValue(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+result = read_toml("dne.toml", {'hello': 'goodbye'})
+
+load('assert.tilt', 'assert')
+assert.equals({'hello': 'goodbye'}, result)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	if err != nil {
+		fmt.Println(f.PrintOutput())
+	}
+	require.NoError(t, err)
+}
+
+func TestTOMLDoesNotExist(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `result = read_toml("dne.toml")`)
+	result, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dne.toml")
+	testutils.AssertIsNotExist(t, err)
+
+	rs, err := io.GetState(result)
+	require.NoError(t, err)
+	require.Contains(t, rs.Paths, f.JoinPath("dne.toml"))
+}
+
+func TestMalformedTOML(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("options.toml", `key1 = `)
+	f.File("Tiltfile", `result = read_toml("options.toml")`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error parsing TOML from options.toml")
+
+	rs, err := io.GetState(result)
+	require.NoError(t, err)
+	require.Contains(t, rs.Paths, f.JoinPath("options.toml"))
+}
+
+func TestDecodeTOML(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	for _, blob := range []bool{false, true} {
+		t.Run(fmt.Sprintf("blob: %v", blob), func(t *testing.T) {
+			d := `'key1 = "foo"\n[key2]\nkey3 = ["bar", ""]\nkey4 = 1\n'`
+			if blob {
+				d = fmt.Sprintf("blob(%s)", d)
+			}
+			d = fmt.Sprintf("observed = decode_toml(%s)", d)
+			tf := d + `
+expected = {
+  'key1': 'foo',
+  'key2': {
+    'key3': ['bar', ''],
+    'key4': 1,
+  },
+}
+
+load('assert.tilt', 'assert')
+assert.equals(expected, observed)
+`
+			f.File("Tiltfile", tf)
+
+			_, err := f.ExecFile("Tiltfile")
+			if err != nil {
+				fmt.Println(f.PrintOutput())
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEncodeTOML(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+observed = encode_toml({
+  'key1': 'foo',
+  'key2': {
+    'key3': ['bar', 'baz'],
+    'key4': True,
+  },
+})
+
+load('assert.tilt', 'assert')
+assert.equals({
+  'key1': 'foo',
+  'key2': {
+    'key3': ['bar', 'baz'],
+    'key4': True,
+  },
+}, decode_toml(observed))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	if err != nil {
+		fmt.Println(f.PrintOutput())
+	}
+	require.NoError(t, err)
+}
+
+func TestEncodeTOMLNonStringMapKey(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `encode_toml({1: 'hello'})`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "only string keys are supported in maps. found key '1' of type int64")
+}
+
+func TestEncodeTOMLNotADict(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `encode_toml(['hello'])`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "encode_toml: for parameter obj: got list, want dict")
+}
@@ -4,12 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
 
+	"github.com/tilt-dev/tilt/internal/helm"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/localexec"
 	tiltfile_io "github.com/tilt-dev/tilt/internal/tiltfile/io"
@@ -100,7 +97,8 @@ func (s *tiltfileState) execLocalCmd(t *starlark.Thread, cmd model.Cmd, logOutpu
 
 func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var path starlark.Value
-	err := s.unpackArgs(fn.Name(), args, kwargs, "paths", &path)
+	var flags value.StringOrStringList
+	err := s.unpackArgs(fn.Name(), args, kwargs, "paths", &path, "flags?", &flags)
 	if err != nil {
 		return nil, err
 	}
@@ -110,36 +108,31 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 		return nil, fmt.Errorf("Argument 0 (paths): %v", err)
 	}
 
-	// NOTE(nick): There's a bug in kustomize where it doesn't properly
-	// handle absolute paths. Convert to relative paths instead:
-	// https://github.com/kubernetes-sigs/kustomize/issues/2789
-	relKustomizePath, err := filepath.Rel(starkit.AbsWorkingDir(thread), absKustomizePath)
+	cacheDir, err := kustomize.TiltDevRemoteBaseCacheDir()
 	if err != nil {
 		return nil, err
 	}
 
-	cmd := model.Cmd{Argv: []string{"kustomize", "build", relKustomizePath}, Dir: starkit.AbsWorkingDir(thread)}
+	yaml, deps, err := kustomize.Build(absKustomizePath, flags.Values, cacheDir)
 
-	_, err = exec.LookPath(cmd.Argv[0])
-	if err != nil {
-		s.logger.Infof("Falling back to `kubectl kustomize` since `%s` was not found in PATH", cmd.Argv[0])
-		cmd.Argv = []string{"kubectl", "kustomize", relKustomizePath}
+	// Watch the kustomization dir, so that creating a kustomization file
+	// that's missing triggers a reload.
+	watchErr := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchRecursive, absKustomizePath)
+	if watchErr != nil {
+		return nil, watchErr
 	}
 
-	yaml, err := s.execLocalCmd(thread, cmd, false)
-	if err != nil {
-		return nil, err
-	}
-	deps, err := kustomize.Deps(absKustomizePath)
-	if err != nil {
-		return nil, fmt.Errorf("resolving deps: %v", err)
-	}
+	// Watch everything kustomize read, even if the build failed,
+	// so that fixing the broken file triggers a reload.
 	for _, d := range deps {
-		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchRecursive, d)
+		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, d)
 		if err != nil {
 			return nil, err
 		}
 	}
+	if err != nil {
+		return nil, fmt.Errorf("kustomize %s: %v", absKustomizePath, err)
+	}
 
 	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("kustomize: %s", absKustomizePath)), nil
 }
@@ -150,102 +143,97 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 	var namespace string
 	var valueFiles value.StringOrStringList
 	var set value.StringOrStringList
+	var repo string
+	var version string
 	err := s.unpackArgs(fn.Name(), args, kwargs,
 		"paths", &path,
 		"name?", &name,
 		"namespace?", &namespace,
 		"values?", &valueFiles,
-		"set?", &set)
+		"set?", &set,
+		"repo?", &repo,
+		"version?", &version)
 	if err != nil {
 		return nil, err
 	}
 
-	localPath, err := value.ValueToAbsPath(thread, path)
+	ctx, err := starkit.ContextFromThread(thread)
 	if err != nil {
-		return nil, fmt.Errorf("Argument 0 (paths): %v", err)
+		return nil, err
 	}
 
-	info, err := os.Stat(localPath)
+	chartCache, err := helm.NewTiltDevChartCache()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("Could not read Helm chart directory %q: does not exist", localPath)
+		return nil, err
+	}
+
+	var chartPath string
+	chartRef, isString := starlark.AsString(path)
+	if isString && (repo != "" || helm.IsOCIRef(chartRef)) {
+		// A remote chart. The chart archive is cached, so we watch it like
+		// any other dependency.
+		chartPath, err = chartCache.Fetch(ctx, repo, chartRef, version)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if version != "" {
+			return nil, fmt.Errorf("helm: version may only be specified for charts from a repo or OCI registry")
+		}
+
+		chartPath, err = value.ValueToAbsPath(thread, path)
+		if err != nil {
+			return nil, fmt.Errorf("Argument 0 (paths): %v", err)
+		}
+
+		info, err := os.Stat(chartPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("Could not read Helm chart directory %q: does not exist", chartPath)
+			}
+			return nil, fmt.Errorf("Could not read Helm chart directory %q: %v", chartPath, err)
+		} else if !info.IsDir() && !isHelmChartArchive(chartPath) {
+			return nil, fmt.Errorf("helm() may only be called on directories with Chart.yaml: %q", chartPath)
 		}
-		return nil, fmt.Errorf("Could not read Helm chart directory %q: %v", localPath, err)
-	} else if !info.IsDir() {
-		return nil, fmt.Errorf("helm() may only be called on directories with Chart.yaml: %q", localPath)
 	}
 
-	err = tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchRecursive, localPath)
+	err = tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchRecursive, chartPath)
 	if err != nil {
 		return nil, err
 	}
 
-	deps, err := localSubchartDependenciesFromPath(localPath)
+	chart, deps, err := helm.LoadChart(ctx, chartPath, chartCache)
 	if err != nil {
 		return nil, err
 	}
 	for _, d := range deps {
-		err = tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchRecursive, starkit.AbsPath(thread, d))
+		err = tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchRecursive, d)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	version, err := getHelmVersion()
-	if err != nil {
-		return nil, err
-	}
-
-	var cmd []string
-
-	if name == "" {
-		// Use 'chart' as the release name, so that the release name is stable
-		// across Tiltfile loads.
-		// This looks like what helm does.
-		// https://github.com/helm/helm/blob/e672a42efae30d45ddd642a26557dcdbf5a9f5f0/pkg/action/install.go#L562
-		name = "chart"
-	}
-
-	if version == helmV3_1andAbove {
-		cmd = []string{"helm", "template", name, localPath, "--include-crds"}
-	} else if version == helmV3_0 {
-		cmd = []string{"helm", "template", name, localPath}
-	} else {
-		cmd = []string{"helm", "template", localPath, "--name", name}
-	}
-
-	if namespace != "" {
-		cmd = append(cmd, "--namespace", namespace)
-	}
+	var absValueFiles []string
 	for _, valueFile := range valueFiles.Values {
-		cmd = append(cmd, "--values", valueFile)
-		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, starkit.AbsPath(thread, valueFile))
+		absValueFile := starkit.AbsPath(thread, valueFile)
+		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, absValueFile)
 		if err != nil {
 			return nil, err
 		}
-	}
-	for _, setArg := range set.Values {
-		cmd = append(cmd, "--set", setArg)
+		absValueFiles = append(absValueFiles, absValueFile)
 	}
 
-	s.logger.Infof("Running: %s", cmd)
-
-	stdout, err := s.execLocalCmd(thread, model.Cmd{Argv: cmd, Dir: starkit.AbsWorkingDir(thread)}, false)
+	yaml, err := helm.Template(chart, helm.TemplateOptions{
+		ReleaseName: name,
+		Namespace:   namespace,
+		ValueFiles:  absValueFiles,
+		Set:         set.Values,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	yaml := filterHelmTestYAML(string(stdout))
-
-	if version == helmV3_0 {
-		// Helm v3.0 has a bug where it doesn't include CRDs in the template output
-		// https://github.com/tilt-dev/tilt/issues/3605
-		crds, err := getHelmCRDs(localPath)
-		if err != nil {
-			return nil, err
-		}
-		yaml = strings.Join(append([]string{yaml}, crds...), "\n---\n")
-	}
+	yaml = filterHelmTestYAML(yaml)
 
 	if namespace != "" {
 		// helm template --namespace doesn't inject the namespace, nor provide
@@ -266,40 +254,5 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 		}
 	}
 
-	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("helm: %s", localPath)), nil
-}
-
-// NOTE(nick): This isn't perfect. For example, it doesn't handle chart deps
-// properly. When possible, prefer Helm 3.1's --include-crds
-func getHelmCRDs(path string) ([]string, error) {
-	crdPath := filepath.Join(path, "crds")
-	result := []string{}
-	err := filepath.Walk(crdPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		isYAML := info != nil && info.Mode().IsRegular() && hasYAMLExtension(path)
-		if !isYAML {
-			return nil
-		}
-		contents, err := ioutil.ReadFile(path)
-		if err != nil {
-			if os.IsNotExist(err) {
-				return nil
-			}
-			return err
-		}
-		result = append(result, string(contents))
-		return nil
-	})
-
-	if err != nil && !os.IsNotExist(err) {
-		return nil, err
-	}
-	return result, nil
-}
-
-func hasYAMLExtension(fname string) bool {
-	ext := filepath.Ext(fname)
-	return strings.EqualFold(ext, ".yaml") || strings.EqualFold(ext, ".yml")
+	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("helm: %s", chartPath)), nil
 }
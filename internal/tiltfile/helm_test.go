@@ -6,12 +6,18 @@
 package tiltfile
 
 import (
-	"os/exec"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
 
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/tiltfile/testdata"
@@ -252,81 +258,6 @@ k8s_yaml(yml)
 	f.loadErrString("helm: for parameter \"set\"", "string", "List", "type dict")
 }
 
-const exampleHelmV2VersionOutput = `Client: v2.12.3geecf22f`
-const exampleHelmV3_0VersionOutput = `v3.0.0`
-const exampleHelmV3_1VersionOutput = `v3.1.0`
-const exampleHelmV3_2VersionOutput = `v3.2.4`
-
-// see https://github.com/tilt-dev/tilt/issues/3788
-const exampleHelmV3_3VersionOutput = `WARNING: Kubernetes configuration file is group-readable. This is insecure. Location: /Users/someone/.kube/config
-WARNING: Kubernetes configuration file is world-readable. This is insecure. Location: /Users/someone/.kube/config
-v3.3.3+g55e3ca0
-`
-
-func TestParseHelmV2Version(t *testing.T) {
-	expected := helmV2
-	assertHelmVersion(t, exampleHelmV2VersionOutput, expected)
-}
-
-func TestParseHelmV3Version(t *testing.T) {
-	expected := helmV3_0
-	assertHelmVersion(t, exampleHelmV3_0VersionOutput, expected)
-}
-
-func TestParseHelmV3_1Version(t *testing.T) {
-	expected := helmV3_1andAbove
-	assertHelmVersion(t, exampleHelmV3_1VersionOutput, expected)
-}
-
-func TestParseHelmV3_2Version(t *testing.T) {
-	expected := helmV3_1andAbove
-	assertHelmVersion(t, exampleHelmV3_2VersionOutput, expected)
-}
-
-func TestParseHelmV3_3Version(t *testing.T) {
-	expected := helmV3_1andAbove
-	assertHelmVersion(t, exampleHelmV3_3VersionOutput, expected)
-}
-
-func TestHelmUnknownVersionError(t *testing.T) {
-	_, err := parseVersion("v4.1.2")
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "could not parse Helm version from string")
-}
-
-const fileRequirementsYAML = `dependencies:
-  - name: foobar
-    version: 1.0.1
-    repository: file://./foobar`
-
-func TestLocalSubchartFileDependencies(t *testing.T) {
-	input := []byte(fileRequirementsYAML)
-	expected := "./foobar"
-	actual, err := localSubchartDependencies(input)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	assert.Contains(t, actual, expected)
-}
-
-const remoteRequirementsYAML = `
-dependencies:
-- name: etcd
-  version: 0.6.2
-  repository: https://kubernetes-charts-incubator.storage.googleapis.com/
-  condition: etcd.deployChart`
-
-func TestSubchartRemoteDependencies(t *testing.T) {
-	input := []byte(remoteRequirementsYAML)
-	actual, err := localSubchartDependencies(input)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	assert.Empty(t, actual)
-}
-
 func TestHelmReleaseName(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -389,20 +320,8 @@ k8s_yaml(helm('./helm'))
 
 	m := manifests[0]
 	yaml := m.K8sTarget().YAML
-	v, err := getHelmVersion()
-	assert.NoError(t, err)
 	assert.Contains(t, yaml, "kind: ServiceAccount")
-	if v == helmV3_0 || v == helmV3_1andAbove {
-		assert.Contains(t, yaml, "kind: UselessMachine")
-	} else {
-		assert.NotContains(t, yaml, "kind: UselessMachine")
-	}
-}
-
-func assertHelmVersion(t *testing.T, versionOutput string, expectedV helmVersion) {
-	actualV, err := parseVersion(versionOutput)
-	require.NoError(t, err, "parsing helm version")
-	require.Equal(t, expectedV, actualV)
+	assert.Contains(t, yaml, "kind: UselessMachine")
 }
 
 func TestYamlErrorFromHelm(t *testing.T) {
@@ -414,17 +333,7 @@ func TestYamlErrorFromHelm(t *testing.T) {
 k8s_yaml(helm('helm'))
 `)
 
-	// TODO(dmiller): there should be a better assertion here
-
-	version, err := getHelmVersion()
-	if err != nil {
-		t.Fatal(err)
-	}
-	if version == helmV2 {
-		f.loadErrString("from helm")
-	} else {
-		f.loadErrString("in helm")
-	}
+	f.loadErrString("helloworld-chart/templates/foo.yaml")
 }
 
 func TestHelmSkipsTests(t *testing.T) {
@@ -447,23 +356,7 @@ k8s_yaml(yml)
 	)
 }
 
-// There's a major helm regression that's breaking everything
-// https://github.com/helm/helm/issues/6708
-func isBuggyHelm(t *testing.T) bool {
-	cmd := exec.Command("helm", "version", "-c", "--short")
-	out, err := cmd.Output()
-	if err != nil {
-		t.Fatalf("Error running helm: %v", err)
-	}
-
-	return strings.Contains(string(out), "v2.15.0")
-}
-
 func TestHelmIncludesRequirements(t *testing.T) {
-	if isBuggyHelm(t) {
-		t.Skipf("Helm v2.15.0 has a major regression, skipping test. See: https://github.com/helm/helm/issues/6708")
-	}
-
 	f := newFixture(t)
 	defer f.TearDown()
 
@@ -476,3 +369,111 @@ k8s_yaml(yml)
 	f.load()
 	f.assertNextManifest("chart-nginx-ingress-controller")
 }
+
+func TestHelmLocalFileDependency(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupHelm()
+	f.file("helm/Chart.yaml", chartYAML+`
+dependencies:
+- name: sidecar
+  version: 0.1.0
+  repository: file://../sidecar
+`)
+	f.file("sidecar/Chart.yaml", `apiVersion: v2
+name: sidecar
+version: 0.1.0`)
+	f.file("sidecar/templates/service-account.yaml", `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: sidecar-sa`)
+
+	f.file("Tiltfile", `
+k8s_yaml(helm('helm'))
+`)
+
+	f.load()
+
+	f.assertNextManifestUnresourced("sidecar-sa", "chart-helloworld-chart")
+	f.assertConfigFiles(
+		"Tiltfile",
+		".tiltignore",
+		"helm",
+		"sidecar",
+	)
+}
+
+func TestHelmFromRepo(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	devDir := f.JoinPath(".tilt-dev")
+	oldDevDir := os.Getenv("TILT_DEV_DIR")
+	_ = os.Setenv("TILT_DEV_DIR", devDir)
+	defer func() {
+		_ = os.Setenv("TILT_DEV_DIR", oldDevDir)
+	}()
+
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "remote", Version: "0.1.0"},
+		Templates: []*chart.File{{Name: "templates/service-account.yaml", Data: []byte(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .Release.Name }}-sa`)}},
+	}
+	archive, err := chartutil.Save(ch, f.TempDir("chart"))
+	require.NoError(t, err)
+	contents, err := ioutil.ReadFile(archive)
+	require.NoError(t, err)
+
+	repo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write([]byte(`apiVersion: v1
+entries:
+  remote:
+  - apiVersion: v2
+    name: remote
+    version: 0.1.0
+    urls:
+    - remote-0.1.0.tgz
+`))
+		case "/remote-0.1.0.tgz":
+			_, _ = w.Write(contents)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer repo.Close()
+
+	f.file("Tiltfile", fmt.Sprintf(`
+k8s_yaml(helm('remote', repo='%s', version='0.1.0', name='rose-quartz'))
+`, repo.URL))
+
+	f.load()
+
+	m := f.assertNextManifestUnresourced("rose-quartz-sa")
+	assert.Contains(t, m.K8sTarget().YAML, "name: rose-quartz-sa")
+
+	cachedChart := ""
+	for _, p := range f.loadResult.ConfigFiles {
+		if strings.HasPrefix(p, devDir) {
+			cachedChart = p
+		}
+	}
+	assert.True(t, strings.HasSuffix(cachedChart, "remote-0.1.0.tgz"),
+		"expected cached chart in config files: %v", f.loadResult.ConfigFiles)
+}
+
+func TestHelmVersionRequiresRepo(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupHelm()
+	f.file("Tiltfile", `
+k8s_yaml(helm('helm', version='1.0.0'))
+`)
+
+	f.loadErrString("version may only be specified for charts from a repo or OCI registry")
+}
@@ -20,7 +20,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	tiltfile_k8s "github.com/tilt-dev/tilt/internal/tiltfile/k8s"
+	"github.com/tilt-dev/tilt/internal/tiltfile/probe"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -51,6 +53,11 @@ type k8sResource struct {
 
 	podReadinessMode model.PodReadinessMode
 
+	readinessProbe *v1alpha1.Probe
+
+	// If true, deploy with server-side apply instead of client-side apply
+	serverSideApply bool
+
 	dependencyIDs []model.TargetID
 
 	triggerMode triggerMode
@@ -61,6 +68,12 @@ type k8sResource struct {
 	manuallyGrouped bool
 
 	links []model.Link
+
+	labels map[string]string
+
+	// If non-nil, this resource is deployed by custom commands
+	// (see k8s_custom_deploy) instead of by applying entities.
+	customDeploy *k8sCustomDeploy
 }
 
 // holds options passed to `k8s_resource` until assembly happens
@@ -76,7 +89,10 @@ type k8sResourceOptions struct {
 	objects           []string
 	manuallyGrouped   bool
 	podReadinessMode  model.PodReadinessMode
+	readinessProbe    *v1alpha1.Probe
+	serverSideApply   bool
 	links             []model.Link
+	labels            map[string]string
 }
 
 func (r *k8sResource) addEntities(entities []k8s.K8sEntity,
@@ -262,8 +278,11 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 	var resourceDepsVal starlark.Sequence
 	var objectsVal starlark.Sequence
 	var podReadinessMode tiltfile_k8s.PodReadinessMode
+	var readinessProbe probe.Probe
 	var links links.LinkList
+	var labels value.LabelSet
 	autoInit := true
+	serverSideApply := false
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"workload?", &workload,
@@ -275,7 +294,10 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		"objects?", &objectsVal,
 		"auto_init?", &autoInit,
 		"pod_readiness?", &podReadinessMode,
+		"readiness_probe?", &readinessProbe,
 		"links?", &links,
+		"labels?", &labels,
+		"server_side_apply?", &serverSideApply,
 	); err != nil {
 		return nil, err
 	}
@@ -333,7 +355,10 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		objects:           objects,
 		manuallyGrouped:   manuallyGrouped,
 		podReadinessMode:  podReadinessMode.Value,
+		readinessProbe:    readinessProbe.Spec(),
+		serverSideApply:   serverSideApply,
 		links:             links.Links,
+		labels:            labels.Values,
 	}
 
 	return starlark.None, nil
@@ -0,0 +1,140 @@
+package tiltfile
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The tag we give the placeholder image that k8s_custom_deploy creates
+// to hang a live_update on. The image is never actually built.
+const k8sCustomDeployImageTag = "tilt-custom-deploy"
+
+// A k8s resource deployed by user-supplied commands rather than YAML.
+type k8sCustomDeploy struct {
+	applyCmd  model.Cmd
+	deleteCmd model.Cmd
+	deps      []string
+}
+
+func (s *tiltfileState) k8sCustomDeploy(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name value.Name
+	var applyCmdVal, applyCmdBatVal, applyCmdDirVal starlark.Value
+	var deleteCmdVal, deleteCmdBatVal, deleteCmdDirVal starlark.Value
+	var applyEnv, deleteEnv value.StringStringMap
+	var imageSelector string
+	var liveUpdateVal starlark.Value
+
+	deps := value.NewLocalPathListUnpacker(thread)
+
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"name", &name,
+		"apply_cmd", &applyCmdVal,
+		"delete_cmd", &deleteCmdVal,
+		"deps", &deps,
+		"image_selector?", &imageSelector,
+		"live_update?", &liveUpdateVal,
+		"apply_dir?", &applyCmdDirVal,
+		"apply_env?", &applyEnv,
+		"apply_cmd_bat?", &applyCmdBatVal,
+		"delete_dir?", &deleteCmdDirVal,
+		"delete_env?", &deleteEnv,
+		"delete_cmd_bat?", &deleteCmdBatVal,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	applyCmd, err := value.ValueGroupToCmdHelper(thread, applyCmdVal, applyCmdBatVal, applyCmdDirVal, applyEnv)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply_cmd")
+	} else if applyCmd.Empty() {
+		return nil, fmt.Errorf("k8s_custom_deploy: apply_cmd cannot be empty")
+	}
+
+	deleteCmd, err := value.ValueGroupToCmdHelper(thread, deleteCmdVal, deleteCmdBatVal, deleteCmdDirVal, deleteEnv)
+	if err != nil {
+		return nil, errors.Wrap(err, "delete_cmd")
+	} else if deleteCmd.Empty() {
+		return nil, fmt.Errorf("k8s_custom_deploy: delete_cmd cannot be empty")
+	}
+
+	liveUpdate, err := s.liveUpdateFromSteps(thread, liveUpdateVal)
+	if err != nil {
+		return nil, errors.Wrap(err, "live_update")
+	}
+
+	res, err := s.makeK8sResource(name.String())
+	if err != nil {
+		return nil, err
+	}
+	res.customDeploy = &k8sCustomDeploy{
+		applyCmd:  applyCmd,
+		deleteCmd: deleteCmd,
+		deps:      deps.Value,
+	}
+
+	if liveUpdate.Empty() {
+		return starlark.None, nil
+	}
+
+	if imageSelector == "" {
+		return nil, fmt.Errorf("k8s_custom_deploy: image_selector is required when using live_update")
+	}
+
+	ref, err := container.ParseNamed(imageSelector)
+	if err != nil {
+		return nil, fmt.Errorf("k8s_custom_deploy: can't parse image_selector %q: %v", imageSelector, err)
+	}
+
+	// Tilt doesn't build any images for a custom deploy. To live update
+	// containers running the selected image, we register a placeholder image
+	// whose "build" is a no-op, and which watches the same deps.
+	img := &dockerImage{
+		workDir:          starkit.AbsWorkingDir(thread),
+		configurationRef: container.NewRefSelector(ref),
+		customCommand:    model.ToHostCmd(":"),
+		customDeps:       deps.Value,
+		customTag:        k8sCustomDeployImageTag,
+		disablePush:      true,
+		skipsLocalDocker: true,
+		liveUpdate:       liveUpdate,
+	}
+	err = s.buildIndex.addImage(img)
+	if err != nil {
+		return nil, err
+	}
+
+	res.imageRefs = append(res.imageRefs, ref)
+	res.imageRefMap[ref.String()]++
+
+	return starlark.None, nil
+}
+
+// Attaches the custom deploy commands and file dependencies to a K8sTarget.
+func (cd *k8sCustomDeploy) applyToTarget(kt model.K8sTarget, hasLiveUpdate bool) model.K8sTarget {
+	kt.ApplyCmd = toKubernetesApplyCmd(cd.applyCmd)
+	kt.DeleteCmd = toKubernetesApplyCmd(cd.deleteCmd)
+
+	// With a live update, the placeholder image already watches the deps,
+	// and falls back to a full re-apply when a change can't be synced.
+	if !hasLiveUpdate {
+		kt = kt.WithPathDependencies(cd.deps, reposForPaths(cd.deps))
+	}
+	return kt
+}
+
+func toKubernetesApplyCmd(cmd model.Cmd) *v1alpha1.KubernetesApplyCmd {
+	return &v1alpha1.KubernetesApplyCmd{
+		Args: cmd.Argv,
+		Dir:  cmd.Dir,
+		Env:  cmd.Env,
+	}
+}
@@ -0,0 +1,135 @@
+package tiltfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestK8sCustomDeploy(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+k8s_custom_deploy('foo',
+                  apply_cmd='apply.sh',
+                  delete_cmd='delete.sh',
+                  deps=['foo'],
+                  apply_env={'MODE': 'dev'})
+`)
+
+	f.load()
+	m := f.assertNextManifest("foo")
+	f.assertNoMoreManifests()
+
+	kt := m.K8sTarget()
+	assert.Empty(t, kt.YAML)
+	require.NotNil(t, kt.ApplyCmd)
+	assert.Equal(t, model.ToHostCmd("apply.sh").Argv, kt.ApplyCmd.Args)
+	assert.Equal(t, f.Path(), kt.ApplyCmd.Dir)
+	assert.Equal(t, []string{"MODE=dev"}, kt.ApplyCmd.Env)
+	require.NotNil(t, kt.DeleteCmd)
+	assert.Equal(t, model.ToHostCmd("delete.sh").Argv, kt.DeleteCmd.Args)
+	assert.Equal(t, []string{f.JoinPath("foo")}, kt.Dependencies())
+	assert.Empty(t, m.ImageTargets)
+}
+
+func TestK8sCustomDeployWithResourceOptions(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+k8s_custom_deploy('foo', apply_cmd='apply.sh', delete_cmd='delete.sh', deps=[])
+k8s_resource('foo', port_forwards=8000, resource_deps=['bar'])
+local_resource('bar', 'echo hi')
+`)
+
+	f.load()
+	f.assertNextManifest("foo",
+		[]model.PortForward{{LocalPort: 8000}},
+		resourceDeps("bar"))
+	f.assertNextManifest("bar")
+	f.assertNoMoreManifests()
+}
+
+func TestK8sCustomDeployLiveUpdate(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+k8s_custom_deploy('foo',
+                  apply_cmd='apply.sh',
+                  delete_cmd='delete.sh',
+                  deps=['src'],
+                  image_selector='my-img',
+                  live_update=[sync('src', '/src')])
+`)
+
+	f.load()
+	m := f.assertNextManifest("foo",
+		cb(image("my-img"),
+			deps(f.JoinPath("src")),
+			tag(k8sCustomDeployImageTag),
+			disablePush(true)))
+
+	require.Len(t, m.ImageTargets, 1)
+	assert.False(t, m.ImageTargets[0].LiveUpdateInfo().Empty())
+
+	// The placeholder image watches the deps, so the deploy target doesn't have to.
+	assert.Empty(t, m.K8sTarget().Dependencies())
+}
+
+func TestK8sCustomDeployLiveUpdateNoImageSelector(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+k8s_custom_deploy('foo',
+                  apply_cmd='apply.sh',
+                  delete_cmd='delete.sh',
+                  deps=['src'],
+                  live_update=[sync('src', '/src')])
+`)
+
+	f.loadErrString("image_selector is required when using live_update")
+}
+
+func TestK8sCustomDeployEmptyApplyCmd(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+k8s_custom_deploy('foo', apply_cmd='', delete_cmd='delete.sh', deps=[])
+`)
+
+	f.loadErrString("apply_cmd cannot be empty")
+}
+
+func TestK8sCustomDeployConflictsWithWorkload(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_custom_deploy('foo', apply_cmd='apply.sh', delete_cmd='delete.sh', deps=[])
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+`)
+
+	f.loadErrString(`k8s_resource named "foo" already exists`)
+}
+
+func TestK8sCustomDeployServerSideApply(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+k8s_custom_deploy('foo', apply_cmd='apply.sh', delete_cmd='delete.sh', deps=[])
+k8s_resource('foo', server_side_apply=True)
+`)
+
+	f.loadErrString(`k8s_resource "foo": server_side_apply can't be used with k8s_custom_deploy`)
+}
@@ -30,9 +30,9 @@ type localResource struct {
 	ignores       []string
 	allowParallel bool
 	links         []model.Link
+	labels        map[string]string
 
 	// for use in testing mvp
-	tags   []string
 	isTest bool
 
 	readinessProbe *v1alpha1.Probe
@@ -48,7 +48,9 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 
 	deps := value.NewLocalPathListUnpacker(thread)
 
-	var resourceDepsVal, tagsVal starlark.Sequence
+	var resourceDepsVal starlark.Sequence
+	var labels value.LabelSet
+	var tags value.StringOrStringList
 	var ignoresVal starlark.Value
 	var allowParallel bool
 	var links links.LinkList
@@ -80,12 +82,13 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		"serve_cmd_bat?", &serveCmdBatVal,
 		"allow_parallel?", &allowParallel,
 		"links?", &links,
-		"tags?", &tagsVal,
+		"tags?", &tags,
 		"env?", &updateEnv,
 		"serve_env?", &serveEnv,
 		"readiness_probe?", &readinessProbe,
 		"dir?", &updateCmdDirVal,
 		"serve_dir?", &serveCmdDirVal,
+		"labels?", &labels,
 	); err != nil {
 		return nil, err
 	}
@@ -96,9 +99,11 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 	if err != nil {
 		return nil, errors.Wrapf(err, "%s: resource_deps", fn.Name())
 	}
-	tags, err := value.SequenceToStringSlice(tagsVal)
+
+	// tags predate labels, and are treated as labels.
+	err = labels.AddAll(tags.Values)
 	if err != nil {
-		return nil, errors.Wrapf(err, "%s: resource_deps", fn.Name())
+		return nil, errors.Wrapf(err, "%s: tags", fn.Name())
 	}
 
 	ignores, err := parseValuesToStrings(ignoresVal, "ignore")
@@ -132,7 +137,7 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		ignores:        ignores,
 		allowParallel:  allowParallel,
 		links:          links.Links,
-		tags:           tags,
+		labels:         labels.Values,
 		isTest:         isTest,
 		readinessProbe: readinessProbe.Spec(),
 	}
@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Implements functions for marking values in the Tiltfile as secrets,
+// so that Tilt scrubs them from logs.
+//
+// Unlike secrets from Kubernetes Secret objects, these are scrubbed even
+// with secret_settings(disable_scrub=True), because the user asked for them
+// explicitly.
+type Extension struct {
+}
+
+func NewExtension() Extension {
+	return Extension{}
+}
+
+func (e Extension) NewState() interface{} {
+	return model.SecretSet{}
+}
+
+func (e Extension) OnStart(env *starkit.Environment) error {
+	err := env.AddBuiltin("secret", e.secret)
+	if err != nil {
+		return err
+	}
+	return env.AddBuiltin("read_secret", e.readSecret)
+}
+
+func (e Extension) secret(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var v starlark.Value
+	var name string
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"value", &v,
+		"name?", &name); err != nil {
+		return nil, err
+	}
+
+	s, ok := value.AsString(v)
+	if !ok {
+		return nil, fmt.Errorf("%s: for parameter value: got %s, want string", fn.Name(), v.Type())
+	}
+	if name == "" {
+		name = "tiltfile"
+	}
+
+	err := addSecret(thread, fn.Name(), name, []byte(s))
+	if err != nil {
+		return nil, err
+	}
+	return starlark.String(s), nil
+}
+
+func (e Extension) readSecret(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var path starlark.Value
+	var name string
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"path", &path,
+		"name?", &name); err != nil {
+		return nil, err
+	}
+
+	p, err := value.ValueToAbsPath(thread, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid type for path: %v", fn.Name(), err)
+	}
+
+	contents, err := io.ReadFile(thread, p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+
+	// Files usually end in a newline, but the newline isn't part of the secret.
+	contents = bytes.TrimRight(contents, "\r\n")
+	if name == "" {
+		name = filepath.Base(p)
+	}
+
+	err = addSecret(thread, fn.Name(), name, contents)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.String(contents), nil
+}
+
+func addSecret(thread *starlark.Thread, fnName string, name string, v []byte) error {
+	// Short values match too much of the logs to scrub, so they'd leak.
+	if len(v) < model.MinSecretLengthToScrub {
+		return fmt.Errorf("%s: secret %q is too short to scrub from logs (must be at least %d characters)",
+			fnName, name, model.MinSecretLengthToScrub)
+	}
+
+	return starkit.SetState(thread, func(existing model.SecretSet) (model.SecretSet, error) {
+		result := model.SecretSet{}
+		result.AddAll(existing)
+		result.AddSecret(name, "", v)
+		return result, nil
+	})
+}
+
+var _ starkit.StatefulExtension = Extension{}
+
+func MustState(model starkit.Model) model.SecretSet {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (model.SecretSet, error) {
+	var state model.SecretSet
+	err := m.Load(&state)
+	return state, err
+}
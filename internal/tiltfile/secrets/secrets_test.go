@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+)
+
+func TestSecret(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+token = secret('abc123-token', name='api-token')
+print(token)
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123-token\n", f.PrintOutput())
+
+	s := MustState(result)
+	assert.Equal(t, "TOKEN=[redacted secret api-token]", string(s.Scrub([]byte("TOKEN=abc123-token"))))
+}
+
+func TestSecretDefaultName(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+secret('abc123-token')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	s := MustState(result)
+	assert.Equal(t, "[redacted secret tiltfile]", string(s.Scrub([]byte("abc123-token"))))
+}
+
+func TestSecretNotAString(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+secret(123)
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secret: for parameter value: got int, want string")
+}
+
+func TestSecretTooShort(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+secret('abc', name='pin')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `secret: secret "pin" is too short to scrub from logs (must be at least 5 characters)`)
+}
+
+func TestReadSecretTooShort(t *testing.T) {
+	f := NewFixture(t)
+	f.File("pin.txt", "1234\n")
+	f.File("Tiltfile", `
+read_secret('pin.txt')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `read_secret: secret "pin.txt" is too short to scrub from logs`)
+}
+
+func TestReadSecret(t *testing.T) {
+	f := NewFixture(t)
+	f.File("password.txt", "hunter2-password\n")
+	f.File("Tiltfile", `
+password = read_secret('password.txt')
+print(password)
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2-password\n", f.PrintOutput())
+
+	s := MustState(result)
+	assert.Equal(t, "[redacted secret password.txt]", string(s.Scrub([]byte("hunter2-password"))))
+	assert.Contains(t, io.MustState(result).Paths, f.JoinPath("password.txt"))
+}
+
+func TestReadSecretMissing(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+read_secret('password.txt')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read_secret: ")
+}
+
+func NewFixture(tb testing.TB) *starkit.Fixture {
+	ret := starkit.NewFixture(tb, NewExtension(), io.NewExtension())
+	ret.UseRealFS()
+	return ret
+}
@@ -42,7 +42,7 @@ t = test("test-foo", "echo hi",
 		updateCmd(f.Path(), "echo hi", nil),
 		deps("a.txt", "b.txt")))
 	assert.True(t, foo.LocalTarget().IsTest, "should be flagged as test manifest")
-	assert.Equal(t, []string{"beep", "boop"}, foo.LocalTarget().Tags)
+	assert.Equal(t, map[string]string{"beep": "beep", "boop": "boop"}, foo.Labels)
 	assert.Equal(t, model.TriggerModeManualWithAutoInit, foo.TriggerMode)
 }
 
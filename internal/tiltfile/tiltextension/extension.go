@@ -6,30 +6,42 @@ package tiltextension
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 )
 
 type Extension struct {
-	fetcher Fetcher
-	store   Store
+	newFetcher FetcherFactory
+	store      Store
+	lockPath   string
 }
 
-func NewExtension(fetcher Fetcher, store Store) *Extension {
+func NewExtension(newFetcher FetcherFactory, store Store, lockPath string) *Extension {
 	return &Extension{
-		fetcher: fetcher,
-		store:   store,
+		newFetcher: newFetcher,
+		store:      store,
+		lockPath:   lockPath,
 	}
 }
 
 type State struct {
 	ExtsLoaded map[string]bool
+
+	// The repos declared with extension_repo(), in the order they were declared.
+	Repos []*v1alpha1.ExtensionRepo
 }
 
 func (e Extension) NewState() interface{} {
@@ -39,13 +51,76 @@ func (e Extension) NewState() interface{} {
 }
 
 type Fetcher interface {
-	Fetch(ctx context.Context, moduleName string) (ModuleContents, error)
+	Fetch(ctx context.Context, moduleName string, version ModuleVersion) (ModuleContents, error)
 	CleanUp() error
 }
 
 func (e *Extension) OnStart(env *starkit.Environment) error {
 	env.AddLoadInterceptor(e)
-	return nil
+	return env.AddBuiltin("extension_repo", e.extensionRepo)
+}
+
+func (e *Extension) extensionRepo(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, repoURL, ref string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"name", &name,
+		"url", &repoURL,
+		"ref?", &ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" || strings.ContainsAny(name, "/@") || len(path.IsValidPathSegmentName(name)) != 0 {
+		return nil, fmt.Errorf("%s: invalid name %q. Names may not contain '/' or '@'", fn.Name(), name)
+	}
+	if repoURL == "" {
+		return nil, fmt.Errorf("%s: url cannot be empty", fn.Name())
+	}
+
+	// Anything that doesn't look like a URL is a directory on local disk.
+	if !strings.Contains(repoURL, "://") && !strings.HasPrefix(repoURL, "git@") {
+		dir := starkit.AbsPath(thread, repoURL)
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s: %s is not a directory", fn.Name(), dir)
+		}
+		repoURL = (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()
+	}
+
+	repo := &v1alpha1.ExtensionRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{v1alpha1.AnnotationOwnerTiltfile: "true"},
+		},
+		Spec: v1alpha1.ExtensionRepoSpec{
+			URL: repoURL,
+			Ref: ref,
+		},
+	}
+	if dir, ok := localRepoDir(repoURL); ok {
+		repo.Status.Path = dir
+	}
+
+	err = starkit.SetState(thread, func(existing State) (State, error) {
+		for _, r := range existing.Repos {
+			if r.Name != name {
+				continue
+			}
+			if r.Spec != repo.Spec {
+				return existing, fmt.Errorf("%s: repo %q is already defined with url %q", fn.Name(), name, r.Spec.URL)
+			}
+			return existing, nil
+		}
+		existing.Repos = append(append([]*v1alpha1.ExtensionRepo{}, existing.Repos...), repo)
+		return existing, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return starlark.None, nil
 }
 
 func (e *Extension) recordExtensionLoaded(ctx context.Context, t *starlark.Thread, moduleName string) {
@@ -58,18 +133,87 @@ func (e *Extension) recordExtensionLoaded(ctx context.Context, t *starlark.Threa
 	}
 }
 
+// Records the commit that an ExtensionRepo resolved to in its status.
+func (e *Extension) recordRepoCommit(ctx context.Context, t *starlark.Thread, repoName, commit string) {
+	if repoName == "" || commit == "" {
+		return
+	}
+	err := starkit.SetState(t, func(existing State) (State, error) {
+		repos := make([]*v1alpha1.ExtensionRepo, 0, len(existing.Repos))
+		for _, r := range existing.Repos {
+			if r.Name == repoName {
+				r = r.DeepCopy()
+				r.Status.CheckoutRef = commit
+			}
+			repos = append(repos, r)
+		}
+		existing.Repos = repos
+		return existing, nil
+	})
+	if err != nil {
+		logger.Get(ctx).Debugf("error updating state on Tilt extensions loader: %v", err)
+	}
+}
+
+// The resolvers for the repos declared so far, followed by the default repo.
+func (e *Extension) resolvers(t *starlark.Thread) (ResolverChain, error) {
+	m, err := starkit.ModelFromThread(t)
+	if err != nil {
+		return nil, err
+	}
+	state, err := GetState(m)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := ResolverChain{}
+	for _, repo := range state.Repos {
+		chain = append(chain, repoResolver{repo: repo})
+	}
+	return append(chain, defaultResolver{}), nil
+}
+
 const extensionPrefix = "ext://"
 
+// Splits `name@ref` into the module name and the ref.
+func parseModuleArg(arg string) (string, string, error) {
+	moduleName := strings.TrimPrefix(arg, extensionPrefix)
+	ref := ""
+	if i := strings.LastIndex(moduleName, "@"); i != -1 {
+		moduleName, ref = moduleName[:i], moduleName[i+1:]
+		if ref == "" {
+			return "", "", fmt.Errorf("invalid extension %q: expected a ref after @", arg)
+		}
+	}
+	if moduleName == "" {
+		return "", "", fmt.Errorf("invalid extension %q: missing name", arg)
+	}
+
+	// The name is a path in the extension repo and in tilt_modules,
+	// so it mustn't be able to point outside of them.
+	for _, segment := range strings.Split(moduleName, "/") {
+		if segment == ".." || segment == "." || segment == "" || strings.Contains(segment, `\`) {
+			return "", "", fmt.Errorf("invalid extension %q: name must be a relative path without . or .. segments", arg)
+		}
+	}
+	return moduleName, ref, nil
+}
+
 func (e *Extension) LocalPath(t *starlark.Thread, arg string) (localPath string, err error) {
 	ctx, err := starkit.ContextFromThread(t)
 	if err != nil {
 		return "", err
 	}
+
 	if !strings.HasPrefix(arg, extensionPrefix) {
 		return "", nil
 	}
 
-	moduleName := strings.TrimPrefix(arg, extensionPrefix)
+	moduleName, ref, err := parseModuleArg(arg)
+	if err != nil {
+		return "", err
+	}
+
 	defer func() {
 		if err == nil {
 			// NOTE(maia): Maybe in future we want to track if there was an error or not?
@@ -78,25 +222,185 @@ func (e *Extension) LocalPath(t *starlark.Thread, arg string) (localPath string,
 		}
 	}()
 
+	chain, err := e.resolvers(t)
+	if err != nil {
+		return "", err
+	}
+	resolved, ok := chain.Resolve(moduleName)
+	if !ok {
+		return "", fmt.Errorf("no extension repo for %q", moduleName)
+	}
+
+	if resolved.LocalDir != "" {
+		localPath = filepath.Join(resolved.LocalDir, filepath.FromSlash(resolved.Path), extensionFileName)
+		_, err := os.Stat(localPath)
+		if err != nil {
+			return "", fmt.Errorf("extension %q not found in repo %q: %v", resolved.Path, resolved.RepoName, err)
+		}
+		return localPath, nil
+	}
+
+	if ref == "" {
+		ref = resolved.Ref
+	}
+	repo := resolved.RepoURL
+	if repo == DefaultExtensionRepo {
+		repo = ""
+	}
+
+	lock, err := ReadLockFile(e.lockPath)
+	if err != nil {
+		return "", err
+	}
+	locked, isLocked := lock.Get(moduleName)
+	pinned := isLocked && locked.Ref == ref && locked.Repo == repo
+
 	// If the module can't be found we fetch it below
 	localPath, err = e.store.ModulePath(ctx, moduleName)
 	if err != nil && !os.IsNotExist(err) {
 		return "", err
 	}
+
 	if localPath != "" {
-		return localPath, nil
+		stored, hasMetadata, err := e.store.ModuleMetadata(ctx, moduleName)
+		if err != nil {
+			return "", err
+		}
+
+		// Use the local copy if it's the commit that's pinned.
+		if pinned && hasMetadata && stored.Commit == locked.Commit {
+			e.recordRepoCommit(ctx, t, resolved.RepoName, locked.Commit)
+			return localPath, nil
+		}
+
+		// If nothing's pinned, the local copy predates the lockfile. It's good
+		// enough if the Tiltfile didn't ask for a specific ref or repo.
+		if !isLocked && ref == "" && repo == "" {
+			if !hasMetadata {
+				// Copied into tilt_modules by hand, so there's nothing to pin.
+				return localPath, nil
+			}
+
+			// Pin the commit it was fetched at, so that the next load agrees.
+			// If it was fetched before we recorded commits, refetch it below.
+			if stored.Commit != "" {
+				err := WriteLockFile(e.lockPath, lock.With(LockedExtension{
+					Name:   moduleName,
+					Ref:    stored.Ref,
+					Commit: stored.Commit,
+				}))
+				if err != nil {
+					return "", err
+				}
+				e.recordRepoCommit(ctx, t, resolved.RepoName, stored.Commit)
+				return localPath, nil
+			}
+		}
 	}
 
-	contents, err := e.fetcher.Fetch(ctx, moduleName)
+	version := ModuleVersion{Ref: ref}
+	if pinned {
+		version.Commit = locked.Commit
+	}
+	localPath, commit, err := e.fetchAndLock(ctx, lock, moduleName, resolved.RepoURL, resolved.Path, version)
 	if err != nil {
 		return "", err
 	}
+	e.recordRepoCommit(ctx, t, resolved.RepoName, commit)
+	return localPath, nil
+}
 
+// Fetches the extension at path in the repo, stores it as moduleName,
+// and pins the commit that we fetched.
+func (e *Extension) fetchAndLock(ctx context.Context, lock LockFile, moduleName, repoURL, path string, version ModuleVersion) (string, string, error) {
+	fetcher := e.newFetcher(repoURL)
+	contents, err := fetcher.Fetch(ctx, path, version)
+	if err != nil {
+		return "", "", err
+	}
 	defer func() {
-		_ = e.fetcher.CleanUp()
+		_ = fetcher.CleanUp()
 	}()
 
-	return e.store.Write(ctx, contents)
+	contents.Name = moduleName
+	localPath, err := e.store.Write(ctx, contents)
+	if err != nil {
+		return "", "", err
+	}
+
+	if contents.Commit != "" {
+		locked := LockedExtension{
+			Name:   moduleName,
+			Ref:    version.Ref,
+			Commit: contents.Commit,
+		}
+		if repoURL != DefaultExtensionRepo {
+			locked.Repo = repoURL
+		}
+		err = WriteLockFile(e.lockPath, lock.With(locked))
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return localPath, contents.Commit, nil
+}
+
+// Update fetches the latest commit for each pinned extension, and pins it
+// in the lockfile. If names is empty, updates all the pinned extensions.
+func (e *Extension) Update(ctx context.Context, names []string) error {
+	lock, err := ReadLockFile(e.lockPath)
+	if err != nil {
+		return err
+	}
+
+	toUpdate := []LockedExtension{}
+	if len(names) == 0 {
+		toUpdate = append(toUpdate, lock.Extensions...)
+	} else {
+		for _, name := range names {
+			locked, ok := lock.Get(name)
+			if !ok {
+				return fmt.Errorf("extension %q is not in %s", name, e.lockPath)
+			}
+			toUpdate = append(toUpdate, locked)
+		}
+	}
+
+	l := logger.Get(ctx)
+	if len(toUpdate) == 0 {
+		l.Infof("No extensions pinned in %s", e.lockPath)
+		return nil
+	}
+
+	for _, locked := range toUpdate {
+		repoURL := locked.Repo
+		if repoURL == "" {
+			repoURL = DefaultExtensionRepo
+		}
+
+		_, commit, err := e.fetchAndLock(ctx, lock, locked.Name, repoURL, pathInRepo(locked), ModuleVersion{Ref: locked.Ref})
+		if err != nil {
+			return errors.Wrapf(err, "updating extension %q", locked.Name)
+		}
+
+		lock, err = ReadLockFile(e.lockPath)
+		if err != nil {
+			return err
+		}
+		if commit == locked.Commit {
+			l.Infof("%s: up to date at %s", locked.Name, shortCommit(commit))
+		} else {
+			l.Infof("%s: %s -> %s", locked.Name, shortCommit(locked.Commit), shortCommit(commit))
+		}
+	}
+	return nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
 }
 
 var _ starkit.LoadInterceptor = (*Extension)(nil)
@@ -3,15 +3,19 @@ package tiltextension
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/internal/tiltfile/include"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
 func TestFetchableAlreadyPresentWorks(t *testing.T) {
@@ -26,6 +30,8 @@ printFoo()
 
 	res := f.assertExecOutput("foo")
 	f.assertLoadRecorded(res, "fetchable")
+	assert.Empty(t, f.fetcher.fetches)
+	f.assertLocked()
 }
 
 func TestUnfetchableAlreadyPresentWorks(t *testing.T) {
@@ -40,6 +46,35 @@ printFoo()
 
 	res := f.assertExecOutput("foo")
 	f.assertLoadRecorded(res, "unfetchable")
+	f.assertLocked()
+}
+
+func TestUnpinnedModuleAlreadyPresentIsPinned(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeModuleInStore("fetchable", "", "cccccc")
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.assertExecOutput("cccccc")
+	assert.Empty(t, f.fetcher.fetches)
+	f.assertLocked(LockedExtension{Name: "fetchable", Commit: "cccccc"})
+}
+
+func TestModuleFetchedWithoutCommitIsRefetched(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeModuleInStore("fetchable", "", "")
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.assertExecOutput("aaaaaa")
+	assert.Equal(t, []ModuleVersion{{}}, f.fetcher.fetches)
+	f.assertLocked(LockedExtension{Name: "fetchable", Commit: "aaaaaa"})
 }
 
 func TestFetchFetchableWorks(t *testing.T) {
@@ -122,25 +157,284 @@ printFoo()
 	f.assertLoadRecorded(res, "unfetchable")
 }
 
+func TestFetchPinsCommit(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.assertExecOutput("aaaaaa")
+	f.assertLocked(LockedExtension{Name: "fetchable", Commit: "aaaaaa"})
+}
+
+func TestFetchRefPinsCommit(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.fetcher.commits = map[string]string{"v1": "111111"}
+	f.tiltfile(`
+load("ext://fetchable@v1", "printFoo")
+printFoo()
+`)
+	res := f.assertExecOutput("111111")
+	f.assertLoadRecorded(res, "fetchable")
+	f.assertLocked(LockedExtension{Name: "fetchable", Ref: "v1", Commit: "111111"})
+}
+
+func TestFetchUsesPinnedCommit(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.fetcher.commits = map[string]string{"": "bbbbbb"}
+	f.writeLockFile(LockedExtension{Name: "fetchable", Commit: "aaaaaa"})
+	f.tiltfile(`
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.assertExecOutput("aaaaaa")
+	assert.Equal(t, []ModuleVersion{{Commit: "aaaaaa"}}, f.fetcher.fetches)
+}
+
+func TestPinnedModuleAlreadyPresentWorks(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLockFile(LockedExtension{Name: "fetchable", Ref: "v1", Commit: "111111"})
+	f.writeModuleInStore("fetchable", "v1", "111111")
+	f.tiltfile(`
+load("ext://fetchable@v1", "printFoo")
+printFoo()
+`)
+	f.assertExecOutput("111111")
+	assert.Empty(t, f.fetcher.fetches)
+}
+
+func TestPinnedModuleAtOtherCommitRefetches(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.writeLockFile(LockedExtension{Name: "fetchable", Ref: "v1", Commit: "111111"})
+	f.writeModuleInStore("fetchable", "v1", "000000")
+	f.tiltfile(`
+load("ext://fetchable@v1", "printFoo")
+printFoo()
+`)
+	f.assertExecOutput("111111")
+	assert.Equal(t, []ModuleVersion{{Ref: "v1", Commit: "111111"}}, f.fetcher.fetches)
+	f.assertLocked(LockedExtension{Name: "fetchable", Ref: "v1", Commit: "111111"})
+}
+
+func TestChangedRefRefetches(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.fetcher.commits = map[string]string{"v2": "222222"}
+	f.writeLockFile(LockedExtension{Name: "fetchable", Ref: "v1", Commit: "111111"})
+	f.writeModuleLocally("fetchable", libText)
+	f.tiltfile(`
+load("ext://fetchable@v2", "printFoo")
+printFoo()
+`)
+	f.assertExecOutput("222222")
+	f.assertLocked(LockedExtension{Name: "fetchable", Ref: "v2", Commit: "222222"})
+}
+
+func TestInvalidRef(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+load("ext://fetchable@", "printFoo")
+`)
+	f.assertError("expected a ref after @")
+}
+
+func TestUpdate(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.fetcher.commits = map[string]string{"v1": "111112"}
+	f.writeLockFile(LockedExtension{Name: "fetchable", Ref: "v1", Commit: "111111"})
+	f.writeModuleLocally("fetchable", libText)
+
+	err := f.ext.Update(f.ctx(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []ModuleVersion{{Ref: "v1"}}, f.fetcher.fetches)
+	f.assertLocked(LockedExtension{Name: "fetchable", Ref: "v1", Commit: "111112"})
+
+	contents, err := ioutil.ReadFile(f.tmp.JoinPath("project", "tilt_modules", "fetchable", "Tiltfile"))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "111112")
+}
+
+func TestUpdateUnknownExtension(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	err := f.ext.Update(f.ctx(), []string{"fetchable"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `extension "fetchable" is not in`)
+}
+
+func TestExtensionRepo(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.fetcher.commits = map[string]string{"v1": "111111"}
+	f.tiltfile(`
+extension_repo('myorg', 'https://github.com/myorg/tilt-extensions', ref='v1')
+load("ext://myorg/fetchable", "printFoo")
+printFoo()
+`)
+	res := f.assertExecOutput("111111")
+	f.assertLoadRecorded(res, "myorg/fetchable")
+	assert.Equal(t, []string{"https://github.com/myorg/tilt-extensions"}, f.fetcher.repos)
+	f.assertLocked(LockedExtension{
+		Name:   "myorg/fetchable",
+		Ref:    "v1",
+		Commit: "111111",
+		Repo:   "https://github.com/myorg/tilt-extensions",
+	})
+
+	repos := MustState(res).Repos
+	require.Len(t, repos, 1)
+	assert.Equal(t, "myorg", repos[0].Name)
+	assert.Equal(t, "v1", repos[0].Spec.Ref)
+	assert.Equal(t, "111111", repos[0].Status.CheckoutRef)
+}
+
+func TestExtensionRepoDoesNotShadowDefaultRepo(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+extension_repo('myorg', 'https://github.com/myorg/tilt-extensions')
+load("ext://fetchable", "printFoo")
+printFoo()
+`)
+	f.assertExecOutput("aaaaaa")
+	assert.Equal(t, []string{DefaultExtensionRepo}, f.fetcher.repos)
+	f.assertLocked(LockedExtension{Name: "fetchable", Commit: "aaaaaa"})
+}
+
+func TestExtensionRepoLocalDir(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.skf.File("exts/helper/Tiltfile", libText)
+	f.tiltfile(`
+extension_repo('local', './exts')
+load("ext://local/helper", "printFoo")
+printFoo()
+`)
+	res := f.assertExecOutput("foo")
+	f.assertLoadRecorded(res, "local/helper")
+
+	// Local extensions are loaded in place, and never fetched or pinned.
+	assert.Empty(t, f.fetcher.repos)
+	f.assertLocked()
+
+	repos := MustState(res).Repos
+	require.Len(t, repos, 1)
+	assert.Equal(t, "file://"+filepath.ToSlash(f.skf.JoinPath("exts")), repos[0].Spec.URL)
+	assert.Equal(t, f.skf.JoinPath("exts"), repos[0].Status.Path)
+}
+
+func TestExtensionRepoLocalDirMissingExtension(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.skf.File("exts/helper/Tiltfile", libText)
+	f.tiltfile(`
+extension_repo('local', './exts')
+load("ext://local/other", "printFoo")
+`)
+	f.assertError(`extension "other" not found in repo "local"`)
+}
+
+func TestExtensionRepoPathOutsideRepo(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.skf.File("exts/helper/Tiltfile", libText)
+	f.skf.File("secret/Tiltfile", libText)
+	f.tiltfile(`
+extension_repo('local', './exts')
+load("ext://local/../secret", "printFoo")
+`)
+	f.assertError("name must be a relative path without . or .. segments")
+}
+
+func TestExtensionRepoInvalidName(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+extension_repo('my/org', 'https://github.com/myorg/tilt-extensions')
+`)
+	f.assertError(`invalid name "my/org"`)
+}
+
+func TestExtensionRepoRedefined(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+extension_repo('myorg', 'https://github.com/myorg/tilt-extensions')
+extension_repo('myorg', 'https://github.com/myorg/tilt-extensions')
+extension_repo('myorg', 'https://github.com/otherorg/tilt-extensions')
+`)
+	f.assertError(`repo "myorg" is already defined`)
+}
+
+func TestUpdateExtensionRepo(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.fetcher.commits = map[string]string{"": "bbbbbb"}
+	f.writeLockFile(LockedExtension{
+		Name:   "myorg/fetchable",
+		Commit: "aaaaaa",
+		Repo:   "https://github.com/myorg/tilt-extensions",
+	})
+
+	err := f.ext.Update(f.ctx(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/myorg/tilt-extensions"}, f.fetcher.repos)
+	f.assertLocked(LockedExtension{
+		Name:   "myorg/fetchable",
+		Commit: "bbbbbb",
+		Repo:   "https://github.com/myorg/tilt-extensions",
+	})
+}
+
 type extensionFixture struct {
-	t   *testing.T
-	skf *starkit.Fixture
-	tmp *tempdir.TempDirFixture
+	t       *testing.T
+	skf     *starkit.Fixture
+	tmp     *tempdir.TempDirFixture
+	ext     *Extension
+	fetcher *fakeFetcher
 }
 
 func newExtensionFixture(t *testing.T) *extensionFixture {
 	tmp := tempdir.NewTempDirFixture(t)
+	fetcher := &fakeFetcher{t: t}
 	ext := NewExtension(
-		&fakeFetcher{t: t},
+		fetcher.forRepo,
 		NewLocalStore(tmp.JoinPath("project")),
+		LockFilePath(tmp.JoinPath("project")),
 	)
 	skf := starkit.NewFixture(t, ext, include.IncludeFn{})
 	skf.UseRealFS()
 
 	return &extensionFixture{
-		t:   t,
-		skf: skf,
-		tmp: tmp,
+		t:       t,
+		skf:     skf,
+		tmp:     tmp,
+		ext:     ext,
+		fetcher: fetcher,
 	}
 }
 
@@ -190,10 +484,37 @@ func (f *extensionFixture) assertNoLoadsRecorded(model starkit.Model) {
 	f.assertLoadRecorded(model)
 }
 
+func (f *extensionFixture) ctx() context.Context {
+	return logger.WithLogger(context.Background(), logger.NewTestLogger(os.Stdout))
+}
+
+func (f *extensionFixture) writeLockFile(exts ...LockedExtension) {
+	f.tmp.MkdirAll("project")
+	err := WriteLockFile(LockFilePath(f.tmp.JoinPath("project")), LockFile{Extensions: exts})
+	require.NoError(f.t, err)
+}
+
+func (f *extensionFixture) assertLocked(expected ...LockedExtension) {
+	lf, err := ReadLockFile(LockFilePath(f.tmp.JoinPath("project")))
+	require.NoError(f.t, err)
+	assert.Equal(f.t, expected, lf.Extensions)
+}
+
 func (f *extensionFixture) writeModuleLocally(name string, contents string) {
 	f.tmp.WriteFile(filepath.Join("project", "tilt_modules", name, "Tiltfile"), contents)
 }
 
+// Writes the module as if it had been fetched at the given commit.
+func (f *extensionFixture) writeModuleInStore(name, ref, commit string) {
+	_, err := NewLocalStore(f.tmp.JoinPath("project")).Write(f.ctx(), ModuleContents{
+		Name:   name,
+		Dir:    dirWithTiltfile(f.t, fmt.Sprintf("%s\nprint(%q)\n", libText, commit)),
+		Ref:    ref,
+		Commit: commit,
+	})
+	require.NoError(f.t, err)
+}
+
 const libText = `
 def printFoo():
   print("foo")
@@ -212,18 +533,39 @@ def printFoo():
 	printBar()
 `
 
+// Serves the "fetchable" module. Each ref's latest commit is in
+// commits (default "aaaaaa"), and prints the commit it was fetched at.
 type fakeFetcher struct {
-	t *testing.T
+	t       *testing.T
+	commits map[string]string
+	fetches []ModuleVersion
+	repos   []string
 }
 
-func (f *fakeFetcher) Fetch(ctx context.Context, moduleName string) (ModuleContents, error) {
+func (f *fakeFetcher) forRepo(repoURL string) Fetcher {
+	f.repos = append(f.repos, repoURL)
+	return f
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, moduleName string, version ModuleVersion) (ModuleContents, error) {
 	if moduleName != "fetchable" {
 		return ModuleContents{}, fmt.Errorf("module %s can't be fetched because... reasons", moduleName)
 	}
+	f.fetches = append(f.fetches, version)
+
+	commit := version.Commit
+	if commit == "" {
+		commit = f.commits[version.Ref]
+	}
+	if commit == "" {
+		commit = "aaaaaa"
+	}
 
 	return ModuleContents{
-		Name: "fetchable",
-		Dir:  dirWithTiltfile(f.t, libText),
+		Name:   "fetchable",
+		Dir:    dirWithTiltfile(f.t, fmt.Sprintf("%s\nprint(%q)\n", libText, commit)),
+		Ref:    version.Ref,
+		Commit: commit,
 	}, nil
 }
 
@@ -0,0 +1,72 @@
+package tiltextension
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const DefaultExtensionRepo = "https://github.com/tilt-dev/tilt-extensions"
+
+// GitFetcher fetches extensions from a git repo, through the RepoCache.
+type GitFetcher struct {
+	cache    *RepoCache
+	repoURL  string
+	tempDirs []string
+}
+
+func NewGitFetcher(cache *RepoCache, repoURL string) *GitFetcher {
+	return &GitFetcher{
+		cache:   cache,
+		repoURL: repoURL,
+	}
+}
+
+func NewGitFetcherFactory(cache *RepoCache) FetcherFactory {
+	return func(repoURL string) Fetcher {
+		return NewGitFetcher(cache, repoURL)
+	}
+}
+
+func (f *GitFetcher) CleanUp() error {
+	var lastErr error
+	for _, dir := range f.tempDirs {
+		err := os.RemoveAll(dir)
+		if err != nil {
+			lastErr = err
+		}
+	}
+	f.tempDirs = nil
+	return lastErr
+}
+
+func (f *GitFetcher) Fetch(ctx context.Context, moduleName string, version ModuleVersion) (ModuleContents, error) {
+	commit, err := f.cache.Resolve(ctx, f.repoURL, version.Ref, version.Commit)
+	if err != nil {
+		return ModuleContents{}, fmt.Errorf("Fetching extensions: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "tilt-extensions")
+	if err != nil {
+		return ModuleContents{}, err
+	}
+	f.tempDirs = append(f.tempDirs, dir)
+
+	err = f.cache.Export(ctx, f.repoURL, commit, moduleName, dir)
+	if err != nil {
+		return ModuleContents{}, fmt.Errorf("Fetching extensions: %v", err)
+	}
+
+	return ModuleContents{
+		Name:              moduleName,
+		Dir:               dir,
+		ExtensionRegistry: f.repoURL,
+		TimeFetched:       time.Now(),
+		Ref:               version.Ref,
+		Commit:            commit,
+	}, nil
+}
+
+var _ Fetcher = (*GitFetcher)(nil)
@@ -0,0 +1,93 @@
+package tiltextension
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// The lockfile lives next to the root Tiltfile, and should be checked in,
+// so that everyone on a team loads the same version of each extension.
+const LockFileName = "tilt_extensions.lock"
+
+func LockFilePath(tiltfileDir string) string {
+	return filepath.Join(tiltfileDir, LockFileName)
+}
+
+// The commit that an extension was resolved to.
+type LockedExtension struct {
+	Name string
+
+	// The ref from the load() statement (e.g., `ext://name@ref`),
+	// or from the extension_repo(). Empty for the default branch.
+	Ref string
+
+	Commit string
+
+	// The URL of the repo the extension came from.
+	// Empty for the default repo.
+	Repo string `json:",omitempty"`
+}
+
+type LockFile struct {
+	Extensions []LockedExtension
+}
+
+// Reads the lockfile at path. A missing lockfile is empty.
+func ReadLockFile(path string) (LockFile, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return LockFile{}, nil
+	} else if err != nil {
+		return LockFile{}, errors.Wrapf(err, "unable to read extension lockfile at path %s", path)
+	}
+
+	var lf LockFile
+	err = json.Unmarshal(b, &lf)
+	if err != nil {
+		return LockFile{}, errors.Wrapf(err, "unable to parse extension lockfile at path %s", path)
+	}
+	return lf, nil
+}
+
+func WriteLockFile(path string, lf LockFile) error {
+	sort.Slice(lf.Extensions, func(i, j int) bool {
+		return lf.Extensions[i].Name < lf.Extensions[j].Name
+	})
+
+	js, err := json.MarshalIndent(lf, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "internal error: unable to marshal lockfile as JSON")
+	}
+
+	err = ioutil.WriteFile(path, append(js, '\n'), 0644)
+	if err != nil {
+		return errors.Wrapf(err, "unable to write extension lockfile at path %s", path)
+	}
+	return nil
+}
+
+func (lf LockFile) Get(name string) (LockedExtension, bool) {
+	for _, e := range lf.Extensions {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return LockedExtension{}, false
+}
+
+// Returns a copy of the lockfile with the given extension added or replaced.
+func (lf LockFile) With(ext LockedExtension) LockFile {
+	result := LockFile{}
+	for _, e := range lf.Extensions {
+		if e.Name != ext.Name {
+			result.Extensions = append(result.Extensions, e)
+		}
+	}
+	result.Extensions = append(result.Extensions, ext)
+	return result
+}
@@ -0,0 +1,201 @@
+package tiltextension
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+const repoCacheDirName = "extensions"
+
+var unsafeRepoDirChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// RepoCache keeps a mirror of each extension repo on disk, so that pinned
+// extensions load without network access, and so that we don't re-download
+// the whole repo every time we load an extension.
+type RepoCache struct {
+	rootDir string
+}
+
+func NewRepoCache(rootDir string) *RepoCache {
+	return &RepoCache{rootDir: rootDir}
+}
+
+// Creates a RepoCache under the Tilt dev dir (usually ~/.tilt-dev).
+func NewTiltDevRepoCache() (*RepoCache, error) {
+	dir, err := dirs.GetTiltDevDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewRepoCache(filepath.Join(dir, repoCacheDirName)), nil
+}
+
+func (c *RepoCache) repoDir(repoURL string) string {
+	name := strings.TrimPrefix(repoURL, "https://")
+	name = strings.TrimSuffix(name, ".git")
+	name = strings.Trim(unsafeRepoDirChars.ReplaceAllString(name, "_"), "_")
+	return filepath.Join(c.rootDir, name)
+}
+
+// Resolve returns the commit that ref points to in the repo at repoURL.
+// An empty ref means the repo's default branch.
+//
+// If pinned is set, it's the commit we expect, e.g., from a lockfile.
+// We only go to the network if we don't already have it.
+//
+// Otherwise, we update the cache from the network first. If that fails,
+// we fall back to what's in the cache, so that Tilt still works offline.
+func (c *RepoCache) Resolve(ctx context.Context, repoURL, ref, pinned string) (string, error) {
+	dir := c.repoDir(repoURL)
+	_, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		err = c.clone(ctx, repoURL, dir)
+		if err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	} else if pinned != "" && c.hasCommit(ctx, dir, pinned) {
+		return pinned, nil
+	} else {
+		_, err := git(ctx, dir, "remote", "update", "--prune")
+		if err != nil {
+			logger.Get(ctx).Warnf("Unable to update extensions from %s, using cached copy: %v", repoURL, err)
+		}
+	}
+
+	if pinned != "" {
+		if !c.hasCommit(ctx, dir, pinned) {
+			return "", fmt.Errorf("commit %s not found in %s", pinned, repoURL)
+		}
+		return pinned, nil
+	}
+
+	rev := ref
+	if rev == "" {
+		rev = "HEAD"
+	}
+	out, err := git(ctx, dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("ref %q not found in %s", ref, repoURL)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Export copies the contents of subdir at the given commit to dest.
+func (c *RepoCache) Export(ctx context.Context, repoURL, commit, subdir, dest string) error {
+	cmd := exec.CommandContext(ctx, "git", "archive", "--format=tar", commit, subdir)
+	cmd.Dir = c.repoDir(repoURL)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("%s not found in %s at %s: %s", subdir, repoURL, commit, strings.TrimSpace(stderr.String()))
+	}
+	return untarDir(bytes.NewReader(out), subdir, dest)
+}
+
+func (c *RepoCache) hasCommit(ctx context.Context, dir, commit string) bool {
+	_, err := git(ctx, dir, "cat-file", "-e", commit+"^{commit}")
+	return err == nil
+}
+
+// Clones to a temp dir first, so that an interrupted clone doesn't
+// leave a broken mirror behind.
+func (c *RepoCache) clone(ctx context.Context, repoURL, dir string) error {
+	err := os.MkdirAll(c.rootDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempDir(c.rootDir, "clone")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmp)
+	}()
+
+	_, err = git(ctx, tmp, "clone", "--quiet", "--mirror", repoURL, "repo")
+	if err != nil {
+		return errors.Wrapf(err, "Fetching extensions from %s", repoURL)
+	}
+	return os.Rename(filepath.Join(tmp, "repo"), dir)
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", err
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return string(out), nil
+}
+
+// Extracts the files under prefix in the tarball to dest.
+func untarDir(r io.Reader, prefix, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(prefix, filepath.FromSlash(header.Name))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		p := filepath.Join(dest, rel)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(p, 0755)
+		case tar.TypeReg:
+			err = writeFile(p, tr, os.FileMode(header.Mode).Perm())
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeFile(p string, r io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
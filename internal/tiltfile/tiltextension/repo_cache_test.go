@@ -0,0 +1,128 @@
+package tiltextension
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestRepoCacheResolvesDefaultBranch(t *testing.T) {
+	f := newRepoCacheFixture(t)
+	first := f.commit("fetchable/Tiltfile", "print('v1')")
+
+	commit, err := f.cache.Resolve(f.ctx, f.repoURL, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, first, commit)
+
+	second := f.commit("fetchable/Tiltfile", "print('v2')")
+	commit, err = f.cache.Resolve(f.ctx, f.repoURL, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, second, commit)
+}
+
+func TestRepoCacheResolvesTag(t *testing.T) {
+	f := newRepoCacheFixture(t)
+	first := f.commit("fetchable/Tiltfile", "print('v1')")
+	f.git("tag", "v1")
+	f.commit("fetchable/Tiltfile", "print('v2')")
+
+	commit, err := f.cache.Resolve(f.ctx, f.repoURL, "v1", "")
+	require.NoError(t, err)
+	assert.Equal(t, first, commit)
+
+	_, err = f.cache.Resolve(f.ctx, f.repoURL, "v2", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `ref "v2" not found`)
+}
+
+func TestRepoCachePinnedCommitWorksOffline(t *testing.T) {
+	f := newRepoCacheFixture(t)
+	first := f.commit("fetchable/Tiltfile", "print('v1')")
+
+	_, err := f.cache.Resolve(f.ctx, f.repoURL, "", "")
+	require.NoError(t, err)
+
+	// Take the upstream repo away.
+	require.NoError(t, os.RemoveAll(f.repo))
+
+	commit, err := f.cache.Resolve(f.ctx, f.repoURL, "", first)
+	require.NoError(t, err)
+	assert.Equal(t, first, commit)
+
+	// An unpinned ref falls back to the cache.
+	commit, err = f.cache.Resolve(f.ctx, f.repoURL, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, first, commit)
+}
+
+func TestRepoCacheExport(t *testing.T) {
+	f := newRepoCacheFixture(t)
+	first := f.commit("fetchable/Tiltfile", "print('v1')")
+	f.commit("fetchable/Tiltfile", "print('v2')")
+
+	_, err := f.cache.Resolve(f.ctx, f.repoURL, "", "")
+	require.NoError(t, err)
+
+	dest := f.JoinPath("dest")
+	err = f.cache.Export(f.ctx, f.repoURL, first, "fetchable", dest)
+	require.NoError(t, err)
+
+	contents, err := ioutil.ReadFile(filepath.Join(dest, "Tiltfile"))
+	require.NoError(t, err)
+	assert.Equal(t, "print('v1')", string(contents))
+
+	err = f.cache.Export(f.ctx, f.repoURL, first, "unfetchable", f.JoinPath("dest2"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unfetchable not found")
+}
+
+type repoCacheFixture struct {
+	*tempdir.TempDirFixture
+	ctx     context.Context
+	cache   *RepoCache
+	repo    string
+	repoURL string
+}
+
+func newRepoCacheFixture(t *testing.T) *repoCacheFixture {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	tmp := tempdir.NewTempDirFixture(t)
+	t.Cleanup(tmp.TearDown)
+
+	f := &repoCacheFixture{
+		TempDirFixture: tmp,
+		ctx:            logger.WithLogger(context.Background(), logger.NewTestLogger(os.Stdout)),
+		cache:          NewRepoCache(tmp.JoinPath("cache")),
+		repo:           tmp.JoinPath("repo"),
+		repoURL:        "file://" + filepath.ToSlash(tmp.JoinPath("repo")),
+	}
+	f.MkdirAll("repo")
+	f.git("init", "--quiet")
+	return f
+}
+
+func (f *repoCacheFixture) commit(path, contents string) string {
+	f.WriteFile(filepath.Join("repo", path), contents)
+	f.git("add", "-A")
+	f.git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "update")
+	return strings.TrimSpace(f.git("rev-parse", "HEAD"))
+}
+
+func (f *repoCacheFixture) git(args ...string) string {
+	out, err := git(f.ctx, f.repo, args...)
+	require.NoError(f.T(), err)
+	return out
+}
@@ -0,0 +1,114 @@
+package tiltextension
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Creates a Fetcher for the repo at the given URL.
+type FetcherFactory func(repoURL string) Fetcher
+
+// Where to load an extension from.
+type ResolvedModule struct {
+	// The name of the extension_repo() that the extension lives in.
+	// Empty for the default repo.
+	RepoName string
+	RepoURL  string
+
+	// The path of the extension within its repo.
+	Path string
+
+	// The ref to fetch, unless the load() asks for a specific one.
+	Ref string
+
+	// For repos on local disk, the repo's directory.
+	//
+	// We load extensions from local repos in place, rather than copying them,
+	// so that edits show up the next time the Tiltfile loads.
+	LocalDir string
+}
+
+// A Resolver finds the repo that an extension lives in.
+type Resolver interface {
+	// Returns false if the extension isn't in this resolver's repo.
+	Resolve(moduleName string) (ResolvedModule, bool)
+}
+
+// A ResolverChain asks each of its resolvers in turn, and returns the
+// first match.
+type ResolverChain []Resolver
+
+func (c ResolverChain) Resolve(moduleName string) (ResolvedModule, bool) {
+	for _, r := range c {
+		resolved, ok := r.Resolve(moduleName)
+		if ok {
+			return resolved, true
+		}
+	}
+	return ResolvedModule{}, false
+}
+
+// Resolves `ext://name/path` to path in the repo declared with
+// extension_repo(name, ...) earlier in the Tiltfile.
+type repoResolver struct {
+	repo *v1alpha1.ExtensionRepo
+}
+
+func (r repoResolver) Resolve(moduleName string) (ResolvedModule, bool) {
+	prefix := r.repo.Name + "/"
+	if !strings.HasPrefix(moduleName, prefix) {
+		return ResolvedModule{}, false
+	}
+
+	// Make sure the extension can't point outside the repo.
+	p := path.Clean(strings.TrimPrefix(moduleName, prefix))
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") || path.IsAbs(p) {
+		return ResolvedModule{}, false
+	}
+
+	resolved := ResolvedModule{
+		RepoName: r.repo.Name,
+		RepoURL:  r.repo.Spec.URL,
+		Path:     p,
+		Ref:      r.repo.Spec.Ref,
+	}
+	if dir, ok := localRepoDir(r.repo.Spec.URL); ok {
+		resolved.LocalDir = dir
+	}
+	return resolved, true
+}
+
+// Resolves every extension to the tilt-extensions repo.
+type defaultResolver struct{}
+
+func (defaultResolver) Resolve(moduleName string) (ResolvedModule, bool) {
+	return ResolvedModule{
+		RepoURL: DefaultExtensionRepo,
+		Path:    moduleName,
+	}, true
+}
+
+// Returns the directory of a file:// repo URL.
+func localRepoDir(repoURL string) (string, bool) {
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
+
+// The path of a pinned extension within its repo.
+//
+// Extensions from the default repo are named by their path. Extensions from
+// other repos are prefixed with the name of the repo.
+func pathInRepo(locked LockedExtension) string {
+	if locked.Repo == "" || locked.Repo == DefaultExtensionRepo {
+		return locked.Name
+	}
+	i := strings.Index(locked.Name, "/")
+	return locked.Name[i+1:]
+}
@@ -20,6 +20,12 @@ type Store interface {
 	// ModulePath is used to check if an extension exists before fetching it
 	// Returns ErrNotExist if module doesn't exist
 	ModulePath(ctx context.Context, moduleName string) (string, error)
+
+	// ModuleMetadata returns what was recorded when the extension was written.
+	// Returns ok=false if nothing was recorded, e.g., the module was copied
+	// into the store by hand.
+	ModuleMetadata(ctx context.Context, moduleName string) (metadata Metadata, ok bool, err error)
+
 	Write(ctx context.Context, contents ModuleContents) (string, error)
 }
 
@@ -29,13 +35,22 @@ type ModuleContents struct {
 	ExtensionRegistry string
 	TimeFetched       time.Time
 
-	// NOTE(nick): Currently this is missing any kind of integrity hashing or versioning.
-	// This used to have a non-functional versioning stub, which we deleted in
-	// https://github.com/tilt-dev/tilt/pull/3779
-	//
-	// If we decide to add versioning semantics, we should try to adapt something from
-	// an existing implementation (e.g., go-get's versioning sematnics) rather
-	// than trying to implement versioning from scratch.
+	// The ref that was requested, and the commit it resolved to.
+	// We pin extensions to a commit in the lockfile, rather than
+	// implementing any kind of semver resolution.
+	Ref    string
+	Commit string
+}
+
+// Which version of an extension to fetch.
+type ModuleVersion struct {
+	// A branch, tag, or commit in the extension repo.
+	// Empty means the repo's default branch.
+	Ref string
+
+	// The exact commit to fetch, e.g., from the lockfile. If empty, fetches
+	// the latest commit of Ref.
+	Commit string
 }
 
 type LocalStore struct {
@@ -46,6 +61,8 @@ type Metadata struct {
 	Name              string
 	ExtensionRegistry string
 	TimeFetched       time.Time
+	Ref               string `json:",omitempty"`
+	Commit            string `json:",omitempty"`
 }
 
 type MetadataFile struct {
@@ -69,13 +86,46 @@ func (s *LocalStore) ModulePath(ctx context.Context, moduleName string) (string,
 	return tiltfilePath, nil
 }
 
+func (s *LocalStore) ModuleMetadata(ctx context.Context, moduleName string) (Metadata, bool, error) {
+	metadataFile, err := s.readMetadataFile()
+	if err != nil {
+		return Metadata{}, false, err
+	}
+	for _, e := range metadataFile.Extensions {
+		if e.Name == moduleName {
+			return e, true, nil
+		}
+	}
+	return Metadata{}, false, nil
+}
+
+// Reads the metadata file. A missing metadata file is empty.
+func (s *LocalStore) readMetadataFile() (MetadataFile, error) {
+	extensionMetadataFilePath := filepath.Join(s.baseDir, metadataFileName)
+	b, err := ioutil.ReadFile(extensionMetadataFilePath)
+	if os.IsNotExist(err) {
+		return MetadataFile{}, nil
+	} else if err != nil {
+		return MetadataFile{}, errors.Wrapf(err, "unable to open extension metadata file at path %s", extensionMetadataFilePath)
+	}
+
+	var metadataFile MetadataFile
+	err = json.Unmarshal(b, &metadataFile)
+	if err != nil {
+		return MetadataFile{}, errors.Wrapf(err, "Unable to unmarshal metadata file at path %s", extensionMetadataFilePath)
+	}
+	return metadataFile, nil
+}
+
 // TODO(dmiller): handle atomic writes to the metadata file and the modules?
 // Right now if a write to the metadata file fails the module will still be written
 
-// TODO(dmiller): should this error if we try to write an extension with the same name as
-// one that already exists?
+// If an extension with the same name already exists, it's replaced.
 func (s *LocalStore) Write(ctx context.Context, contents ModuleContents) (string, error) {
 	moduleDir := filepath.Join(s.baseDir, contents.Name)
+	if err := os.RemoveAll(moduleDir); err != nil {
+		return "", errors.Wrapf(err, "couldn't remove old module directory %s at path %s", contents.Name, moduleDir)
+	}
 	if err := os.MkdirAll(moduleDir, os.FileMode(0700)); err != nil {
 		return "", errors.Wrapf(err, "couldn't create module directory %s at path %s", contents.Name, moduleDir)
 	}
@@ -89,31 +139,29 @@ func (s *LocalStore) Write(ctx context.Context, contents ModuleContents) (string
 		Name:              contents.Name,
 		ExtensionRegistry: contents.ExtensionRegistry,
 		TimeFetched:       contents.TimeFetched,
+		Ref:               contents.Ref,
+		Commit:            contents.Commit,
 	}
 
 	// read file if it exists, append extension, write out the file
-	var metadataFile MetadataFile
-	extensionMetadataFilePath := filepath.Join(s.baseDir, metadataFileName)
-	b, err := ioutil.ReadFile(extensionMetadataFilePath)
-	if os.IsNotExist(err) {
-		metadataFile = MetadataFile{
-			Extensions: []Metadata{metadata},
-		}
-	} else if err != nil {
-		return "", errors.Wrapf(err, "unable to open extension metadata file at path %s", extensionMetadataFilePath)
-	} else {
-		err = json.Unmarshal(b, &metadataFile)
-		if err != nil {
-			return "", errors.Wrapf(err, "Unable to unmarshal metadata file at path %s", extensionMetadataFilePath)
+	metadataFile, err := s.readMetadataFile()
+	if err != nil {
+		return "", err
+	}
+	extensions := []Metadata{}
+	for _, e := range metadataFile.Extensions {
+		if e.Name != metadata.Name {
+			extensions = append(extensions, e)
 		}
-		metadataFile.Extensions = append(metadataFile.Extensions, metadata)
 	}
+	metadataFile.Extensions = append(extensions, metadata)
 
 	js, err := json.MarshalIndent(metadataFile, "", "  ")
 	if err != nil {
 		return "", errors.Wrap(err, "internal error: unable to marshal metadataFile as JSON")
 	}
 
+	extensionMetadataFilePath := filepath.Join(s.baseDir, metadataFileName)
 	err = ioutil.WriteFile(extensionMetadataFilePath, js, 0600)
 	if err != nil {
 		return "", errors.Wrapf(err, "unable to write extension metadata file at path %s", extensionMetadataFilePath)
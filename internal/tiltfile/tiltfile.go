@@ -25,6 +25,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/k8scontext"
 	"github.com/tilt-dev/tilt/internal/tiltfile/metrics"
+	"github.com/tilt-dev/tilt/internal/tiltfile/secrets"
 	"github.com/tilt-dev/tilt/internal/tiltfile/secretsettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/telemetry"
@@ -32,6 +33,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 	"github.com/tilt-dev/tilt/internal/tiltfile/version"
 	"github.com/tilt-dev/tilt/internal/tiltfile/watch"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -52,20 +54,36 @@ type TiltfileLoadResult struct {
 	VersionSettings     model.VersionSettings
 	UpdateSettings      model.UpdateSettings
 	WatchSettings       model.WatchSettings
+	ExtensionRepos      []*v1alpha1.ExtensionRepo
+	UserConfigSettings  []model.UserConfigSetting
 
 	// For diagnostic purposes only
 	BuiltinCalls []starkit.BuiltinCall `json:"-"`
 }
 
+// Returns the primary orchestrator of the loaded manifests.
+//
+// If the Tiltfile has both K8s and DC resources, K8s wins.
 func (r TiltfileLoadResult) Orchestrator() model.Orchestrator {
+	result := model.OrchestratorUnknown
 	for _, manifest := range r.Manifests {
 		if manifest.IsK8s() {
 			return model.OrchestratorK8s
 		} else if manifest.IsDC() {
-			return model.OrchestratorDC
+			result = model.OrchestratorDC
 		}
 	}
-	return model.OrchestratorUnknown
+	return result
+}
+
+// Returns true if any of the loaded manifests is a Docker Compose service.
+func (r TiltfileLoadResult) HasDockerCompose() bool {
+	for _, manifest := range r.Manifests {
+		if manifest.IsDC() {
+			return true
+		}
+	}
+	return false
 }
 
 type TiltfileLoader interface {
@@ -198,6 +216,8 @@ func (tfl tiltfileLoader) Load(ctx context.Context, filename string, userConfigS
 	tlr.AnalyticsOpt = aSettings.Opt
 
 	tlr.Secrets = s.extractSecrets()
+	tiltfileSecrets, _ := secrets.GetState(result)
+	tlr.Secrets.AddAll(tiltfileSecrets)
 	tlr.FeatureFlags = s.features.ToEnabled()
 	tlr.Error = err
 	tlr.Manifests = manifests
@@ -215,11 +235,15 @@ func (tfl tiltfileLoader) Load(ctx context.Context, filename string, userConfigS
 	us, _ := updatesettings.GetState(result)
 	tlr.UpdateSettings = us
 
+	cs, _ := config.GetState(result)
+	tlr.UserConfigSettings = cs.UserConfigSettings()
+
 	duration := time.Since(start)
 	if tlr.Error == nil {
 		s.logger.Infof("Successfully loaded Tiltfile (%s)", duration)
 	}
 	extState, _ := tiltextension.GetState(result)
+	tlr.ExtensionRepos = extState.Repos
 	tfl.reportTiltfileLoaded(s.builtinCallCounts, s.builtinArgCounts, duration, extState.ExtsLoaded)
 	reportTiltfileExecMetrics(ctx, duration, err != nil)
 
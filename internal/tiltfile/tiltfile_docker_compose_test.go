@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -227,7 +228,31 @@ docker_compose('docker-compose2.yml')`
 	assert.Equal(t, 2, len(f.loadResult.Manifests))
 }
 
-func TestDockerComposeAndK8sNotSupported(t *testing.T) {
+func TestDockerComposeAndK8s(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("docker-compose.yml", barServiceConfig)
+	tf := `docker_compose('docker-compose.yml')
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+dc_resource('bar', resource_deps=['foo'])`
+	f.file("Tiltfile", tf)
+
+	f.load()
+
+	foo := f.assertNextManifest("foo", db(image("gcr.io/foo")), deployment("foo"))
+	assert.True(t, foo.IsK8s())
+	bar := f.assertNextManifest("bar", resourceDeps("foo"))
+	assert.True(t, bar.IsDC())
+	f.assertNoMoreManifests()
+
+	assert.Equal(t, model.OrchestratorK8s, f.loadResult.Orchestrator())
+	assert.True(t, f.loadResult.HasDockerCompose())
+}
+
+func TestDockerComposeAndK8sDuplicateName(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
@@ -238,8 +263,46 @@ docker_build('gcr.io/foo', 'foo')
 k8s_yaml('foo.yaml')`
 	f.file("Tiltfile", tf)
 
-	f.loadErrString("can't declare both k8s " +
-		"resources/entities and docker-compose resources")
+	f.loadErrString(`resource "foo" is declared more than once (as a Kubernetes resource and as a Docker Compose resource)`)
+}
+
+func TestDockerComposeAndK8sRenamed(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("docker-compose.yml", simpleConfig)
+	tf := `docker_compose('docker-compose.yml')
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', new_name='foo-k8s')`
+	f.file("Tiltfile", tf)
+
+	f.load()
+
+	f.assertNextManifest("foo-k8s", db(image("gcr.io/foo")), deployment("foo"))
+	f.assertNextManifest("foo")
+	f.assertNoMoreManifests()
+}
+
+func TestDefaultRegistryWithDockerComposeAndK8s(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("docker-compose.yml", barServiceConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+default_registry('bar.com')
+`)
+
+	f.load()
+
+	f.assertNextManifest("foo", db(image("gcr.io/foo").withLocalRef("bar.com/gcr.io_foo")))
+	f.assertNextManifest("bar")
+	f.assertNoMoreManifests()
 }
 
 func TestDockerComposeResourceCreationFromAbsPath(t *testing.T) {
@@ -716,6 +779,170 @@ dc_resource('bar', resource_deps=['foo'])
 	f.assertNextManifest("bar", resourceDeps("foo"))
 }
 
+func TestDCResourceReadinessProbe(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', readiness_probe=probe(tcp_socket=tcp_socket_action(12312)))
+`)
+
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, &v1alpha1.Probe{
+		Handler: v1alpha1.Handler{
+			TCPSocket: &v1alpha1.TCPSocketAction{Port: 12312},
+		},
+	}, m.DockerComposeTarget().ReadinessProbe)
+}
+
+func TestDCResourceLiveUpdate(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', live_update=[
+  fall_back_on('foo/requirements.txt'),
+  sync('foo', '/app'),
+  run('echo hi'),
+])
+`)
+
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Empty(t, m.ImageTargets)
+
+	lu := m.DockerComposeTarget().LiveUpdateInfo()
+	assert.Equal(t, []model.Sync{
+		{LocalPath: f.JoinPath("foo"), ContainerPath: "/app"},
+	}, lu.SyncSteps())
+	assert.Equal(t, []string{f.JoinPath("foo", "requirements.txt")}, lu.FallBackOnFiles().Paths)
+	assert.Len(t, lu.RunSteps(), 1)
+}
+
+func TestDCResourceLiveUpdateRequiresBuild(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("docker-compose.yml", barServiceConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('bar', live_update=[sync('.', '/app')])
+`)
+
+	f.loadErrString(`live_update requires that service "bar" have a`)
+}
+
+func TestDCResourceLiveUpdateWithDockerBuild(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', './foo')
+docker_compose('docker-compose.yml')
+dc_resource('foo', 'gcr.io/foo', live_update=[sync('foo', '/app')])
+`)
+
+	f.loadErrString("pass live_update to its docker_build() instead")
+}
+
+func TestDCResourcePortForwards(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', port_forwards=[port_forward(12312, name='web', link_path='/status')])
+`)
+
+	f.load("foo")
+	f.assertDcManifest("foo",
+		dcPublishedPorts(12312),
+		dcPortForwards(model.MustPortForward(12312, 80, "", "web", "/status")),
+	)
+}
+
+func TestDCResourcePortForwardNotPublished(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', port_forwards=8000)
+`)
+
+	f.loadErrString("service does not publish port 8000 (published ports: 12312)")
+}
+
+func TestDCResourcePortForwardWrongContainerPort(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', port_forwards='12312:8080')
+`)
+
+	f.loadErrString("service publishes port 12312 to container port 80, not 8080")
+}
+
+func TestDockerComposePublishedPortConflict(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", `version: '3'
+services:
+  foo:
+    build: ./foo
+    ports:
+      - "12312:80"
+  bar:
+    image: bar-image
+    ports:
+      - "12312:3000"
+`)
+	f.file("Tiltfile", "docker_compose('docker-compose.yml')")
+
+	f.loadErrString(`both publish port 12312`)
+}
+
+func TestDockerComposePublishedPortConflictsWithK8sPortForward(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("docker-compose.yml", `version: '3'
+services:
+  bar:
+    image: bar-image
+    ports:
+      - "12312:3000"
+`)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', port_forwards=12312)
+`)
+
+	f.loadErrString(`k8s_resource "foo" forwards local port 12312, but docker-compose service "bar" already publishes port 12312`)
+}
+
 func (f *fixture) assertDcManifest(name model.ManifestName, opts ...interface{}) model.Manifest {
 	m := f.assertNextManifest(name)
 
@@ -736,6 +963,8 @@ func (f *fixture) assertDcManifest(name model.ManifestName, opts ...interface{})
 			assert.Equal(f.t, strings.TrimSpace(opt.df), strings.TrimSpace(string(dcInfo.DfRaw)), "docker compose Dockerfile raw")
 		case dcPublishedPortsHelper:
 			assert.Equal(f.t, opt.ports, dcInfo.PublishedPorts(), "docker compose published ports")
+		case dcPortForwardsHelper:
+			assert.Equal(f.t, opt.pfs, dcInfo.PortForwards, "docker compose port forwards")
 		default:
 			f.t.Fatalf("unexpected arg to assertDcManifest: %T %v", opt, opt)
 		}
@@ -782,3 +1011,11 @@ type dcPublishedPortsHelper struct {
 func dcPublishedPorts(ports ...int) dcPublishedPortsHelper {
 	return dcPublishedPortsHelper{ports: ports}
 }
+
+type dcPortForwardsHelper struct {
+	pfs []model.PortForward
+}
+
+func dcPortForwards(pfs ...model.PortForward) dcPortForwardsHelper {
+	return dcPortForwardsHelper{pfs: pfs}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/loaddynamic"
 	"github.com/tilt-dev/tilt/internal/tiltfile/metrics"
 	"github.com/tilt-dev/tilt/internal/tiltfile/os"
+	"github.com/tilt-dev/tilt/internal/tiltfile/secrets"
 	"github.com/tilt-dev/tilt/internal/tiltfile/secretsettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/shlex"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
@@ -181,11 +182,11 @@ func (s *tiltfileState) loadManifests(absFilename string, userConfigState model.
 
 	s.configExt.UserConfigState = userConfigState
 
-	dlr, err := tiltextension.NewTempDirDownloader()
+	repoCache, err := tiltextension.NewTiltDevRepoCache()
 	if err != nil {
 		return nil, starkit.Model{}, err
 	}
-	fetcher := tiltextension.NewGithubFetcher(dlr)
+	tiltfileDir := filepath.Dir(absFilename)
 
 	result, err := starkit.ExecFile(absFilename,
 		s,
@@ -204,11 +205,12 @@ func (s *tiltfileState) loadManifests(absFilename string, userConfigState model.
 		metrics.NewExtension(),
 		updatesettings.NewExtension(),
 		secretsettings.NewExtension(),
+		secrets.NewExtension(),
 		encoding.NewExtension(),
 		shlex.NewExtension(),
 		watch.NewExtension(),
 		loaddynamic.NewExtension(),
-		tiltextension.NewExtension(fetcher, tiltextension.NewLocalStore(filepath.Dir(absFilename))),
+		tiltextension.NewExtension(tiltextension.NewGitFetcherFactory(repoCache), tiltextension.NewLocalStore(tiltfileDir), tiltextension.LockFilePath(tiltfileDir)),
 		links.NewExtension(),
 		print.NewExtension(),
 		probe.NewExtension(),
@@ -247,11 +249,14 @@ If you're sure you want to deploy there, add:
 	allow_k8s_contexts('%s')
 to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext, kubeContext)
 		}
-	} else {
-		manifests, err = s.translateDC(resources.dc)
+	}
+
+	if !resources.dc.Empty() {
+		dcManifests, err := s.translateDC(resources.dc)
 		if err != nil {
 			return nil, result, err
 		}
+		manifests = append(manifests, dcManifests...)
 	}
 
 	err = s.validateLiveUpdatesForManifests(manifests)
@@ -285,6 +290,11 @@ to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext
 		manifests = append(manifests, yamlManifest)
 	}
 
+	err = validateUniqueManifestNames(manifests)
+	if err != nil {
+		return nil, starkit.Model{}, err
+	}
+
 	err = validateResourceDependencies(manifests)
 	if err != nil {
 		return nil, starkit.Model{}, err
@@ -318,6 +328,7 @@ const (
 	k8sYamlN                    = "k8s_yaml"
 	filterYamlN                 = "filter_yaml"
 	k8sResourceN                = "k8s_resource"
+	k8sCustomDeployN            = "k8s_custom_deploy"
 	portForwardN                = "port_forward"
 	k8sKindN                    = "k8s_kind"
 	k8sImageJSONPathN           = "k8s_image_json_path"
@@ -498,6 +509,7 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{k8sYamlN, s.k8sYaml},
 		{filterYamlN, s.filterYaml},
 		{k8sResourceN, s.k8sResource},
+		{k8sCustomDeployN, s.k8sCustomDeploy},
 		{localResourceN, s.localResource},
 		{testN, s.localResource}, // test is just a fork of local resource, w/ some switches based on fn.Name()
 		{portForwardN, s.portForward},
@@ -540,10 +552,11 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 
 // Returns the current orchestrator.
 //
-// Note that assemble() will eventually error out if this has
-// both DC and K8s resources.
+// A Tiltfile may declare both DC and K8s resources. In that case, K8s is
+// considered the primary orchestrator (e.g., for registry and docker client
+// selection), and DC resources talk to the local Docker daemon.
 func (s *tiltfileState) orchestrator() model.Orchestrator {
-	if !s.dc.Empty() {
+	if !s.dc.Empty() && len(s.k8s) == 0 && len(s.k8sUnresourced) == 0 {
 		return model.OrchestratorDC
 	}
 	return model.OrchestratorK8s
@@ -565,11 +578,6 @@ func (s *tiltfileState) assemble() (resourceSet, []k8s.K8sEntity, error) {
 		return resourceSet{}, nil, err
 	}
 
-	if !s.dc.Empty() && (len(s.k8s) > 0 || len(s.k8sUnresourced) > 0) {
-		return resourceSet{}, nil, fmt.Errorf("can't declare both k8s " +
-			"resources/entities and docker-compose resources")
-	}
-
 	err = s.assertAllImagesMatched()
 	if err != nil {
 		s.logger.Warnf("%s", err.Error())
@@ -611,7 +619,9 @@ func (s *tiltfileState) assertAllImagesMatched() error {
 	}
 
 	configType := "Kubernetes"
-	if len(s.dc.services) > 0 {
+	if len(s.dc.services) > 0 && len(s.k8s) > 0 {
+		configType = "Kubernetes or Docker Compose"
+	} else if len(s.dc.services) > 0 {
 		configType = "Docker Compose"
 	}
 	return s.buildIndex.unmatchedImageWarning(unmatchedImages[0], configType)
@@ -641,7 +651,9 @@ func (s *tiltfileState) assembleImages() error {
 }
 
 func (s *tiltfileState) assembleDC() error {
-	if len(s.dc.services) > 0 && !s.defaultReg.Empty() {
+	// default_registry only applies to images deployed to Kubernetes,
+	// so only complain if there's nothing for it to apply to.
+	if len(s.dc.services) > 0 && len(s.k8s) == 0 && !s.defaultReg.Empty() {
 		return errors.New("default_registry is not supported with docker compose")
 	}
 
@@ -656,6 +668,40 @@ func (s *tiltfileState) assembleDC() error {
 			//  b. there is no img ref from config, and img ref from user is not of form .*_<svc_name>
 		}
 	}
+	return s.validateDCPublishedPorts()
+}
+
+// docker-compose only notices that two services publish the same port when it
+// fails to start the second one, so catch the conflict when we load the Tiltfile.
+func (s *tiltfileState) validateDCPublishedPorts() error {
+	type owner struct {
+		svc  string
+		port dockercompose.Port
+	}
+	var seen []owner
+	for _, svc := range s.dc.services {
+		for _, p := range svc.Ports {
+			for _, o := range seen {
+				if o.port.ConflictsWith(p) {
+					return fmt.Errorf("docker-compose services %q and %q both publish port %s",
+						o.svc, svc.Name, p)
+				}
+			}
+			seen = append(seen, owner{svc: svc.Name, port: p})
+		}
+	}
+
+	for _, r := range s.k8s {
+		for _, pf := range r.portForwards {
+			for _, o := range seen {
+				if o.port.ConflictsWith(dockercompose.Port{Published: pf.LocalPort, HostIP: pf.Host}) {
+					return fmt.Errorf("k8s_resource %q forwards local port %d, "+
+						"but docker-compose service %q already publishes port %s",
+						r.name, pf.LocalPort, o.svc, o.port)
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -696,11 +742,14 @@ func (s *tiltfileState) assembleK8s() error {
 		if r, ok := s.k8sByName[workload]; ok {
 			r.extraPodSelectors = opts.extraPodSelectors
 			r.podReadinessMode = opts.podReadinessMode
+			r.readinessProbe = opts.readinessProbe
+			r.serverSideApply = opts.serverSideApply
 			r.portForwards = opts.portForwards
 			r.triggerMode = opts.triggerMode
 			r.autoInit = opts.autoInit
 			r.resourceDeps = opts.resourceDeps
 			r.links = opts.links
+			r.labels = opts.labels
 			if opts.newName != "" && opts.newName != r.name {
 				if _, ok := s.k8sByName[opts.newName]; ok {
 					return fmt.Errorf("k8s_resource at %s specified to rename %q to %q, but there already exists a resource with that name", opts.tiltfilePosition.String(), r.name, opts.newName)
@@ -913,7 +962,7 @@ func (s *tiltfileState) assembleK8sUnresourced() error {
 }
 
 func (s *tiltfileState) validateK8s(r *k8sResource) error {
-	if len(r.entities) == 0 {
+	if len(r.entities) == 0 && r.customDeploy == nil {
 		return fmt.Errorf("resource %q: could not associate any k8s YAML with this resource", r.name)
 	}
 
@@ -1030,7 +1079,7 @@ func (s *tiltfileState) translateK8s(resources []*k8sResource, updateSettings mo
 			Name:                 mn,
 			TriggerMode:          tm,
 			ResourceDependencies: mds,
-		}
+		}.WithLabels(r.labels)
 
 		k8sTarget, err := k8s.NewTarget(mn.TargetName(), r.entities,
 			s.defaultedPortForwards(r.portForwards), r.extraPodSelectors,
@@ -1040,13 +1089,23 @@ func (s *tiltfileState) translateK8s(resources []*k8sResource, updateSettings mo
 			return nil, err
 		}
 
-		m = m.WithDeployTarget(k8sTarget)
-
 		iTargets, err := s.imgTargetsForDependencyIDs(r.dependencyIDs, registry)
 		if err != nil {
 			return nil, errors.Wrapf(err, "getting image build info for %s", r.name)
 		}
 
+		if r.customDeploy != nil {
+			if r.serverSideApply {
+				return nil, fmt.Errorf("k8s_resource %q: server_side_apply can't be used with k8s_custom_deploy", r.name)
+			}
+			k8sTarget = r.customDeploy.applyToTarget(k8sTarget, len(iTargets) > 0)
+		}
+		k8sTarget.ServerSideApply = r.serverSideApply
+
+		k8sTarget = k8sTarget.WithReadinessProbe(r.readinessProbe)
+
+		m = m.WithDeployTarget(k8sTarget)
+
 		m = m.WithImageTargets(iTargets)
 
 		result = append(result, m)
@@ -1260,6 +1319,10 @@ func (s *tiltfileState) imgTargetsForDependencyIDsHelper(ids []model.TargetID, c
 				CacheFrom:   image.cacheFrom,
 				PullParent:  image.pullParent,
 				ExtraTags:   image.extraTags,
+				RemoteCache: image.remoteCache,
+				Platform:    image.platform,
+				Builder:     image.builder,
+				BuilderTLS:  image.builderTLS,
 			})
 		case CustomBuild:
 			r := model.CustomBuild{
@@ -1322,6 +1385,15 @@ func (s *tiltfileState) translateDC(dc dcResourceSet) ([]model.Manifest, error)
 			if iTarg.OverrideCommand != nil {
 				return nil, fmt.Errorf("docker_build/custom_build.entrypoint not supported for Docker Compose resources")
 			}
+			if iTarg.IsDockerBuild() && iTarg.DockerBuildInfo().Builder != "" {
+				return nil, fmt.Errorf("docker_build.builder not supported for Docker Compose resources")
+			}
+		}
+
+		if len(iTargets) > 0 && !svc.LiveUpdate.Empty() {
+			return nil, fmt.Errorf("dc_resource(%q): live_update is only supported for services that "+
+				"docker-compose builds. Tilt builds image %q, so pass live_update to its docker_build() instead",
+				svc.Name, container.FamiliarString(iTargets[0].Refs.ConfigurationRef))
 		}
 
 		m = m.WithImageTargets(iTargets)
@@ -1406,7 +1478,6 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 			WithIgnores(ignores).
 			WithAllowParallel(r.allowParallel).
 			WithLinks(r.links).
-			WithTags(r.tags).
 			WithIsTest(r.isTest).
 			WithReadinessProbe(r.readinessProbe)
 		var mds []model.ManifestName
@@ -1417,7 +1488,7 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 			Name:                 mn,
 			TriggerMode:          tm,
 			ResourceDependencies: mds,
-		}.WithDeployTarget(lt)
+		}.WithDeployTarget(lt).WithLabels(r.labels)
 
 		result = append(result, m)
 	}
@@ -1425,6 +1496,33 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 	return result, nil
 }
 
+// Resources of different kinds (e.g., a Kubernetes workload and a Docker Compose
+// service) are assembled independently, so make sure they don't collide.
+func validateUniqueManifestNames(ms []model.Manifest) error {
+	seen := make(map[model.ManifestName]model.Manifest, len(ms))
+	for _, m := range ms {
+		if prev, ok := seen[m.Name]; ok {
+			return fmt.Errorf("resource %q is declared more than once (as a %s resource and as a %s resource). "+
+				"Use k8s_resource(new_name=...) to rename one of them", m.Name, manifestKind(prev), manifestKind(m))
+		}
+		seen[m.Name] = m
+	}
+	return nil
+}
+
+func manifestKind(m model.Manifest) string {
+	switch {
+	case m.IsK8s():
+		return "Kubernetes"
+	case m.IsDC():
+		return "Docker Compose"
+	case m.IsLocal():
+		return "local"
+	default:
+		return "unknown"
+	}
+}
+
 func validateResourceDependencies(ms []model.Manifest) error {
 	// make sure that:
 	// 1. all deps exist
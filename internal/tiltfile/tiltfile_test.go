@@ -395,7 +395,7 @@ k8s_resource("the-deployment", "foo")
 `)
 	f.load()
 	f.assertNextManifest("foo", deployment("the-deployment"), numEntities(2))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", ".", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml")
 }
 
 func TestKustomizeError(t *testing.T) {
@@ -404,6 +404,37 @@ func TestKustomizeError(t *testing.T) {
 
 	f.file("Tiltfile", "kustomize('.')")
 	f.loadErrString("unable to find one of 'kustomization.yaml', 'kustomization.yml' or 'Kustomization'")
+
+	// Creating the missing kustomization file should trigger a reload.
+	f.assertConfigFiles("Tiltfile", ".tiltignore", ".")
+}
+
+func TestKustomizeFlags(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("app/kustomization.yaml", `resources:
+- ../shared/deployment.yaml
+`)
+	f.file("shared/deployment.yaml", kustomizeDeploymentText)
+	f.file("Tiltfile", `
+docker_build("gcr.io/foo", "foo")
+k8s_yaml(kustomize("app", flags=["--load-restrictor=LoadRestrictionsNone"]))
+k8s_resource("the-deployment", "foo")
+`)
+	f.load()
+	f.assertNextManifest("foo", deployment("the-deployment"))
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "app", "app/kustomization.yaml", "shared/deployment.yaml")
+}
+
+func TestKustomizeUnknownFlag(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("kustomization.yaml", kustomizeFileText)
+	f.file("Tiltfile", `kustomize(".", flags=["--frobnicate"])`)
+	f.loadErrString("kustomize flags: unknown flag: --frobnicate")
 }
 
 func TestKustomization(t *testing.T) {
@@ -423,7 +454,7 @@ k8s_resource("the-deployment", "foo")
 `)
 	f.load()
 	f.assertNextManifest("foo", deployment("the-deployment"), numEntities(2))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "Kustomization", "service.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", ".", "configMap.yaml", "deployment.yaml", "Kustomization", "service.yaml")
 }
 
 func TestDockerBuildTarget(t *testing.T) {
@@ -496,6 +527,127 @@ docker_build("gcr.io/foo", "foo", pull=True)
 	assert.True(t, m.ImageTargets[0].BuildDetails.(model.DockerBuild).PullParent)
 }
 
+func TestDockerBuildRemoteCache(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", remote_cache=True)
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.True(t, m.ImageTargets[0].BuildDetails.(model.DockerBuild).RemoteCache)
+}
+
+func TestDockerBuildPlatform(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", platform='linux/amd64')
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, "linux/amd64", m.ImageTargets[0].BuildDetails.(model.DockerBuild).Platform)
+}
+
+func TestDockerBuildInvalidPlatform(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", platform='amd64')
+`)
+	f.loadErrString("Argument platform=\"amd64\"")
+}
+
+func TestDockerBuildBuilder(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", builder='tcp://buildkitd:1234')
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, "tcp://buildkitd:1234", m.ImageTargets[0].BuildDetails.(model.DockerBuild).Builder)
+}
+
+func TestDockerBuildBuilderTLS(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", builder='tcp://buildkitd:1234',
+             builder_ca_cert='certs/ca.pem', builder_cert='certs/cert.pem', builder_key='certs/key.pem')
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, model.BuilderTLS{
+		CACert: f.JoinPath("certs", "ca.pem"),
+		Cert:   f.JoinPath("certs", "cert.pem"),
+		Key:    f.JoinPath("certs", "key.pem"),
+	}, m.ImageTargets[0].BuildDetails.(model.DockerBuild).BuilderTLS)
+}
+
+func TestDockerBuildBuilderTLSWithoutBuilder(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", builder_ca_cert='certs/ca.pem')
+`)
+	f.loadErrString("Argument builder_ca_cert: only valid with a builder")
+}
+
+func TestDockerBuildBuilderTLSWithoutCA(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", builder='tcp://buildkitd:1234', builder_cert='certs/cert.pem', builder_key='certs/key.pem')
+`)
+	f.loadErrString("CA certificate")
+}
+
+func TestDockerBuildInvalidBuilder(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", builder='buildkitd:1234')
+`)
+	f.loadErrString("Argument builder=\"buildkitd:1234\"", "unsupported scheme")
+}
+
+func TestDockerBuildBuilderWithSecret(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", builder='tcp://buildkitd:1234', secret='id=mysecret,src=/root/.secret')
+`)
+	f.loadErrString("builder")
+}
+
 func TestDockerBuildCacheFrom(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -845,6 +997,89 @@ dc_resource('foo', links=EXPR)`
 	}
 }
 
+func TestResourceLabels(t *testing.T) {
+	type testCase struct {
+		name     string
+		expr     string
+		expected map[string]string
+		errorMsg string
+	}
+
+	cases := []testCase{
+		{name: "string", expr: "'frontend'", expected: map[string]string{"frontend": "frontend"}},
+		{name: "list", expr: "['frontend', 'tilt.dev/web']",
+			expected: map[string]string{"frontend": "frontend", "tilt.dev/web": "tilt.dev/web"}},
+		{name: "empty_list", expr: "[]"},
+		{name: "invalid", expr: "'front end'", errorMsg: `Invalid label "front end"`},
+		{name: "bad_type", expr: "123", errorMsg: "value should be a string or List or Tuple of strings"},
+	}
+
+	for _, c := range cases {
+		t.Run("LocalResource-"+c.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("Tiltfile", fmt.Sprintf(`
+local_resource('foo', 'echo hi', labels=%s)
+`, c.expr))
+
+			if c.errorMsg != "" {
+				f.loadErrString(c.errorMsg)
+				return
+			}
+
+			f.load()
+			m := f.assertNextManifest("foo")
+			assert.Equal(t, c.expected, m.Labels)
+		})
+
+		t.Run("K8s-"+c.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.setupFoo()
+			f.file("Tiltfile", fmt.Sprintf(`
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', labels=%s)
+`, c.expr))
+
+			if c.errorMsg != "" {
+				f.loadErrString(c.errorMsg)
+				return
+			}
+
+			f.load()
+			m := f.assertNextManifest("foo", db(image("gcr.io/foo")), deployment("foo"))
+			assert.Equal(t, c.expected, m.Labels)
+		})
+
+		t.Run("dc-"+c.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("docker-compose.yml", `version: '3.0'
+services:
+  foo:
+    image: gcr.io/foo
+`)
+			f.file("Tiltfile", fmt.Sprintf(`
+docker_compose('docker-compose.yml')
+dc_resource('foo', labels=%s)
+`, c.expr))
+
+			if c.errorMsg != "" {
+				f.loadErrString(c.errorMsg)
+				return
+			}
+
+			f.load()
+			m := f.assertNextManifest("foo")
+			assert.Equal(t, c.expected, m.Labels)
+		})
+	}
+}
+
 func TestK8sResourceWithLinksAndPortForwards(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -1633,6 +1868,45 @@ k8s_resource('foo', pod_readiness='ignore')
 	)
 }
 
+func TestK8sResourceReadinessProbe(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', port_forwards=8000,
+             readiness_probe=probe(period_secs=5, http_get=http_get_action(port=8000, path='/healthz')))
+`)
+
+	f.load("foo")
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, &v1alpha1.Probe{
+		PeriodSeconds: 5,
+		Handler: v1alpha1.Handler{
+			HTTPGet: &v1alpha1.HTTPGetAction{Port: 8000, Path: "/healthz"},
+		},
+	}, m.K8sTarget().ReadinessProbe)
+}
+
+func TestK8sResourceServerSideApply(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFooAndBar()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+docker_build('gcr.io/bar', 'bar')
+k8s_yaml(['foo.yaml', 'bar.yaml'])
+k8s_resource('foo', server_side_apply=True)
+`)
+
+	f.load()
+	assert.True(t, f.assertNextManifest("foo").K8sTarget().ServerSideApply)
+	assert.False(t, f.assertNextManifest("bar").K8sTarget().ServerSideApply)
+}
+
 func TestPodReadinessOverrideConfigMap(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -4504,6 +4778,25 @@ secret_settings(disable_scrub=True)
 	assert.Empty(t, secrets, "expect no secrets to be collected if scrubbing secrets is disabled")
 }
 
+func TestTiltfileSecrets(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("token.txt", "my-api-token\n")
+	f.file("Tiltfile", `
+secret('my-password', name='db')
+local_resource('api', serve_cmd='./api', serve_env={'TOKEN': read_secret('token.txt')})
+secret_settings(disable_scrub=True)
+`)
+
+	f.load()
+
+	secrets := f.loadResult.Secrets
+	assert.Equal(t, "[redacted secret db] [redacted secret token.txt]",
+		string(secrets.Scrub([]byte("my-password my-api-token"))))
+	assert.Contains(t, f.loadResult.ConfigFiles, f.JoinPath("token.txt"))
+}
+
 func TestDockerPruneSettings(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -0,0 +1,46 @@
+package value
+
+import (
+	"fmt"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// A set of labels to attach to a resource, expressed in the Tiltfile
+// as either a string or a list of strings.
+//
+// Each label becomes a key on the resource's metadata, with the label
+// itself as the value, so labels must be valid Kubernetes label keys.
+type LabelSet struct {
+	Values map[string]string
+}
+
+var _ starlark.Unpacker = &LabelSet{}
+
+func (ls *LabelSet) Unpack(v starlark.Value) error {
+	ls.Values = nil
+
+	var labels StringOrStringList
+	err := labels.Unpack(v)
+	if err != nil {
+		return err
+	}
+
+	return ls.AddAll(labels.Values)
+}
+
+// Validate and add labels to the set.
+func (ls *LabelSet) AddAll(labels []string) error {
+	for _, l := range labels {
+		if issues := validation.IsQualifiedName(l); len(issues) != 0 {
+			return fmt.Errorf("Invalid label %q: %s", l, strings.Join(issues, ", "))
+		}
+		if ls.Values == nil {
+			ls.Values = make(map[string]string)
+		}
+		ls.Values[l] = l
+	}
+	return nil
+}
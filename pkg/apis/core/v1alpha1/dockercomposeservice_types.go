@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Tilt Dev Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DockerComposeService represents a container orchestrated by Docker Compose.
+//
+// +k8s:openapi-gen=true
+type DockerComposeService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec   DockerComposeServiceSpec   `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	Status DockerComposeServiceStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// DockerComposeServiceList
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DockerComposeServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []DockerComposeService `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// DockerComposeServiceSpec defines the desired state of a Docker Compose service.
+type DockerComposeServiceSpec struct {
+	// The name of the service to create.
+	Service string `json:"service" protobuf:"bytes,1,opt,name=service"`
+
+	// A specification of the project the service belongs to.
+	//
+	// Each service spec keeps its own copy of the project spec.
+	Project DockerComposeProject `json:"project" protobuf:"bytes,2,opt,name=project"`
+}
+
+// DockerComposeProject identifies the Docker Compose project a service belongs to.
+type DockerComposeProject struct {
+	// Configuration files to load.
+	//
+	// Equivalent to `docker-compose -f <path> -f <path2>`.
+	ConfigPaths []string `json:"configPaths" protobuf:"bytes,1,rep,name=configPaths"`
+}
+
+var _ resource.Object = &DockerComposeService{}
+var _ resourcestrategy.Validater = &DockerComposeService{}
+
+func (in *DockerComposeService) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *DockerComposeService) NamespaceScoped() bool {
+	return false
+}
+
+func (in *DockerComposeService) New() runtime.Object {
+	return &DockerComposeService{}
+}
+
+func (in *DockerComposeService) NewList() runtime.Object {
+	return &DockerComposeServiceList{}
+}
+
+func (in *DockerComposeService) GetGroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "tilt.dev",
+		Version:  "v1alpha1",
+		Resource: "dockercomposeservices",
+	}
+}
+
+func (in *DockerComposeService) IsStorageVersion() bool {
+	return true
+}
+
+func (in *DockerComposeService) Validate(ctx context.Context) field.ErrorList {
+	var fieldErrors field.ErrorList
+	if in.Spec.Service == "" {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("spec", "service"), "cannot be empty"))
+	}
+	if len(in.Spec.Project.ConfigPaths) == 0 {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("spec", "project", "configPaths"), "cannot be empty"))
+	}
+	return fieldErrors
+}
+
+var _ resource.ObjectList = &DockerComposeServiceList{}
+
+func (in *DockerComposeServiceList) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+// DockerComposeServiceStatus defines the observed state of DockerComposeService
+type DockerComposeServiceStatus struct {
+	// The ID of the container running the service, if any.
+	//
+	// +optional
+	ContainerID string `json:"containerID,omitempty" protobuf:"bytes,1,opt,name=containerID"`
+
+	// The name of the container running the service, if any.
+	//
+	// +optional
+	ContainerName string `json:"containerName,omitempty" protobuf:"bytes,2,opt,name=containerName"`
+
+	// Current state of the container running the service.
+	//
+	// +optional
+	ContainerState *DockerContainerState `json:"containerState,omitempty" protobuf:"bytes,3,opt,name=containerState"`
+
+	// The last time `docker-compose up` started for this service.
+	//
+	// +optional
+	LastApplyStartTime metav1.MicroTime `json:"lastApplyStartTime,omitempty" protobuf:"bytes,4,opt,name=lastApplyStartTime"`
+
+	// The last time `docker-compose up` finished for this service.
+	//
+	// +optional
+	LastApplyFinishTime metav1.MicroTime `json:"lastApplyFinishTime,omitempty" protobuf:"bytes,5,opt,name=lastApplyFinishTime"`
+
+	// An error bringing up the service, if any.
+	//
+	// +optional
+	ApplyError string `json:"applyError,omitempty" protobuf:"bytes,6,opt,name=applyError"`
+}
+
+// State of a Docker container, as reported by the Docker daemon.
+//
+// Based on the ContainerState type in the Docker API.
+type DockerContainerState struct {
+	// String representation of the container state.
+	// Can be one of "created", "running", "paused", "restarting", "removing", "exited", or "dead".
+	Status string `json:"status,omitempty" protobuf:"bytes,1,opt,name=status"`
+
+	// Whether the container is running.
+	Running bool `json:"running,omitempty" protobuf:"varint,2,opt,name=running"`
+
+	// The error from the last time the container tried to start, if any.
+	Error string `json:"error,omitempty" protobuf:"bytes,3,opt,name=error"`
+
+	// The exit code of the container's main process, if it has exited.
+	ExitCode int32 `json:"exitCode,omitempty" protobuf:"varint,4,opt,name=exitCode"`
+
+	// When the container process last started.
+	StartedAt metav1.MicroTime `json:"startedAt,omitempty" protobuf:"bytes,5,opt,name=startedAt"`
+
+	// When the container process last exited.
+	FinishedAt metav1.MicroTime `json:"finishedAt,omitempty" protobuf:"bytes,6,opt,name=finishedAt"`
+}
+
+// DockerComposeService implements ObjectWithStatusSubResource interface.
+var _ resource.ObjectWithStatusSubResource = &DockerComposeService{}
+
+func (in *DockerComposeService) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+// DockerComposeServiceStatus{} implements StatusSubResource interface.
+var _ resource.StatusSubResource = &DockerComposeServiceStatus{}
+
+func (in DockerComposeServiceStatus) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*DockerComposeService).Status = in
+}
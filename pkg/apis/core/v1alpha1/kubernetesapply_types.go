@@ -74,8 +74,12 @@ type KubernetesApplyList struct {
 
 // KubernetesApplySpec defines the desired state of KubernetesApply
 type KubernetesApplySpec struct {
-	// The YAML to apply to the cluster. Required.
-	YAML string `json:"yaml" protobuf:"bytes,1,opt,name=yaml"`
+	// The YAML to apply to the cluster.
+	//
+	// Exactly one of YAML OR ApplyCmd MUST be provided.
+	//
+	// +optional
+	YAML string `json:"yaml,omitempty" protobuf:"bytes,1,opt,name=yaml"`
 
 	// Names of image maps that this applier depends on.
 	//
@@ -103,6 +107,56 @@ type KubernetesApplySpec struct {
 	//
 	// +optional
 	Timeout metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,4,opt,name=timeout"`
+
+	// ApplyCmd is a custom command to execute to deploy entities to the Kubernetes cluster.
+	//
+	// The command must be idempotent, e.g. it must not fail if some or all entities already exist.
+	//
+	// The ApplyCmd MUST return valid Kubernetes YAML for the entities it applied to the cluster.
+	//
+	// Exactly one of YAML OR ApplyCmd MUST be provided.
+	//
+	// +optional
+	ApplyCmd *KubernetesApplyCmd `json:"applyCmd,omitempty" protobuf:"bytes,5,opt,name=applyCmd"`
+
+	// DeleteCmd is a custom command to execute to delete entities created by ApplyCmd and clean up any
+	// additional state.
+	//
+	// +optional
+	DeleteCmd *KubernetesApplyCmd `json:"deleteCmd,omitempty" protobuf:"bytes,6,opt,name=deleteCmd"`
+
+	// ServerSideApply applies the YAML with server-side apply, using `tilt`
+	// as the field manager.
+	//
+	// With client-side apply (the default), Tilt deletes and re-creates objects
+	// when an update fails on immutable fields. With server-side apply, Tilt never
+	// deletes an object to update it. Immutable field errors and conflicts with
+	// other field managers are reported as errors instead.
+	//
+	// Cannot be used with ApplyCmd.
+	//
+	// +optional
+	ServerSideApply bool `json:"serverSideApply,omitempty" protobuf:"varint,7,opt,name=serverSideApply"`
+}
+
+// KubernetesApplyCmd is a command to run to apply or delete Kubernetes entities.
+type KubernetesApplyCmd struct {
+	// Args are the command-line arguments for the command. Must have length >= 1.
+	Args []string `json:"args" protobuf:"bytes,1,rep,name=args"`
+
+	// Dir is the working directory for the process.
+	//
+	// If not specified, defaults to the Tilt main working directory.
+	//
+	// +optional
+	Dir string `json:"dir,omitempty" protobuf:"bytes,2,opt,name=dir"`
+
+	// Env is any additional environment variables in the form KEY=VALUE.
+	//
+	// These are appended to the Tilt environment when executing the command.
+	//
+	// +optional
+	Env []string `json:"env,omitempty" protobuf:"bytes,3,rep,name=env"`
 }
 
 var _ resource.Object = &KubernetesApply{}
@@ -137,9 +191,25 @@ func (in *KubernetesApply) IsStorageVersion() bool {
 }
 
 func (in *KubernetesApply) Validate(ctx context.Context) field.ErrorList {
+	return in.Spec.Validate(ctx)
+}
+
+func (in *KubernetesApplySpec) Validate(ctx context.Context) field.ErrorList {
 	var fieldErrors field.ErrorList
-	if in.Spec.YAML == "" {
-		fieldErrors = append(fieldErrors, field.Required(field.NewPath("yaml"), "cannot be empty"))
+	if in.YAML == "" && in.ApplyCmd == nil {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("yaml"), "one of yaml or applyCmd must be provided"))
+	}
+	if in.YAML != "" && in.ApplyCmd != nil {
+		fieldErrors = append(fieldErrors, field.Invalid(field.NewPath("applyCmd"), in.ApplyCmd, "applyCmd cannot be provided with yaml"))
+	}
+	if in.ApplyCmd != nil && len(in.ApplyCmd.Args) == 0 {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("applyCmd", "args"), "cannot be empty"))
+	}
+	if in.ServerSideApply && in.ApplyCmd != nil {
+		fieldErrors = append(fieldErrors, field.Invalid(field.NewPath("serverSideApply"), in.ServerSideApply, "serverSideApply cannot be used with applyCmd"))
+	}
+	if in.DeleteCmd != nil && len(in.DeleteCmd.Args) == 0 {
+		fieldErrors = append(fieldErrors, field.Required(field.NewPath("deleteCmd", "args"), "cannot be empty"))
 	}
 
 	// TODO(nick): Validate the image locators as well.
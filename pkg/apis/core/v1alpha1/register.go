@@ -40,6 +40,14 @@ const AnnotationManifest = "tilt.dev/resource"
 // its logs should appear under.
 const AnnotationSpanID = "tilt.dev/log-span-id"
 
+// A comma-separated list of the environment variables on an object
+// whose values are secrets, so that CLIs know to hide them.
+const AnnotationSecretEnv = "tilt.dev/secret-env"
+
+// An annotation on objects declared in the Tiltfile, so that Tilt
+// only deletes the objects it created when the Tiltfile stops declaring them.
+const AnnotationOwnerTiltfile = "tilt.dev/owner-tiltfile"
+
 // SchemeGroupVersion is group version used to register these objects
 var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: Version}
 
@@ -55,6 +63,8 @@ func AllResourceObjects() []resource.Object {
 		&UIResource{},
 		&UIButton{},
 		&PortForward{},
+		&DockerComposeService{},
+		&ExtensionRepo{},
 		//&ImageMap{},
 
 		// Hey! You! If you're adding a new top-level type, add the type object here.
@@ -72,6 +82,8 @@ func AllResourceLists() []runtime.Object {
 		&UIResourceList{},
 		&UIButtonList{},
 		&PortForwardList{},
+		&DockerComposeServiceList{},
+		&ExtensionRepoList{},
 		//&ImageMapList{},
 
 		// Hey! You! If you're adding a new top-level type, add the List type here.
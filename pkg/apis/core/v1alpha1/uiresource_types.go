@@ -164,6 +164,12 @@ type UIResourceStatus struct {
 	// Queued is a simple indicator of whether the resource is queued for an update.
 	// +optional
 	Queued bool `json:"queued,omitempty" protobuf:"varint,13,opt,name=queued"`
+
+	// Disabled is true when the user has turned the resource off.
+	//
+	// A disabled resource doesn't build, and anything it deployed is torn down.
+	// +optional
+	Disabled bool `json:"disabled,omitempty" protobuf:"varint,15,opt,name=disabled"`
 }
 
 // UIResource implements ObjectWithStatusSubResource interface.
@@ -310,6 +316,45 @@ type UIResourceKubernetes struct {
 	// for this resource.
 	// +optional
 	DisplayNames []string `json:"displayNames,omitempty" protobuf:"bytes,9,rep,name=displayNames"`
+
+	// Recent Kubernetes Warning events on objects deployed by this resource,
+	// most recent first.
+	//
+	// Events with the same reason on the same object are combined.
+	// +optional
+	WarningEvents []UIResourceKubernetesEvent `json:"warningEvents,omitempty" protobuf:"bytes,10,rep,name=warningEvents"`
+}
+
+// UIResourceKubernetesEvent summarizes Kubernetes Warning events with the
+// same reason on the same object (e.g., FailedScheduling on a Pod).
+type UIResourceKubernetesEvent struct {
+	// The kind of the object the event is about, e.g., Pod.
+	// +optional
+	ObjectKind string `json:"objectKind,omitempty" protobuf:"bytes,1,opt,name=objectKind"`
+
+	// The name of the object the event is about.
+	// +optional
+	ObjectName string `json:"objectName,omitempty" protobuf:"bytes,2,opt,name=objectName"`
+
+	// A short, machine-readable reason for the event, e.g., BackOff.
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,3,opt,name=reason"`
+
+	// The most recent human-readable message for the event.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+
+	// The number of times the event has occurred.
+	// +optional
+	Count int32 `json:"count,omitempty" protobuf:"varint,5,opt,name=count"`
+
+	// The first time the event occurred.
+	// +optional
+	FirstTimestamp metav1.Time `json:"firstTimestamp,omitempty" protobuf:"bytes,6,opt,name=firstTimestamp"`
+
+	// The most recent time the event occurred.
+	// +optional
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty" protobuf:"bytes,7,opt,name=lastTimestamp"`
 }
 
 // UIResourceLocal contains status information specific to local commands.
@@ -2,8 +2,10 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 
@@ -65,6 +67,22 @@ func (l Level) ToProtoID() int32 {
 	return l.id
 }
 
+func (l Level) String() string {
+	switch l {
+	case DebugLvl:
+		return "debug"
+	case VerboseLvl:
+		return "verbose"
+	case InfoLvl:
+		return "info"
+	case WarnLvl:
+		return "warn"
+	case ErrorLvl:
+		return "error"
+	}
+	return "none"
+}
+
 // If l is the logger level, determine if we should display
 // logs of the given severity.
 func (l Level) ShouldDisplay(log Level) bool {
@@ -84,6 +102,33 @@ var (
 	ErrorLvl   = Level{id: 5, severity: 500}
 )
 
+// Inverse of Level.ToProtoID. Unknown IDs are treated as InfoLvl.
+func LevelFromProtoID(id int32) Level {
+	for _, l := range []Level{NoneLvl, DebugLvl, VerboseLvl, InfoLvl, WarnLvl, ErrorLvl} {
+		if l.id == id {
+			return l
+		}
+	}
+	return InfoLvl
+}
+
+// Parses a user-facing level name (e.g., from a CLI flag or query param).
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return DebugLvl, nil
+	case "verbose":
+		return VerboseLvl, nil
+	case "info":
+		return InfoLvl, nil
+	case "warn", "warning":
+		return WarnLvl, nil
+	case "error":
+		return ErrorLvl, nil
+	}
+	return NoneLvl, fmt.Errorf("unknown log level %q. Must be one of: debug, verbose, info, warn, error", name)
+}
+
 type contextKey struct{}
 
 var LoggerContextKey = contextKey{}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/tilt-dev/tilt/internal/sliceutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
 type DockerComposeTarget struct {
@@ -29,7 +30,19 @@ type DockerComposeTarget struct {
 
 	publishedPorts []int
 
+	// Published ports that the user declared with dc_resource(port_forwards).
+	// If set, these are shown instead of all the published ports.
+	PortForwards []PortForward
+
 	Links []Link
+
+	// An optional probe that Tilt runs after the container starts. If set,
+	// the resource isn't considered ready until the probe succeeds.
+	ReadinessProbe *v1alpha1.Probe
+
+	// Live Update steps for a service that docker-compose builds from
+	// its `build:` section. (Images built by Tilt carry their own LiveUpdate.)
+	liveUpdate LiveUpdate
 }
 
 // TODO(nick): This is a temporary hack until we figure out how we want
@@ -67,6 +80,25 @@ func (t DockerComposeTarget) WithLinks(links []Link) DockerComposeTarget {
 	return t
 }
 
+func (t DockerComposeTarget) WithPortForwards(pfs []PortForward) DockerComposeTarget {
+	t.PortForwards = pfs
+	return t
+}
+
+func (t DockerComposeTarget) WithReadinessProbe(probeSpec *v1alpha1.Probe) DockerComposeTarget {
+	t.ReadinessProbe = probeSpec
+	return t
+}
+
+func (t DockerComposeTarget) LiveUpdateInfo() LiveUpdate {
+	return t.liveUpdate
+}
+
+func (t DockerComposeTarget) WithLiveUpdate(lu LiveUpdate) DockerComposeTarget {
+	t.liveUpdate = lu
+	return t
+}
+
 func (t DockerComposeTarget) WithPublishedPorts(ports []int) DockerComposeTarget {
 	t.publishedPorts = ports
 	return t
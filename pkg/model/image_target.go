@@ -243,6 +243,18 @@ type DockerBuild struct {
 
 	Network string
 
+	// The platform to build for, e.g., "linux/amd64".
+	//
+	// Equivalent to the docker build --platform flag.
+	Platform string
+
+	// The address of a standalone BuildKit daemon to build on,
+	// e.g., "tcp://buildkitd:1234". If empty, builds on the Docker daemon.
+	Builder string
+
+	// How to secure the connection to the builder.
+	BuilderTLS BuilderTLS
+
 	PullParent bool
 	CacheFrom  []string
 
@@ -254,10 +266,31 @@ type DockerBuild struct {
 	// Named 'tag' for consistency with how it's used throughout the docker API,
 	// even though this is really more like a reference.NamedTagged
 	ExtraTags []string
+
+	// Look for an image built from the same inputs in the registry before
+	// building, and push under a content-addressed tag so that others
+	// (e.g., CI or teammates) can find the images we build.
+	RemoteCache bool
 }
 
 func (DockerBuild) buildDetails() {}
 
+// Paths to the PEM files for a TLS connection to a remote builder.
+//
+// If empty, we connect to the builder without TLS.
+type BuilderTLS struct {
+	// The CA certificate that signed the builder's certificate.
+	CACert string
+
+	// The client certificate and key, if the builder verifies clients.
+	Cert string
+	Key  string
+}
+
+func (t BuilderTLS) Empty() bool {
+	return t == BuilderTLS{}
+}
+
 type DockerBuildTarget string
 
 func (s DockerBuildTarget) String() string { return string(s) }
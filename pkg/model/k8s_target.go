@@ -8,6 +8,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
@@ -49,6 +50,10 @@ type K8sTarget struct {
 
 	PodReadinessMode PodReadinessMode
 
+	// An optional probe that Tilt runs after deploy. If set, the resource
+	// isn't considered ready until the probe succeeds.
+	ReadinessProbe *v1alpha1.Probe
+
 	// Map configRef -> number of times we (expect to) inject it.
 	// NOTE(maia): currently this map is only for use in metrics, though someday
 	// we want a better way of mapping configRefs -> their injection point(s)
@@ -59,6 +64,12 @@ type K8sTarget struct {
 	// zero+ links assoc'd with this resource (to be displayed in UIs,
 	// in addition to any port forwards/LB endpoints)
 	Links []Link
+
+	// Local files that should trigger a redeploy when they change.
+	// Only used by k8s_custom_deploy, where Tilt doesn't know what
+	// files the apply command reads.
+	PathDependencies []string
+	localRepos       []LocalGitRepo
 }
 
 func NewK8sTargetForTesting(yaml string) K8sTarget {
@@ -99,10 +110,18 @@ func (k8s K8sTarget) Validate() error {
 		return fmt.Errorf("[Validate] K8s resources missing name:\n%s", k8s.YAML)
 	}
 
-	if k8s.YAML == "" {
+	if k8s.YAML == "" && k8s.ApplyCmd == nil {
 		return fmt.Errorf("[Validate] K8s resources %q missing YAML", k8s.Name)
 	}
 
+	if k8s.YAML != "" && k8s.ApplyCmd != nil {
+		return fmt.Errorf("[Validate] K8s resources %q cannot have both YAML and a custom apply command", k8s.Name)
+	}
+
+	if k8s.ApplyCmd != nil && len(k8s.ApplyCmd.Args) == 0 {
+		return fmt.Errorf("[Validate] K8s resources %q custom apply command is empty", k8s.Name)
+	}
+
 	return nil
 }
 
@@ -127,9 +146,43 @@ func (k8s K8sTarget) WithDependencyIDs(ids []TargetID) K8sTarget {
 	return k8s
 }
 
+func (k8s K8sTarget) WithReadinessProbe(probeSpec *v1alpha1.Probe) K8sTarget {
+	k8s.ReadinessProbe = probeSpec
+	return k8s
+}
+
 func (k8s K8sTarget) WithRefInjectCounts(ric map[string]int) K8sTarget {
 	k8s.refInjectCounts = ric
 	return k8s
 }
 
+func (k8s K8sTarget) WithPathDependencies(paths []string, repos []LocalGitRepo) K8sTarget {
+	k8s.PathDependencies = paths
+	k8s.localRepos = repos
+	return k8s
+}
+
+// Whether this target is deployed by a user-supplied command
+// rather than by Tilt applying YAML.
+func (k8s K8sTarget) HasCustomDeploy() bool {
+	return k8s.ApplyCmd != nil
+}
+
+// Implements: engine.WatchableManifest
+func (k8s K8sTarget) Dependencies() []string {
+	return sliceutils.DedupedAndSorted(k8s.PathDependencies)
+}
+
+func (k8s K8sTarget) LocalRepos() []LocalGitRepo {
+	return k8s.localRepos
+}
+
+func (k8s K8sTarget) Dockerignores() []Dockerignore {
+	return nil
+}
+
+func (k8s K8sTarget) IgnoredLocalDirectories() []string {
+	return nil
+}
+
 var _ TargetSpec = K8sTarget{}
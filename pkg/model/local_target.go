@@ -22,8 +22,7 @@ type LocalTarget struct {
 	AllowParallel bool
 
 	// For testing MVP
-	Tags   []string // eventually we might want tags to be more widespread -- stored on manifest maybe?
-	IsTest bool     // does this target represent a Test?
+	IsTest bool // does this target represent a Test?
 
 	ReadinessProbe *v1alpha1.Probe
 }
@@ -68,11 +67,6 @@ func (lt LocalTarget) WithIsTest(isTest bool) LocalTarget {
 	return lt
 }
 
-func (lt LocalTarget) WithTags(tags []string) LocalTarget {
-	lt.Tags = tags
-	return lt
-}
-
 func (lt LocalTarget) WithReadinessProbe(probeSpec *v1alpha1.Probe) LocalTarget {
 	lt.ReadinessProbe = probeSpec
 	return lt
@@ -0,0 +1,519 @@
+package logstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const defaultArchiveMaxFileSize = 4 * 1000 * 1000
+const defaultArchiveMaxFiles = 20
+
+const archiveFileExt = ".jsonl"
+
+// An on-disk, segmented log archive.
+//
+// A LogStore with an archive writes every segment to disk as well as
+// keeping it in memory, so that logs outlive both the in-memory truncation
+// and the Tilt process.
+//
+// Segments are stored as JSON lines, in files of bounded size. Each file
+// is named by the archive checkpoint of its first segment. Once there are
+// too many files, the oldest one is deleted.
+//
+// Each time Tilt opens the archive, it starts a new session. Span IDs are
+// only unique within a session.
+//
+// Appends are numbered right away, but written to disk on a background
+// goroutine, so that a slow disk doesn't block the callers (which often
+// hold the engine state lock). Reads wait for pending writes.
+//
+// Thread-safe.
+type Archive struct {
+	// Guards the write queue.
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	session int
+	next    Checkpoint
+
+	pending []archiveOp
+	writing bool
+	closed  bool
+
+	// The first error from the writer. Once the writer fails,
+	// it stops writing.
+	err error
+
+	// Guards the files on disk.
+	diskMu sync.Mutex
+
+	dir         string
+	maxFileSize int
+	maxFiles    int
+
+	// Sorted by first checkpoint, oldest first.
+	files       []archiveFile
+	current     *os.File
+	currentSize int
+}
+
+type archiveFile struct {
+	path  string
+	first Checkpoint
+}
+
+// A pending disk write: either new lines to append, or a secret scrub.
+type archiveOp struct {
+	lines []archiveLine
+
+	secrets         model.SecretSet
+	scrubCheckpoint Checkpoint
+}
+
+type archiveLine struct {
+	checkpoint Checkpoint
+	data       []byte
+}
+
+// A single log segment, as stored on disk.
+type ArchivedSegment struct {
+	Checkpoint   Checkpoint         `json:"checkpoint"`
+	Session      int                `json:"session"`
+	SpanID       SpanID             `json:"spanID"`
+	ManifestName model.ManifestName `json:"manifestName,omitempty"`
+	Time         time.Time          `json:"time"`
+	Level        int32              `json:"level"`
+	Fields       logger.Fields      `json:"fields,omitempty"`
+	Text         string             `json:"text"`
+	Anchor       bool               `json:"anchor,omitempty"`
+}
+
+// Opens the archive in the given directory, creating it if necessary,
+// and starts a new session.
+func OpenArchive(dir string) (*Archive, error) {
+	err := os.MkdirAll(dir, os.FileMode(0700))
+	if err != nil {
+		return nil, errors.Wrap(err, "opening log archive")
+	}
+
+	a := &Archive{
+		dir:         dir,
+		maxFileSize: defaultArchiveMaxFileSize,
+		maxFiles:    defaultArchiveMaxFiles,
+	}
+	a.cond = sync.NewCond(&a.mu)
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening log archive")
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, archiveFileExt) {
+			continue
+		}
+		first, err := strconv.Atoi(strings.TrimSuffix(name, archiveFileExt))
+		if err != nil {
+			continue
+		}
+		a.files = append(a.files, archiveFile{path: filepath.Join(dir, name), first: Checkpoint(first)})
+	}
+	sort.Slice(a.files, func(i, j int) bool { return a.files[i].first < a.files[j].first })
+
+	// Pick up the numbering where the previous session left off.
+	for i := len(a.files) - 1; i >= 0; i-- {
+		segments, err := readArchiveFile(a.files[i].path)
+		if err != nil {
+			return nil, err
+		}
+		if len(segments) > 0 {
+			last := segments[len(segments)-1]
+			a.next = last.Checkpoint + 1
+			a.session = last.Session + 1
+			break
+		}
+	}
+
+	go a.writeLoop()
+	return a, nil
+}
+
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// The session that new segments are written to.
+func (a *Archive) Session() int {
+	return a.session
+}
+
+// The checkpoint that the next appended segment will get.
+func (a *Archive) Next() Checkpoint {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.next
+}
+
+// Append segments to the archive. Each segment gets the next checkpoint.
+//
+// The segments are written to disk in the background. Returns an error
+// if an earlier write failed.
+func (a *Archive) Append(segments []LogSegment, spans map[SpanID]*Span) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+
+	op := archiveOp{lines: make([]archiveLine, 0, len(segments))}
+	for _, seg := range segments {
+		var mn model.ManifestName
+		if span, ok := spans[seg.SpanID]; ok {
+			mn = span.ManifestName
+		}
+		line, err := json.Marshal(ArchivedSegment{
+			Checkpoint:   a.next,
+			Session:      a.session,
+			SpanID:       seg.SpanID,
+			ManifestName: mn,
+			Time:         seg.Time,
+			Level:        seg.Level.ToProtoID(),
+			Fields:       seg.Fields,
+			Text:         string(seg.Text),
+			Anchor:       seg.Anchor,
+		})
+		if err != nil {
+			return errors.Wrap(err, "archiving logs")
+		}
+		op.lines = append(op.lines, archiveLine{checkpoint: a.next, data: line})
+		a.next++
+	}
+	a.enqueue(op)
+	return nil
+}
+
+// Must hold a.mu.
+func (a *Archive) enqueue(op archiveOp) {
+	if a.closed {
+		return
+	}
+	a.pending = append(a.pending, op)
+	a.cond.Broadcast()
+}
+
+// Waits until all pending writes have hit the disk, and returns
+// the writer's error, if any.
+func (a *Archive) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for len(a.pending) > 0 || a.writing {
+		a.cond.Wait()
+	}
+	return a.err
+}
+
+func (a *Archive) writeLoop() {
+	for {
+		a.mu.Lock()
+		for len(a.pending) == 0 && !a.closed {
+			a.cond.Wait()
+		}
+		if len(a.pending) == 0 {
+			a.mu.Unlock()
+			return
+		}
+		ops := a.pending
+		a.pending = nil
+		a.writing = true
+		failed := a.err != nil
+		a.mu.Unlock()
+
+		var err error
+		if !failed {
+			err = a.write(ops)
+		}
+
+		a.mu.Lock()
+		a.writing = false
+		if err != nil && a.err == nil {
+			a.err = err
+		}
+		a.cond.Broadcast()
+		a.mu.Unlock()
+	}
+}
+
+func (a *Archive) write(ops []archiveOp) error {
+	a.diskMu.Lock()
+	defer a.diskMu.Unlock()
+
+	for _, op := range ops {
+		if op.secrets != nil {
+			err := a.scrubSecretsStartingAt(op.secrets, op.scrubCheckpoint)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := a.appendLines(op.lines)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Must hold a.diskMu.
+func (a *Archive) appendLines(lines []archiveLine) error {
+	var buf []byte
+	for _, line := range lines {
+		if a.current == nil || a.currentSize+len(buf) >= a.maxFileSize {
+			err := a.flush(buf)
+			if err != nil {
+				return err
+			}
+			buf = nil
+
+			err = a.rollover(line.checkpoint)
+			if err != nil {
+				return err
+			}
+		}
+		buf = append(buf, line.data...)
+		buf = append(buf, '\n')
+	}
+	return a.flush(buf)
+}
+
+func (a *Archive) flush(buf []byte) error {
+	if len(buf) == 0 {
+		return nil
+	}
+	n, err := a.current.Write(buf)
+	a.currentSize += n
+	if err != nil {
+		return errors.Wrap(err, "archiving logs")
+	}
+	return nil
+}
+
+// Start a new file, and delete the oldest files if there are too many.
+func (a *Archive) rollover(first Checkpoint) error {
+	if a.current != nil {
+		_ = a.current.Close()
+		a.current = nil
+	}
+
+	path := filepath.Join(a.dir, fmt.Sprintf("%020d%s", first, archiveFileExt))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, os.FileMode(0600))
+	if err != nil {
+		return errors.Wrap(err, "archiving logs")
+	}
+	a.current = f
+	a.currentSize = 0
+	a.files = append(a.files, archiveFile{path: path, first: first})
+
+	for len(a.files) > a.maxFiles {
+		_ = os.Remove(a.files[0].path)
+		a.files = a.files[1:]
+	}
+	return nil
+}
+
+// Read at most `limit` segments before the given checkpoint, in order.
+//
+// If the manifest set is non-empty, only reads segments for those manifests.
+func (a *Archive) ReadBefore(before Checkpoint, limit int, mns model.ManifestNameSet) ([]ArchivedSegment, error) {
+	err := a.Flush()
+	if err != nil {
+		return nil, err
+	}
+
+	a.diskMu.Lock()
+	defer a.diskMu.Unlock()
+
+	var result []ArchivedSegment
+	for i := len(a.files) - 1; i >= 0 && len(result) < limit; i-- {
+		file := a.files[i]
+		if file.first >= before {
+			continue
+		}
+
+		segments, err := readArchiveFile(file.path)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := len(segments) - 1; j >= 0 && len(result) < limit; j-- {
+			seg := segments[j]
+			if seg.Checkpoint >= before {
+				continue
+			}
+			if len(mns) > 0 && !mns[seg.ManifestName] {
+				continue
+			}
+			result = append(result, seg)
+		}
+	}
+
+	// We collected the segments newest-first.
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
+
+// Scrub secrets from all segments starting at the given checkpoint.
+//
+// Secrets are often discovered after the logs that print them (e.g., while
+// the Tiltfile is executing), so we need to rewrite the archive after the fact.
+//
+// The rewrite happens in the background, after any pending appends. Returns an
+// error if an earlier write failed.
+func (a *Archive) ScrubSecretsStartingAt(secrets model.SecretSet, checkpoint Checkpoint) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+
+	// The caller may keep adding to the set after we return.
+	copied := model.SecretSet{}
+	copied.AddAll(secrets)
+	a.enqueue(archiveOp{secrets: copied, scrubCheckpoint: checkpoint})
+	return nil
+}
+
+// Must hold a.diskMu.
+func (a *Archive) scrubSecretsStartingAt(secrets model.SecretSet, checkpoint Checkpoint) error {
+	for i, file := range a.files {
+		isLast := i == len(a.files)-1
+		if !isLast && a.files[i+1].first <= checkpoint {
+			// This whole file is before the checkpoint.
+			continue
+		}
+
+		err := a.scrubFile(file, secrets, checkpoint, isLast)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Archive) scrubFile(file archiveFile, secrets model.SecretSet, checkpoint Checkpoint, isCurrent bool) error {
+	segments, err := readArchiveFile(file.path)
+	if err != nil {
+		return err
+	}
+
+	var buf []byte
+	for _, seg := range segments {
+		if seg.Checkpoint >= checkpoint {
+			seg.Text = string(secrets.Scrub([]byte(seg.Text)))
+		}
+		line, err := json.Marshal(seg)
+		if err != nil {
+			return errors.Wrap(err, "scrubbing log archive")
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	tmpPath := file.path + ".tmp"
+	err = ioutil.WriteFile(tmpPath, buf, os.FileMode(0600))
+	if err != nil {
+		return errors.Wrap(err, "scrubbing log archive")
+	}
+
+	if isCurrent && a.current != nil {
+		_ = a.current.Close()
+		a.current = nil
+	}
+
+	err = os.Rename(tmpPath, file.path)
+	if err != nil {
+		return errors.Wrap(err, "scrubbing log archive")
+	}
+
+	if isCurrent {
+		f, err := os.OpenFile(file.path, os.O_WRONLY|os.O_APPEND, os.FileMode(0600))
+		if err != nil {
+			return errors.Wrap(err, "scrubbing log archive")
+		}
+		a.current = f
+		a.currentSize = len(buf)
+	}
+	return nil
+}
+
+// Flushes pending writes and closes the archive.
+func (a *Archive) Close() error {
+	flushErr := a.Flush()
+
+	a.mu.Lock()
+	a.closed = true
+	a.cond.Broadcast()
+	a.mu.Unlock()
+
+	a.diskMu.Lock()
+	defer a.diskMu.Unlock()
+	if a.current == nil {
+		return flushErr
+	}
+	err := a.current.Close()
+	a.current = nil
+	if flushErr != nil {
+		return flushErr
+	}
+	return err
+}
+
+func readArchiveFile(path string) ([]ArchivedSegment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Deleted out from under us, treat it as empty.
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reading log archive")
+	}
+	defer func() { _ = f.Close() }()
+
+	var result []ArchivedSegment
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), defaultArchiveMaxFileSize)
+	for scanner.Scan() {
+		var seg ArchivedSegment
+		err := json.Unmarshal(scanner.Bytes(), &seg)
+		if err != nil {
+			// A partially-written line (e.g., if Tilt was killed mid-write).
+			// Skip it rather than losing the whole file.
+			continue
+		}
+		result = append(result, seg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading log archive")
+	}
+	return result, nil
+}
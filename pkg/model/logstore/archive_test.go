@@ -0,0 +1,201 @@
+package logstore
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestArchiveAppendAndRead(t *testing.T) {
+	a := newTestArchive(t)
+
+	l := NewLogStore()
+	require.NoError(t, l.SetArchive(a))
+	l.Append(newTestLogEvent("fe", time.Now(), "hello\n"), nil)
+	l.Append(newTestLogEvent("be", time.Now(), "goodbye\n"), nil)
+
+	segs, err := a.ReadBefore(a.Next(), 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello\n", "goodbye\n"}, archivedTexts(segs))
+	assert.Equal(t, model.ManifestName("fe"), segs[0].ManifestName)
+	assert.Equal(t, Checkpoint(0), segs[0].Checkpoint)
+
+	segs, err = a.ReadBefore(a.Next(), 10, model.ManifestNameSet{"be": true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"goodbye\n"}, archivedTexts(segs))
+
+	segs, err = a.ReadBefore(1, 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello\n"}, archivedTexts(segs))
+}
+
+func TestArchiveRolloverAndRetention(t *testing.T) {
+	a := newTestArchive(t)
+	a.maxFileSize = 200
+	a.maxFiles = 3
+
+	l := NewLogStore()
+	require.NoError(t, l.SetArchive(a))
+	for i := 0; i < 50; i++ {
+		l.Append(newTestLogEvent("fe", time.Now(), "line\n"), nil)
+	}
+	require.NoError(t, a.Flush())
+
+	entries, err := ioutil.ReadDir(a.Dir())
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	// Only the newest segments are retained, but they're still numbered
+	// from the start of the archive.
+	segs, err := a.ReadBefore(a.Next(), 100, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, segs)
+	assert.True(t, segs[0].Checkpoint > 0)
+	assert.Equal(t, Checkpoint(49), segs[len(segs)-1].Checkpoint)
+}
+
+func TestArchiveReopenStartsNewSession(t *testing.T) {
+	a := newTestArchive(t)
+	l := NewLogStore()
+	require.NoError(t, l.SetArchive(a))
+	l.Append(newTestLogEvent("fe", time.Now(), "first session\n"), nil)
+	require.NoError(t, a.Close())
+
+	a2, err := OpenArchive(a.Dir())
+	require.NoError(t, err)
+	defer func() { _ = a2.Close() }()
+	assert.Equal(t, 1, a2.Session())
+	assert.Equal(t, Checkpoint(1), a2.Next())
+
+	l2 := NewLogStore()
+	require.NoError(t, l2.SetArchive(a2))
+	l2.Append(newTestLogEvent("fe", time.Now(), "second session\n"), nil)
+
+	segs, err := a2.ReadBefore(a2.Next(), 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first session\n", "second session\n"}, archivedTexts(segs))
+	assert.Equal(t, 0, segs[0].Session)
+	assert.Equal(t, 1, segs[1].Session)
+}
+
+func TestArchiveScrubSecrets(t *testing.T) {
+	a := newTestArchive(t)
+	l := NewLogStore()
+	require.NoError(t, l.SetArchive(a))
+	l.Append(newTestLogEvent("fe", time.Now(), "password: hunter2\n"), nil)
+
+	secrets := model.SecretSet{}
+	secrets.AddSecret("my-secret", "password", []byte("hunter2"))
+	l.ScrubSecretsStartingAt(secrets, 0)
+	l.Append(newTestLogEvent("fe", time.Now(), "still writable\n"), nil)
+
+	segs, err := a.ReadBefore(a.Next(), 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"password: [redacted secret my-secret:password]\n", "still writable\n"}, archivedTexts(segs))
+	assert.NoError(t, l.ArchiveError())
+}
+
+func TestArchiveSkipsPartialLines(t *testing.T) {
+	a := newTestArchive(t)
+	l := NewLogStore()
+	require.NoError(t, l.SetArchive(a))
+	l.Append(newTestLogEvent("fe", time.Now(), "hello\n"), nil)
+	require.NoError(t, a.Flush())
+
+	f, err := os.OpenFile(a.files[0].path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"checkpoint":1,"tex`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	segs, err := a.ReadBefore(10, 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello\n"}, archivedTexts(segs))
+}
+
+func TestHistory(t *testing.T) {
+	a := newTestArchive(t)
+	l := NewLogStore()
+	l.maxLogLengthInBytes = 20
+	require.NoError(t, l.SetArchive(a))
+
+	for i := 0; i < 10; i++ {
+		l.Append(newTestLogEvent("fe", time.Now(), "abcd\n"), nil)
+	}
+
+	// Some of the logs have been truncated from memory.
+	inMemory, err := l.ToLogList(0)
+	require.NoError(t, err)
+	require.True(t, inMemory.FromCheckpoint > 0)
+
+	history, err := l.History(Checkpoint(inMemory.FromCheckpoint), 100, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), history.FromCheckpoint)
+	assert.Equal(t, inMemory.FromCheckpoint, history.ToCheckpoint)
+	assert.Len(t, history.Segments, int(inMemory.FromCheckpoint))
+	assert.Equal(t, "fe", history.Spans["fe"].ManifestName)
+
+	history, err = l.History(0, 100, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(-1), history.FromCheckpoint)
+	assert.Empty(t, history.Segments)
+}
+
+func TestHistoryPreviousSession(t *testing.T) {
+	a := newTestArchive(t)
+	l := NewLogStore()
+	require.NoError(t, l.SetArchive(a))
+	l.Append(newTestLogEvent("fe", time.Now(), "build failed\n"), nil)
+	require.NoError(t, a.Close())
+
+	a2, err := OpenArchive(a.Dir())
+	require.NoError(t, err)
+	defer func() { _ = a2.Close() }()
+
+	// Logs written before the archive was attached are copied over.
+	l2 := NewLogStore()
+	l2.Append(newTestLogEvent("fe", time.Now(), "rebuilding\n"), nil)
+	require.NoError(t, l2.SetArchive(a2))
+
+	history, err := l2.History(0, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, history.Segments, 1)
+	assert.Equal(t, int32(-1), history.FromCheckpoint)
+	assert.Equal(t, "build failed\n", history.Segments[0].Text)
+	assert.Equal(t, "session:0:fe", history.Segments[0].SpanId)
+	assert.Equal(t, "fe", history.Spans["session:0:fe"].ManifestName)
+}
+
+func TestHistoryWithoutArchive(t *testing.T) {
+	l := NewLogStoreForTesting("hello\n")
+	history, err := l.History(l.Checkpoint(), 100, nil)
+	require.NoError(t, err)
+	assert.Empty(t, history.Segments)
+	assert.Equal(t, int32(-1), history.FromCheckpoint)
+}
+
+func newTestArchive(t *testing.T) *Archive {
+	dir, err := ioutil.TempDir("", strings.ReplaceAll(t.Name(), "/", "_"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	a, err := OpenArchive(dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = a.Close() })
+	return a
+}
+
+func archivedTexts(segs []ArchivedSegment) []string {
+	result := []string{}
+	for _, seg := range segs {
+		result = append(result, seg.Text)
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package logstore
+
+import (
+	"bytes"
+	"regexp"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/webview"
+)
+
+// Criteria for selecting log segments. The zero value selects everything.
+type Filter struct {
+	// If non-empty, only select logs for these manifests.
+	ManifestNames model.ManifestNameSet
+
+	// If non-empty, only select logs for these spans.
+	SpanIDs map[SpanID]bool
+
+	// Only select logs at least this severe.
+	MinLevel logger.Level
+
+	// If non-zero, only select logs at or after Since, and before Until.
+	Since time.Time
+	Until time.Time
+
+	// If non-nil, only select logs whose text matches.
+	//
+	// The pattern is matched against each segment (without its trailing
+	// newline), which is usually (but not always) a whole line.
+	Pattern *regexp.Regexp
+}
+
+func (f Filter) Matches(seg LogSegment, mn model.ManifestName) bool {
+	if len(f.ManifestNames) > 0 && !f.ManifestNames[mn] {
+		return false
+	}
+	if len(f.SpanIDs) > 0 && !f.SpanIDs[seg.SpanID] {
+		return false
+	}
+	if !seg.Level.AsSevereAs(f.MinLevel) {
+		return false
+	}
+	if !f.Since.IsZero() && seg.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !seg.Time.Before(f.Until) {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.Match(bytes.TrimRight(seg.Text, "\r\n")) {
+		return false
+	}
+	return true
+}
+
+// Returns at most `limit` segments matching the filter, starting at
+// the given checkpoint.
+//
+// FromCheckpoint is where the search actually started, which may be later than
+// `from` if the log has been truncated. ToCheckpoint is where the search
+// stopped. To get the next page, query again from ToCheckpoint.
+// If the page has fewer than `limit` segments, there are no more matching logs (yet).
+func (s *LogStore) Query(filter Filter, from Checkpoint, limit int) (*webview.LogList, error) {
+	startIndex := s.checkpointToIndex(from)
+	spans := make(map[string]*webview.LogSpan)
+	segments := []*webview.LogSegment{}
+
+	i := startIndex
+	for ; i < len(s.segments) && len(segments) < limit; i++ {
+		segment := s.segments[i]
+		span := s.spans[segment.SpanID]
+		if !filter.Matches(segment, span.ManifestName) {
+			continue
+		}
+
+		spanID := string(segment.SpanID)
+		if _, ok := spans[spanID]; !ok {
+			spans[spanID] = &webview.LogSpan{ManifestName: span.ManifestName.String()}
+		}
+
+		time, err := ptypes.TimestampProto(segment.Time)
+		if err != nil {
+			return nil, errors.Wrap(err, "Query")
+		}
+		segments = append(segments, &webview.LogSegment{
+			SpanId: spanID,
+			Level:  webview.LogLevel(segment.Level.ToProtoID()),
+			Time:   time,
+			Text:   string(segment.Text),
+			Anchor: segment.Anchor,
+			Fields: segment.Fields,
+		})
+	}
+
+	return &webview.LogList{
+		Spans:          spans,
+		Segments:       segments,
+		FromCheckpoint: int32(s.checkpointFromIndex(startIndex)),
+		ToCheckpoint:   int32(s.checkpointFromIndex(i)),
+	}, nil
+}
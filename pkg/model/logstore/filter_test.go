@@ -0,0 +1,74 @@
+package logstore
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestQueryFilters(t *testing.T) {
+	start := time.Now()
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", start, "fe starting\n"), nil)
+	l.Append(newTestLogEvent("be", start.Add(time.Second), "be starting\n"), nil)
+
+	warn := newTestLogEvent("fe", start.Add(2*time.Second), "fe is slow\n")
+	warn.level = logger.WarnLvl
+	l.Append(warn, nil)
+
+	l.Append(newTestLogEvent("be", start.Add(3*time.Second), "be crashed\n"), nil)
+
+	for _, tc := range []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"all", Filter{}, []string{"fe starting\n", "be starting\n", "fe is slow\n", "be crashed\n"}},
+		{"manifest", Filter{ManifestNames: model.ManifestNameSet{"be": true}}, []string{"be starting\n", "be crashed\n"}},
+		{"span", Filter{SpanIDs: map[SpanID]bool{"fe": true}}, []string{"fe starting\n", "fe is slow\n"}},
+		{"level", Filter{MinLevel: logger.WarnLvl}, []string{"fe is slow\n"}},
+		{"time", Filter{Since: start.Add(time.Second), Until: start.Add(3 * time.Second)}, []string{"be starting\n", "fe is slow\n"}},
+		{"pattern", Filter{Pattern: regexp.MustCompile("start")}, []string{"fe starting\n", "be starting\n"}},
+		{"combined", Filter{ManifestNames: model.ManifestNameSet{"be": true}, Pattern: regexp.MustCompile("crash")}, []string{"be crashed\n"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := l.Query(tc.filter, 0, 100)
+			require.NoError(t, err)
+
+			texts := []string{}
+			for _, seg := range result.Segments {
+				texts = append(texts, seg.Text)
+			}
+			assert.Equal(t, tc.expected, texts)
+			assert.Equal(t, int32(0), result.FromCheckpoint)
+			assert.Equal(t, int32(4), result.ToCheckpoint)
+		})
+	}
+}
+
+func TestQueryPagination(t *testing.T) {
+	l := NewLogStore()
+	for _, mn := range []model.ManifestName{"fe", "be", "fe", "be", "fe"} {
+		l.Append(newTestLogEvent(mn, time.Now(), string(mn)+"\n"), nil)
+	}
+
+	filter := Filter{ManifestNames: model.ManifestNameSet{"fe": true}}
+	page, err := l.Query(filter, 0, 2)
+	require.NoError(t, err)
+	assert.Len(t, page.Segments, 2)
+	assert.Equal(t, int32(3), page.ToCheckpoint)
+	assert.Equal(t, "fe", page.Spans["fe"].ManifestName)
+	assert.Nil(t, page.Spans["be"])
+
+	page, err = l.Query(filter, Checkpoint(page.ToCheckpoint), 2)
+	require.NoError(t, err)
+	assert.Len(t, page.Segments, 1)
+	assert.Equal(t, int32(3), page.FromCheckpoint)
+	assert.Equal(t, int32(5), page.ToCheckpoint)
+}
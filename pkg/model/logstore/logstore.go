@@ -156,6 +156,16 @@ type LogStore struct {
 
 	// If the log is truncated, we need to adjust all checkpoints
 	checkpointOffset Checkpoint
+
+	// An optional on-disk copy of the log, so that we can page back
+	// through logs that have been truncated (or logs from a previous session).
+	archive *Archive
+
+	// Converts a LogStore checkpoint to an Archive checkpoint.
+	archiveOffset Checkpoint
+
+	// If writing to the archive fails, we stop archiving and remember why.
+	archiveErr error
 }
 
 func NewLogStoreForTesting(msg string) *LogStore {
@@ -241,6 +251,41 @@ func (s *LogStore) ScrubSecretsStartingAt(secrets model.SecretSet, checkpoint Ch
 	}
 
 	s.len = s.computeLen()
+
+	if s.archive != nil {
+		err := s.archive.ScrubSecretsStartingAt(secrets, checkpoint+s.archiveOffset)
+		if err != nil {
+			s.stopArchiving(err)
+		}
+	}
+}
+
+// Start copying all logs to the given archive, including the logs
+// we already have in memory.
+func (s *LogStore) SetArchive(a *Archive) error {
+	s.archive = nil
+	s.archiveErr = nil
+	if a == nil {
+		return nil
+	}
+
+	s.archiveOffset = a.Next() - s.checkpointFromIndex(0)
+	err := a.Append(s.segments, s.spans)
+	if err != nil {
+		return err
+	}
+	s.archive = a
+	return nil
+}
+
+// The error that made us stop archiving logs, if any.
+func (s *LogStore) ArchiveError() error {
+	return s.archiveErr
+}
+
+func (s *LogStore) stopArchiving(err error) {
+	s.archive = nil
+	s.archiveErr = err
 }
 
 func (s *LogStore) Append(le LogEvent, secrets model.SecretSet) {
@@ -274,6 +319,13 @@ func (s *LogStore) Append(le LogEvent, secrets model.SecretSet) {
 	s.segments = append(s.segments, added...)
 	span.LastSegmentIndex = len(s.segments) - 1
 
+	if s.archive != nil {
+		err := s.archive.Append(added, s.spans)
+		if err != nil {
+			s.stopArchiving(err)
+		}
+	}
+
 	s.len += len(msg)
 	s.ensureMaxLength()
 }
@@ -550,6 +602,85 @@ func (s *LogStore) ToLogList(fromCheckpoint Checkpoint) (*webview.LogList, error
 	}, nil
 }
 
+// Returns at most `limit` archived log segments before the given checkpoint.
+//
+// This lets clients page back through logs that have been truncated
+// from memory, or that were written by a previous Tilt session.
+// Checkpoints in the result may be negative.
+//
+// If the manifest set is non-empty, only returns logs for those manifests.
+func (s *LogStore) History(before Checkpoint, limit int, mns model.ManifestNameSet) (*webview.LogList, error) {
+	return s.HistoryReader().History(before, limit, mns)
+}
+
+// Reads log history from the archive.
+//
+// Reading the archive hits the disk, so callers holding a lock on the
+// LogStore should grab a HistoryReader, release the lock, then read.
+type HistoryReader struct {
+	archive       *Archive
+	archiveOffset Checkpoint
+}
+
+func (s *LogStore) HistoryReader() HistoryReader {
+	return HistoryReader{archive: s.archive, archiveOffset: s.archiveOffset}
+}
+
+// See LogStore.History.
+func (r HistoryReader) History(before Checkpoint, limit int, mns model.ManifestNameSet) (*webview.LogList, error) {
+	if r.archive == nil || limit <= 0 {
+		return &webview.LogList{
+			FromCheckpoint: -1,
+			ToCheckpoint:   -1,
+		}, nil
+	}
+
+	archived, err := r.archive.ReadBefore(before+r.archiveOffset, limit, mns)
+	if err != nil {
+		return nil, errors.Wrap(err, "History")
+	}
+	if len(archived) == 0 {
+		return &webview.LogList{
+			FromCheckpoint: -1,
+			ToCheckpoint:   -1,
+		}, nil
+	}
+
+	session := r.archive.Session()
+	spans := make(map[string]*webview.LogSpan)
+	segments := make([]*webview.LogSegment, 0, len(archived))
+	for _, seg := range archived {
+		// Span IDs are only unique within a session.
+		spanID := string(seg.SpanID)
+		if seg.Session != session {
+			spanID = fmt.Sprintf("session:%d:%s", seg.Session, seg.SpanID)
+		}
+		if _, ok := spans[spanID]; !ok {
+			spans[spanID] = &webview.LogSpan{ManifestName: seg.ManifestName.String()}
+		}
+
+		time, err := ptypes.TimestampProto(seg.Time)
+		if err != nil {
+			return nil, errors.Wrap(err, "History")
+		}
+		segments = append(segments, &webview.LogSegment{
+			SpanId: spanID,
+			Level:  webview.LogLevel(seg.Level),
+			Time:   time,
+			Text:   seg.Text,
+			Anchor: seg.Anchor,
+			Fields: seg.Fields,
+		})
+	}
+
+	return &webview.LogList{
+		Spans:          spans,
+		Segments:       segments,
+		FromCheckpoint: int32(archived[0].Checkpoint - r.archiveOffset),
+		ToCheckpoint:   int32(archived[len(archived)-1].Checkpoint - r.archiveOffset + 1),
+	}, nil
+}
+
 func (s *LogStore) String() string {
 	return s.toLogString(logOptions{
 		spans:              s.spans,
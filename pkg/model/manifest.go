@@ -56,6 +56,10 @@ type Manifest struct {
 	ResourceDependencies []ManifestName
 
 	Source ManifestSource
+
+	// Labels for grouping and filtering resources. Copied onto the
+	// metadata of the resource's API objects.
+	Labels map[string]string
 }
 
 func (m Manifest) ID() TargetID {
@@ -175,6 +179,19 @@ func (m Manifest) WithTriggerMode(mode TriggerMode) Manifest {
 	return m
 }
 
+func (m Manifest) WithLabels(labels map[string]string) Manifest {
+	m.Labels = nil
+	if len(labels) == 0 {
+		return m
+	}
+
+	m.Labels = make(map[string]string, len(labels))
+	for k, v := range labels {
+		m.Labels[k] = v
+	}
+	return m
+}
+
 func (m Manifest) TargetIDSet() map[TargetID]bool {
 	result := make(map[TargetID]bool)
 	specs := m.TargetSpecs()
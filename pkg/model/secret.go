@@ -3,13 +3,14 @@ package model
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 )
 
 // Don't scrub secrets less than 5 characters,
 // to avoid weird behavior where we scrub too much
 // https://app.clubhouse.io/windmill/story/3568/small-secrets-lead-to-weird-behavior
-const minSecretLengthToScrub = 5
+const MinSecretLengthToScrub = 5
 
 // Secrets are different than other kinds of build/deploy outputs.
 //
@@ -24,15 +25,21 @@ func (s SecretSet) AddAll(other SecretSet) {
 	}
 }
 
+// Adds a secret. The key may be empty if the secret isn't part of a larger object
+// (e.g., for secrets marked in the Tiltfile).
 func (s SecretSet) AddSecret(name string, key string, value []byte) {
 	v := string(value)
 	valueEncoded := base64.StdEncoding.EncodeToString(value)
+	replacement := fmt.Sprintf("[redacted secret %s:%s]", name, key)
+	if key == "" {
+		replacement = fmt.Sprintf("[redacted secret %s]", name)
+	}
 	s[v] = Secret{
 		Name:         name,
 		Key:          key,
 		Value:        value,
 		ValueEncoded: []byte(valueEncoded),
-		Replacement:  []byte(fmt.Sprintf("[redacted secret %s:%s]", name, key)),
+		Replacement:  []byte(replacement),
 	}
 }
 
@@ -43,6 +50,47 @@ func (s SecretSet) Scrub(text []byte) []byte {
 	return text
 }
 
+// Scrubs secrets from JSON, where they may appear with their special characters escaped.
+func (s SecretSet) ScrubJSON(text []byte) []byte {
+	for _, secret := range s {
+		text = secret.Scrub(text)
+		if len(secret.Value) < MinSecretLengthToScrub {
+			continue
+		}
+
+		// encoders differ on whether they escape HTML, so check both ways.
+		for _, escapeHTML := range []bool{true, false} {
+			escaped := jsonEscape(secret.Value, escapeHTML)
+			if !bytes.Equal(escaped, secret.Value) && bytes.Contains(text, escaped) {
+				text = bytes.ReplaceAll(text, escaped, jsonEscape(secret.Replacement, escapeHTML))
+			}
+		}
+	}
+	return text
+}
+
+// Returns true if the text contains any of the secrets.
+func (s SecretSet) Contains(text []byte) bool {
+	for _, secret := range s {
+		if len(secret.Value) >= MinSecretLengthToScrub && bytes.Contains(text, secret.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// The contents of a JSON string with the given value, without the quotes.
+func jsonEscape(value []byte, escapeHTML bool) []byte {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(escapeHTML)
+	err := encoder.Encode(string(value))
+	if err != nil {
+		return value
+	}
+	return bytes.TrimSuffix(bytes.TrimPrefix(bytes.TrimSpace(buf.Bytes()), []byte(`"`)), []byte(`"`))
+}
+
 type Secret struct {
 	// The name of the secret in the kubernetes cluster, so the user
 	// can look it up themselves.
@@ -50,15 +98,15 @@ type Secret struct {
 
 	Key string
 
-	Value []byte
-
-	ValueEncoded []byte
+	// Never serialized, so that dumps of the engine state don't leak secrets.
+	Value        []byte `json:"-"`
+	ValueEncoded []byte `json:"-"`
 
 	Replacement []byte
 }
 
 func (s Secret) Scrub(text []byte) []byte {
-	if len(s.Value) < minSecretLengthToScrub {
+	if len(s.Value) < MinSecretLengthToScrub {
 		return text
 	}
 	if bytes.Contains(text, s.Value) {
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretScrub(t *testing.T) {
+	s := SecretSet{}
+	s.AddSecret("my-secret", "password", []byte("hunter2"))
+	s.AddSecret("token", "", []byte("abc123"))
+
+	assert.Equal(t, "password is [redacted secret my-secret:password]",
+		string(s.Scrub([]byte("password is hunter2"))))
+	assert.Equal(t, "encoded: [redacted secret my-secret:password]",
+		string(s.Scrub([]byte("encoded: aHVudGVyMg=="))))
+	assert.Equal(t, "token=[redacted secret token]",
+		string(s.Scrub([]byte("token=abc123"))))
+}
+
+func TestSecretScrubSkipsShortSecrets(t *testing.T) {
+	s := SecretSet{}
+	s.AddSecret("my-secret", "password", []byte("abc"))
+	assert.Equal(t, "abc", string(s.Scrub([]byte("abc"))))
+	assert.False(t, s.Contains([]byte("abc")))
+}
+
+func TestSecretScrubJSON(t *testing.T) {
+	s := SecretSet{}
+	s.AddSecret("my-secret", "password", []byte(`a"b<c>d`))
+
+	assert.Equal(t, `{"a": "x[redacted secret my-secret:password]y"}`,
+		string(s.ScrubJSON([]byte(`{"a": "xa\"b<c>dy"}`))))
+	assert.Equal(t, `{"a": "x[redacted secret my-secret:password]y"}`,
+		string(s.ScrubJSON([]byte(`{"a": "xa\"b\u003cc\u003edy"}`))))
+}
+
+func TestSecretContains(t *testing.T) {
+	s := SecretSet{}
+	s.AddSecret("my-secret", "password", []byte("hunter2"))
+	assert.True(t, s.Contains([]byte("PASSWORD=hunter2")))
+	assert.False(t, s.Contains([]byte("PASSWORD=hunter3")))
+}
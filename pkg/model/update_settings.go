@@ -11,7 +11,7 @@ const (
 )
 
 type UpdateSettings struct {
-	maxParallelUpdates int           // max number of updates to run concurrently
+	maxParallelUpdates int           // max number of updates to run concurrently (and of image builds per update)
 	k8sUpsertTimeout   time.Duration // timeout for k8s upsert operations
 }
 
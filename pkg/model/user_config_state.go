@@ -16,3 +16,28 @@ func (ucs UserConfigState) WithArgs(args []string) UserConfigState {
 	ucs.ArgsChangeTime = time.Now()
 	return ucs
 }
+
+// A setting that the Tiltfile defined with one of the config.define_* functions.
+//
+// Lets UIs show a form for Tiltfile args, instead of asking for raw CLI args.
+type UserConfigSetting struct {
+	Name string `json:"name"`
+
+	// One of "string", "list[string]", "bool", "int", "enum", or "object".
+	Type string `json:"type"`
+
+	Usage string `json:"usage,omitempty"`
+
+	// The allowed values of an enum setting.
+	Choices []string `json:"choices,omitempty"`
+
+	// The value if neither the args nor tilt_config.json set it.
+	Default interface{} `json:"default,omitempty"`
+
+	// True if the setting takes the positional args (i.e., args=True).
+	Positional bool `json:"positional,omitempty"`
+
+	// The value of the setting the last time config.parse() ran.
+	// nil if the setting wasn't set.
+	Value interface{} `json:"value,omitempty"`
+}
@@ -0,0 +1,80 @@
+import { mount } from "enzyme"
+import fetchMock from "fetch-mock"
+import React from "react"
+import {
+  cleanupMockAnalyticsCalls,
+  expectIncrs,
+  mockAnalyticsCalls,
+} from "./analytics_test_helpers"
+import {
+  DisableToggle,
+  DisableToggleRoot,
+  DisableToggleTooltip,
+  setResourceDisabled,
+} from "./DisableToggle"
+
+describe("DisableToggle", () => {
+  beforeEach(() => {
+    fetchMock.reset()
+    mockAnalyticsCalls()
+  })
+
+  afterEach(() => {
+    cleanupMockAnalyticsCalls()
+  })
+
+  it("shows the action for the current state", () => {
+    const root = mount(
+      <div>
+        <DisableToggle disabled={false} onToggle={() => {}} />
+        <DisableToggle disabled={true} onToggle={() => {}} />
+      </div>
+    )
+
+    let toggles = root.find(DisableToggleRoot)
+    expect(toggles).toHaveLength(2)
+
+    expect(toggles.at(0).text()).toEqual("disable")
+    expect(toggles.at(0).prop("title")).toEqual(DisableToggleTooltip.isEnabled)
+    expect(toggles.at(0).hasClass("is-disabled")).toBeFalsy()
+
+    expect(toggles.at(1).text()).toEqual("enable")
+    expect(toggles.at(1).prop("title")).toEqual(DisableToggleTooltip.isDisabled)
+    expect(toggles.at(1).hasClass("is-disabled")).toBeTruthy()
+  })
+
+  it("POSTs to endpoint when clicked", () => {
+    fetchMock.mock("/api/override/disable", JSON.stringify({}))
+
+    let toggleFoobar = setResourceDisabled.bind(null, "foobar")
+    const root = mount(
+      <DisableToggle disabled={false} onToggle={toggleFoobar} />
+    )
+
+    let element = root.find(DisableToggle)
+    expect(element).toHaveLength(1)
+
+    let preventDefaulted = false
+    element.simulate("click", {
+      preventDefault: () => {
+        preventDefaulted = true
+      },
+    })
+    expect(preventDefaulted).toEqual(true)
+
+    expect(fetchMock.calls().length).toEqual(2) // 1 call to analytics, one to /override
+    expectIncrs({
+      name: "ui.web.toggleDisabled",
+      tags: { action: "click", toDisabled: "true" },
+    })
+
+    expect(fetchMock.calls()[1][0]).toEqual("/api/override/disable")
+    expect(fetchMock.calls()[1][1]?.method).toEqual("post")
+    expect(fetchMock.calls()[1][1]?.body).toEqual(
+      JSON.stringify({
+        manifest_names: ["foobar"],
+        disabled: true,
+      })
+    )
+  })
+})
@@ -0,0 +1,86 @@
+import React from "react"
+import styled from "styled-components"
+import { InstrumentedButton } from "./instrumentedComponents"
+import {
+  AnimDuration,
+  Color,
+  Font,
+  FontSize,
+  mixinResetButtonStyle,
+} from "./style-helpers"
+
+export let DisableToggleRoot = styled(InstrumentedButton)`
+  ${mixinResetButtonStyle}
+  display: flex;
+  align-items: center;
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+  color: ${Color.grayLight};
+  transition: opacity ${AnimDuration.short} linear;
+  opacity: 0;
+
+  .u-showTriggerModeOnHover:hover &,
+  &:focus,
+  &.is-disabled {
+    opacity: 1;
+  }
+
+  &:hover {
+    color: ${Color.white};
+  }
+`
+
+export const DisableToggleTooltip = {
+  isDisabled: "Turn this resource back on",
+  isEnabled: "Turn this resource off and tear down what it deployed",
+}
+
+export function setResourceDisabled(name: string, disabled: boolean) {
+  let url = "/api/override/disable"
+
+  fetch(url, {
+    method: "post",
+    body: JSON.stringify({
+      manifest_names: [name],
+      disabled: disabled,
+    }),
+  }).then((response) => {
+    if (!response.ok) {
+      console.log(response)
+    }
+  })
+}
+
+type DisableToggleProps = {
+  disabled: boolean
+  onToggle: (disabled: boolean) => void
+}
+
+export function DisableToggle(props: DisableToggleProps) {
+  let onClick = (e: any) => {
+    // DisableToggle is nested in a link,
+    // and preventDefault is the standard way to cancel the navigation.
+    e.preventDefault()
+
+    // stopPropagation prevents the sidebar item from opening.
+    e.stopPropagation()
+
+    props.onToggle(!props.disabled)
+  }
+
+  return (
+    <DisableToggleRoot
+      className={props.disabled ? "is-disabled" : ""}
+      onClick={onClick}
+      title={
+        props.disabled
+          ? DisableToggleTooltip.isDisabled
+          : DisableToggleTooltip.isEnabled
+      }
+      analyticsName="ui.web.toggleDisabled"
+      analyticsTags={{ toDisabled: (!props.disabled).toString() }}
+    >
+      {props.disabled ? "enable" : "disable"}
+    </DisableToggleRoot>
+  )
+}
@@ -17,6 +17,7 @@ import {
   mixinResetButtonStyle,
   SizeUnit,
 } from "./style-helpers"
+import TiltfileArgsDialog from "./TiltfileArgsDialog"
 import UpdateDialog from "./UpdateDialog"
 
 type TiltBuild = Proto.corev1alpha1TiltBuild
@@ -136,12 +137,14 @@ type GlobalNavProps = {
   showUpdate: boolean
   suggestedVersion: string | null | undefined
   runningBuild: TiltBuild | undefined
+  resourceNames: string[]
 }
 
 export function GlobalNav(props: GlobalNavProps) {
   const shortcutButton = useRef(null as any)
   const accountButton = useRef(null as any)
   const updateButton = useRef(null as any)
+  const tiltfileArgsButton = useRef(null as any)
   const [shortcutsDialogAnchor, setShortcutsDialogAnchor] = useState(
     null as Element | null
   )
@@ -151,9 +154,13 @@ export function GlobalNav(props: GlobalNavProps) {
   const [updateDialogAnchor, setUpdateDialogAnchor] = useState(
     null as Element | null
   )
+  const [tiltfileArgsDialogAnchor, setTiltfileArgsDialogAnchor] = useState(
+    null as Element | null
+  )
   const shortcutsDialogOpen = !!shortcutsDialogAnchor
   const accountMenuOpen = !!accountMenuAnchor
   const updateDialogOpen = !!updateDialogAnchor
+  const tiltfileArgsDialogOpen = !!tiltfileArgsDialogAnchor
   let isSnapshot = props.isSnapshot
   if (isSnapshot) {
     return null
@@ -186,6 +193,15 @@ export function GlobalNav(props: GlobalNavProps) {
     )
   }
 
+  let toggleTiltfileArgsDialog = (action: string) => {
+    if (!tiltfileArgsDialogOpen) {
+      incr("ui.web.menu", { type: "tiltfileArgs", action: action })
+    }
+    setTiltfileArgsDialogAnchor(
+      tiltfileArgsDialogOpen ? null : (tiltfileArgsButton.current as Element)
+    )
+  }
+
   let accountMenuHeader = <AccountMenuHeader {...props} />
   let accountMenuContent = <AccountMenuContent {...props} />
   let snapshotButton = props.snapshot.enabled ? (
@@ -214,6 +230,14 @@ export function GlobalNav(props: GlobalNavProps) {
 
       {snapshotButton}
 
+      <MenuButton
+        ref={tiltfileArgsButton}
+        onClick={() => toggleTiltfileArgsDialog("click")}
+        data-open={tiltfileArgsDialogOpen}
+      >
+        <div>args</div>
+        <MenuButtonLabel>Tiltfile Args</MenuButtonLabel>
+      </MenuButton>
       <MenuButton
         ref={shortcutButton}
         onClick={() => toggleShortcutsDialog("click")}
@@ -254,6 +278,12 @@ export function GlobalNav(props: GlobalNavProps) {
         suggestedVersion={props.suggestedVersion}
         isNewInterface={true}
       />
+      <TiltfileArgsDialog
+        open={tiltfileArgsDialogOpen}
+        anchorEl={tiltfileArgsDialogAnchor}
+        onClose={() => toggleTiltfileArgsDialog("close")}
+        resourceNames={props.resourceNames}
+      />
       <GlobalNavShortcuts
         toggleShortcutsDialog={() => toggleShortcutsDialog("shortcut")}
         snapshot={props.snapshot}
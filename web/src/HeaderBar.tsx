@@ -79,6 +79,7 @@ export default function HeaderBar(props: HeaderBarProps) {
     tiltCloudSchemeHost: session?.tiltCloudSchemeHost ?? "",
     tiltCloudTeamID: session?.tiltCloudTeamID ?? "",
     tiltCloudTeamName: session?.tiltCloudTeamName ?? "",
+    resourceNames: resources.map((r) => r.metadata?.name ?? ""),
   }
 
   const pb = usePathBuilder()
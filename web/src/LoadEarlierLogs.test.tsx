@@ -0,0 +1,66 @@
+import { mount } from "enzyme"
+import fetchMock from "fetch-mock"
+import React from "react"
+import {
+  cleanupMockAnalyticsCalls,
+  mockAnalyticsCalls,
+} from "./analytics_test_helpers"
+import LoadEarlierLogs, { loadEarlierLogs } from "./LoadEarlierLogs"
+import { logLinesToString } from "./logs"
+import LogStore, { LogStoreProvider } from "./LogStore"
+
+describe("LoadEarlierLogs", () => {
+  beforeEach(() => {
+    fetchMock.reset()
+    mockAnalyticsCalls()
+  })
+
+  afterEach(() => {
+    cleanupMockAnalyticsCalls()
+  })
+
+  const createLogStore = (): LogStore => {
+    const logStore = new LogStore()
+    logStore.append({
+      spans: { fe: { manifestName: "fe" } },
+      segments: [{ spanId: "fe", text: "line 3\n" }],
+      fromCheckpoint: 2,
+      toCheckpoint: 3,
+    })
+    return logStore
+  }
+
+  it("is hidden when there is no more history", () => {
+    const logStore = createLogStore()
+    logStore.historyExhausted = true
+    const root = mount(
+      <LogStoreProvider value={logStore}>
+        <LoadEarlierLogs />
+      </LogStoreProvider>
+    )
+    expect(root.find("button")).toHaveLength(0)
+  })
+
+  it("prepends the history before the first checkpoint", async () => {
+    fetchMock.mock(
+      "/api/logs/history?before=2",
+      JSON.stringify({
+        spans: { fe: { manifestName: "fe" } },
+        segments: [
+          { spanId: "fe", text: "line 1\n" },
+          { spanId: "fe", text: "line 2\n" },
+        ],
+        fromCheckpoint: 0,
+        toCheckpoint: 2,
+      })
+    )
+
+    const logStore = createLogStore()
+    await loadEarlierLogs(logStore)
+
+    expect(logStore.firstCheckpoint).toEqual(0)
+    expect(logLinesToString(logStore.manifestLog("fe"), false)).toEqual(
+      "line 1\nline 2\nline 3"
+    )
+  })
+})
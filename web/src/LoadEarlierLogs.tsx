@@ -0,0 +1,74 @@
+import React, { useState } from "react"
+import styled from "styled-components"
+import { InstrumentedButton } from "./instrumentedComponents"
+import LogStore, { useLogStore } from "./LogStore"
+import {
+  AnimDuration,
+  Color,
+  FontSize,
+  mixinResetButtonStyle,
+} from "./style-helpers"
+
+const LoadEarlierLogsButton = styled(InstrumentedButton)`
+  ${mixinResetButtonStyle};
+  margin-left: 1rem;
+  font-size: ${FontSize.small};
+  color: ${Color.white};
+  transition: color ${AnimDuration.default} ease;
+
+  &:hover {
+    color: ${Color.blue};
+  }
+
+  &:disabled {
+    color: ${Color.gray6};
+  }
+`
+
+// Fetch a page of archived logs from before the earliest log we have.
+//
+// The server only has log history if Tilt was started with --persist-logs.
+export const loadEarlierLogs = (logStore: LogStore): Promise<void> => {
+  let url = `/api/logs/history?before=${logStore.firstCheckpoint}`
+  return fetch(url)
+    .then((response) => {
+      if (!response.ok) {
+        return response.text().then((text) => {
+          throw new Error(text)
+        })
+      }
+      return response.json()
+    })
+    .then((logList: Proto.webviewLogList) => {
+      logStore.prepend(logList)
+    })
+    .catch((err) => {
+      console.error(err)
+    })
+}
+
+const LoadEarlierLogs: React.FC = () => {
+  const logStore = useLogStore()
+  const [loading, setLoading] = useState(false)
+
+  if (logStore.historyExhausted || logStore.firstCheckpoint === -1) {
+    return null
+  }
+
+  let onClick = () => {
+    setLoading(true)
+    loadEarlierLogs(logStore).finally(() => setLoading(false))
+  }
+
+  return (
+    <LoadEarlierLogsButton
+      onClick={onClick}
+      disabled={loading}
+      analyticsName="ui.web.loadEarlierLogs"
+    >
+      Load Earlier Logs
+    </LoadEarlierLogsButton>
+  )
+}
+
+export default LoadEarlierLogs
@@ -3,6 +3,7 @@ import { useStorageState } from "react-storage-hooks"
 import styled from "styled-components"
 import ClearLogs from "./ClearLogs"
 import { InstrumentedButton } from "./instrumentedComponents"
+import LoadEarlierLogs from "./LoadEarlierLogs"
 import {
   AnimDuration,
   Color,
@@ -120,6 +121,7 @@ const LogActions: React.FC<LogActionsProps> = ({
   return (
     <LogActionsGroup>
       <LogsFontSize />
+      {isSnapshot || <LoadEarlierLogs />}
       {isSnapshot || <ClearLogs resourceName={resourceName} />}
     </LogActionsGroup>
   )
@@ -506,6 +506,37 @@ describe("LogStore", () => {
     expect(logLinesToString(logs.manifestLog("be"), false)).toEqual("")
   })
 
+  it("prepends log history", () => {
+    let logs = new LogStore()
+
+    logs.append({
+      spans: { fe: { manifestName: "fe" } },
+      segments: [newManifestSegment("fe", "line 3\n")],
+      fromCheckpoint: 2,
+      toCheckpoint: 3,
+    })
+    expect(logs.firstCheckpoint).toEqual(2)
+
+    logs.prepend({
+      spans: { "session:0:fe": { manifestName: "fe" } },
+      segments: [
+        newManifestSegment("session:0:fe", "line 1\n"),
+        newManifestSegment("session:0:fe", "line 2\n"),
+      ],
+      fromCheckpoint: 0,
+      toCheckpoint: 2,
+    })
+    expect(logs.firstCheckpoint).toEqual(0)
+    expect(logs.historyExhausted).toEqual(false)
+    expect(logLinesToString(logs.manifestLog("fe"), false)).toEqual(
+      "line 1\nline 2\nline 3"
+    )
+
+    logs.prepend({ fromCheckpoint: -1, toCheckpoint: -1 })
+    expect(logs.historyExhausted).toEqual(true)
+    expect(logs.segments).toHaveLength(3)
+  })
+
   it("weights on recenty and length", () => {
     let logs = new LogStore()
     expect(
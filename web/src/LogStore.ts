@@ -76,6 +76,13 @@ class LogStore {
   // Track which segments we've received from the server.
   checkpoint: number
 
+  // The server checkpoint of the earliest segment we've received,
+  // so that we can page back through older logs. -1 if we have no logs.
+  firstCheckpoint: number = -1
+
+  // Set when the server has no older logs to page back through.
+  historyExhausted: boolean = false
+
   spans: { [key: string]: LogSpan }
 
   // These are held in-memory so we can send them on snapshot, and are
@@ -183,10 +190,53 @@ class LogStore {
       newSegments = newSegments.slice(deleteCount)
     }
 
+    if (this.firstCheckpoint === -1 && newSegments.length) {
+      this.firstCheckpoint = Math.max(fromCheckpoint, this.checkpoint)
+    }
+
     if (toCheckpoint > this.checkpoint) {
       this.checkpoint = toCheckpoint
     }
 
+    this.addSpans(newSpans)
+
+    newSegments.forEach((segment) => this.addSegment(segment))
+
+    this.invokeUpdateCallbacks({
+      action: LogUpdateAction.append,
+    })
+
+    this.ensureMaxLength()
+  }
+
+  // Add a page of older logs (from the server's log history) before
+  // the logs we already have, triggering a full rebuild of the line cache.
+  prepend(logList: Proto.webviewLogList) {
+    let newSpans = logList.spans as { [key: string]: Proto.webviewLogSpan }
+    let newSegments = logList.segments ?? []
+    let fromCheckpoint = logList.fromCheckpoint ?? -1
+    if (fromCheckpoint < 0 || newSegments.length === 0) {
+      this.historyExhausted = true
+      return
+    }
+
+    this.firstCheckpoint = fromCheckpoint
+    this.addSpans(newSpans)
+
+    // Make room for the older logs, so that they don't get truncated
+    // as soon as they're loaded.
+    newSegments.forEach((segment) => {
+      this.maxLogLength += segment.text?.length || 0
+    })
+
+    this.rebuild(newSegments.concat(this.segments))
+
+    this.invokeUpdateCallbacks({
+      action: LogUpdateAction.truncate,
+    })
+  }
+
+  private addSpans(newSpans: { [key: string]: Proto.webviewLogSpan }) {
     for (let key in newSpans) {
       let spanId = key || defaultSpanId
       let existingSpan = this.spans[spanId]
@@ -199,14 +249,24 @@ class LogStore {
         }
       }
     }
+  }
 
-    newSegments.forEach((segment) => this.addSegment(segment))
+  // Reset the state of the logstore to the given segments.
+  private rebuild(segments: Proto.webviewLogSegment[]) {
+    this.logLength = 0
+    this.lines = []
+    this.lineCache = []
+    this.segmentToLine = []
 
-    this.invokeUpdateCallbacks({
-      action: LogUpdateAction.append,
-    })
+    for (const span of Object.values(this.spans)) {
+      span.firstLineIndex = -1
+      span.lastLineIndex = -1
+    }
 
-    this.ensureMaxLength()
+    this.segments = []
+    for (const segment of segments) {
+      this.addSegment(segment)
+    }
   }
 
   private invokeUpdateCallbacks(e: LogUpdateEvent) {
@@ -666,22 +726,7 @@ class LogStore {
     }
 
     newSegments.reverse()
-
-    // Reset the state of the logstore.
-    this.logLength = 0
-    this.lines = []
-    this.lineCache = []
-    this.segmentToLine = []
-
-    for (const span of Object.values(this.spans)) {
-      span.firstLineIndex = -1
-      span.lastLineIndex = -1
-    }
-
-    this.segments = []
-    for (const segment of newSegments) {
-      this.addSegment(segment)
-    }
+    this.rebuild(newSegments)
 
     this.invokeUpdateCallbacks({
       action: LogUpdateAction.truncate,
@@ -47,6 +47,7 @@ export default function OverviewResourceBar(props: OverviewResourceBarProps) {
     tiltCloudSchemeHost: session?.tiltCloudSchemeHost ?? "",
     tiltCloudTeamID: session?.tiltCloudTeamID ?? "",
     tiltCloudTeamName: session?.tiltCloudTeamName ?? "",
+    resourceNames: resources.map((r) => r.metadata?.name ?? ""),
   }
 
   return (
@@ -24,7 +24,9 @@ class SidebarItem {
   triggerMode: TriggerMode
   hasPendingChanges: boolean
   queued: boolean
+  disabled: boolean
   lastBuild: Build | null = null
+  labels: string[]
 
   /**
    * Create a pared down SidebarItem from a ResourceView
@@ -52,7 +54,11 @@ class SidebarItem {
     this.triggerMode = status.triggerMode ?? TriggerMode.TriggerModeAuto
     this.hasPendingChanges = !!status.hasPendingChanges
     this.queued = !!status.queued
+    this.disabled = !!status.disabled
     this.lastBuild = lastBuild
+    this.labels = Object.keys(
+      (res.metadata?.labels ?? {}) as { [key: string]: string }
+    ).sort()
   }
 }
 
@@ -1,6 +1,7 @@
 import React from "react"
 import TimeAgo from "react-timeago"
 import styled from "styled-components"
+import { DisableToggle, setResourceDisabled } from "./DisableToggle"
 import PathBuilder from "./PathBuilder"
 import { useResourceNav } from "./ResourceNav"
 import SidebarIcon from "./SidebarIcon"
@@ -65,6 +66,10 @@ export let SidebarItemBox = styled.div`
     color: ${Color.gray};
   }
 
+  &.isDisabled {
+    opacity: ${ColorAlpha.translucent};
+  }
+
   &.isBuilding::after {
     content: "";
     position: absolute;
@@ -197,6 +202,10 @@ export type SidebarItemViewProps = {
 }
 
 function buildStatusText(item: SidebarItem): string {
+  if (item.disabled) {
+    return "Disabled"
+  }
+
   let buildDur = item.lastBuildDur ? formatBuildDuration(item.lastBuildDur) : ""
   let buildStatus = item.buildStatus
   if (buildStatus === ResourceStatus.Pending) {
@@ -264,8 +273,10 @@ export default function SidebarItemView(props: SidebarItemViewProps) {
 
   let isSelectedClass = isSelected ? "isSelected" : ""
   let isBuildingClass = building ? "isBuilding" : ""
+  let isDisabledClass = item.disabled ? "isDisabled" : ""
   let onTrigger = triggerUpdate.bind(null, item.name)
   let onModeToggle = toggleTriggerMode.bind(null, item.name)
+  let onDisableToggle = setResourceDisabled.bind(null, item.name)
 
   return (
     <SidebarItemRoot
@@ -277,7 +288,7 @@ export default function SidebarItemView(props: SidebarItemViewProps) {
         analyticsName="ui.web.sidebarStarButton"
       />
       <SidebarItemBox
-        className={`${isSelectedClass} ${isBuildingClass}`}
+        className={`${isSelectedClass} ${isBuildingClass} ${isDisabledClass}`}
         tabIndex={-1}
         role="button"
         onClick={(e) => nav.openResource(item.name)}
@@ -318,6 +329,12 @@ export default function SidebarItemView(props: SidebarItemViewProps) {
                 onModeToggle={onModeToggle}
               />
             )}
+            {!item.isTiltfile && (
+              <DisableToggle
+                disabled={item.disabled}
+                onToggle={onDisableToggle}
+              />
+            )}
           </SidebarItemBuildBox>
         </SidebarItemInnerBox>
       </SidebarItemBox>
@@ -16,7 +16,12 @@ import {
 import { assertSidebarItemsAndOptions } from "./OverviewSidebarOptions.test"
 import PathBuilder from "./PathBuilder"
 import SidebarItem from "./SidebarItem"
-import SidebarResources from "./SidebarResources"
+import SidebarItemView from "./SidebarItemView"
+import SidebarResources, {
+  groupItemsByLabel,
+  SidebarGroupListSection,
+  UNLABELED_GROUP,
+} from "./SidebarResources"
 import { StarredResourcesContextProvider } from "./StarredResourcesContext"
 import StarResourceButton from "./StarResourceButton"
 import {
@@ -238,4 +243,58 @@ describe("SidebarResources", () => {
       expect(observedOptions).toEqual(expectedOptions)
     }
   )
+
+  function labeledItems(): SidebarItem[] {
+    let labeled = (name: string, labels: string[]) => {
+      let res = oneResourceTestWithName(name)
+      let labelMap: { [key: string]: string } = {}
+      labels.forEach((l) => (labelMap[l] = l))
+      res.metadata!.labels = labelMap
+      return new SidebarItem(res)
+    }
+    return [
+      labeled("frontend", ["web"]),
+      labeled("api", ["backend", "web"]),
+      labeled("db", ["backend"]),
+      new SidebarItem(oneResource()),
+    ]
+  }
+
+  it("groups items by label, with unlabeled items last", () => {
+    let groups = groupItemsByLabel(labeledItems()).map(([label, items]) => [
+      label,
+      items.map((i) => i.name),
+    ])
+    expect(groups).toEqual([
+      ["backend", ["api", "db"]],
+      ["web", ["frontend", "api"]],
+      [UNLABELED_GROUP, ["vigoda"]],
+    ])
+  })
+
+  it("collapses a label group when clicked", () => {
+    const root = mount(
+      <MemoryRouter>
+        <tiltfileKeyContext.Provider value="test">
+          <SidebarResources
+            items={labeledItems()}
+            selected={""}
+            resourceView={ResourceView.Log}
+            pathBuilder={pathBuilder}
+          />
+        </tiltfileKeyContext.Provider>
+      </MemoryRouter>
+    )
+
+    let groups = root.find(SidebarGroupListSection)
+    expect(groups.map((g) => g.props().name)).toEqual([
+      "backend",
+      "web",
+      UNLABELED_GROUP,
+    ])
+    expect(root.find(SidebarItemView).length).toEqual(5)
+
+    root.find(".SidebarGroup").first().simulate("click")
+    expect(root.find(SidebarItemView).length).toEqual(3)
+  })
 })
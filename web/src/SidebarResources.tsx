@@ -1,4 +1,4 @@
-import React, { Dispatch, SetStateAction } from "react"
+import React, { Dispatch, SetStateAction, useState } from "react"
 import styled from "styled-components"
 import { PersistentStateProvider } from "./LocalStorage"
 import { OverviewSidebarOptions } from "./OverviewSidebarOptions"
@@ -27,6 +27,20 @@ let SidebarListSectionName = styled.div`
   color: ${Color.grayLight};
   font-size: ${FontSize.small};
 `
+const SidebarGroupName = styled(SidebarListSectionName)`
+  cursor: pointer;
+  user-select: none;
+
+  &:hover {
+    color: ${Color.grayLightest};
+  }
+`
+
+const SidebarGroupArrow = styled.span`
+  display: inline-block;
+  width: ${SizeUnit(0.5)};
+`
+
 const SidebarListSectionItems = styled.ul`
   margin-top: ${SizeUnit(0.25)};
   list-style: none;
@@ -48,6 +62,57 @@ export function SidebarListSection(
   )
 }
 
+// A section of resources that share a label, which the user can collapse.
+export function SidebarGroupListSection(
+  props: React.PropsWithChildren<{ name: string }>
+): JSX.Element {
+  let [expanded, setExpanded] = useState(true)
+  return (
+    <div>
+      <SidebarGroupName
+        className={`SidebarGroup ${expanded ? "is-expanded" : ""}`}
+        onClick={() => setExpanded(!expanded)}
+      >
+        <SidebarGroupArrow>{expanded ? "▾" : "▸"}</SidebarGroupArrow>
+        {props.name}
+      </SidebarGroupName>
+      {expanded ? (
+        <SidebarListSectionItems>{props.children}</SidebarListSectionItems>
+      ) : null}
+    </div>
+  )
+}
+
+export const UNLABELED_GROUP = "unlabeled"
+
+// Groups items by label, in label order, with unlabeled items last.
+// An item with several labels appears in each of their groups.
+export function groupItemsByLabel(
+  items: SidebarItem[]
+): [string, SidebarItem[]][] {
+  let groups = new Map<string, SidebarItem[]>()
+  let unlabeled: SidebarItem[] = []
+  items.forEach((item) => {
+    if (item.labels.length === 0) {
+      unlabeled.push(item)
+      return
+    }
+    item.labels.forEach((label) => {
+      let group = groups.get(label) ?? []
+      group.push(item)
+      groups.set(label, group)
+    })
+  })
+
+  let result = Array.from(groups.keys())
+    .sort()
+    .map((label): [string, SidebarItem[]] => [label, groups.get(label)!])
+  if (unlabeled.length > 0) {
+    result.push([UNLABELED_GROUP, unlabeled])
+  }
+  return result
+}
+
 type UIResource = Proto.v1alpha1UIResource
 type Build = Proto.v1alpha1UIBuildTerminated
 
@@ -135,15 +200,17 @@ export class SidebarResources extends React.Component<SidebarProps> {
       filteredItems.sort(sortByHasAlerts)
     }
 
-    let listItems = filteredItems.map((item) => (
-      <SidebarItemView
-        key={"sidebarItem-" + item.name}
-        item={item}
-        selected={this.props.selected == item.name}
-        pathBuilder={this.props.pathBuilder}
-        resourceView={this.props.resourceView}
-      />
-    ))
+    let renderItems = (items: SidebarItem[]) =>
+      items.map((item) => (
+        <SidebarItemView
+          key={"sidebarItem-" + item.name}
+          item={item}
+          selected={this.props.selected == item.name}
+          pathBuilder={this.props.pathBuilder}
+          resourceView={this.props.resourceView}
+        />
+      ))
+    let listItems = renderItems(filteredItems)
 
     let nothingSelected = !this.props.selected
     let isOverviewClass =
@@ -162,6 +229,25 @@ export class SidebarResources extends React.Component<SidebarProps> {
       ]
     }
 
+    // If any resources have labels, show them in collapsible groups by label.
+    let sections = (
+      <SidebarListSection name="resources">{listItems}</SidebarListSection>
+    )
+    if (
+      filteredItems.length > 0 &&
+      this.props.items.some((item) => item.labels.length > 0)
+    ) {
+      sections = (
+        <>
+          {groupItemsByLabel(filteredItems).map(([label, items]) => (
+            <SidebarGroupListSection key={"group-" + label} name={label}>
+              {renderItems(items)}
+            </SidebarGroupListSection>
+          ))}
+        </>
+      )
+    }
+
     return (
       <SidebarResourcesRoot className={`Sidebar-resources ${isOverviewClass}`}>
         <SidebarList>
@@ -170,7 +256,7 @@ export class SidebarResources extends React.Component<SidebarProps> {
             options={options}
             setOptions={setOptions}
           />
-          <SidebarListSection name="resources">{listItems}</SidebarListSection>
+          {sections}
         </SidebarList>
         <SidebarKeyboardShortcuts
           selected={this.props.selected}
@@ -0,0 +1,70 @@
+import { mount } from "enzyme"
+import fetchMock from "fetch-mock"
+import React from "react"
+import {
+  listChoices,
+  setTiltfileValue,
+  TiltfileSettingField,
+} from "./TiltfileArgsDialog"
+
+describe("TiltfileArgsDialog", () => {
+  beforeEach(() => {
+    fetchMock.reset()
+  })
+
+  it("offers every resource for a list setting", () => {
+    expect(listChoices(["(Tiltfile)", "fe", "be"], ["be", "infra"])).toEqual([
+      "fe",
+      "be",
+      "infra",
+    ])
+  })
+
+  it("toggles a bool setting", () => {
+    let values: any[] = []
+    const root = mount(
+      <TiltfileSettingField
+        setting={{ name: "debug", type: "bool", default: false }}
+        resourceNames={[]}
+        onChange={(v) => values.push(v)}
+      />
+    )
+
+    root
+      .find("input[type='checkbox']")
+      .simulate("change", { target: { checked: true } })
+    expect(values).toEqual([true])
+  })
+
+  it("checks the resources in a list setting", () => {
+    let values: any[] = []
+    const root = mount(
+      <TiltfileSettingField
+        setting={{ name: "to-run", type: "list[string]", value: ["fe"] }}
+        resourceNames={["fe", "be"]}
+        onChange={(v) => values.push(v)}
+      />
+    )
+
+    let boxes = root.find("input[type='checkbox']")
+    expect(boxes).toHaveLength(2)
+    expect(boxes.at(0).prop("checked")).toEqual(true)
+    expect(boxes.at(1).prop("checked")).toEqual(false)
+
+    boxes.at(1).simulate("change", { target: { checked: true } })
+    expect(values).toEqual([["fe", "be"]])
+  })
+
+  it("POSTs only the changed value", () => {
+    fetchMock.mock("/api/set_tiltfile_values", JSON.stringify({}))
+
+    setTiltfileValue("to-run", ["fe"])
+
+    expect(fetchMock.calls().length).toEqual(1)
+    expect(fetchMock.calls()[0][0]).toEqual("/api/set_tiltfile_values")
+    expect(fetchMock.calls()[0][1]?.method).toEqual("post")
+    expect(fetchMock.calls()[0][1]?.body).toEqual(
+      JSON.stringify({ values: { "to-run": ["fe"] } })
+    )
+  })
+})
@@ -0,0 +1,196 @@
+import Checkbox from "@material-ui/core/Checkbox"
+import Switch from "@material-ui/core/Switch"
+import React, { useEffect, useState } from "react"
+import styled from "styled-components"
+import FloatDialog from "./FloatDialog"
+import { Color, Font, FontSize } from "./style-helpers"
+
+// A setting that the Tiltfile defined with config.define_*.
+// Matches model.UserConfigSetting on the server.
+export type TiltfileSetting = {
+  name: string
+  type: string
+  usage?: string
+  choices?: string[]
+  default?: any
+  positional?: boolean
+  value?: any
+}
+
+type TiltfileArgsPayload = {
+  args: string[]
+  settings: TiltfileSetting[]
+}
+
+type props = {
+  open: boolean
+  onClose: () => void
+  anchorEl: Element | null
+  resourceNames: string[]
+}
+
+let SettingRow = styled.div`
+  margin-bottom: 12px;
+`
+
+let SettingName = styled.div`
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.small};
+`
+
+let SettingUsage = styled.div`
+  font-size: ${FontSize.smallest};
+  color: ${Color.grayLight};
+`
+
+let SettingValue = styled.div`
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+`
+
+let CheckboxLabel = styled.label`
+  display: flex;
+  align-items: center;
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.smallest};
+`
+
+export function settingValue(setting: TiltfileSetting): any {
+  return setting.value ?? setting.default
+}
+
+// Updates the Tiltfile args, which re-runs the Tiltfile.
+// Settings we don't send keep their current args.
+export function setTiltfileValue(name: string, value: any) {
+  fetch("/api/set_tiltfile_values", {
+    method: "post",
+    body: JSON.stringify({ values: { [name]: value } }),
+  }).then((response) => {
+    if (!response.ok) {
+      console.log(response)
+    }
+  })
+}
+
+// The resources a list setting can choose from: every resource,
+// plus anything already in the list (e.g., a group name the Tiltfile expands).
+export function listChoices(resourceNames: string[], selected: string[]) {
+  let choices = resourceNames.filter((n) => n !== "(Tiltfile)")
+  selected.forEach((s) => {
+    if (!choices.includes(s)) {
+      choices.push(s)
+    }
+  })
+  return choices
+}
+
+function ListSetting(props: {
+  setting: TiltfileSetting
+  resourceNames: string[]
+  onChange: (value: string[]) => void
+}) {
+  let selected: string[] = settingValue(props.setting) ?? []
+  let choices = listChoices(props.resourceNames, selected)
+  return (
+    <div>
+      {choices.map((name) => {
+        let checked = selected.includes(name)
+        let onChange = () => {
+          props.onChange(
+            checked ? selected.filter((s) => s !== name) : [...selected, name]
+          )
+        }
+        return (
+          <CheckboxLabel key={name}>
+            <Checkbox size="small" checked={checked} onChange={onChange} />
+            {name}
+          </CheckboxLabel>
+        )
+      })}
+    </div>
+  )
+}
+
+export function TiltfileSettingField(props: {
+  setting: TiltfileSetting
+  resourceNames: string[]
+  onChange: (value: any) => void
+}) {
+  let setting = props.setting
+  let field: React.ReactElement
+  if (setting.type === "bool") {
+    field = (
+      <Switch
+        size="small"
+        checked={!!settingValue(setting)}
+        onChange={(e) => props.onChange(e.target.checked)}
+      />
+    )
+  } else if (setting.type === "list[string]") {
+    field = (
+      <ListSetting
+        setting={setting}
+        resourceNames={props.resourceNames}
+        onChange={props.onChange}
+      />
+    )
+  } else {
+    // Other types are set with `tilt args`.
+    field = (
+      <SettingValue>
+        {JSON.stringify(settingValue(setting) ?? null)}
+      </SettingValue>
+    )
+  }
+
+  return (
+    <SettingRow>
+      <SettingName>{setting.name}</SettingName>
+      {setting.usage ? <SettingUsage>{setting.usage}</SettingUsage> : null}
+      {field}
+    </SettingRow>
+  )
+}
+
+export default function TiltfileArgsDialog(props: props) {
+  let [settings, setSettings] = useState([] as TiltfileSetting[])
+
+  useEffect(() => {
+    if (!props.open) {
+      return
+    }
+    fetch("/api/tiltfile_args")
+      .then((response) => response.json())
+      .then((payload: TiltfileArgsPayload) =>
+        setSettings(payload.settings ?? [])
+      )
+      .catch((err) => console.log(err))
+  }, [props.open])
+
+  let onChange = (name: string, value: any) => {
+    // Show the new value right away.
+    // The Tiltfile reloads with it in the background.
+    setSettings(settings.map((s) => (s.name === name ? { ...s, value } : s)))
+    setTiltfileValue(name, value)
+  }
+
+  let content: React.ReactElement | React.ReactElement[]
+  if (settings.length === 0) {
+    content = <div>This Tiltfile doesn't define any config settings.</div>
+  } else {
+    content = settings.map((s) => (
+      <TiltfileSettingField
+        key={s.name}
+        setting={s}
+        resourceNames={props.resourceNames}
+        onChange={(value) => onChange(s.name, value)}
+      />
+    ))
+  }
+
+  return (
+    <FloatDialog id="tiltfileArgs" title="Tiltfile Args" {...props}>
+      {content}
+    </FloatDialog>
+  )
+}
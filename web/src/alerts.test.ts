@@ -49,6 +49,45 @@ describe("combinedAlerts", () => {
     expect(actual).toEqual(expectedAlerts)
   })
 
+  it("K8s UIResource: should show warning event alerts", () => {
+    let r: UIResource = k8sResource()
+    let rInfo = r.status!.k8sResourceInfo
+    if (!rInfo) throw new Error("missing k8s info")
+    rInfo.warningEvents = [
+      {
+        objectKind: "Pod",
+        objectName: "snack-abc123",
+        reason: "FailedScheduling",
+        message: "0/1 nodes are available: 1 Insufficient cpu.",
+        count: 3,
+      },
+      {
+        objectKind: "Pod",
+        objectName: "snack-abc123",
+        reason: "FailedMount",
+        message: 'secret "snack-creds" not found',
+        count: 1,
+      },
+    ]
+    let actual = combinedAlerts(r, logStore)
+    let expectedAlerts: Alert[] = [
+      {
+        msg:
+          "Pod snack-abc123: FailedScheduling: 0/1 nodes are available: 1 Insufficient cpu. (x3)",
+        resourceName: "snack",
+        level: FilterLevel.warn,
+        source: FilterSource.runtime,
+      },
+      {
+        msg: 'Pod snack-abc123: FailedMount: secret "snack-creds" not found',
+        resourceName: "snack",
+        level: FilterLevel.warn,
+        source: FilterSource.runtime,
+      },
+    ]
+    expect(actual).toEqual(expectedAlerts)
+  })
+
   it("K8s UIResource should show the first build alert", () => {
     let r: UIResource = k8sResource()
     r.status!.buildHistory = [
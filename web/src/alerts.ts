@@ -51,6 +51,7 @@ function runtimeAlerts(r: UIResource, logStore: LogStore | null): Alert[] {
     if (crashRebuild(r)) {
       result.push(crashRebuildAlert(r))
     }
+    result.push(...k8sWarningEventAlerts(r))
   }
 
   return result
@@ -112,6 +113,18 @@ function crashRebuildAlert(r: UIResource): Alert {
     source: FilterSource.runtime,
   }
 }
+function k8sWarningEventAlerts(r: UIResource): Alert[] {
+  let rInfo = r.status?.k8sResourceInfo as K8sResourceInfo
+  return (rInfo.warningEvents ?? []).map((e) => {
+    let count = (e.count ?? 0) > 1 ? ` (x${e.count})` : ""
+    return {
+      msg: `${e.objectKind} ${e.objectName}: ${e.reason}: ${e.message}${count}`,
+      resourceName: r.metadata?.name ?? "",
+      level: FilterLevel.warn,
+      source: FilterSource.runtime,
+    }
+  })
+}
 function buildFailedAlert(
   resource: UIResource,
   logStore: LogStore | null
@@ -167,4 +180,5 @@ export {
   buildFailedAlert,
   crashRebuildAlert,
   podRestartAlert,
+  k8sWarningEventAlerts,
 }
@@ -406,6 +406,13 @@ declare namespace Proto {
     updateStatus?: string;
     specs?: v1alpha1UIResourceTargetSpec[];
     queued?: boolean;
+    /**
+     * Disabled is true when the user has turned the resource off.
+     *
+     * A disabled resource doesn't build, and anything it deployed is torn down.
+     * +optional
+     */
+    disabled?: boolean;
   }
   export interface v1alpha1UIResourceSpec {}
   export interface v1alpha1UIResourceLocal {
@@ -433,6 +440,23 @@ declare namespace Proto {
     podRestarts?: number;
     spanID?: string;
     displayNames?: string[];
+    /**
+     * Recent Kubernetes Warning events on objects deployed by this resource,
+     * most recent first.
+     *
+     * Events with the same reason on the same object are combined.
+     * +optional
+     */
+    warningEvents?: v1alpha1UIResourceKubernetesEvent[];
+  }
+  export interface v1alpha1UIResourceKubernetesEvent {
+    objectKind?: string;
+    objectName?: string;
+    reason?: string;
+    message?: string;
+    count?: number;
+    firstTimestamp?: string;
+    lastTimestamp?: string;
   }
   export interface v1alpha1UIResource {
     metadata?: v1ObjectMeta;